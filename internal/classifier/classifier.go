@@ -0,0 +1,406 @@
+package classifier
+
+import (
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+)
+
+type Classifier struct {
+	logger   *logger.Logger
+	config   *types.Config
+	patterns map[string]*utils.RegistryPattern
+}
+
+func New(config *types.Config, logger *logger.Logger) *Classifier {
+	c := &Classifier{
+		logger:   logger,
+		config:   config,
+		patterns: make(map[string]*utils.RegistryPattern),
+	}
+	if config == nil {
+		return c
+	}
+
+	detection := config.ImageDetection
+	for _, patterns := range [][]string{detection.IgnoreRegistries, detection.CustomPrivateRegistries, detection.CustomPublicRegistries, detection.IncludeOnly, detection.NoMigrate} {
+		for _, pattern := range patterns {
+			if _, compiled := c.patterns[pattern]; compiled {
+				continue
+			}
+			compiled, err := utils.CompileRegistryPattern(pattern)
+			if err != nil {
+				logger.Warn("invalid_registry_pattern").
+					Str("pattern", pattern).
+					Err(err).
+					Send()
+				continue
+			}
+			c.patterns[pattern] = compiled
+		}
+	}
+
+	return c
+}
+
+func (c *Classifier) IsPublic(imageName string) bool {
+	imageLower := strings.ToLower(imageName)
+
+	c.logger.Debug("starting_image_classification").
+		Str("image", imageName).
+		Str("image_lower", imageLower).
+		Send()
+
+	if c.shouldIgnoreRegistry(imageName) {
+		c.logger.Debug("image_classification_result").
+			Str("image", imageName).
+			Str("decision", "ignored").
+			Str("reason", "registry_in_ignore_list").
+			Bool("is_public", false).
+			Send()
+		return false
+	}
+
+	if c.isCustomPrivateRegistry(imageName) {
+		c.logger.Debug("image_classification_result").
+			Str("image", imageName).
+			Str("decision", "private").
+			Str("reason", "custom_private_registry").
+			Bool("is_public", false).
+			Send()
+		return false
+	}
+
+	if c.isCustomPublicRegistry(imageName) {
+		c.logger.Debug("image_classification_result").
+			Str("image", imageName).
+			Str("decision", "public").
+			Str("reason", "custom_public_registry").
+			Bool("is_public", true).
+			Send()
+		return true
+	}
+
+	knownPrivateRegistries := []string{
+		"localhost",
+		"127.0.0.1",
+	}
+
+	for _, registry := range knownPrivateRegistries {
+		if strings.HasPrefix(imageLower, registry) {
+			c.logger.Debug("image_classification_result").
+				Str("image", imageName).
+				Str("decision", "private").
+				Str("reason", "known_private_registry").
+				Str("matched_registry", registry).
+				Bool("is_public", false).
+				Send()
+			return false
+		}
+	}
+
+	if c.isPrivateRegistry(imageName) {
+		c.logger.Debug("image_classification_result").
+			Str("image", imageName).
+			Str("decision", "private").
+			Str("reason", "detected_as_private_registry").
+			Bool("is_public", false).
+			Send()
+		return false
+	}
+
+	c.logger.Debug("image_classification_result").
+		Str("image", imageName).
+		Str("decision", "public").
+		Str("reason", "default_public_classification").
+		Bool("is_public", true).
+		Send()
+	return true
+}
+
+func (c *Classifier) shouldIgnoreRegistry(imageName string) bool {
+	if c.config == nil || len(c.config.ImageDetection.IgnoreRegistries) == 0 {
+		c.logger.Debug("ignore_registry_check").
+			Str("image", imageName).
+			Bool("has_config", c.config != nil).
+			Int("ignore_list_size", 0).
+			Bool("should_ignore", false).
+			Send()
+		return false
+	}
+
+	imageLower := strings.ToLower(imageName)
+	for _, ignored := range c.config.ImageDetection.IgnoreRegistries {
+		if c.matchesRegistryPattern(imageLower, ignored) {
+			c.logger.Debug("ignore_registry_check").
+				Str("image", imageName).
+				Str("matched_ignore_pattern", ignored).
+				Bool("should_ignore", true).
+				Send()
+			return true
+		}
+	}
+
+	c.logger.Debug("ignore_registry_check").
+		Str("image", imageName).
+		Int("ignore_list_size", len(c.config.ImageDetection.IgnoreRegistries)).
+		Bool("should_ignore", false).
+		Send()
+	return false
+}
+
+func (c *Classifier) isCustomPrivateRegistry(imageName string) bool {
+	if c.config == nil || len(c.config.ImageDetection.CustomPrivateRegistries) == 0 {
+		c.logger.Debug("custom_private_registry_check").
+			Str("image", imageName).
+			Bool("has_config", c.config != nil).
+			Int("private_list_size", 0).
+			Bool("is_custom_private", false).
+			Send()
+		return false
+	}
+
+	imageLower := strings.ToLower(imageName)
+	for _, privateReg := range c.config.ImageDetection.CustomPrivateRegistries {
+		if c.matchesRegistryPattern(imageLower, privateReg) {
+			c.logger.Debug("custom_private_registry_check").
+				Str("image", imageName).
+				Str("matched_private_pattern", privateReg).
+				Bool("is_custom_private", true).
+				Send()
+			return true
+		}
+	}
+
+	c.logger.Debug("custom_private_registry_check").
+		Str("image", imageName).
+		Int("private_list_size", len(c.config.ImageDetection.CustomPrivateRegistries)).
+		Bool("is_custom_private", false).
+		Send()
+	return false
+}
+
+func (c *Classifier) isCustomPublicRegistry(imageName string) bool {
+	if c.config == nil || len(c.config.ImageDetection.CustomPublicRegistries) == 0 {
+		c.logger.Debug("custom_public_registry_check").
+			Str("image", imageName).
+			Bool("has_config", c.config != nil).
+			Int("public_list_size", 0).
+			Bool("is_custom_public", false).
+			Send()
+		return false
+	}
+
+	imageLower := strings.ToLower(imageName)
+	for _, publicReg := range c.config.ImageDetection.CustomPublicRegistries {
+		if c.matchesRegistryPattern(imageLower, publicReg) {
+			c.logger.Debug("custom_public_registry_check").
+				Str("image", imageName).
+				Str("matched_public_pattern", publicReg).
+				Bool("is_custom_public", true).
+				Send()
+			return true
+		}
+	}
+
+	c.logger.Debug("custom_public_registry_check").
+		Str("image", imageName).
+		Int("public_list_size", len(c.config.ImageDetection.CustomPublicRegistries)).
+		Bool("is_custom_public", false).
+		Send()
+	return false
+}
+
+func (c *Classifier) MatchesIncludeOnly(imageName string) bool {
+	if c.config == nil || len(c.config.ImageDetection.IncludeOnly) == 0 {
+		return true
+	}
+
+	return c.matchesAnyImagePattern(imageName, c.config.ImageDetection.IncludeOnly)
+}
+
+func (c *Classifier) MatchesNoMigrate(imageName string) bool {
+	if c.config == nil || len(c.config.ImageDetection.NoMigrate) == 0 {
+		return false
+	}
+
+	return c.matchesAnyImagePattern(imageName, c.config.ImageDetection.NoMigrate)
+}
+
+func (c *Classifier) matchesAnyImagePattern(imageName string, patterns []string) bool {
+	parsed := utils.ParseImageName(strings.ToLower(imageName))
+	candidates := []string{
+		strings.ToLower(imageName),
+		parsed.FullRepository,
+		parsed.Registry + "/" + parsed.FullRepository,
+	}
+
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			if c.matchesRegistryPattern(candidate, pattern) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *Classifier) matchesRegistryPattern(imageLower, pattern string) bool {
+	compiled, ok := c.patterns[pattern]
+	return ok && compiled.Match(imageLower)
+}
+
+func (c *Classifier) isPrivateRegistry(imageName string) bool {
+	imageLower := strings.ToLower(imageName)
+
+	c.logger.Debug("private_registry_detection_start").
+		Str("image", imageName).
+		Send()
+
+	if isKubernetesRegistryImage(imageLower) {
+		c.logger.Debug("private_registry_detection").
+			Str("image", imageName).
+			Str("type", "kubernetes_registry_redirect").
+			Bool("is_private", false).
+			Send()
+		return false
+	}
+
+	if strings.Contains(imageLower, ".dkr.ecr.") &&
+		strings.Contains(imageLower, ".amazonaws.com") &&
+		!strings.HasPrefix(imageLower, "public.ecr.aws") {
+		c.logger.Debug("private_registry_detection").
+			Str("image", imageName).
+			Str("type", "ecr_private").
+			Bool("is_private", true).
+			Send()
+		return true
+	}
+
+	if strings.Contains(imageLower, ".azurecr.io") &&
+		!strings.Contains(imageLower, "mcr.microsoft.com") {
+		c.logger.Debug("private_registry_detection").
+			Str("image", imageName).
+			Str("type", "azure_private").
+			Bool("is_private", true).
+			Send()
+		return true
+	}
+
+	if (strings.Contains(imageLower, ".gcr.io") ||
+		strings.Contains(imageLower, ".pkg.dev")) &&
+		!strings.HasPrefix(imageLower, "gcr.io/google-containers") &&
+		!strings.HasPrefix(imageLower, "k8s.gcr.io") &&
+		!strings.HasPrefix(imageLower, "registry.k8s.io") {
+		c.logger.Debug("private_registry_detection").
+			Str("image", imageName).
+			Str("type", "gcp_private").
+			Bool("is_private", true).
+			Send()
+		return true
+	}
+
+	if strings.HasPrefix(imageLower, "ghcr.io/") {
+		segments := strings.Split(strings.TrimPrefix(imageLower, "ghcr.io/"), "/")
+		if len(segments) >= 2 && segments[0] != "" && segments[len(segments)-1] != "" {
+			c.logger.Debug("private_registry_detection").
+				Str("image", imageName).
+				Str("type", "ghcr_private").
+				Str("owner", segments[0]).
+				Int("path_segments", len(segments)).
+				Bool("is_private", true).
+				Send()
+			return true
+		}
+
+		c.logger.Debug("private_registry_detection").
+			Str("image", imageName).
+			Str("type", "ghcr_missing_owner").
+			Int("path_segments", len(segments)).
+			Bool("is_private", false).
+			Send()
+		return false
+	}
+
+	knownPublicRegistries := []string{
+		"docker.io",
+		"index.docker.io",
+		"registry-1.docker.io",
+		"quay.io",
+		"registry.k8s.io",
+		"k8s.gcr.io",
+		"gcr.io/google-containers",
+		"mcr.microsoft.com",
+		"public.ecr.aws",
+	}
+
+	for _, publicReg := range knownPublicRegistries {
+		if strings.HasPrefix(imageLower, publicReg) ||
+			strings.Contains(imageLower, publicReg) {
+			c.logger.Debug("private_registry_detection").
+				Str("image", imageName).
+				Str("type", "known_public_registry").
+				Str("matched_registry", publicReg).
+				Bool("is_private", false).
+				Send()
+			return false
+		}
+	}
+
+	parts := strings.Split(imageName, "/")
+	if len(parts) >= 2 && strings.Contains(parts[0], ".") &&
+		!strings.Contains(parts[0], "docker.io") &&
+		!strings.Contains(parts[0], "index.docker.io") &&
+		!strings.Contains(parts[0], "registry-1.docker.io") {
+
+		firstPart := strings.ToLower(parts[0])
+		for _, publicReg := range knownPublicRegistries {
+			if strings.Contains(firstPart, strings.ToLower(publicReg)) {
+				c.logger.Debug("private_registry_detection").
+					Str("image", imageName).
+					Str("type", "public_registry_in_custom_domain_check").
+					Str("detected_public", publicReg).
+					Bool("is_private", false).
+					Send()
+				return false
+			}
+		}
+
+		c.logger.Debug("private_registry_detection").
+			Str("image", imageName).
+			Str("type", "custom_domain_private").
+			Str("detected_registry", parts[0]).
+			Int("parts_count", len(parts)).
+			Bool("is_private", true).
+			Send()
+		return true
+	}
+
+	c.logger.Debug("private_registry_detection").
+		Str("image", imageName).
+		Str("type", "not_detected_as_private").
+		Bool("is_private", false).
+		Send()
+	return false
+}
+
+func isKubernetesRegistryImage(imageLower string) bool {
+	host, path, found := strings.Cut(imageLower, "/")
+	if !found {
+		return false
+	}
+
+	switch {
+	case host == "registry.k8s.io", host == "k8s.gcr.io":
+		return true
+	case strings.HasSuffix(host, "-docker.pkg.dev") && strings.HasPrefix(path, "k8s-artifacts-prod/"):
+		return true
+	case strings.HasPrefix(host, "prod-registry-k8s-io-"):
+		return true
+	}
+
+	return false
+}
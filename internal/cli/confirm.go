@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+type migrationConfirmation struct {
+	in          *bufio.Reader
+	out         io.Writer
+	autoConfirm bool
+}
+
+func newMigrationConfirmation(in io.Reader, out io.Writer, autoConfirm bool) *migrationConfirmation {
+	return &migrationConfirmation{
+		in:          bufio.NewReader(in),
+		out:         out,
+		autoConfirm: autoConfirm,
+	}
+}
+
+func (c *migrationConfirmation) Confirm(action string, images []*types.ImageInfo) []*types.ImageInfo {
+	if c.autoConfirm || len(images) == 0 {
+		return images
+	}
+
+	grouped := make(map[string][]*types.ImageInfo)
+	for _, image := range images {
+		grouped[image.Image] = append(grouped[image.Image], image)
+	}
+	names := make([]string, 0, len(grouped))
+	for name := range grouped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(c.out, "%s - %d imagem(ns) planejada(s):\n", action, len(names))
+	for i, name := range names {
+		fmt.Fprintf(c.out, "  %d. %s (%s)\n", i+1, name, strings.Join(imageNamespaces(grouped[name]), ", "))
+	}
+
+	switch c.ask("Continuar? [s]im / [n]ão / [e]scolher por item: ") {
+	case "s", "sim", "y", "yes":
+		return images
+	case "e", "escolher":
+		var selected []*types.ImageInfo
+		for _, name := range names {
+			switch c.ask(fmt.Sprintf("  Incluir %s? [s/N]: ", name)) {
+			case "s", "sim", "y", "yes":
+				selected = append(selected, grouped[name]...)
+			}
+		}
+		return selected
+	default:
+		return nil
+	}
+}
+
+func confirmMigration(action string, images []*types.ImageInfo) []*types.ImageInfo {
+	if !migrateInteractive {
+		return images
+	}
+
+	selected := newMigrationConfirmation(os.Stdin, os.Stderr, migrateYes).Confirm(action, images)
+	if len(selected) == 0 {
+		log.Warn("migration_cancelled").
+			Str("command", action).
+			Str("message", "Nenhuma imagem confirmada para migração").
+			Send()
+		return nil
+	}
+
+	log.Info("migration_confirmed").
+		Str("command", action).
+		Int("selected", len(selected)).
+		Int("planned", len(images)).
+		Send()
+	return selected
+}
+
+func (c *migrationConfirmation) ask(question string) string {
+	fmt.Fprint(c.out, question)
+	answer, err := c.in.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(c.out)
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(answer))
+}
+
+func imageNamespaces(images []*types.ImageInfo) []string {
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, image := range images {
+		if image.Namespace != "" && !seen[image.Namespace] {
+			seen[image.Namespace] = true
+			namespaces = append(namespaces, image.Namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
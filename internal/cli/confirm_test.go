@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestMigrationConfirmation_Confirm(t *testing.T) {
+	images := []*types.ImageInfo{
+		{Image: "nginx:1.25", Namespace: "web"},
+		{Image: "redis:7", Namespace: "cache"},
+		{Image: "nginx:1.25", Namespace: "staging"},
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		autoConfirm bool
+		expected    []string
+	}{
+		{name: "auto confirm skips prompt", input: "", autoConfirm: true, expected: []string{"nginx:1.25", "redis:7", "nginx:1.25"}},
+		{name: "confirm all", input: "s\n", expected: []string{"nginx:1.25", "redis:7", "nginx:1.25"}},
+		{name: "decline", input: "n\n", expected: nil},
+		{name: "no input declines", input: "", expected: nil},
+		{name: "per item selection", input: "e\nn\nsim\n", expected: []string{"redis:7"}},
+		{name: "per item selection keeps every namespace", input: "e\ns\n\n", expected: []string{"nginx:1.25", "nginx:1.25"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			confirmation := newMigrationConfirmation(strings.NewReader(tt.input), &out, tt.autoConfirm)
+
+			selected := confirmation.Confirm("migrate cluster", images)
+
+			var got []string
+			for _, image := range selected {
+				got = append(got, image.Image)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Confirm() = %v, expected %v", got, tt.expected)
+			}
+			if tt.autoConfirm && out.Len() != 0 {
+				t.Errorf("auto confirm should not prompt, got output %q", out.String())
+			}
+			if !tt.autoConfirm && !strings.Contains(out.String(), "nginx:1.25 (staging, web)") {
+				t.Errorf("prompt should list planned images, got %q", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kevinfinalboss/privateer/internal/kubernetes"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/spf13/cobra"
+)
+
+var exportFile string
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: getMessage("export_short"),
+	Long:  getMessage("export_long"),
+}
+
+var exportRenovateCmd = &cobra.Command{
+	Use:   "renovate",
+	Short: getMessage("export_renovate_short"),
+	Long:  getMessage("export_renovate_long"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportRenovate()
+	},
+}
+
+var exportInventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: getMessage("export_inventory_short"),
+	Long:  getMessage("export_inventory_long"),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportInventory()
+	},
+}
+
+func init() {
+	exportCmd.Short = getMessage("export_short")
+	exportCmd.Long = getMessage("export_long")
+	exportRenovateCmd.Short = getMessage("export_renovate_short")
+	exportRenovateCmd.Long = getMessage("export_renovate_long")
+
+	exportInventoryCmd.Short = getMessage("export_inventory_short")
+	exportInventoryCmd.Long = getMessage("export_inventory_long")
+
+	exportRenovateCmd.Flags().StringVarP(&exportFile, "file", "f", "", getMessage("flag_export_file"))
+	exportInventoryCmd.Flags().StringVarP(&exportFile, "file", "f", "", getMessage("flag_export_file"))
+
+	exportCmd.AddCommand(exportRenovateCmd)
+	exportCmd.AddCommand(exportInventoryCmd)
+}
+
+func exportRenovate() error {
+	renovateConfig, err := reporter.GenerateRenovateConfig(cfg)
+	if err != nil {
+		log.Error("renovate_export_failed").Err(err).Send()
+		return err
+	}
+
+	data, err := renovateConfig.JSON()
+	if err != nil {
+		return err
+	}
+
+	if exportFile == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(exportFile, data, 0644); err != nil {
+		return fmt.Errorf("falha ao escrever %s: %w", exportFile, err)
+	}
+
+	log.Info("renovate_config_exported").
+		Str("file", exportFile).
+		Int("package_rules", len(renovateConfig.PackageRules)).
+		Send()
+
+	return nil
+}
+
+func exportInventory() error {
+	client, err := kubernetes.NewClient(cfg, log)
+	if err != nil {
+		return err
+	}
+
+	publicImages, err := scanClusterImages(commandContext(), client)
+	if err != nil {
+		return fmt.Errorf("falha ao escanear imagens do cluster: %w", err)
+	}
+
+	data, err := reporter.NewInventory(cfg.Kubernetes.Context, publicImages).JSON()
+	if err != nil {
+		return err
+	}
+
+	if exportFile == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(exportFile, data, 0644); err != nil {
+		return fmt.Errorf("falha ao escrever %s: %w", exportFile, err)
+	}
+
+	log.Info("inventory_exported").
+		Str("file", exportFile).
+		Int("images", len(publicImages)).
+		Send()
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/history"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit   int
+	historyImage   string
+	historyCommand string
+	historyImages  []history.ImageRecord
+	runStartedAt   time.Time
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Lista o histórico de migrações",
+	Long:  "Lista as execuções de migrate gravadas no histórico local (requer history.enabled: true na configuração)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listHistory(summaryOutput)
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Mostra os detalhes de uma execução do histórico",
+	Long:  "Mostra o resumo e o resultado por imagem de uma execução gravada no histórico",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showHistory(summaryOutput, args[0])
+	},
+}
+
+func init() {
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, getMessage("flag_history_limit"))
+	historyCmd.Flags().StringVar(&historyImage, "image", "", getMessage("flag_history_image"))
+	historyCmd.Flags().StringVar(&historyCommand, "command", "", getMessage("flag_history_command"))
+
+	historyCmd.AddCommand(historyShowCmd)
+}
+
+func openHistory() (*history.Store, bool, error) {
+	if _, err := os.Stat(statePath()); os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	store, err := openRunState()
+	if err != nil {
+		return nil, false, err
+	}
+	return history.New(store), true, nil
+}
+
+func listHistory(out io.Writer) error {
+	store, found, err := openHistory()
+	if err != nil {
+		return err
+	}
+
+	var runs []*history.Run
+	if found {
+		runs, err = store.List(history.Filter{Command: historyCommand, Image: historyImage, Limit: historyLimit})
+		if err != nil {
+			return err
+		}
+	}
+
+	if outputFormat == reporter.OutputFormatJSON {
+		if runs == nil {
+			runs = []*history.Run{}
+		}
+		return writeHistoryJSON(out, runs)
+	}
+
+	if len(runs) == 0 {
+		fmt.Fprintln(out, "Nenhuma execução registrada no histórico")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tCOMANDO\tUSUÁRIO\tINÍCIO\tDURAÇÃO\tDRY-RUN\tSUCESSO\tIMAGENS")
+	for _, run := range runs {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%t\t%t\t%d\n",
+			run.ID,
+			run.Command,
+			run.User,
+			run.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			run.FinishedAt.Sub(run.StartedAt).Round(time.Second),
+			run.DryRun,
+			run.Success,
+			len(run.Images))
+	}
+	return writer.Flush()
+}
+
+func showHistory(out io.Writer, id string) error {
+	store, found, err := openHistory()
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("execução %s não encontrada: histórico vazio em %s", id, statePath())
+	}
+
+	run, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	return writeHistoryJSON(out, run)
+}
+
+func writeHistoryJSON(out io.Writer, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("falha ao serializar histórico: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+func recordHistory() {
+	if cfg == nil || !cfg.History.Enabled || commandSummary == nil || !strings.HasPrefix(commandSummary.Command, "migrate") {
+		return
+	}
+
+	db, err := openRunState()
+	if err != nil {
+		log.Warn("history_record_failed").Err(err).Send()
+		return
+	}
+	store := history.New(db)
+
+	run := &history.Run{
+		Command:    commandSummary.Command,
+		User:       currentUser(),
+		StartedAt:  runStartedAt,
+		FinishedAt: time.Now(),
+		DryRun:     commandSummary.DryRun,
+		Success:    commandSummary.Success,
+		Summary:    commandSummary,
+		Images:     historyImages,
+	}
+	if err := store.Record(run); err != nil {
+		log.Warn("history_record_failed").Err(err).Send()
+		return
+	}
+
+	log.Info("history_recorded").
+		Str("id", run.ID).
+		Str("command", run.Command).
+		Int("images", len(run.Images)).
+		Send()
+}
+
+func currentUser() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
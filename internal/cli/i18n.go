@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"os"
+	"strings"
+
 	"github.com/kevinfinalboss/privateer/internal/config"
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/pkg/types"
@@ -30,9 +33,27 @@ func getLanguageFromFlags() string {
 	if language != "" {
 		return language
 	}
+	if lang := languageFromArgs(os.Args[1:]); lang != "" {
+		return lang
+	}
 	return "pt-BR"
 }
 
+func languageFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, found := strings.CutPrefix(arg, "--language="); found {
+			return value
+		}
+		if arg == "--language" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
 func getMessage(key string) string {
 	if i18n == nil {
 		initI18n()
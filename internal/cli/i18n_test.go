@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLanguageFromArgs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"scan", "cluster", "--language", "en-US", "--help"}, "en-US"},
+		{[]string{"--language=en-US", "migrate", "--help"}, "en-US"},
+		{[]string{"history", "--limit", "5"}, ""},
+		{[]string{"scan", "--", "--language", "en-US"}, ""},
+		{[]string{"scan", "--language"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := languageFromArgs(tt.args); got != tt.expected {
+				t.Errorf("languageFromArgs(%v) = %q, expected %q", tt.args, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlagUsage_Localized(t *testing.T) {
+	locales := make(map[string]map[string]string)
+	for _, language := range []string{"pt-BR", "en-US"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "locales", language+".yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var locale struct {
+			Messages map[string]string `yaml:"messages"`
+		}
+		if err := yaml.Unmarshal(data, &locale); err != nil {
+			t.Fatalf("%s: %v", language, err)
+		}
+		locales[language] = locale.Messages
+	}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+			if flag.Name == "help" || flag.Name == "version" {
+				return
+			}
+			if !strings.HasPrefix(flag.Usage, "flag_") {
+				t.Errorf("%s --%s: usage %q is not a locale key", cmd.CommandPath(), flag.Name, flag.Usage)
+				return
+			}
+			for language, messages := range locales {
+				if messages[flag.Usage] == "" {
+					t.Errorf("%s --%s: key %s missing from %s", cmd.CommandPath(), flag.Name, flag.Usage, language)
+				}
+			}
+		})
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(rootCmd)
+}
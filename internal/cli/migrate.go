@@ -6,13 +6,29 @@ import (
 
 	"github.com/kevinfinalboss/privateer/internal/github"
 	"github.com/kevinfinalboss/privateer/internal/gitops"
+	"github.com/kevinfinalboss/privateer/internal/history"
 	"github.com/kevinfinalboss/privateer/internal/kubernetes"
 	"github.com/kevinfinalboss/privateer/internal/migration"
 	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
 	"github.com/kevinfinalboss/privateer/pkg/types"
 	"github.com/spf13/cobra"
 )
 
+var (
+	migrateSince          string
+	migrateNoCleanup      bool
+	migrateMaxPRs         int
+	migratePrivateMove    bool
+	migrateSourceRegistry string
+	migrateTargetRegistry string
+	migrateInteractive    bool
+	migrateYes            bool
+	migrateFromInventory  string
+	migrateBranch         string
+	migratePreview        bool
+)
+
 var migrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Migra imagens públicas para registries privados",
@@ -47,46 +63,78 @@ var migrateAllCmd = &cobra.Command{
 }
 
 func init() {
+	migrateCmd.PersistentFlags().StringVar(&migrateSince, "since", "", getMessage("flag_migrate_since"))
+	migrateCmd.PersistentFlags().BoolVar(&migrateNoCleanup, "no-cleanup", false, getMessage("flag_migrate_no_cleanup"))
+	migrateCmd.PersistentFlags().BoolVar(&migrateInteractive, "interactive", false, getMessage("flag_migrate_interactive"))
+	migrateCmd.PersistentFlags().BoolVarP(&migrateYes, "yes", "y", false, getMessage("flag_migrate_yes"))
+	migrateCmd.PersistentFlags().IntVar(&migrateMaxPRs, "max-prs", 0, getMessage("flag_migrate_max_prs"))
+
+	migrateClusterCmd.Flags().BoolVar(&migratePrivateMove, "include-private-move", false, getMessage("flag_migrate_include_private_move"))
+	migrateClusterCmd.Flags().StringVar(&migrateSourceRegistry, "source-registry", "", getMessage("flag_migrate_source_registry"))
+	migrateClusterCmd.Flags().StringVar(&migrateTargetRegistry, "target-registry", "", getMessage("flag_migrate_target_registry"))
+
+	migrateGithubCmd.Flags().StringVar(&migrateBranch, "branch", "", getMessage("flag_migrate_branch"))
+	migrateGithubCmd.Flags().BoolVar(&migratePreview, "preview", false, getMessage("flag_migrate_preview"))
+	migrateGithubCmd.Flags().StringVar(&migrateFromInventory, "from-inventory", "", getMessage("flag_migrate_from_inventory"))
+
 	migrateCmd.AddCommand(migrateClusterCmd)
 	migrateCmd.AddCommand(migrateGithubCmd)
 	migrateCmd.AddCommand(migrateAllCmd)
 }
 
 func migrateCluster() error {
-	ctx := context.Background()
+	_, summary, _, err := runClusterMigration("migrate cluster", false)
+	if err != nil {
+		return err
+	}
+	commandSummary = reporter.NewMigrationCommandSummary("migrate cluster", summary, cfg.Settings.DryRun)
+	historyImages = history.MigrationImages(summary)
+	return nil
+}
+
+func runClusterMigration(action string, deferReporting bool) (*migration.Engine, *types.MigrationSummary, []*types.ImageInfo, error) {
+	ctx := commandContext()
+
+	if migrateSince != "" {
+		cfg.Settings.Since = migrateSince
+	}
+
+	sourceHost, err := privateMoveSourceHost()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	if len(cfg.Registries) == 0 {
 		log.Error("no_registries_configured").Send()
-		return fmt.Errorf("nenhum registry configurado. Execute 'privateer init' para configurar")
+		return nil, nil, nil, fmt.Errorf("nenhum registry configurado. Execute 'privateer init' para configurar")
 	}
 
-	registryManager := registry.NewManager(log)
-	for _, regConfig := range cfg.Registries {
-		if err := registryManager.AddRegistry(&regConfig); err != nil {
-			log.Error("registry_add_failed").
-				Str("name", regConfig.Name).
-				Err(err).
-				Send()
-			return err
-		}
+	registryManager, err := newMigrationRegistryManager(ctx)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	if err := registryManager.HealthCheck(ctx); err != nil {
-		log.Error("registry_health_check_failed").
-			Err(err).
+	migrationEngine := migration.NewEngine(registryManager, log, cfg)
+	if deferReporting {
+		migrationEngine.DeferReporting()
+	}
+	if migratePrivateMove {
+		migrationEngine.WithPrivateMove(migrateSourceRegistry, migrateTargetRegistry)
+		log.Info("private_move_enabled").
+			Str("source", migrateSourceRegistry).
+			Str("target", migrateTargetRegistry).
 			Send()
-		return err
 	}
 
 	client, err := kubernetes.NewClient(cfg, log)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
 
 	namespaces, err := client.GetNamespaces()
 	if err != nil {
 		log.Error("operation_failed").Err(err).Send()
-		return err
+		return nil, nil, nil, err
 	}
 
 	log.Info("migration_cluster_started").
@@ -102,10 +150,17 @@ func migrateCluster() error {
 		Send()
 
 	scanner := kubernetes.NewScanner(client, log, cfg)
+	scanNamespace := scanner.ScanNamespace
+	if migratePrivateMove {
+		scanNamespace = func(ctx context.Context, namespace string) ([]*types.ImageInfo, error) {
+			return scanner.ScanNamespaceForRegistry(ctx, namespace, sourceHost)
+		}
+	}
+
 	var allPublicImages []*types.ImageInfo
 
 	for _, namespace := range namespaces {
-		publicImages, err := scanner.ScanNamespace(namespace)
+		publicImages, err := scanNamespace(ctx, namespace)
 		if err != nil {
 			log.Error("operation_failed").
 				Str("namespace", namespace).
@@ -118,20 +173,24 @@ func migrateCluster() error {
 
 	if len(allPublicImages) == 0 {
 		log.Info("no_public_images_found").Send()
-		return nil
+		return migrationEngine, &types.MigrationSummary{}, nil, nil
 	}
 
 	log.Info("public_images_found").
 		Int("total", len(allPublicImages)).
 		Send()
 
-	migrationEngine := migration.NewEngine(registryManager, log, cfg)
+	allPublicImages = confirmMigration(action, allPublicImages)
+	if len(allPublicImages) == 0 {
+		return migrationEngine, &types.MigrationSummary{}, nil, nil
+	}
+
 	summary, err := migrationEngine.MigrateImages(ctx, allPublicImages)
 	if err != nil {
 		log.Error("migration_failed").
 			Err(err).
 			Send()
-		return err
+		return nil, nil, nil, err
 	}
 
 	log.Info("migration_summary").
@@ -166,55 +225,29 @@ func migrateCluster() error {
 		Str("operation", "cluster_migrate").
 		Send()
 
-	return nil
+	return migrationEngine, summary, allPublicImages, nil
 }
 
 func migrateGithub() error {
-	ctx := context.Background()
-
-	if !cfg.GitHub.Enabled {
-		log.Error("github_not_enabled").
-			Str("message", "GitHub não está habilitado na configuração").
-			Send()
-		return fmt.Errorf("GitHub não está habilitado. Configure github.enabled: true")
-	}
-
-	if !cfg.GitOps.Enabled {
-		log.Error("gitops_not_enabled").
-			Str("message", "GitOps não está habilitado na configuração").
-			Send()
-		return fmt.Errorf("GitOps não está habilitado. Configure gitops.enabled: true")
-	}
-
-	if cfg.GitHub.Token == "" {
-		log.Error("github_token_missing").
-			Str("message", "Token GitHub não configurado").
-			Send()
-		return fmt.Errorf("token GitHub não configurado. Configure github.token")
-	}
-
-	enabledRepos := 0
-	for _, repo := range cfg.GitHub.Repositories {
-		if repo.Enabled {
-			enabledRepos++
-		}
-	}
-
-	if enabledRepos == 0 {
-		log.Error("no_github_repositories").
-			Str("message", "Nenhum repositório GitHub habilitado").
-			Send()
-		return fmt.Errorf("nenhum repositório GitHub habilitado encontrado")
+	summary, err := runGithubMigration(false, selectGithubMigrationImages)
+	if err != nil {
+		return err
 	}
+	commandSummary = reporter.NewGitOpsCommandSummary("migrate github", summary, cfg.Settings.DryRun)
+	historyImages = history.GitOpsImages(summary)
+	return nil
+}
 
-	registryManager := registry.NewManager(log)
+func newMigrationRegistryManager(ctx context.Context) (*registry.Manager, error) {
+	registryManager := registry.NewManager(log).UseHealthCache(runHealthCache)
+	registryManager.ApplySettings(&cfg.Settings)
 	for _, regConfig := range cfg.Registries {
 		if err := registryManager.AddRegistry(&regConfig); err != nil {
 			log.Error("registry_add_failed").
 				Str("name", regConfig.Name).
 				Err(err).
 				Send()
-			return err
+			return nil, err
 		}
 	}
 
@@ -222,41 +255,65 @@ func migrateGithub() error {
 		log.Error("registry_health_check_failed").
 			Err(err).
 			Send()
-		return err
+		return nil, err
 	}
 
-	client, err := kubernetes.NewClient(cfg, log)
+	return registryManager, nil
+}
+
+func runGithubMigration(deferReporting bool, selectImages func() ([]*types.ImageInfo, error)) (*types.GitOpsSummary, error) {
+	ctx := commandContext()
+
+	if migrateNoCleanup {
+		cfg.GitOps.NoCleanup = true
+	}
+	if migrateMaxPRs > 0 {
+		cfg.GitOps.MaxPRs = migrateMaxPRs
+	}
+	if migrateBranch != "" {
+		cfg.GitOps.Branch = migrateBranch
+	}
+	if migratePreview {
+		cfg.GitOps.DryRunPreview = true
+	}
+
+	enabledRepos, err := checkGithubMigrationConfig()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	registryManager, err := newMigrationRegistryManager(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Info("github_migration_started").
 		Int("enabled_repositories", enabledRepos).
 		Bool("dry_run", cfg.Settings.DryRun).
 		Bool("auto_pr", cfg.GitOps.AutoPR).
+		Str("inventory", migrateFromInventory).
 		Send()
 
-	publicImages, err := scanClusterImages(client)
+	publicImages, err := selectImages()
 	if err != nil {
-		return fmt.Errorf("falha ao escanear imagens do cluster: %w", err)
+		return nil, err
 	}
-
 	if len(publicImages) == 0 {
-		log.Info("no_public_images_for_github").
-			Str("message", "Nenhuma imagem pública encontrada no cluster").
-			Send()
-		return nil
+		return &types.GitOpsSummary{}, nil
 	}
 
 	githubClient := github.NewClient(&cfg.GitHub, log)
-	gitopsEngine := gitops.NewEngine(githubClient, registryManager, log, cfg)
+	gitopsEngine := gitops.NewEngine(githubClient, registryManager, log, cfg).UseState(sharedState())
+	if deferReporting {
+		gitopsEngine.DeferReporting()
+	}
 
 	summary, err := gitopsEngine.MigrateRepositories(ctx, publicImages)
 	if err != nil {
 		log.Error("github_migration_failed").
 			Err(err).
 			Send()
-		return err
+		return nil, err
 	}
 
 	log.Info("github_migration_summary").
@@ -268,6 +325,12 @@ func migrateGithub() error {
 		Str("processing_time", summary.ProcessingTime).
 		Send()
 
+	if summary.TrackingIssueURL != "" {
+		log.Info("tracking_issue_created").
+			Str("url", summary.TrackingIssueURL).
+			Send()
+	}
+
 	if len(summary.Results) > 0 {
 		for _, result := range summary.Results {
 			if result.Success && result.PullRequest != nil {
@@ -295,7 +358,7 @@ func migrateGithub() error {
 		Str("operation", "github_migrate").
 		Send()
 
-	return nil
+	return summary, nil
 }
 
 func migrateAll() error {
@@ -304,19 +367,25 @@ func migrateAll() error {
 		Send()
 
 	log.Info("phase_1_cluster_migration").Send()
-	if err := migrateCluster(); err != nil {
+	migrationEngine, migrationSummary, selectedImages, err := runClusterMigration("migrate all", true)
+	if err != nil {
 		log.Error("phase_1_failed").
 			Err(err).
 			Send()
 		return fmt.Errorf("falha na migração do cluster: %w", err)
 	}
 
+	var gitopsSummary *types.GitOpsSummary
 	if cfg.GitHub.Enabled && cfg.GitOps.Enabled {
 		log.Info("phase_2_github_migration").Send()
-		if err := migrateGithub(); err != nil {
+		gitopsSummary, err = runGithubMigration(true, func() ([]*types.ImageInfo, error) {
+			return selectedImages, nil
+		})
+		if err != nil {
 			log.Error("phase_2_failed").
 				Err(err).
 				Send()
+			migrationEngine.ReportCombined(commandContext(), migrationSummary, nil)
 			return fmt.Errorf("falha na migração do GitHub: %w", err)
 		}
 	} else {
@@ -325,6 +394,24 @@ func migrateAll() error {
 			Send()
 	}
 
+	summary := migrationEngine.ReportCombined(commandContext(), migrationSummary, gitopsSummary)
+	commandSummary = reporter.NewMigrationCommandSummary("migrate all", summary, cfg.Settings.DryRun)
+	historyImages = history.MigrationImages(summary)
+
+	repositoriesProcessed, pullRequests := 0, 0
+	if summary.GitOps != nil {
+		repositoriesProcessed = summary.GitOps.ProcessedRepositories
+		pullRequests = summary.GitOps.SuccessfulPRs
+	}
+
+	log.Info("full_migration_summary").
+		Int("images_total", summary.TotalImages).
+		Int("images_migrated", summary.SuccessCount).
+		Int("images_failed", summary.FailureCount).
+		Int("repositories_processed", repositoriesProcessed).
+		Int("pull_requests", pullRequests).
+		Send()
+
 	log.Info("full_migration_completed").
 		Str("message", "Migração completa finalizada com sucesso").
 		Send()
@@ -332,7 +419,111 @@ func migrateAll() error {
 	return nil
 }
 
-func scanClusterImages(client *kubernetes.Client) ([]*types.ImageInfo, error) {
+func privateMoveSourceHost() (string, error) {
+	if !migratePrivateMove {
+		return "", nil
+	}
+
+	if migrateSourceRegistry == "" || migrateTargetRegistry == "" {
+		return "", fmt.Errorf("--include-private-move requer --source-registry e --target-registry")
+	}
+
+	for _, regConfig := range cfg.Registries {
+		if regConfig.Name == migrateSourceRegistry {
+			if regConfig.URL == "" {
+				return "", fmt.Errorf("registry de origem %s não possui url configurada", migrateSourceRegistry)
+			}
+			return regConfig.URL, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", types.ErrRegistryNotFound, migrateSourceRegistry)
+}
+
+func checkGithubMigrationConfig() (int, error) {
+	if !cfg.GitHub.Enabled {
+		log.Error("github_not_enabled").
+			Str("message", "GitHub não está habilitado na configuração").
+			Send()
+		return 0, fmt.Errorf("GitHub não está habilitado. Configure github.enabled: true")
+	}
+
+	if !cfg.GitOps.Enabled {
+		log.Error("gitops_not_enabled").
+			Str("message", "GitOps não está habilitado na configuração").
+			Send()
+		return 0, fmt.Errorf("GitOps não está habilitado. Configure gitops.enabled: true")
+	}
+
+	if cfg.GitHub.Token == "" {
+		log.Error("github_token_missing").
+			Str("message", "Token GitHub não configurado").
+			Send()
+		return 0, fmt.Errorf("token GitHub não configurado. Configure github.token")
+	}
+
+	enabledRepos := 0
+	for _, repo := range cfg.GitHub.Repositories {
+		if repo.Enabled {
+			enabledRepos++
+		}
+	}
+
+	if enabledRepos == 0 {
+		log.Error("no_github_repositories").
+			Str("message", "Nenhum repositório GitHub habilitado").
+			Send()
+		return 0, fmt.Errorf("nenhum repositório GitHub habilitado encontrado")
+	}
+
+	return enabledRepos, nil
+}
+
+func selectGithubMigrationImages() ([]*types.ImageInfo, error) {
+	publicImages, err := githubMigrationImages()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(publicImages) == 0 {
+		log.Info("no_public_images_for_github").
+			Str("message", "Nenhuma imagem pública encontrada no cluster").
+			Send()
+		return nil, nil
+	}
+
+	return confirmMigration("migrate github", publicImages), nil
+}
+
+func githubMigrationImages() ([]*types.ImageInfo, error) {
+	if migrateFromInventory != "" {
+		images, err := reporter.LoadInventory(migrateFromInventory)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info("inventory_loaded").
+			Str("file", migrateFromInventory).
+			Int("images", len(images)).
+			Send()
+
+		return images, nil
+	}
+
+	client, err := kubernetes.NewClient(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("%w (use --from-inventory com um arquivo gerado por 'privateer export inventory' para migrar sem acesso ao cluster)", err)
+	}
+
+	publicImages, err := scanClusterImages(commandContext(), client)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao escanear imagens do cluster: %w", err)
+	}
+
+	return publicImages, nil
+}
+
+func scanClusterImages(ctx context.Context, client *kubernetes.Client) ([]*types.ImageInfo, error) {
 	namespaces, err := client.GetNamespaces()
 	if err != nil {
 		return nil, err
@@ -342,7 +533,7 @@ func scanClusterImages(client *kubernetes.Client) ([]*types.ImageInfo, error) {
 	var allPublicImages []*types.ImageInfo
 
 	for _, namespace := range namespaces {
-		publicImages, err := scanner.ScanNamespace(namespace)
+		publicImages, err := scanner.ScanNamespace(ctx, namespace)
 		if err != nil {
 			log.Error("namespace_scan_failed").
 				Str("namespace", namespace).
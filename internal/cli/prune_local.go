@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/spf13/cobra"
+)
+
+var pruneLocalCmd = &cobra.Command{
+	Use:   "prune-local",
+	Short: "Remove imagens locais deixadas por migrações anteriores",
+	Long:  "Remove do docker local as imagens com prefixo dos registries de destino configurados (criadas pelo Privateer). Com --dry-run apenas lista as imagens que seriam removidas",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return pruneLocal()
+	},
+}
+
+func pruneLocal() error {
+	commandSummary = reporter.NewCommandSummary("prune-local", cfg.Settings.DryRun)
+
+	pruneErr := runPruneLocal(commandContext())
+	if pruneErr == nil {
+		return nil
+	}
+
+	commandSummary.Success = false
+	if err := writeCommandSummary(); err != nil {
+		return err
+	}
+	return pruneErr
+}
+
+func runPruneLocal(ctx context.Context) error {
+	prefixes := registry.PrunePrefixes(cfg.Registries)
+	if len(prefixes) == 0 {
+		return fmt.Errorf("nenhum registry de destino configurado para identificar imagens locais do Privateer")
+	}
+
+	localImages, err := registry.ListLocalImages(ctx)
+	if err != nil {
+		return err
+	}
+
+	images := registry.SelectPrunableImages(localImages, prefixes)
+	log.Info("prune_local_images_selected").
+		Strs("prefixes", prefixes).
+		Int("local_images", len(localImages)).
+		Int("prunable_images", len(images)).
+		Bool("dry_run", cfg.Settings.DryRun).
+		Send()
+
+	commandSummary.Prune = &reporter.PruneFindings{}
+	if cfg.Settings.DryRun {
+		commandSummary.Prune.Removed = images
+		return nil
+	}
+
+	for _, image := range images {
+		if err := registry.RemoveLocalImage(ctx, image); err != nil {
+			log.Warn("prune_local_image_failed").
+				Str("image", image).
+				Err(err).
+				Send()
+			commandSummary.Prune.Failed = append(commandSummary.Prune.Failed, image)
+			continue
+		}
+		commandSummary.Prune.Removed = append(commandSummary.Prune.Removed, image)
+	}
+
+	if failed := len(commandSummary.Prune.Failed); failed > 0 {
+		return fmt.Errorf("falha ao remover %d de %d imagens locais", failed, len(images))
+	}
+	return nil
+}
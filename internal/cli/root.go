@@ -1,21 +1,44 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/kevinfinalboss/privateer/internal/config"
+	"github.com/kevinfinalboss/privateer/internal/httpreplay"
 	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/kevinfinalboss/privateer/internal/webhook"
 	"github.com/kevinfinalboss/privateer/pkg/types"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile  string
-	language string
-	logLevel string
-	dryRun   bool
-	log      *logger.Logger
-	cfg      *types.Config
+	cfgFile         string
+	language        string
+	logLevel        string
+	kubeContext     string
+	registryTypes   []string
+	dryRun          bool
+	outputFormat    string
+	reportFormat    string
+	runTimeout      time.Duration
+	commandSummary  *reporter.CommandSummary
+	summaryWritten  bool
+	reportsDisabled bool
+	summaryOutput   io.Writer = os.Stdout
+	runCommand      string
+	runCtx          context.Context
+	cancelRun       context.CancelFunc
+	runHealthCache  *registry.HealthCache
+	log             *logger.Logger
+	cfg             *types.Config
 )
 
 var rootCmd = &cobra.Command{
@@ -23,18 +46,24 @@ var rootCmd = &cobra.Command{
 	Short: getMessage("root_short"),
 	Long:  getMessage("root_long"),
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		runStartedAt = time.Now()
+
 		if cmd.Name() == "init" {
 			return nil
 		}
 
+		if err := reporter.ValidateOutputFormat(outputFormat); err != nil {
+			return err
+		}
+		if err := reporter.ValidateReportFormat(reportFormat); err != nil {
+			return err
+		}
+
 		var err error
 
 		cfg, err = config.Load(cfgFile)
 		if err != nil {
-			if cfgFile != "" {
-				return fmt.Errorf("erro ao carregar configuração: %w", err)
-			}
-			cfg = config.GetDefaultConfig()
+			return fmt.Errorf("erro ao carregar configuração: %w", err)
 		}
 
 		if language != "" {
@@ -43,32 +72,145 @@ var rootCmd = &cobra.Command{
 		if logLevel != "" {
 			cfg.Settings.LogLevel = logLevel
 		}
+		if kubeContext != "" {
+			cfg.Kubernetes.Context = kubeContext
+		}
 		if cmd.Flags().Changed("dry-run") {
 			cfg.Settings.DryRun = dryRun
 		}
+		if err := config.ApplyRegistryTypes(cfg, registryTypes); err != nil {
+			return err
+		}
 
-		log = logger.NewWithConfig(cfg)
+		if outputFormat == reporter.OutputFormatJSON {
+			log = logger.NewWithWriter(cfg, os.Stderr)
+		} else {
+			log = logger.NewWithConfig(cfg)
+		}
 
-		if err != nil && cfgFile == "" {
+		configPath, err := config.ResolvePath(cfgFile)
+		if _, statErr := os.Stat(configPath); err != nil || statErr != nil {
 			log.Warn("config_not_found").Send()
-		} else if cfgFile != "" {
-			log.Info("config_loaded").Str("file", cfgFile).Send()
 		} else {
-			log.Info("config_loaded").Str("file", "~/.privateer/config.yaml").Send()
+			log.Info("config_loaded").Str("file", configPath).Send()
 		}
 
 		log.Info("app_started").
 			Str("version", "v0.1.0").
 			Str("language", cfg.Settings.Language).
 			Bool("dry_run", cfg.Settings.DryRun).
+			Str("timeout", runTimeout.String()).
 			Send()
 
+		runCommand = strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+		runCtx, cancelRun = newRunContext(runTimeout)
+		runHealthCache = registry.NewHealthCache()
+
 		return nil
 	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if timeoutErr := runTimeoutError(); timeoutErr != nil {
+			return timeoutErr
+		}
+		return writeCommandSummary()
+	},
+}
+
+func newRunContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+func commandContext() context.Context {
+	if runCtx == nil {
+		return context.Background()
+	}
+	return runCtx
+}
+
+func runTimeoutError() error {
+	if runCtx == nil || !errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return nil
+	}
+	return fmt.Errorf("tempo limite de %s excedido: %w", runTimeout, context.DeadlineExceeded)
+}
+
+func finishRun(err error) error {
+	defer closeRunState()
+	defer flushHTTPCassettes()
+	if cancelRun != nil {
+		defer cancelRun()
+	}
+
+	timeoutErr := runTimeoutError()
+	if timeoutErr == nil {
+		return err
+	}
+
+	log.Error("run_timeout").
+		Str("command", runCommand).
+		Str("timeout", runTimeout.String()).
+		Err(err).
+		Send()
+
+	if !summaryWritten {
+		if commandSummary == nil {
+			commandSummary = reporter.NewCommandSummary(runCommand, cfg.Settings.DryRun)
+		}
+		commandSummary.MarkTimedOut()
+		if writeErr := writeCommandSummary(); writeErr != nil {
+			log.Warn("command_summary_write_failed").Err(writeErr).Send()
+		}
+	}
+
+	return timeoutErr
+}
+
+func flushHTTPCassettes() {
+	if err := httpreplay.Flush(); err != nil && log != nil {
+		log.Warn("http_cassette_flush_failed").Err(err).Send()
+	}
+}
+
+func writeCommandSummary() error {
+	if commandSummary == nil || summaryWritten {
+		return nil
+	}
+	summaryWritten = true
+
+	if !reportsDisabled {
+		reportPath, err := reporter.NewHTMLReporter(log).GenerateCommandReport(commandSummary, reportFormat)
+		if err != nil {
+			log.Warn("command_report_failed").Err(err).Send()
+		}
+		commandSummary.Report = reportPath
+
+		sendSummaryWebhook()
+		recordHistory()
+	}
+
+	return commandSummary.Write(summaryOutput, outputFormat)
+}
+
+func sendSummaryWebhook() {
+	if cfg == nil || !cfg.Webhooks.HTTP.Enabled || cfg.Webhooks.HTTP.URL == "" {
+		return
+	}
+
+	if err := webhook.NewHTTPWebhook(cfg.Webhooks.HTTP, log).Send(context.Background(), commandSummary); err != nil {
+		log.Warn("http_webhook_failed").Err(err).Send()
+		return
+	}
+
+	log.Info("http_webhook_sent").
+		Str("command", commandSummary.Command).
+		Send()
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	return finishRun(rootCmd.Execute())
 }
 
 func init() {
@@ -80,7 +222,13 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", getMessage("flag_config"))
 	rootCmd.PersistentFlags().StringVar(&language, "language", "", getMessage("flag_language"))
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", getMessage("flag_log_level"))
+	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", getMessage("flag_context"))
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, getMessage("flag_dry_run"))
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", reporter.OutputFormatText, getMessage("flag_output_format"))
+	rootCmd.PersistentFlags().StringVar(&reportFormat, "report", "", getMessage("flag_report"))
+	rootCmd.PersistentFlags().Lookup("report").NoOptDefVal = reporter.ReportFormatHTML
+	rootCmd.PersistentFlags().DurationVar(&runTimeout, "timeout", 0, getMessage("flag_timeout"))
+	rootCmd.PersistentFlags().StringArrayVar(&registryTypes, "registry-type", nil, getMessage("flag_registry_type"))
 
 	addSubcommands()
 }
@@ -90,4 +238,8 @@ func addSubcommands() {
 	rootCmd.AddCommand(migrateCmd)
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(pruneLocalCmd)
 }
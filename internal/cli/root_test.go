@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/config"
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/rs/zerolog"
+)
+
+func TestFinishRun_Timeout(t *testing.T) {
+	previousLog, previousCfg, previousOutput, previousLevel := log, cfg, summaryOutput, zerolog.GlobalLevel()
+	defer func() {
+		log, cfg, summaryOutput = previousLog, previousCfg, previousOutput
+		zerolog.SetGlobalLevel(previousLevel)
+		commandSummary, summaryWritten, runCtx, cancelRun, runTimeout = nil, false, nil, nil, 0
+	}()
+
+	tests := []struct {
+		name     string
+		summary  *reporter.CommandSummary
+		expected []string
+	}{
+		{
+			name:     "partial migration results are reported",
+			summary:  reporter.NewMigrationCommandSummary("migrate cluster", &types.MigrationSummary{TotalImages: 3, SuccessCount: 1, FailureCount: 2}, false),
+			expected: []string{"command: migrate cluster", "status: timed_out", "migration: total=3 success=1 failed=2"},
+		},
+		{
+			name:     "summary is emitted even when the command aborted early",
+			expected: []string{"command: migrate cluster", "status: timed_out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			cfg = &types.Config{Settings: types.SettingsConfig{LogLevel: "error"}}
+			log = logger.NewTest()
+			summaryOutput = &output
+			outputFormat = reporter.OutputFormatText
+			commandSummary, summaryWritten = tt.summary, false
+			runCommand = "migrate cluster"
+			runTimeout = time.Millisecond
+			runCtx, cancelRun = newRunContext(runTimeout)
+			<-runCtx.Done()
+
+			err := finishRun(errors.New("falha ao escanear imagens do cluster"))
+
+			if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "tempo limite de 1ms excedido") {
+				t.Fatalf("finishRun() = %v, expected a timeout error", err)
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(output.String(), want) {
+					t.Errorf("summary missing %q:\n%s", want, output.String())
+				}
+			}
+		})
+	}
+}
+
+func TestFinishRun_WithoutTimeout(t *testing.T) {
+	defer func() { runCtx, cancelRun, runTimeout = nil, nil, 0 }()
+
+	runTimeout = time.Minute
+	runCtx, cancelRun = newRunContext(runTimeout)
+
+	commandErr := errors.New("falha")
+	if err := finishRun(commandErr); err != commandErr {
+		t.Errorf("finishRun() = %v, expected the command error unchanged", err)
+	}
+}
+
+func TestRootFlags_OutputAndExportFile(t *testing.T) {
+	previousFormat, previousExportOutput := outputFormat, exportFile
+	defer func() { outputFormat, exportFile = previousFormat, previousExportOutput }()
+
+	for _, args := range [][]string{{"export", "renovate"}, {"export", "inventory"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			outputFormat, exportFile = reporter.OutputFormatText, ""
+
+			cmd, _, err := rootCmd.Find(args)
+			if err != nil {
+				t.Fatalf("Find(%v) error = %v", args, err)
+			}
+			if err := cmd.ParseFlags([]string{"--output", "json", "-f", "renovate.json"}); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			if outputFormat != reporter.OutputFormatJSON {
+				t.Errorf("outputFormat = %q, expected %q", outputFormat, reporter.OutputFormatJSON)
+			}
+			if exportFile != "renovate.json" {
+				t.Errorf("exportFile = %q, expected renovate.json", exportFile)
+			}
+		})
+	}
+}
+
+func TestRootFlags_RegistryType(t *testing.T) {
+	previousTypes := registryTypes
+	defer func() { registryTypes = previousTypes }()
+
+	for _, args := range [][]string{{"migrate", "cluster"}, {"export", "inventory"}, {"scan", "github"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			registryTypes = nil
+
+			cmd, _, err := rootCmd.Find(args)
+			if err != nil {
+				t.Fatalf("Find(%v) error = %v", args, err)
+			}
+			if err := cmd.ParseFlags([]string{"--registry-type", "harbor"}); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			if cmd.Flags().Lookup("registry-type") != rootCmd.PersistentFlags().Lookup("registry-type") {
+				t.Error("--registry-type should be the single flag declared on the root command")
+			}
+			if len(registryTypes) != 1 || registryTypes[0] != "harbor" {
+				t.Errorf("registryTypes = %v, expected [harbor]", registryTypes)
+			}
+		})
+	}
+}
+
+func TestRootPreRun_InvalidDiscoveredConfig(t *testing.T) {
+	previousCfg, previousLog, previousFile, previousFormat := cfg, log, cfgFile, outputFormat
+	defer func() {
+		cfg, log, cfgFile, outputFormat = previousCfg, previousLog, previousFile, previousFormat
+		runCtx, cancelRun = nil, nil
+	}()
+
+	workDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(config.ConfigEnvVar, "")
+	t.Chdir(workDir)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "missing config falls back to defaults", wantErr: false},
+		{name: "invalid config is rejected", content: "settings:\n  registry_selection: bogus\n", wantErr: true},
+		{name: "unparsable config is rejected", content: "settings: [\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(filepath.Join(workDir, "privateer.yaml"))
+			if tt.content != "" {
+				if err := os.WriteFile(filepath.Join(workDir, "privateer.yaml"), []byte(tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cfgFile, outputFormat = "", reporter.OutputFormatText
+
+			err := rootCmd.PersistentPreRunE(rootCmd, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PersistentPreRunE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if cancelRun != nil {
+				cancelRun()
+			}
+		})
+	}
+}
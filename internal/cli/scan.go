@@ -1,14 +1,23 @@
 package cli
 
 import (
-	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/kevinfinalboss/privateer/internal/github"
 	"github.com/kevinfinalboss/privateer/internal/kubernetes"
 	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/kevinfinalboss/privateer/internal/scanner"
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -36,15 +45,26 @@ var scanGithubCmd = &cobra.Command{
 	},
 }
 
+var (
+	scanOnlyMissing  bool
+	scanCSVOutput    string
+	scanFailIfPublic bool
+)
+
 type ScanResult struct {
-	PublicImages       []*types.ImageInfo
-	AvailableInPrivate map[string][]string
-	NotAvailableImages []*types.ImageInfo
-	RegistryStats      map[string]int
-	TotalScanned       int
-	TotalPublic        int
-	TotalAvailable     int
-	ScanDuration       time.Duration
+	PublicImages        []*types.ImageInfo
+	AvailableInPrivate  map[string][]string
+	NotAvailableImages  []*types.ImageInfo
+	RegistryStats       map[string]int
+	TotalScanned        int
+	TotalPublic         int
+	TotalAvailable      int
+	UniquePublic        int
+	UniqueAvailable     int
+	UniqueNotAvailable  int
+	ScanDuration        time.Duration
+	UnhealthyRegistries map[string]error
+	FailedNamespaces    []string
 }
 
 func init() {
@@ -55,6 +75,10 @@ func init() {
 	scanGithubCmd.Short = getMessage("scan_github_short")
 	scanGithubCmd.Long = getMessage("scan_github_long")
 
+	scanClusterCmd.Flags().BoolVar(&scanOnlyMissing, "only-missing", false, getMessage("flag_scan_only_missing"))
+	scanClusterCmd.Flags().BoolVar(&scanFailIfPublic, "fail-if-public", false, getMessage("flag_scan_fail_if_public"))
+	scanClusterCmd.Flags().StringVar(&scanCSVOutput, "csv", "", getMessage("flag_scan_csv"))
+
 	scanCmd.AddCommand(scanClusterCmd)
 	scanCmd.AddCommand(scanGithubCmd)
 }
@@ -67,7 +91,7 @@ func scanCluster() error {
 		return err
 	}
 
-	registryManager := registry.NewManager(log)
+	registryManager := registry.NewManager(log).UseHealthCache(runHealthCache)
 	for _, regConfig := range cfg.Registries {
 		if err := registryManager.AddRegistry(&regConfig); err != nil {
 			log.Warn("registry_add_failed").
@@ -89,10 +113,11 @@ func scanCluster() error {
 		Int("registries", registryManager.GetRegistryCount()).
 		Send()
 
-	ctx := context.Background()
-	if err := registryManager.HealthCheck(ctx); err != nil {
+	ctx := commandContext()
+	unhealthyRegistries := registryManager.CheckHealth(ctx)
+	if len(unhealthyRegistries) > 0 {
 		log.Warn("registry_health_check_issues").
-			Err(err).
+			Int("unhealthy_registries", len(unhealthyRegistries)).
 			Send()
 	}
 
@@ -110,19 +135,21 @@ func scanCluster() error {
 
 	scanner := kubernetes.NewScanner(client, log, cfg)
 	result := &ScanResult{
-		PublicImages:       make([]*types.ImageInfo, 0),
-		AvailableInPrivate: make(map[string][]string),
-		NotAvailableImages: make([]*types.ImageInfo, 0),
-		RegistryStats:      make(map[string]int),
+		PublicImages:        make([]*types.ImageInfo, 0),
+		AvailableInPrivate:  make(map[string][]string),
+		NotAvailableImages:  make([]*types.ImageInfo, 0),
+		RegistryStats:       make(map[string]int),
+		UnhealthyRegistries: unhealthyRegistries,
 	}
 
 	for _, namespace := range namespaces {
-		publicImages, err := scanner.ScanNamespace(namespace)
+		publicImages, err := scanner.ScanNamespace(ctx, namespace)
 		if err != nil {
 			log.Error("operation_failed").
 				Str("namespace", namespace).
 				Err(err).
 				Send()
+			result.FailedNamespaces = append(result.FailedNamespaces, namespace)
 			continue
 		}
 
@@ -136,13 +163,20 @@ func scanCluster() error {
 		Int("public_images", len(result.PublicImages)).
 		Send()
 
-	validatedMap, err := registryManager.ValidateImagesBatch(ctx, result.PublicImages, cfg)
+	validatedMap, validationErrors, err := registryManager.ValidateImagesBatch(ctx, result.PublicImages, cfg)
 	if err != nil {
 		log.Warn("batch_validation_partial_failure").
 			Err(err).
 			Send()
 	}
 
+	for publicImage, validationErr := range validationErrors {
+		log.Warn("image_validation_unverified").
+			Str("public_image", publicImage).
+			Err(validationErr).
+			Send()
+	}
+
 	for _, image := range result.PublicImages {
 		privateImage, registryName, err := registryManager.FindImageInRegistries(ctx, image, cfg)
 		if err != nil {
@@ -163,13 +197,28 @@ func scanCluster() error {
 		result.TotalAvailable++
 	}
 
+	result.UniquePublic = utils.CountUniqueImages(result.PublicImages)
+	result.UniqueAvailable = len(result.AvailableInPrivate)
+	result.UniqueNotAvailable = utils.CountUniqueImages(result.NotAvailableImages)
 	result.ScanDuration = time.Since(startTime)
 
 	printScanSummary(result, validatedMap)
-	printDetailedResults(result)
+	printDetailedResults(result, scanOnlyMissing)
 	printRegistryStats(result)
 	printRecommendations(result)
 
+	if scanCSVOutput != "" {
+		if err := exportScanCSV(result, scanCSVOutput); err != nil {
+			return err
+		}
+	}
+
+	commandSummary = reporter.NewCommandSummary("scan cluster", cfg.Settings.DryRun)
+	commandSummary.Scan = clusterScanFindings(result)
+	if len(result.FailedNamespaces) > 0 {
+		commandSummary.Success = false
+	}
+
 	log.Info("operation_completed").
 		Str("operation", "cluster_scan").
 		Int("total_scanned", result.TotalScanned).
@@ -178,7 +227,61 @@ func scanCluster() error {
 		Str("duration", result.ScanDuration.String()).
 		Send()
 
-	return nil
+	if !scanFailIfPublic {
+		return nil
+	}
+
+	gateErr := scanGateError(result, scanOnlyMissing)
+	if gateErr == nil {
+		return nil
+	}
+
+	if err := writeCommandSummary(); err != nil {
+		return err
+	}
+	return gateErr
+}
+
+func scanGateError(result *ScanResult, onlyMissing bool) error {
+	var errs []error
+	if len(result.FailedNamespaces) > 0 {
+		errs = append(errs, fmt.Errorf("falha ao escanear %d namespace(s): %s", len(result.FailedNamespaces), strings.Join(result.FailedNamespaces, ", ")))
+	}
+	if err := publicImagesError(result, onlyMissing); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func publicImagesError(result *ScanResult, onlyMissing bool) error {
+	images := result.PublicImages
+	if onlyMissing {
+		images = result.NotAvailableImages
+	}
+
+	seen := make(map[string]bool)
+	var offending []string
+	for _, image := range images {
+		if seen[image.Image] {
+			continue
+		}
+		seen[image.Image] = true
+		offending = append(offending, image.Image)
+	}
+
+	if len(offending) == 0 {
+		return nil
+	}
+	sort.Strings(offending)
+
+	for _, image := range offending {
+		log.Error("public_image_policy_violation").
+			Str("image", image).
+			Bool("only_missing", onlyMissing).
+			Send()
+	}
+
+	return fmt.Errorf("%w: %d imagem(ns): %s", types.ErrPublicImagesFound, len(offending), strings.Join(offending, ", "))
 }
 
 func printScanSummary(result *ScanResult, validatedMap map[string]string) {
@@ -189,9 +292,13 @@ func printScanSummary(result *ScanResult, validatedMap map[string]string) {
 	log.Info("scan_results_summary").
 		Int("total_images_scanned", result.TotalScanned).
 		Int("public_images_found", result.TotalPublic).
+		Int("unique_public_images", result.UniquePublic).
 		Int("available_in_private", result.TotalAvailable).
+		Int("unique_available_in_private", result.UniqueAvailable).
 		Int("not_available_in_private", len(result.NotAvailableImages)).
+		Int("unique_not_available_in_private", result.UniqueNotAvailable).
 		Int("validated_from_batch", len(validatedMap)).
+		Int("unhealthy_registries", len(result.UnhealthyRegistries)).
 		Str("scan_duration", result.ScanDuration.String()).
 		Send()
 
@@ -201,10 +308,65 @@ func printScanSummary(result *ScanResult, validatedMap map[string]string) {
 			Float64("percentage", availabilityPercentage).
 			Send()
 	}
+
+	if len(result.UnhealthyRegistries) > 0 {
+		log.Warn("unhealthy_registries").
+			Str("separator", "-------------------------------------------").
+			Str("message", "Imagens \"não disponíveis\" podem estar apenas inacessíveis nestes registries").
+			Send()
+
+		for _, registry := range reporter.NewUnhealthyRegistries(result.UnhealthyRegistries) {
+			log.Warn("registry_unhealthy").
+				Str("registry", registry.Name).
+				Str("error", registry.Error).
+				Send()
+		}
+	}
+
+	printDeprecatedRegistries(result.PublicImages)
+}
+
+func printDeprecatedRegistries(images []*types.ImageInfo) {
+	deprecated := deprecatedRegistryImages(images)
+	if len(deprecated) == 0 {
+		return
+	}
+
+	log.Warn("deprecated_public_registries").
+		Str("separator", "-------------------------------------------").
+		Str("message", "Imagens em registries públicos descontinuados; atualize para o registry equivalente").
+		Int("unique_images", len(deprecated)).
+		Send()
+
+	for _, image := range deprecated {
+		log.Warn("deprecated_registry_image").
+			Str("image", image.Image).
+			Str("replacement", image.Replacement).
+			Send()
+	}
 }
 
-func printDetailedResults(result *ScanResult) {
-	if len(result.AvailableInPrivate) > 0 {
+func deprecatedRegistryImages(images []*types.ImageInfo) []reporter.DeprecatedImage {
+	seen := make(map[string]bool)
+	var deprecated []reporter.DeprecatedImage
+	for _, image := range images {
+		if seen[image.Image] {
+			continue
+		}
+		seen[image.Image] = true
+		if replacement, ok := utils.DeprecatedRegistryReplacement(image.Image); ok {
+			deprecated = append(deprecated, reporter.DeprecatedImage{Image: image.Image, Replacement: replacement})
+		}
+	}
+
+	sort.Slice(deprecated, func(i, j int) bool {
+		return deprecated[i].Image < deprecated[j].Image
+	})
+	return deprecated
+}
+
+func printDetailedResults(result *ScanResult, onlyMissing bool) {
+	if len(result.AvailableInPrivate) > 0 && !onlyMissing {
 		log.Info("images_available_in_private").
 			Str("separator", "-------------------------------------------").
 			Send()
@@ -329,7 +491,57 @@ func printRecommendations(result *ScanResult) {
 	}
 }
 
+func exportScanCSV(result *ScanResult, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("falha ao criar %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := writeScanCSV(file, result); err != nil {
+		return fmt.Errorf("falha ao escrever %s: %w", path, err)
+	}
+
+	log.Info("scan_csv_exported").
+		Str("file", path).
+		Int("images", len(result.PublicImages)).
+		Send()
+
+	return nil
+}
+
+func writeScanCSV(w io.Writer, result *ScanResult) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"image", "namespace", "resource_type", "resource_name", "container", "is_public", "available_privately", "private_location"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, image := range result.PublicImages {
+		locations := result.AvailableInPrivate[image.Image]
+		row := []string{
+			image.Image,
+			image.Namespace,
+			image.ResourceType,
+			image.ResourceName,
+			image.Container,
+			strconv.FormatBool(image.IsPublic),
+			strconv.FormatBool(len(locations) > 0),
+			strings.Join(locations, ";"),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
 func scanGithub() error {
+	ctx := commandContext()
+
 	if !cfg.GitHub.Enabled {
 		log.Warn("github_not_enabled").
 			Str("message", "GitHub scanning não está habilitado na configuração").
@@ -337,18 +549,26 @@ func scanGithub() error {
 		return nil
 	}
 
-	if cfg.GitHub.Token == "" {
+	enabledRepos, tokenRequired := 0, false
+	for _, repo := range cfg.GitHub.Repositories {
+		if repo.Enabled {
+			enabledRepos++
+			tokenRequired = tokenRequired || !github.UsesSSH(repo)
+		}
+	}
+
+	if tokenRequired && cfg.GitHub.Token == "" {
 		log.Error("github_token_missing").
 			Str("message", "Token GitHub não configurado").
 			Send()
 		return nil
 	}
 
-	enabledRepos := 0
-	for _, repo := range cfg.GitHub.Repositories {
-		if repo.Enabled {
-			enabledRepos++
-		}
+	if enabledRepos == 0 {
+		log.Warn("no_github_repositories").
+			Str("message", "Nenhum repositório GitHub habilitado").
+			Send()
+		return nil
 	}
 
 	log.Info("scanning_github_repositories").
@@ -356,20 +576,196 @@ func scanGithub() error {
 		Int("enabled_repositories", enabledRepos).
 		Send()
 
-	for _, repo := range cfg.GitHub.Repositories {
-		if repo.Enabled {
-			log.Info("github_repository_configured").
-				Str("repository", repo.Name).
-				Int("priority", repo.Priority).
-				Strs("paths", repo.Paths).
+	client, err := kubernetes.NewClient(cfg, log)
+	if err != nil {
+		return err
+	}
+
+	publicImages, err := scanClusterImages(ctx, client)
+	if err != nil {
+		return fmt.Errorf("falha ao escanear imagens do cluster: %w", err)
+	}
+
+	if len(publicImages) == 0 {
+		log.Info("no_public_images_for_github").
+			Str("message", "Nenhuma imagem pública encontrada no cluster").
+			Send()
+		return nil
+	}
+
+	githubClient := github.NewClient(&cfg.GitHub, log)
+	if tokenRequired {
+		if err := githubClient.ValidateToken(ctx); err != nil {
+			return err
+		}
+	}
+
+	fileScanner := scanner.NewFileScanner(githubClient, log, cfg).UseState(sharedState())
+	results := fileScanner.ScanRepositories(ctx, publicImages)
+
+	printGithubScanResults(results)
+	printRepoOnlyImages(results)
+
+	commandSummary = reporter.NewCommandSummary("scan github", cfg.Settings.DryRun)
+	commandSummary.Scan = githubScanFindings(publicImages, results)
+
+	return nil
+}
+
+func printGithubScanResults(results []scanner.RepositoryScanResult) {
+	log.Info("github_scan_results").
+		Str("separator", "===========================================").
+		Send()
+
+	totalDetections := 0
+	failedRepositories := 0
+
+	for _, result := range results {
+		if result.Error != nil {
+			failedRepositories++
+			continue
+		}
+
+		detections := make([]types.ImageDetectionResult, len(result.Detections))
+		copy(detections, result.Detections)
+		sort.SliceStable(detections, func(i, j int) bool {
+			if detections[i].FilePath != detections[j].FilePath {
+				return detections[i].FilePath < detections[j].FilePath
+			}
+			return detections[i].LineNumber < detections[j].LineNumber
+		})
+
+		log.Info("github_repository_scanned").
+			Str("repository", result.Repository).
+			Int("detections", len(detections)).
+			Send()
+
+		for _, detection := range detections {
+			log.Info("github_image_detected").
+				Str("repository", result.Repository).
+				Str("file", detection.FilePath).
+				Int("line", detection.LineNumber).
+				Str("image", detection.FullImage).
+				Float64("confidence", detection.Confidence).
 				Send()
 		}
+
+		totalDetections += len(detections)
+	}
+
+	if totalDetections > 0 {
+		log.Info("gitops_recommendation").
+			Str("message", "Para atualizar repositórios com imagens disponíveis").
+			Str("command", "privateer migrate github --dry-run").
+			Send()
 	}
 
 	log.Info("operation_completed").
 		Str("operation", "github_scan").
-		Str("message", "Use 'privateer migrate github --dry-run' para escanear repositórios").
+		Int("repositories_scanned", len(results)-failedRepositories).
+		Int("repositories_failed", failedRepositories).
+		Int("images_detected", totalDetections).
 		Send()
+}
 
-	return nil
+func printRepoOnlyImages(results []scanner.RepositoryScanResult) {
+	locations := make(map[string][]string)
+	for _, result := range results {
+		for _, image := range result.RepoOnlyImages {
+			locations[image.FullImage] = append(locations[image.FullImage], fmt.Sprintf("%s:%s:%d", result.Repository, image.FilePath, image.LineNumber))
+		}
+	}
+
+	if len(locations) == 0 {
+		return
+	}
+
+	log.Info("repo_only_public_images").
+		Str("separator", "-------------------------------------------").
+		Send()
+
+	sortedImages := make([]string, 0, len(locations))
+	for image := range locations {
+		sortedImages = append(sortedImages, image)
+	}
+	sort.Strings(sortedImages)
+
+	for _, image := range sortedImages {
+		log.Info("repo_only_image").
+			Str("public_image", image).
+			Strs("locations", locations[image]).
+			Int("reference_count", len(locations[image])).
+			Send()
+	}
+
+	log.Info("repo_only_recommendation").
+		Str("message", "Imagens públicas referenciadas nos repositórios mas não executadas no cluster; considere migrá-las proativamente").
+		Int("unique_images", len(sortedImages)).
+		Send()
+}
+
+func clusterScanFindings(result *ScanResult) *reporter.ScanFindings {
+	findings := &reporter.ScanFindings{
+		TotalImages:              result.TotalScanned,
+		UniqueImages:             result.UniquePublic,
+		AvailableInPrivate:       result.TotalAvailable,
+		UniqueAvailableInPrivate: result.UniqueAvailable,
+	}
+
+	seen := make(map[string]bool)
+	for _, image := range result.NotAvailableImages {
+		if seen[image.Image] {
+			continue
+		}
+		seen[image.Image] = true
+		findings.NotAvailable = append(findings.NotAvailable, image.Image)
+	}
+	sort.Strings(findings.NotAvailable)
+
+	if len(result.UnhealthyRegistries) > 0 {
+		findings.UnhealthyRegistries = reporter.NewUnhealthyRegistries(result.UnhealthyRegistries)
+	}
+	findings.DeprecatedRegistries = deprecatedRegistryImages(result.PublicImages)
+
+	return findings
+}
+
+func githubScanFindings(publicImages []*types.ImageInfo, results []scanner.RepositoryScanResult) *reporter.ScanFindings {
+	findings := &reporter.ScanFindings{
+		TotalImages:  len(publicImages),
+		UniqueImages: utils.CountUniqueImages(publicImages),
+	}
+
+	repoOnly := make(map[string]bool)
+	for _, result := range results {
+		repository := reporter.RepositoryFindings{
+			Repository: result.Repository,
+			Detections: len(result.Detections),
+		}
+		if result.Error != nil {
+			repository.Error = result.Error.Error()
+		}
+
+		images := make(map[string]bool)
+		for _, detection := range result.Detections {
+			images[detection.FullImage] = true
+		}
+		for image := range images {
+			repository.Images = append(repository.Images, image)
+		}
+		sort.Strings(repository.Images)
+
+		for _, image := range result.RepoOnlyImages {
+			repoOnly[image.FullImage] = true
+		}
+
+		findings.Repositories = append(findings.Repositories, repository)
+	}
+
+	for image := range repoOnly {
+		findings.RepoOnlyImages = append(findings.RepoOnlyImages, image)
+	}
+	sort.Strings(findings.RepoOnlyImages)
+
+	return findings
 }
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestPrintDetailedResults_OnlyMissing(t *testing.T) {
+	result := &ScanResult{
+		AvailableInPrivate: map[string][]string{
+			"nginx:1.25": {"harbor-prod"},
+		},
+		NotAvailableImages: []*types.ImageInfo{
+			{Image: "redis:7", Namespace: "cache", ResourceType: "Deployment", ResourceName: "redis"},
+			{Image: "postgres:16", Namespace: "database", ResourceType: "StatefulSet", ResourceName: "postgres"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		onlyMissing   bool
+		wantAvailable bool
+	}{
+		{name: "full output", onlyMissing: false, wantAvailable: true},
+		{name: "only missing", onlyMissing: true, wantAvailable: false},
+	}
+
+	previous := log
+	defer func() { log = previous }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log = logger.NewWithWriter(&types.Config{Settings: types.SettingsConfig{LogLevel: "info"}}, &buf)
+
+			printDetailedResults(result, tt.onlyMissing)
+
+			output := buf.String()
+			for _, image := range []string{"redis:7", "postgres:16"} {
+				if !strings.Contains(output, image) {
+					t.Errorf("expected missing image %s in output:\n%s", image, output)
+				}
+			}
+			if got := strings.Contains(output, "nginx:1.25"); got != tt.wantAvailable {
+				t.Errorf("available image listed = %v, expected %v:\n%s", got, tt.wantAvailable, output)
+			}
+		})
+	}
+}
+
+func TestWriteScanCSV(t *testing.T) {
+	result := &ScanResult{
+		PublicImages: []*types.ImageInfo{
+			{Image: "nginx:1.25", Namespace: "web", ResourceType: "Deployment", ResourceName: "frontend", Container: "nginx", IsPublic: true},
+			{Image: "redis:7", Namespace: "cache", ResourceType: "StatefulSet", ResourceName: "redis", Container: "redis", IsPublic: true},
+		},
+		AvailableInPrivate: map[string][]string{
+			"nginx:1.25": {"harbor.company.com/library/nginx:1.25 (harbor-prod)", "ghcr.io/company/nginx:1.25 (ghcr)"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeScanCSV(&buf, result); err != nil {
+		t.Fatalf("writeScanCSV() unexpected error: %v", err)
+	}
+
+	expected := "image,namespace,resource_type,resource_name,container,is_public,available_privately,private_location\n" +
+		"nginx:1.25,web,Deployment,frontend,nginx,true,true,harbor.company.com/library/nginx:1.25 (harbor-prod);ghcr.io/company/nginx:1.25 (ghcr)\n" +
+		"redis:7,cache,StatefulSet,redis,redis,true,false,\n"
+	if buf.String() != expected {
+		t.Errorf("CSV output = %q, expected %q", buf.String(), expected)
+	}
+}
+
+func TestPrintScanSummary_DeprecatedRegistryWarning(t *testing.T) {
+	result := &ScanResult{
+		PublicImages: []*types.ImageInfo{
+			{Image: "k8s.gcr.io/pause:3.2", Namespace: "kube-system"},
+			{Image: "k8s.gcr.io/pause:3.2", Namespace: "default"},
+			{Image: "nginx:1.25", Namespace: "web"},
+		},
+	}
+
+	previous := log
+	defer func() { log = previous }()
+
+	var buf bytes.Buffer
+	log = logger.NewWithWriter(&types.Config{Settings: types.SettingsConfig{LogLevel: "info"}}, &buf)
+
+	printScanSummary(result, nil)
+
+	output := buf.String()
+	if !strings.Contains(output, "deprecated_registry_image") || !strings.Contains(output, "registry.k8s.io/pause:3.2") {
+		t.Errorf("expected deprecation warning suggesting registry.k8s.io/pause:3.2:\n%s", output)
+	}
+	if strings.Count(output, "deprecated_registry_image") != 1 {
+		t.Errorf("expected a single warning for the duplicated image:\n%s", output)
+	}
+	if strings.Contains(output, "nginx:1.25") {
+		t.Errorf("nginx:1.25 should not be flagged as deprecated:\n%s", output)
+	}
+
+	findings := clusterScanFindings(result)
+	if len(findings.DeprecatedRegistries) != 1 || findings.DeprecatedRegistries[0].Replacement != "registry.k8s.io/pause:3.2" {
+		t.Errorf("DeprecatedRegistries = %+v", findings.DeprecatedRegistries)
+	}
+}
+
+func TestPublicImagesError(t *testing.T) {
+	result := &ScanResult{
+		PublicImages: []*types.ImageInfo{
+			{Image: "nginx:1.25", Namespace: "web"},
+			{Image: "redis:7", Namespace: "cache"},
+			{Image: "nginx:1.25", Namespace: "staging"},
+		},
+		NotAvailableImages: []*types.ImageInfo{
+			{Image: "redis:7", Namespace: "cache"},
+		},
+	}
+
+	previous := log
+	defer func() { log = previous }()
+	log = logger.NewWithWriter(&types.Config{Settings: types.SettingsConfig{LogLevel: "error"}}, &bytes.Buffer{})
+
+	tests := []struct {
+		name        string
+		result      *ScanResult
+		onlyMissing bool
+		expected    string
+	}{
+		{name: "all public images", result: result, expected: "2 imagem(ns): nginx:1.25, redis:7"},
+		{name: "only images missing privately", result: result, onlyMissing: true, expected: "1 imagem(ns): redis:7"},
+		{name: "no public images", result: &ScanResult{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := publicImagesError(tt.result, tt.onlyMissing)
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("publicImagesError() = %v, expected nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, types.ErrPublicImagesFound) {
+				t.Fatalf("publicImagesError() = %v, expected ErrPublicImagesFound", err)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("publicImagesError() = %q, expected it to contain %q", err.Error(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestScanGateError(t *testing.T) {
+	previous := log
+	defer func() { log = previous }()
+	log = logger.NewWithWriter(&types.Config{Settings: types.SettingsConfig{LogLevel: "error"}}, &bytes.Buffer{})
+
+	tests := []struct {
+		name      string
+		result    *ScanResult
+		expected  []string
+		wantError bool
+	}{
+		{name: "clean scan", result: &ScanResult{}},
+		{
+			name:      "failed namespace without public images",
+			result:    &ScanResult{FailedNamespaces: []string{"payments"}},
+			expected:  []string{"falha ao escanear 1 namespace(s): payments"},
+			wantError: true,
+		},
+		{
+			name: "failed namespace and public images",
+			result: &ScanResult{
+				FailedNamespaces: []string{"payments", "billing"},
+				PublicImages:     []*types.ImageInfo{{Image: "nginx:1.25", Namespace: "web"}},
+			},
+			expected:  []string{"falha ao escanear 2 namespace(s): payments, billing", "1 imagem(ns): nginx:1.25"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := scanGateError(tt.result, false)
+			if !tt.wantError {
+				if err != nil {
+					t.Fatalf("scanGateError() = %v, expected nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("scanGateError() = nil, expected an error")
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("scanGateError() = %q, expected it to contain %q", err.Error(), expected)
+				}
+			}
+		})
+	}
+}
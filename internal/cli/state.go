@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"github.com/kevinfinalboss/privateer/internal/state"
+)
+
+var runState *state.Store
+
+func statePath() string {
+	if cfg != nil && cfg.History.Path != "" {
+		return cfg.History.Path
+	}
+	return state.DefaultPath()
+}
+
+func openRunState() (*state.Store, error) {
+	if runState != nil {
+		return runState, nil
+	}
+
+	store, err := state.Open(statePath())
+	if err != nil {
+		return nil, err
+	}
+	runState = store
+	return store, nil
+}
+
+func sharedState() *state.Store {
+	store, err := openRunState()
+	if err != nil {
+		log.Warn("state_open_failed").Err(err).Send()
+		return nil
+	}
+	return store
+}
+
+func closeRunState() {
+	if runState == nil {
+		return
+	}
+
+	if err := runState.Close(); err != nil && log != nil {
+		log.Warn("state_close_failed").Err(err).Send()
+	}
+	runState = nil
+}
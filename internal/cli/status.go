@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"github.com/kevinfinalboss/privateer/internal/reporter"
 	"github.com/spf13/cobra"
 )
 
@@ -12,6 +13,7 @@ var statusCmd = &cobra.Command{
 		log.Info("operation_completed").
 			Str("operation", "status").
 			Send()
+		commandSummary = reporter.NewCommandSummary("status", cfg.Settings.DryRun)
 		return nil
 	},
 }
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/internal/github"
+	"github.com/kevinfinalboss/privateer/internal/gitops"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/kevinfinalboss/privateer/internal/scanner"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var verifyFailOnDrift bool
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verifica se os repositórios ainda referenciam imagens públicas",
+	Long:  "Verifica se os repositórios GitOps ainda referenciam imagens públicas que já estão disponíveis em registries privados",
+}
+
+var verifyGithubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Verifica drift nos repositórios GitHub",
+	Long:  "Escaneia os repositórios GitOps, sem acesso ao cluster e sem notificações ou relatórios, e lista as referências a imagens públicas que já possuem cópia nos registries privados",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return verifyGithub()
+	},
+}
+
+func init() {
+	verifyGithubCmd.Flags().BoolVar(&verifyFailOnDrift, "fail-on-drift", false, getMessage("flag_verify_fail_on_drift"))
+
+	verifyCmd.AddCommand(verifyGithubCmd)
+}
+
+func verifyGithub() error {
+	cfg.Settings.DryRun = true
+	reportsDisabled = true
+
+	summary, scanFailures, err := runGithubVerification()
+	if err != nil {
+		return err
+	}
+
+	commandSummary = reporter.NewDriftCommandSummary("verify github", summary)
+	commandSummary.Success = commandSummary.Success && len(scanFailures) == 0
+
+	log.Info("gitops_drift_verified").
+		Int("drift_count", len(commandSummary.Drift)).
+		Int("scan_failures", len(scanFailures)).
+		Bool("fail_on_drift", verifyFailOnDrift).
+		Send()
+
+	if !verifyFailOnDrift {
+		return nil
+	}
+
+	gateErr := verificationGateError(commandSummary, summary, scanFailures)
+	if gateErr == nil {
+		return nil
+	}
+
+	if err := writeCommandSummary(); err != nil {
+		return err
+	}
+	return gateErr
+}
+
+// verificationGateError fails the --fail-on-drift gate on drift and also when
+// a repository could not be scanned or verified, so an unreadable repository
+// never passes as clean.
+func verificationGateError(commandSummary *reporter.CommandSummary, summary *types.GitOpsSummary, scanFailures []string) error {
+	var errs []error
+	if len(scanFailures) > 0 {
+		errs = append(errs, fmt.Errorf("falha ao escanear %d repositório(s): %s", len(scanFailures), strings.Join(scanFailures, ", ")))
+	}
+	if summary != nil && summary.FailedOperations > 0 {
+		errs = append(errs, fmt.Errorf("%d operação(ões) GitOps falharam durante a verificação", summary.FailedOperations))
+	}
+	if err := commandSummary.DriftError(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func runGithubVerification() (*types.GitOpsSummary, []string, error) {
+	ctx := commandContext()
+
+	enabledRepos, err := checkGithubMigrationConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	registryManager, err := newMigrationRegistryManager(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	githubClient := github.NewClient(&cfg.GitHub, log)
+	if err := githubClient.ValidateToken(ctx); err != nil {
+		return nil, nil, fmt.Errorf("falha na validação do token GitHub: %w", err)
+	}
+
+	publicImages, scanFailures := repositoryPublicImages(scanner.NewFileScanner(githubClient, log, cfg).UseState(sharedState()).ScanRepositories(ctx, nil))
+
+	log.Info("github_verification_started").
+		Int("enabled_repositories", enabledRepos).
+		Int("public_images", len(publicImages)).
+		Send()
+
+	if len(publicImages) == 0 {
+		return &types.GitOpsSummary{}, scanFailures, nil
+	}
+
+	summary, err := gitops.NewEngine(githubClient, registryManager, log, cfg).UseState(sharedState()).ScanOnly().MigrateRepositories(ctx, publicImages)
+	return summary, scanFailures, err
+}
+
+func repositoryPublicImages(results []scanner.RepositoryScanResult) ([]*types.ImageInfo, []string) {
+	seen := make(map[string]bool)
+	var images []*types.ImageInfo
+	var scanFailures []string
+
+	for _, result := range results {
+		if result.Error != nil {
+			log.Warn("github_verification_scan_failed").
+				Str("repository", result.Repository).
+				Err(result.Error).
+				Send()
+			scanFailures = append(scanFailures, result.Repository)
+			continue
+		}
+
+		for _, detection := range result.RepoOnlyImages {
+			if seen[detection.FullImage] {
+				continue
+			}
+			seen[detection.FullImage] = true
+
+			images = append(images, &types.ImageInfo{
+				Image:      detection.FullImage,
+				IsPublic:   true,
+				Registry:   detection.Registry,
+				Repository: detection.Repository,
+				Tag:        detection.Tag,
+				Digest:     detection.Digest,
+			})
+		}
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Image < images[j].Image
+	})
+	return images, scanFailures
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/kevinfinalboss/privateer/internal/scanner"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestRepositoryPublicImages(t *testing.T) {
+	previous := log
+	defer func() { log = previous }()
+	log = logger.NewTest()
+
+	results := []scanner.RepositoryScanResult{
+		{Repository: "company/web", RepoOnlyImages: []types.ImageDetectionResult{
+			{FullImage: "redis:7.0", Registry: "docker.io", Repository: "redis", Tag: "7.0"},
+			{FullImage: "nginx:1.25", Registry: "docker.io", Repository: "nginx", Tag: "1.25"},
+		}},
+		{Repository: "company/api", RepoOnlyImages: []types.ImageDetectionResult{
+			{FullImage: "nginx:1.25", Registry: "docker.io", Repository: "nginx", Tag: "1.25"},
+		}},
+		{Repository: "company/broken", Error: errors.New("falha"), RepoOnlyImages: []types.ImageDetectionResult{
+			{FullImage: "postgres:16"},
+		}},
+	}
+
+	images, scanFailures := repositoryPublicImages(results)
+	if len(images) != 2 || images[0].Image != "nginx:1.25" || images[1].Image != "redis:7.0" {
+		t.Fatalf("repositoryPublicImages() = %+v, expected nginx and redis once each", images)
+	}
+	if !images[0].IsPublic || images[0].Tag != "1.25" {
+		t.Errorf("image = %+v, expected a public image with its tag", images[0])
+	}
+	if len(scanFailures) != 1 || scanFailures[0] != "company/broken" {
+		t.Errorf("scan failures = %v, expected company/broken", scanFailures)
+	}
+}
+
+func TestVerificationGateError(t *testing.T) {
+	previous := log
+	defer func() { log = previous }()
+	log = logger.NewTest()
+
+	clean := &types.GitOpsSummary{Results: []*types.GitOpsResult{{Repository: "company/web", Success: true}}}
+	_, brokenRepository := repositoryPublicImages([]scanner.RepositoryScanResult{
+		{Repository: "company/web"},
+		{Repository: "company/broken", Error: errors.New("falha de autenticação SSH")},
+	})
+
+	tests := []struct {
+		name         string
+		summary      *types.GitOpsSummary
+		scanFailures []string
+		wantErr      string
+	}{
+		{name: "clean run passes", summary: clean},
+		{name: "unreadable repository fails", summary: clean, scanFailures: brokenRepository, wantErr: "company/broken"},
+		{name: "failed GitOps operation fails", summary: &types.GitOpsSummary{FailedOperations: 1}, wantErr: "operação(ões) GitOps falharam"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verificationGateError(reporter.NewDriftCommandSummary("verify github", tt.summary), tt.summary, tt.scanFailures)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("verificationGateError() = %v, expected nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("verificationGateError() = %v, expected an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteCommandSummary_ReportsDisabled(t *testing.T) {
+	previousLog, previousCfg, previousOutput := log, cfg, summaryOutput
+	defer func() {
+		log, cfg, summaryOutput = previousLog, previousCfg, previousOutput
+		commandSummary, summaryWritten, reportsDisabled = nil, false, false
+	}()
+
+	var posts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	log = logger.NewTest()
+	cfg = &types.Config{Webhooks: types.WebhookConfig{HTTP: types.HTTPWebhookConfig{Enabled: true, URL: server.URL}}}
+	summaryOutput = &output
+	outputFormat = reporter.OutputFormatText
+	reportsDisabled = true
+	commandSummary, summaryWritten = reporter.NewDriftCommandSummary("verify github", &types.GitOpsSummary{}), false
+
+	if err := writeCommandSummary(); err != nil {
+		t.Fatalf("writeCommandSummary() error = %v", err)
+	}
+
+	if atomic.LoadInt32(&posts) != 0 {
+		t.Errorf("summary webhook called %d times, expected none for a scan-only run", posts)
+	}
+	if commandSummary.Report != "" {
+		t.Errorf("report = %q, expected no report for a scan-only run", commandSummary.Report)
+	}
+	if !strings.Contains(output.String(), "command: verify github") {
+		t.Errorf("summary not written:\n%s", output.String())
+	}
+}
@@ -1,25 +1,61 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
 	"gopkg.in/yaml.v3"
 )
 
-func Load(configFile string) (*types.Config, error) {
-	if configFile == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, err
+const ConfigEnvVar = "PRIVATEER_CONFIG"
+
+var commitTrailerPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*: \S.*$`)
+
+func ResolvePath(configFile string) (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+	if envFile := os.Getenv(ConfigEnvVar); envFile != "" {
+		return envFile, nil
+	}
+
+	candidates := []string{"privateer.yaml"}
+	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); xdgConfigHome != "" {
+		candidates = append(candidates, filepath.Join(xdgConfigHome, "privateer", "config.yaml"))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
 		}
-		configFile = filepath.Join(home, ".privateer", "config.yaml")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".privateer", "config.yaml"), nil
+}
+
+func isExplicitPath(configFile string) bool {
+	return configFile != "" || os.Getenv(ConfigEnvVar) != ""
+}
+
+func Load(configFile string) (*types.Config, error) {
+	explicit := isExplicitPath(configFile)
+	configFile, err := ResolvePath(configFile)
+	if err != nil {
+		return nil, err
 	}
 
 	data, err := os.ReadFile(configFile)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) && !explicit {
 			return GetDefaultConfig(), nil
 		}
 		return nil, err
@@ -31,6 +67,9 @@ func Load(configFile string) (*types.Config, error) {
 	}
 
 	applyDefaults(&config)
+	if err := validate(&config); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
 
@@ -160,15 +199,25 @@ func applyDefaults(config *types.Config) {
 		}
 	}
 
+	config.Settings.RegistrySelection = normalizeRegistrySelection(config.Settings.RegistrySelection)
+
 	if config.Webhooks.Discord.Name == "" {
 		config.Webhooks.Discord.Name = "Privateer 🏴‍☠️"
 	}
+	for i := range config.Registries {
+		registry := &config.Registries[i]
+		registry.Role = normalizeRegistryRole(registry.Role)
+	}
+
+	config.Webhooks.Discord.NotifyOn = normalizeNotifyOn(config.Webhooks.Discord.NotifyOn)
+	for i := range config.Webhooks.DiscordTargets {
+		target := &config.Webhooks.DiscordTargets[i]
+		target.NotifyOn = normalizeNotifyOn(target.NotifyOn)
+	}
 
 	for i := range config.GitHub.Repositories {
 		repo := &config.GitHub.Repositories[i]
-		if repo.BranchStrategy == "" {
-			repo.BranchStrategy = "create_new"
-		}
+		repo.BranchStrategy = normalizeBranchStrategy(repo.BranchStrategy)
 		if repo.PRSettings.CommitPrefix == "" {
 			repo.PRSettings.CommitPrefix = "🏴‍☠️ Privateer:"
 		}
@@ -178,17 +227,212 @@ func applyDefaults(config *types.Config) {
 	}
 }
 
-func Save(config *types.Config, configFile string) error {
-	if configFile == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return err
+func normalizeBranchStrategy(strategy string) string {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	strategy = strings.ReplaceAll(strategy, "-", "_")
+	if strategy == "" {
+		return types.BranchStrategyCreateNew
+	}
+	return strategy
+}
+
+func normalizeNotifyOn(notifyOn string) string {
+	notifyOn = strings.ToLower(strings.TrimSpace(notifyOn))
+	if notifyOn == "" {
+		return types.NotifyOnAlways
+	}
+	return notifyOn
+}
+
+func normalizeRegistrySelection(selection string) string {
+	selection = strings.ToLower(strings.TrimSpace(selection))
+	selection = strings.ReplaceAll(selection, "-", "_")
+	if selection == "" {
+		return types.RegistrySelectionPriority
+	}
+	return selection
+}
+
+func normalizeRegistryRole(role string) string {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if role == "" {
+		return types.RegistryRoleBoth
+	}
+	return role
+}
+
+func ApplyRegistryTypes(config *types.Config, overrides []string) error {
+	for _, override := range overrides {
+		name, registryType, found := strings.Cut(override, "=")
+		if !found {
+			name, registryType = "", name
+		}
+		name = strings.TrimSpace(name)
+		registryType = strings.ToLower(strings.TrimSpace(registryType))
+
+		switch registryType {
+		case "docker", "harbor", "ecr", "ghcr", "oci-layout":
+		default:
+			return fmt.Errorf("--registry-type inválido '%s': use docker, harbor, ecr, ghcr ou oci-layout", override)
 		}
-		configDir := filepath.Join(home, ".privateer")
-		if err := os.MkdirAll(configDir, 0755); err != nil {
-			return err
+
+		matched := false
+		for i := range config.Registries {
+			registry := &config.Registries[i]
+			if name != "" && registry.Name != name {
+				continue
+			}
+			matched = true
+
+			registry.Type = registryType
+			if err := validateRegistryTypeFields(*registry); err != nil {
+				return err
+			}
 		}
-		configFile = filepath.Join(configDir, "config.yaml")
+
+		if !matched {
+			if name == "" {
+				return fmt.Errorf("--registry-type %s: nenhum registry configurado", registryType)
+			}
+			return fmt.Errorf("--registry-type %s: registry '%s' não encontrado na configuração", override, name)
+		}
+	}
+	return nil
+}
+
+func validateRegistryTypeFields(registry types.RegistryConfig) error {
+	var missing string
+	switch registry.Type {
+	case "docker", "harbor":
+		if registry.URL == "" {
+			missing = "url"
+		}
+	case "ecr":
+		if registry.Region == "" {
+			missing = "region"
+		}
+	case "ghcr":
+		if registry.Project == "" && registry.Username == "" {
+			missing = "project ou username"
+		}
+	case "oci-layout":
+		if registry.Path == "" && registry.URL == "" {
+			missing = "path"
+		}
+	}
+
+	if missing != "" {
+		return fmt.Errorf("registry %s com tipo %s requer o campo %s", registry.Name, registry.Type, missing)
+	}
+	return nil
+}
+
+func validateRegistrySelection(config *types.Config) error {
+	switch config.Settings.RegistrySelection {
+	case types.RegistrySelectionPriority, types.RegistrySelectionRoundRobin, types.RegistrySelectionRoundRobinBalanced:
+	case types.RegistrySelectionByRule:
+		if len(config.Settings.RegistryRules) == 0 {
+			return fmt.Errorf("registry_selection %s requer ao menos uma regra em registry_rules", types.RegistrySelectionByRule)
+		}
+	default:
+		return fmt.Errorf("registry_selection inválido '%s': use %s, %s, %s ou %s", config.Settings.RegistrySelection, types.RegistrySelectionPriority, types.RegistrySelectionRoundRobin, types.RegistrySelectionRoundRobinBalanced, types.RegistrySelectionByRule)
+	}
+
+	for _, rule := range config.Settings.RegistryRules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("regra de registry_rules sem pattern para o registry %s", rule.Registry)
+		}
+		found := false
+		for _, registry := range config.Registries {
+			if registry.Name == rule.Registry {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("registry '%s' da regra '%s' em registry_rules não está configurado", rule.Registry, rule.Pattern)
+		}
+	}
+	return nil
+}
+
+func validate(config *types.Config) error {
+	if config.Settings.MaxBandwidth < 0 {
+		return fmt.Errorf("max_bandwidth inválido %d: use um valor em bytes por segundo maior que zero", config.Settings.MaxBandwidth)
+	}
+	for _, registry := range config.Registries {
+		switch registry.Role {
+		case types.RegistryRoleTarget, types.RegistryRoleValidate, types.RegistryRoleBoth:
+		default:
+			return fmt.Errorf("role inválida '%s' no registry %s: use %s, %s ou %s", registry.Role, registry.Name, types.RegistryRoleTarget, types.RegistryRoleValidate, types.RegistryRoleBoth)
+		}
+		if registry.LifecyclePolicy != "" && !json.Valid([]byte(registry.LifecyclePolicy)) {
+			return fmt.Errorf("lifecycle_policy inválida no registry %s: informe um documento JSON válido", registry.Name)
+		}
+	}
+	if err := validateRegistrySelection(config); err != nil {
+		return err
+	}
+	if err := validateRegistryPatterns(config.ImageDetection); err != nil {
+		return err
+	}
+	for _, repo := range config.GitHub.Repositories {
+		if repo.SSH.PrivateKeyPath != "" && repo.SSH.KnownHostsPath == "" && !repo.SSH.AcceptNewHostKeys {
+			return fmt.Errorf("ssh.known_hosts_path não configurado no repositório %s: informe um arquivo known_hosts ou habilite ssh.accept_new_host_keys", repo.Name)
+		}
+		switch repo.BranchStrategy {
+		case types.BranchStrategyCreateNew, types.BranchStrategyUseMain:
+		default:
+			return fmt.Errorf("branch_strategy inválida '%s' no repositório %s: use %s ou %s", repo.BranchStrategy, repo.Name, types.BranchStrategyCreateNew, types.BranchStrategyUseMain)
+		}
+	}
+	for _, trailer := range config.GitOps.Committer.Trailers {
+		if !commitTrailerPattern.MatchString(strings.TrimSpace(trailer)) {
+			return fmt.Errorf("trailer de commit inválido '%s': use o formato 'Chave: valor' (ex: Co-authored-by: Nome <email>)", trailer)
+		}
+	}
+	if repository := config.GitOps.TrackingIssue.Repository; repository != "" && len(strings.Split(repository, "/")) != 2 {
+		return fmt.Errorf("tracking_issue.repository inválido '%s': use o formato owner/repo", repository)
+	}
+	targets := append([]types.DiscordWebhookConfig{config.Webhooks.Discord}, config.Webhooks.DiscordTargets...)
+	for _, target := range targets {
+		switch target.NotifyOn {
+		case types.NotifyOnAlways, types.NotifyOnSuccess, types.NotifyOnFailure:
+		default:
+			return fmt.Errorf("notify_on inválido '%s' no webhook Discord: use %s, %s ou %s", target.NotifyOn, types.NotifyOnAlways, types.NotifyOnSuccess, types.NotifyOnFailure)
+		}
+	}
+	return nil
+}
+
+func validateRegistryPatterns(detection types.ImageDetectionConfig) error {
+	lists := []struct {
+		key      string
+		patterns []string
+	}{
+		{"custom_public_registries", detection.CustomPublicRegistries},
+		{"custom_private_registries", detection.CustomPrivateRegistries},
+		{"ignore_registries", detection.IgnoreRegistries},
+		{"include_only", detection.IncludeOnly},
+		{"no_migrate", detection.NoMigrate},
+	}
+	for _, list := range lists {
+		for _, pattern := range list.patterns {
+			if _, err := utils.CompileRegistryPattern(pattern); err != nil {
+				return fmt.Errorf("padrão inválido '%s' em image_detection.%s: %w", pattern, list.key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func Save(config *types.Config, configFile string) error {
+	configFile, err := ResolvePath(configFile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+		return err
 	}
 
 	data, err := yaml.Marshal(config)
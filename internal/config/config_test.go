@@ -0,0 +1,267 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestResolvePath_Precedence(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	workDir := filepath.Join(root, "work")
+	xdgConfigHome := filepath.Join(root, "xdg")
+
+	flagFile := filepath.Join(root, "flag.yaml")
+	envFile := filepath.Join(root, "env.yaml")
+	localFile := filepath.Join(workDir, "privateer.yaml")
+	xdgFile := filepath.Join(xdgConfigHome, "privateer", "config.yaml")
+	homeFile := filepath.Join(home, ".privateer", "config.yaml")
+
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		files    []string
+		expected string
+	}{
+		{"flag wins over everything", flagFile, envFile, []string{localFile, xdgFile}, flagFile},
+		{"env var over local file", "", envFile, []string{localFile, xdgFile}, envFile},
+		{"local file over xdg", "", "", []string{localFile, xdgFile}, "privateer.yaml"},
+		{"xdg over home", "", "", []string{xdgFile}, xdgFile},
+		{"home default", "", "", nil, homeFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, dir := range []string{home, workDir, filepath.Dir(xdgFile)} {
+				os.RemoveAll(dir)
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, file := range tt.files {
+				if err := os.WriteFile(file, []byte("settings:\n  language: en-US\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+			t.Setenv(ConfigEnvVar, tt.env)
+			t.Chdir(workDir)
+
+			got, err := ResolvePath(tt.flag)
+			if err != nil {
+				t.Fatalf("ResolvePath() unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ResolvePath() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoad_ExplicitPathMissing(t *testing.T) {
+	root := t.TempDir()
+	missing := filepath.Join(root, "missing.yaml")
+	t.Setenv("HOME", root)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Chdir(root)
+
+	tests := []struct {
+		name    string
+		flag    string
+		env     string
+		wantErr bool
+	}{
+		{name: "missing flag file fails", flag: missing, wantErr: true},
+		{name: "missing env file fails", env: missing, wantErr: true},
+		{name: "missing discovered file uses defaults", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigEnvVar, tt.env)
+
+			config, err := Load(tt.flag)
+			if tt.wantErr {
+				if err == nil || !os.IsNotExist(err) {
+					t.Fatalf("Load() = %v, expected a not-exist error", err)
+				}
+				return
+			}
+			if err != nil || config == nil {
+				t.Fatalf("Load() = %v, %v; expected defaults", config, err)
+			}
+		})
+	}
+}
+
+func TestSave_ResolvedPath(t *testing.T) {
+	root := t.TempDir()
+	envFile := filepath.Join(root, "env", "privateer.yaml")
+	t.Setenv("HOME", root)
+	t.Setenv(ConfigEnvVar, envFile)
+
+	config := GetDefaultConfig()
+	config.Settings.Language = "en-US"
+	if err := Save(config, ""); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Settings.Language != "en-US" {
+		t.Errorf("Load() language = %q, expected the saved en-US", loaded.Settings.Language)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".privateer", "config.yaml")); !os.IsNotExist(err) {
+		t.Errorf("Save() wrote the home default instead of %s", envFile)
+	}
+}
+
+func TestLoad_BranchStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		expected string
+		wantErr  bool
+	}{
+		{name: "empty defaults to create_new", strategy: "", expected: "create_new"},
+		{name: "use_main is kept", strategy: "use_main", expected: "use_main"},
+		{name: "normalizes case and dashes", strategy: " Use-Main ", expected: "use_main"},
+		{name: "unknown strategy is rejected", strategy: "push_direct", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			content := "github:\n  repositories:\n    - name: company/manifests\n      branch_strategy: \"" + tt.strategy + "\"\n"
+			if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := Load(configFile)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Load() expected error for strategy %q", tt.strategy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got := config.GitHub.Repositories[0].BranchStrategy; got != tt.expected {
+				t.Errorf("branch strategy = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoad_RegistryPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr bool
+	}{
+		{name: "prefix", pattern: "ghcr.io/myorg"},
+		{name: "glob", pattern: "*.azurecr.io"},
+		{name: "regex", pattern: `regex:^quay\\.io/(prometheus|grafana)/`},
+		{name: "invalid regex is rejected", pattern: "regex:([", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			content := "image_detection:\n  ignore_registries:\n    - \"" + tt.pattern + "\"\n"
+			if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load(configFile)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "image_detection.ignore_registries") {
+					t.Fatalf("Load() = %v, expected an ignore_registries pattern error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_LifecyclePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr bool
+	}{
+		{name: "valid json", policy: `{"rules":[]}`},
+		{name: "invalid json is rejected", policy: `{"rules":[`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			content := "registries:\n  - name: ecr\n    type: ecr\n    region: us-east-1\n    lifecycle_policy: '" + tt.policy + "'\n"
+			if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load(configFile)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "lifecycle_policy") {
+					t.Fatalf("Load() = %v, expected a lifecycle_policy error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyRegistryTypes(t *testing.T) {
+	newConfig := func() *types.Config {
+		return &types.Config{Registries: []types.RegistryConfig{
+			{Name: "mirror", Type: "harbor", URL: "https://registry.company.com", Enabled: true},
+			{Name: "backup", Type: "ghcr", Project: "company", Enabled: true},
+		}}
+	}
+
+	config := newConfig()
+	if got := registry.TargetPrefix(&config.Registries[0]); got != "registry.company.com/library" {
+		t.Fatalf("harbor prefix = %q, expected registry.company.com/library", got)
+	}
+
+	if err := ApplyRegistryTypes(config, []string{"mirror=docker"}); err != nil {
+		t.Fatalf("ApplyRegistryTypes() unexpected error: %v", err)
+	}
+	if config.Registries[0].Type != "docker" || config.Registries[1].Type != "ghcr" {
+		t.Fatalf("types = %s, %s, expected only mirror forced to docker", config.Registries[0].Type, config.Registries[1].Type)
+	}
+	if got := registry.TargetPrefix(&config.Registries[0]); got != "registry.company.com" {
+		t.Errorf("forced docker prefix = %q, expected registry.company.com", got)
+	}
+
+	errorCases := map[string][]string{
+		"unknown type":           {"mirror=quay"},
+		"unknown registry":       {"missing=docker"},
+		"missing required field": {"backup=docker"},
+		"type for all":           {"harbor"},
+	}
+	for name, overrides := range errorCases {
+		if err := ApplyRegistryTypes(newConfig(), overrides); err == nil {
+			t.Errorf("%s: ApplyRegistryTypes(%v) expected error", name, overrides)
+		}
+	}
+}
@@ -5,39 +5,70 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/kevinfinalboss/privateer/internal/httpreplay"
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/pkg/types"
 )
 
 const (
-	GitHubAPIURL   = "https://api.github.com"
-	DefaultTimeout = 30 * time.Second
-	MaxRetries     = 3
-	RetryDelay     = 2 * time.Second
+	GitHubAPIURL        = "https://api.github.com"
+	DefaultTimeout      = 30 * time.Second
+	MaxRetries          = 3
+	RetryDelay          = 2 * time.Second
+	MaxIdleConns        = 100
+	MaxIdleConnsPerHost = 20
+	IdleConnTimeout     = 90 * time.Second
 )
 
 type Client struct {
 	token      string
+	baseURL    string
 	httpClient *http.Client
 	logger     *logger.Logger
 	config     *types.GitHubConfig
 }
 
 func NewClient(config *types.GitHubConfig, logger *logger.Logger) *Client {
+	baseURL := strings.TrimSuffix(config.APIURL, "/")
+	if baseURL == "" {
+		baseURL = GitHubAPIURL
+	}
+
 	return &Client{
-		token: config.Token,
+		token:   config.Token,
+		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: DefaultTimeout,
+			Timeout:   DefaultTimeout,
+			Transport: httpreplay.Wrap(newTransport()),
 		},
 		logger: logger,
 		config: config,
 	}
 }
 
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          MaxIdleConns,
+		MaxIdleConnsPerHost:   MaxIdleConnsPerHost,
+		IdleConnTimeout:       IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
 func (c *Client) ValidateToken(ctx context.Context) error {
 	c.logger.Debug("github_token_validation").Send()
 
@@ -48,7 +79,7 @@ func (c *Client) ValidateToken(ctx context.Context) error {
 	}
 
 	if resp.StatusCode == 401 {
-		return fmt.Errorf("token GitHub não autorizado - verifique permissões")
+		return fmt.Errorf("%w: verifique as permissões do token GitHub", types.ErrTokenUnauthorized)
 	}
 
 	if resp.StatusCode != 200 {
@@ -85,7 +116,7 @@ func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*types.
 	}
 
 	if resp.StatusCode == 404 {
-		return nil, fmt.Errorf("repositório %s/%s não encontrado", owner, repo)
+		return nil, fmt.Errorf("%w: %s/%s", types.ErrRepositoryNotFound, owner, repo)
 	}
 
 	if resp.StatusCode != 200 {
@@ -222,7 +253,7 @@ func (c *Client) CheckPermissions(ctx context.Context, owner, repo string) (*typ
 }
 
 func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body io.Reader) (*types.GitHubResponse, error) {
-	url := GitHubAPIURL + endpoint
+	url := c.baseURL + endpoint
 
 	var lastErr error
 	for attempt := 0; attempt < MaxRetries; attempt++ {
@@ -309,7 +340,7 @@ func (c *Client) ParseRepositoryName(repoName string) (owner, repo string, err e
 func (c *Client) parseRepositoryName(repoName string) (owner, repo string, err error) {
 	parts := strings.Split(repoName, "/")
 	if len(parts) != 2 {
-		return "", "", fmt.Errorf("formato de repositório inválido: %s (deve ser owner/repo)", repoName)
+		return "", "", fmt.Errorf("%w: %s (deve ser owner/repo)", types.ErrInvalidRepoName, repoName)
 	}
 	return parts[0], parts[1], nil
 }
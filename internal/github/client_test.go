@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestNewClient_TransportTuning(t *testing.T) {
+	client := NewClient(&types.GitHubConfig{Token: "token"}, logger.NewTest())
+
+	if client.httpClient.Timeout != DefaultTimeout {
+		t.Errorf("Timeout = %v, expected %v", client.httpClient.Timeout, DefaultTimeout)
+	}
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.httpClient.Transport)
+	}
+
+	if transport.MaxIdleConns != MaxIdleConns {
+		t.Errorf("MaxIdleConns = %d, expected %d", transport.MaxIdleConns, MaxIdleConns)
+	}
+	if transport.MaxIdleConnsPerHost != MaxIdleConnsPerHost {
+		t.Errorf("MaxIdleConnsPerHost = %d, expected %d", transport.MaxIdleConnsPerHost, MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != IdleConnTimeout {
+		t.Errorf("IdleConnTimeout = %v, expected %v", transport.IdleConnTimeout, IdleConnTimeout)
+	}
+	if transport.DialContext == nil || transport.Proxy == nil {
+		t.Errorf("expected dialer and proxy to be configured")
+	}
+}
+
+func TestClient_SentinelErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{"message": "error"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&types.GitHubConfig{Token: "token", APIURL: server.URL}, logger.NewTest())
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		call     func() error
+		sentinel error
+	}{
+		{
+			name:     "unauthorized token",
+			call:     func() error { return client.ValidateToken(ctx) },
+			sentinel: types.ErrTokenUnauthorized,
+		},
+		{
+			name: "missing repository",
+			call: func() error {
+				_, err := client.GetRepository(ctx, "company", "missing")
+				return err
+			},
+			sentinel: types.ErrRepositoryNotFound,
+		},
+		{
+			name: "invalid repository name",
+			call: func() error {
+				_, _, err := client.ParseRepositoryName("company-manifests")
+				return err
+			},
+			sentinel: types.ErrInvalidRepoName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("error = %v, expected errors.Is(%v)", err, tt.sentinel)
+			}
+		})
+	}
+}
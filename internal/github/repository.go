@@ -10,16 +10,66 @@ import (
 	"github.com/kevinfinalboss/privateer/pkg/types"
 )
 
+const (
+	DefaultCommitterName  = "Privateer Bot"
+	DefaultCommitterEmail = "privateer@devops.local"
+	defaultBranchName     = "privateer"
+)
+
 type RepositoryManager struct {
-	client *Client
+	client     *Client
+	committer  types.Committer
+	trailers   []string
+	commitDate time.Time
 }
 
 func NewRepositoryManager(client *Client) *RepositoryManager {
 	return &RepositoryManager{
 		client: client,
+		committer: types.Committer{
+			Name:  DefaultCommitterName,
+			Email: DefaultCommitterEmail,
+		},
 	}
 }
 
+func (rm *RepositoryManager) WithCommitter(name, email string) *RepositoryManager {
+	if name != "" {
+		rm.committer.Name = name
+	}
+	if email != "" {
+		rm.committer.Email = email
+	}
+	return rm
+}
+
+func (rm *RepositoryManager) WithTrailers(trailers []string) *RepositoryManager {
+	for _, trailer := range trailers {
+		if trailer = strings.TrimSpace(trailer); trailer != "" {
+			rm.trailers = append(rm.trailers, trailer)
+		}
+	}
+	return rm
+}
+
+func (rm *RepositoryManager) WithCommitDate(date time.Time) *RepositoryManager {
+	rm.commitDate = date
+	return rm
+}
+
+func (rm *RepositoryManager) commitMessage(message string) string {
+	var missing []string
+	for _, trailer := range rm.trailers {
+		if !strings.Contains(message, trailer) {
+			missing = append(missing, trailer)
+		}
+	}
+	if len(missing) == 0 {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + strings.Join(missing, "\n")
+}
+
 func (rm *RepositoryManager) CreateBranch(ctx context.Context, owner, repo, branchName, baseSHA string) (*types.BranchOperation, error) {
 	rm.client.logger.Debug("github_create_branch").
 		Str("owner", owner).
@@ -28,7 +78,7 @@ func (rm *RepositoryManager) CreateBranch(ctx context.Context, owner, repo, bran
 		Str("base_sha", baseSHA).
 		Send()
 
-	if exists, err := rm.branchExists(ctx, owner, repo, branchName); err != nil {
+	if exists, err := rm.BranchExists(ctx, owner, repo, branchName); err != nil {
 		return nil, err
 	} else if exists {
 		rm.client.logger.Info("github_branch_exists").
@@ -80,7 +130,27 @@ func (rm *RepositoryManager) CreateBranch(ctx context.Context, owner, repo, bran
 	}, nil
 }
 
-func (rm *RepositoryManager) branchExists(ctx context.Context, owner, repo, branchName string) (bool, error) {
+func (rm *RepositoryManager) DeleteBranch(ctx context.Context, owner, repo, branchName string) error {
+	endpoint := fmt.Sprintf("/repos/%s/%s/git/refs/heads/%s", owner, repo, branchName)
+	resp, err := rm.client.MakeRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != 204 {
+		return fmt.Errorf("falha ao remover branch: status %d", resp.StatusCode)
+	}
+
+	rm.client.logger.Info("github_branch_deleted").
+		Str("owner", owner).
+		Str("repo", repo).
+		Str("branch", branchName).
+		Send()
+
+	return nil
+}
+
+func (rm *RepositoryManager) BranchExists(ctx context.Context, owner, repo, branchName string) (bool, error) {
 	branches, err := rm.client.ListBranches(ctx, owner, repo)
 	if err != nil {
 		return false, err
@@ -126,6 +196,29 @@ func (rm *RepositoryManager) GetDefaultBranch(ctx context.Context, owner, repo s
 	return repository.DefaultBranch, defaultSHA, nil
 }
 
+func (rm *RepositoryManager) GetBaseBranch(ctx context.Context, owner, repo, baseBranch string) (string, string, error) {
+	if baseBranch == "" {
+		return rm.GetDefaultBranch(ctx, owner, repo)
+	}
+
+	branches, err := rm.client.ListBranches(ctx, owner, repo)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, branch := range branches {
+		if branch.Name == baseBranch {
+			rm.client.logger.Debug("github_base_branch").
+				Str("branch", baseBranch).
+				Str("sha", branch.Commit.SHA).
+				Send()
+			return baseBranch, branch.Commit.SHA, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("branch base %s não encontrada em %s/%s", baseBranch, owner, repo)
+}
+
 func (rm *RepositoryManager) UpdateFile(ctx context.Context, owner, repo, path, content, message, branch string) (*types.UpdateFileResponse, error) {
 	rm.client.logger.Debug("github_update_file").
 		Str("owner", owner).
@@ -143,15 +236,19 @@ func (rm *RepositoryManager) UpdateFile(ctx context.Context, owner, repo, path,
 	endpoint := fmt.Sprintf("/repos/%s/%s/contents/%s", owner, repo, path)
 
 	payload := types.UpdateFileRequest{
-		Message: message,
+		Message: rm.commitMessage(message),
 		Content: content,
 		Branch:  branch,
 		Committer: &types.Committer{
-			Name:  "Privateer Bot",
-			Email: "privateer@devops.local",
+			Name:  rm.committer.Name,
+			Email: rm.committer.Email,
 		},
 	}
 
+	if !rm.commitDate.IsZero() {
+		payload.Committer.Date = rm.commitDate.UTC().Format(time.RFC3339)
+	}
+
 	if existingSHA != "" {
 		payload.SHA = existingSHA
 	}
@@ -189,12 +286,12 @@ func (rm *RepositoryManager) ListRepositoryFiles(ctx context.Context, repoConfig
 		return nil, err
 	}
 
-	_, defaultSHA, err := rm.GetDefaultBranch(ctx, owner, repo)
+	_, baseSHA, err := rm.GetBaseBranch(ctx, owner, repo, repoConfig.PRSettings.BaseBranch)
 	if err != nil {
 		return nil, err
 	}
 
-	tree, err := rm.client.GetTree(ctx, owner, repo, defaultSHA, true)
+	tree, err := rm.client.GetTree(ctx, owner, repo, baseSHA, true)
 	if err != nil {
 		return nil, err
 	}
@@ -205,7 +302,7 @@ func (rm *RepositoryManager) ListRepositoryFiles(ctx context.Context, repoConfig
 			continue
 		}
 
-		if rm.shouldIncludeFile(entry.Path, repoConfig) {
+		if shouldIncludeFile(entry.Path, repoConfig) {
 			relevantFiles = append(relevantFiles, entry)
 		}
 	}
@@ -219,7 +316,7 @@ func (rm *RepositoryManager) ListRepositoryFiles(ctx context.Context, repoConfig
 	return relevantFiles, nil
 }
 
-func (rm *RepositoryManager) shouldIncludeFile(filePath string, repoConfig types.GitHubRepositoryConfig) bool {
+func shouldIncludeFile(filePath string, repoConfig types.GitHubRepositoryConfig) bool {
 	for _, excludedPath := range repoConfig.ExcludedPaths {
 		if strings.HasPrefix(filePath, excludedPath) {
 			return false
@@ -235,7 +332,7 @@ func (rm *RepositoryManager) shouldIncludeFile(filePath string, repoConfig types
 			return true
 		}
 
-		if rm.matchesPattern(filePath, includePath) {
+		if matchesPattern(filePath, includePath) {
 			return true
 		}
 	}
@@ -243,7 +340,7 @@ func (rm *RepositoryManager) shouldIncludeFile(filePath string, repoConfig types
 	return false
 }
 
-func (rm *RepositoryManager) matchesPattern(filePath, pattern string) bool {
+func matchesPattern(filePath, pattern string) bool {
 	if strings.Contains(pattern, "*") {
 		parts := strings.Split(pattern, "*")
 		if len(parts) == 2 {
@@ -253,7 +350,7 @@ func (rm *RepositoryManager) matchesPattern(filePath, pattern string) bool {
 	return false
 }
 
-func (rm *RepositoryManager) ValidateRepositoryAccess(ctx context.Context, repoConfig types.GitHubRepositoryConfig) error {
+func (rm *RepositoryManager) ValidateRepositoryAccess(ctx context.Context, repoConfig types.GitHubRepositoryConfig, requirePush bool) error {
 	owner, repo, err := parseRepositoryName(repoConfig.Name)
 	if err != nil {
 		return err
@@ -268,6 +365,14 @@ func (rm *RepositoryManager) ValidateRepositoryAccess(ctx context.Context, repoC
 		return fmt.Errorf("sem permissão de leitura no repositório %s", repoConfig.Name)
 	}
 
+	if !permissions.Push && requirePush {
+		rm.client.logger.Error("github_push_permission_required").
+			Str("repo", repoConfig.Name).
+			Str("message", "Sem permissão de escrita e gitops.require_push está habilitado").
+			Send()
+		return fmt.Errorf("sem permissão de escrita no repositório %s (gitops.require_push habilitado): conceda acesso de push ao token", repoConfig.Name)
+	}
+
 	if !permissions.Push {
 		rm.client.logger.Warn("github_no_push_permission").
 			Str("repo", repoConfig.Name).
@@ -295,7 +400,42 @@ func (rm *RepositoryManager) GenerateBranchName(prefix, imageInfo string) string
 
 	timestamp := time.Now().Format("20060102-150405")
 
-	return fmt.Sprintf("%s%s-%s", prefix, cleanImage, timestamp)
+	return SanitizeBranchName(fmt.Sprintf("%s%s-%s", prefix, cleanImage, timestamp))
+}
+
+func SanitizeBranchName(name string) string {
+	var builder strings.Builder
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			builder.WriteRune('-')
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	sanitized := strings.ReplaceAll(builder.String(), "@{", "-")
+	for strings.Contains(sanitized, "..") {
+		sanitized = strings.ReplaceAll(sanitized, "..", ".")
+	}
+
+	var components []string
+	for _, component := range strings.Split(sanitized, "/") {
+		component = strings.TrimLeft(component, ".")
+		if strings.HasSuffix(component, ".lock") {
+			component = strings.TrimSuffix(component, ".lock") + "-lock"
+		}
+		if component != "" {
+			components = append(components, component)
+		}
+	}
+
+	sanitized = strings.TrimLeft(strings.Join(components, "/"), "-")
+	sanitized = strings.TrimRight(sanitized, "./")
+	if sanitized == "" || sanitized == "@" {
+		return defaultBranchName
+	}
+
+	return sanitized
 }
 
 func (rm *RepositoryManager) GetFilesByExtension(files []types.TreeEntry, extensions []string) []types.TreeEntry {
@@ -316,7 +456,7 @@ func (rm *RepositoryManager) GetFilesByExtension(files []types.TreeEntry, extens
 func parseRepositoryName(repoName string) (owner, repo string, err error) {
 	parts := strings.Split(repoName, "/")
 	if len(parts) != 2 {
-		return "", "", fmt.Errorf("formato de repositório inválido: %s", repoName)
+		return "", "", fmt.Errorf("%w: %s", types.ErrInvalidRepoName, repoName)
 	}
 	return parts[0], parts[1], nil
 }
@@ -0,0 +1,135 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestRepositoryManager_UpdateFile_Committer(t *testing.T) {
+	tests := []struct {
+		name          string
+		committerName string
+		email         string
+		expected      types.Committer
+	}{
+		{
+			name:     "defaults when not configured",
+			expected: types.Committer{Name: DefaultCommitterName, Email: DefaultCommitterEmail},
+		},
+		{
+			name:          "configured committer",
+			committerName: "Platform Bot",
+			email:         "platform-bot@company.com",
+			expected:      types.Committer{Name: "Platform Bot", Email: "platform-bot@company.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received types.UpdateFileRequest
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					http.NotFound(w, r)
+					return
+				}
+				if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+					t.Errorf("invalid payload: %v", err)
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"commit":{"sha":"abc123"}}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(&types.GitHubConfig{Token: "token", APIURL: server.URL}, logger.NewTest())
+			repoManager := NewRepositoryManager(client).WithCommitter(tt.committerName, tt.email)
+
+			if _, err := repoManager.UpdateFile(context.Background(), "company", "manifests", "values.yaml", "Y29udGVudA==", "migrate", "privateer/migrate"); err != nil {
+				t.Fatalf("UpdateFile() unexpected error: %v", err)
+			}
+
+			if received.Committer == nil || *received.Committer != tt.expected {
+				t.Errorf("committer = %+v, expected %+v", received.Committer, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepositoryManager_UpdateFile_TrailersAndDate(t *testing.T) {
+	var received types.UpdateFileRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"commit":{"sha":"abc123"}}`))
+	}))
+	defer server.Close()
+
+	runStartedAt := time.Date(2026, 3, 10, 11, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+	client := NewClient(&types.GitHubConfig{Token: "token", APIURL: server.URL}, logger.NewTest())
+	repoManager := NewRepositoryManager(client).
+		WithTrailers([]string{"Co-authored-by: Platform Team <platform@company.com>", " ", "Signed-off-by: Privateer Bot <privateer@devops.local>"}).
+		WithCommitDate(runStartedAt)
+
+	if _, err := repoManager.UpdateFile(context.Background(), "company", "manifests", "values.yaml", "Y29udGVudA==", "migrate nginx\n", "privateer/migrate"); err != nil {
+		t.Fatalf("UpdateFile() unexpected error: %v", err)
+	}
+
+	expectedMessage := "migrate nginx\n\nCo-authored-by: Platform Team <platform@company.com>\nSigned-off-by: Privateer Bot <privateer@devops.local>"
+	if received.Message != expectedMessage {
+		t.Errorf("message = %q, expected %q", received.Message, expectedMessage)
+	}
+	if received.Committer == nil || received.Committer.Date != "2026-03-10T14:30:00Z" {
+		t.Errorf("committer = %+v, expected date 2026-03-10T14:30:00Z", received.Committer)
+	}
+}
+
+func TestSanitizeBranchName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"privateer/migrate-3-images-20240101-120000", "privateer/migrate-3-images-20240101-120000"},
+		{"privateer migrate/nginx~1^2:latest?*[x]", "privateer-migrate/nginx-1-2-latest---x]"},
+		{"privateer//migrate..images", "privateer/migrate.images"},
+		{".hidden/branch.lock/", "hidden/branch-lock"},
+		{"-privateer/@{upstream}", "privateer/-upstream}"},
+		{"release\\candidate\t1.", "release-candidate-1"},
+		{"@", "privateer"},
+		{"...", "privateer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := SanitizeBranchName(tt.name); result != tt.expected {
+				t.Errorf("SanitizeBranchName(%q) = %q, expected %q", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepositoryManager_GenerateBranchName_InvalidPrefix(t *testing.T) {
+	repoManager := NewRepositoryManager(NewClient(&types.GitHubConfig{}, logger.NewTest()))
+
+	branch := repoManager.GenerateBranchName("privateer migrate..", "2-images")
+	if !strings.HasPrefix(branch, "privateer-migrate.2-images-") {
+		t.Errorf("GenerateBranchName() = %q", branch)
+	}
+	if strings.ContainsAny(branch, " ~^:?*[\\") || strings.Contains(branch, "..") {
+		t.Errorf("GenerateBranchName() produced an invalid ref: %q", branch)
+	}
+}
@@ -0,0 +1,250 @@
+package github
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+type gitRunner func(ctx context.Context, env []string, args ...string) error
+
+type SSHRepositoryReader struct {
+	logger  *logger.Logger
+	workDir string
+	runGit  gitRunner
+}
+
+type SSHCheckout struct {
+	Dir        string
+	repoConfig types.GitHubRepositoryConfig
+}
+
+func NewSSHRepositoryReader(logger *logger.Logger) *SSHRepositoryReader {
+	return &SSHRepositoryReader{
+		logger:  logger,
+		workDir: os.TempDir(),
+		runGit:  runGitCommand,
+	}
+}
+
+func UsesSSH(repoConfig types.GitHubRepositoryConfig) bool {
+	return repoConfig.SSH.PrivateKeyPath != ""
+}
+
+func (r *SSHRepositoryReader) Clone(ctx context.Context, repoConfig types.GitHubRepositoryConfig) (*SSHCheckout, error) {
+	keyPath, err := loadSSHPrivateKey(repoConfig.SSH.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	knownHostsPath, err := resolveKnownHosts(repoConfig.SSH)
+	if err != nil {
+		return nil, err
+	}
+
+	cloneURL := sshCloneURL(repoConfig)
+	if knownHostsPath == "" {
+		r.logger.Warn("github_ssh_host_key_not_verified").
+			Str("repo", repoConfig.Name).
+			Str("url", cloneURL).
+			Str("message", "accept_new_host_keys habilitado: a chave do host será aceita no primeiro acesso").
+			Send()
+	}
+
+	dir, err := os.MkdirTemp(r.workDir, "privateer-ssh-*")
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar diretório temporário: %w", err)
+	}
+
+	args := []string{"clone", "--depth", "1"}
+	if repoConfig.PRSettings.BaseBranch != "" {
+		args = append(args, "--branch", repoConfig.PRSettings.BaseBranch)
+	}
+	args = append(args, "--", cloneURL, dir)
+
+	env := []string{
+		"GIT_SSH_COMMAND=" + sshCommand(keyPath, knownHostsPath),
+		"GIT_TERMINAL_PROMPT=0",
+	}
+
+	r.logger.Debug("github_ssh_clone").
+		Str("repo", repoConfig.Name).
+		Str("url", cloneURL).
+		Str("key", keyPath).
+		Send()
+
+	if err := r.runGit(ctx, env, args...); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("falha ao clonar %s via SSH: %w", cloneURL, err)
+	}
+
+	return &SSHCheckout{Dir: dir, repoConfig: repoConfig}, nil
+}
+
+func (c *SSHCheckout) ListFiles() ([]types.TreeEntry, error) {
+	var files []types.TreeEntry
+
+	err := filepath.WalkDir(c.Dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(c.Dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !shouldIncludeFile(rel, c.repoConfig) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, types.TreeEntry{
+			Path: rel,
+			Type: "blob",
+			Size: len(content),
+			SHA:  gitBlobSHA(content),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar arquivos do clone SSH: %w", err)
+	}
+
+	return files, nil
+}
+
+func (c *SSHCheckout) ReadFile(filePath string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(c.Dir, filepath.FromSlash(filePath)))
+	if err != nil {
+		return "", fmt.Errorf("falha ao ler arquivo %s: %w", filePath, err)
+	}
+	return string(content), nil
+}
+
+func (c *SSHCheckout) Close() error {
+	return os.RemoveAll(c.Dir)
+}
+
+func expandHomePath(path string) (string, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("falha ao resolver diretório home: %w", err)
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	return filepath.Abs(path)
+}
+
+func resolveKnownHosts(sshConfig types.SSHConfig) (string, error) {
+	if sshConfig.KnownHostsPath == "" {
+		if sshConfig.AcceptNewHostKeys {
+			return "", nil
+		}
+		return "", fmt.Errorf("ssh.known_hosts_path não configurado: informe um arquivo known_hosts ou habilite ssh.accept_new_host_keys explicitamente")
+	}
+
+	absPath, err := expandHomePath(sshConfig.KnownHostsPath)
+	if err != nil {
+		return "", fmt.Errorf("falha ao resolver known_hosts %s: %w", sshConfig.KnownHostsPath, err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("falha ao acessar known_hosts %s: %w", absPath, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("known_hosts %s não é um arquivo regular", absPath)
+	}
+	return absPath, nil
+}
+
+func loadSSHPrivateKey(path string) (string, error) {
+	absPath, err := expandHomePath(path)
+	if err != nil {
+		return "", fmt.Errorf("falha ao resolver chave SSH %s: %w", path, err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("falha ao acessar chave SSH %s: %w", absPath, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("chave SSH %s não é um arquivo regular", absPath)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("chave SSH %s possui permissões abertas demais (%o), use chmod 600", absPath, info.Mode().Perm())
+	}
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return "", fmt.Errorf("falha ao ler chave SSH %s: %w", absPath, err)
+	}
+	if !strings.Contains(string(content), "PRIVATE KEY-----") {
+		return "", fmt.Errorf("arquivo %s não contém uma chave SSH privada", absPath)
+	}
+
+	return absPath, nil
+}
+
+func sshCloneURL(repoConfig types.GitHubRepositoryConfig) string {
+	if repoConfig.SSH.URL != "" {
+		return repoConfig.SSH.URL
+	}
+	return fmt.Sprintf("git@github.com:%s.git", repoConfig.Name)
+}
+
+func sshCommand(keyPath, knownHostsPath string) string {
+	command := []string{"ssh", "-i", shellQuote(keyPath), "-o", "IdentitiesOnly=yes", "-o", "BatchMode=yes"}
+	if knownHostsPath != "" {
+		command = append(command, "-o", shellQuote("UserKnownHostsFile="+knownHostsPath), "-o", "StrictHostKeyChecking=yes")
+	} else {
+		command = append(command, "-o", "StrictHostKeyChecking=accept-new")
+	}
+	return strings.Join(command, " ")
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func gitBlobSHA(content []byte) string {
+	hash := sha1.New()
+	fmt.Fprintf(hash, "blob %d\x00", len(content))
+	hash.Write(content)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func runGitCommand(ctx context.Context, env []string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), env...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
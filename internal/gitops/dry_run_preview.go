@@ -0,0 +1,67 @@
+package gitops
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kevinfinalboss/privateer/internal/github"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func (e *Engine) previewRepositoryChanges(ctx context.Context, repoConfig types.GitHubRepositoryConfig, result *types.GitOpsResult, validatedReplacements []types.ImageReplacement) error {
+	owner, repo, err := e.parseRepositoryName(repoConfig.Name)
+	if err != nil {
+		return err
+	}
+
+	e.logger.Info("previewing_validated_repository_changes").
+		Str("repository", repoConfig.Name).
+		Str("base_branch", repoConfig.PRSettings.BaseBranch).
+		Int("validated_replacements", len(validatedReplacements)).
+		Send()
+
+	fileReplacements := e.groupValidatedReplacementsByFile(validatedReplacements)
+	filePaths := make([]string, 0, len(fileReplacements))
+	for filePath := range fileReplacements {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	var imagesChanged []types.ImageReplacement
+	for _, filePath := range filePaths {
+		originalContent, modifiedContent, actualReplacements, err := e.renderValidatedFile(ctx, owner, repo, repoConfig.PRSettings.BaseBranch, filePath, fileReplacements[filePath])
+		if err != nil {
+			return err
+		}
+
+		if modifiedContent == originalContent {
+			e.logger.Warn("dry_run_preview_unchanged").
+				Str("repository", repoConfig.Name).
+				Str("file", filePath).
+				Send()
+			continue
+		}
+
+		result.FilesChanged = append(result.FilesChanged, types.FileChange{
+			FilePath:       filePath,
+			FileType:       e.detectFileType(filePath),
+			Changes:        actualReplacements,
+			LinesChanged:   len(actualReplacements),
+			Validated:      true,
+			BackupContent:  originalContent,
+			PreviewContent: modifiedContent,
+		})
+		imagesChanged = append(imagesChanged, actualReplacements...)
+
+		e.logger.Info("dry_run_preview_file_change").
+			Str("repository", repoConfig.Name).
+			Str("file", filePath).
+			Int("validated_changes", len(actualReplacements)).
+			Send()
+	}
+
+	result.ImagesChanged = imagesChanged
+	result.Branch = github.SanitizeBranchName(e.config.GitOps.BranchPrefix + "simulation-validated")
+
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/internal/registry"
 	"github.com/kevinfinalboss/privateer/internal/scanner"
+	"github.com/kevinfinalboss/privateer/internal/state"
 	"github.com/kevinfinalboss/privateer/internal/webhook"
 	"github.com/kevinfinalboss/privateer/pkg/types"
 )
@@ -26,7 +27,13 @@ type Engine struct {
 	replacer        *ImageReplacer
 	prManager       *PullRequestManager
 	tagResolver     *TagResolver
-	discordWebhook  *webhook.DiscordWebhook
+	discordWebhook  *webhook.DiscordNotifier
+	patchesDir      string
+	state           *state.Store
+	prSlots         *prSlotQueue
+	runStartedAt    time.Time
+	deferReporting  bool
+	scanOnly        bool
 }
 
 func NewEngine(githubClient *github.Client, registryManager *registry.Manager, logger *logger.Logger, config *types.Config) *Engine {
@@ -44,18 +51,39 @@ func NewEngine(githubClient *github.Client, registryManager *registry.Manager, l
 		replacer:        replacer,
 		prManager:       prManager,
 		tagResolver:     tagResolver,
+		patchesDir:      defaultPatchesDir(),
+		runStartedAt:    time.Now(),
+		prSlots:         newPRSlotQueue(config.GitOps.MaxPRs, nil),
 	}
 
-	if config.Webhooks.Discord.Enabled && config.Webhooks.Discord.URL != "" {
-		engine.discordWebhook = webhook.NewDiscordWebhook(config.Webhooks.Discord, logger)
+	if notifier := webhook.NewDiscordNotifier(config.Webhooks, logger); notifier != nil {
+		engine.discordWebhook = notifier
 		logger.Info("discord_webhook_enabled_gitops").
 			Str("url", maskWebhookURL(config.Webhooks.Discord.URL)).
+			Int("targets", notifier.TargetCount()).
 			Send()
 	}
 
 	return engine
 }
 
+func (e *Engine) DeferReporting() *Engine {
+	e.deferReporting = true
+	return e
+}
+
+func (e *Engine) UseState(store *state.Store) *Engine {
+	e.state = store
+	e.fileScanner.UseState(store)
+	return e
+}
+
+func (e *Engine) ScanOnly() *Engine {
+	e.scanOnly = true
+	e.discordWebhook = nil
+	return e
+}
+
 func (e *Engine) MigrateRepositories(ctx context.Context, publicImages []*types.ImageInfo) (*types.GitOpsSummary, error) {
 	startTime := time.Now()
 
@@ -90,7 +118,8 @@ func (e *Engine) MigrateRepositories(ctx context.Context, publicImages []*types.
 	}
 
 	enabledRepos := e.getEnabledRepositories()
-	if len(enabledRepos) == 0 {
+	manualMapping := e.config.GitOps.Strategy == "manual_mapping" && len(e.config.GitOps.MappingRules) > 0
+	if len(enabledRepos) == 0 && !manualMapping {
 		err := fmt.Errorf("nenhum repositório GitHub habilitado encontrado")
 		if e.discordWebhook != nil {
 			e.discordWebhook.SendError(ctx, err.Error(), "Seleção de Repositórios")
@@ -112,7 +141,9 @@ func (e *Engine) MigrateRepositories(ctx context.Context, publicImages []*types.
 			Send()
 	}
 
-	validatedImageMap, err := e.buildAndValidatePrivateImageMap(ctx, publicImages)
+	migratableImages := e.excludeNoMigrateImages(publicImages)
+
+	validatedImageMap, err := e.buildAndValidatePrivateImageMap(ctx, migratableImages)
 	if err != nil {
 		validationErr := fmt.Errorf("falha ao validar imagens nos registries privados: %w", err)
 		if e.discordWebhook != nil {
@@ -121,7 +152,7 @@ func (e *Engine) MigrateRepositories(ctx context.Context, publicImages []*types.
 		return nil, validationErr
 	}
 
-	availableImages := e.filterValidatedImages(publicImages, validatedImageMap)
+	availableImages := e.filterValidatedImages(migratableImages, validatedImageMap)
 	if len(availableImages) == 0 {
 		e.logger.Info("no_validated_images_available").
 			Str("message", "Nenhuma imagem pública validada foi encontrada nos registries privados").
@@ -132,12 +163,7 @@ func (e *Engine) MigrateRepositories(ctx context.Context, publicImages []*types.
 			ProcessingTime:    time.Since(startTime).String(),
 		}
 
-		if e.discordWebhook != nil {
-			err := e.sendGitOpsComplete(ctx, summary, e.config.Settings.DryRun)
-			if err != nil {
-				e.logger.Warn("discord_webhook_failed").Err(err).Send()
-			}
-		}
+		e.notifyComplete(ctx, summary)
 
 		return summary, nil
 	}
@@ -147,54 +173,74 @@ func (e *Engine) MigrateRepositories(ctx context.Context, publicImages []*types.
 		Int("total_public", len(publicImages)).
 		Send()
 
+	targets := sortRepositoryTargets(e.resolveRepositoryTargets(enabledRepos, availableImages))
+	e.prSlots = newPRSlotQueue(e.config.GitOps.MaxPRs, targets)
+
+	e.logger.Info("repository_targets_resolved").
+		Str("strategy", e.config.GitOps.Strategy).
+		Int("targets", len(targets)).
+		Send()
+
 	summary := &types.GitOpsSummary{
-		TotalRepositories: len(enabledRepos),
+		TotalRepositories: len(targets),
 		Results:           make([]*types.GitOpsResult, 0),
 		ProcessingTime:    time.Since(startTime).String(),
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	semaphore := make(chan struct{}, e.config.Settings.Concurrency)
+	results := e.processTargets(ctx, targets, validatedImageMap)
 
-	for _, repoConfig := range enabledRepos {
-		wg.Add(1)
-		go func(repo types.GitHubRepositoryConfig) {
-			defer wg.Done()
-
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+	e.aggregateResults(summary, targets, results)
 
-			result := e.processRepository(ctx, repo, availableImages, validatedImageMap)
-
-			mu.Lock()
-			summary.Results = append(summary.Results, result)
-			e.updateSummaryCounters(summary, result)
-			mu.Unlock()
-		}(repoConfig)
+	if e.config.GitOps.TrackingIssue.Enabled && !e.config.Settings.DryRun && !e.scanOnly {
+		e.createTrackingIssue(ctx, summary)
 	}
 
-	wg.Wait()
-
 	summary.ProcessingTime = time.Since(startTime).String()
 
 	e.logger.Info("gitops_migration_completed").
 		Int("repositories_processed", summary.ProcessedRepositories).
 		Int("successful_prs", summary.SuccessfulPRs).
 		Int("failed_operations", summary.FailedOperations).
+		Int("deferred_repositories", summary.DeferredRepositories).
 		Str("processing_time", summary.ProcessingTime).
 		Send()
 
-	if e.discordWebhook != nil {
-		err := e.sendGitOpsComplete(ctx, summary, e.config.Settings.DryRun)
-		if err != nil {
-			e.logger.Warn("discord_webhook_failed").Err(err).Send()
-		}
-	}
+	e.notifyComplete(ctx, summary)
 
 	return summary, nil
 }
 
+func (e *Engine) processTargets(ctx context.Context, targets []repositoryTarget, validatedImageMap map[string]string) []*types.GitOpsResult {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, e.config.Settings.Concurrency)
+	results := make([]*types.GitOpsResult, len(targets))
+
+	for i, target := range targets {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, target repositoryTarget) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			defer e.prSlots.finish(target.config.Name)
+
+			results[i] = e.processRepository(ctx, target.config, target.images, validatedImageMap)
+		}(i, target)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (e *Engine) notifyComplete(ctx context.Context, summary *types.GitOpsSummary) {
+	if e.discordWebhook == nil || e.deferReporting {
+		return
+	}
+
+	if err := e.sendGitOpsComplete(ctx, summary, e.config.Settings.DryRun); err != nil {
+		e.logger.Warn("discord_webhook_failed").Err(err).Send()
+	}
+}
+
 func (e *Engine) sendGitOpsStart(ctx context.Context, totalImages int, repositories []types.GitHubRepositoryConfig, dryRun bool) error {
 	operation := "🚀 GITOPS INICIADO"
 	color := 0x00ff00
@@ -242,12 +288,10 @@ func (e *Engine) sendGitOpsStart(ctx context.Context, totalImages int, repositor
 	}
 
 	message := types.DiscordMessage{
-		Username:  e.discordWebhook.GetName(),
-		AvatarURL: e.discordWebhook.GetAvatar(),
-		Embeds:    []types.DiscordEmbed{embed},
+		Embeds: []types.DiscordEmbed{embed},
 	}
 
-	return e.discordWebhook.SendMessage(ctx, message)
+	return e.discordWebhook.SendMessage(ctx, "", message)
 }
 
 func (e *Engine) sendGitOpsComplete(ctx context.Context, summary *types.GitOpsSummary, dryRun bool) error {
@@ -304,13 +348,16 @@ func (e *Engine) sendGitOpsComplete(ctx context.Context, summary *types.GitOpsSu
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
+	outcome := types.NotifyOnSuccess
+	if summary.FailedOperations > 0 {
+		outcome = types.NotifyOnFailure
+	}
+
 	message := types.DiscordMessage{
-		Username:  e.discordWebhook.GetName(),
-		AvatarURL: e.discordWebhook.GetAvatar(),
-		Embeds:    []types.DiscordEmbed{embed},
+		Embeds: []types.DiscordEmbed{embed},
 	}
 
-	return e.discordWebhook.SendMessage(ctx, message)
+	return e.discordWebhook.SendMessage(ctx, outcome, message)
 }
 
 func (e *Engine) getSuccessfulRepos(results []*types.GitOpsResult, limit int) string {
@@ -372,7 +419,7 @@ func (e *Engine) buildAndValidatePrivateImageMap(ctx context.Context, publicImag
 		Int("public_images", len(publicImages)).
 		Send()
 
-	validatedImageMap, err := e.registryManager.ValidateImagesBatch(ctx, publicImages, e.config)
+	validatedImageMap, validationErrors, err := e.registryManager.ValidateImagesBatch(ctx, publicImages, e.config)
 	if err != nil {
 		return nil, fmt.Errorf("falha na validação em lote: %w", err)
 	}
@@ -380,9 +427,18 @@ func (e *Engine) buildAndValidatePrivateImageMap(ctx context.Context, publicImag
 	e.logger.Info("image_validation_completed").
 		Int("validated_images", len(validatedImageMap)).
 		Int("total_public", len(publicImages)).
-		Int("missing_images", len(publicImages)-len(validatedImageMap)).
+		Int("missing_images", len(publicImages)-len(validatedImageMap)-len(validationErrors)).
+		Int("unverified_images", len(validationErrors)).
 		Send()
 
+	for publicImage, validationErr := range validationErrors {
+		e.logger.Warn("image_validation_unverified").
+			Str("public", publicImage).
+			Str("message", "Não foi possível verificar a imagem nos registries privados - ela não será substituída").
+			Err(validationErr).
+			Send()
+	}
+
 	if len(validatedImageMap) == 0 {
 		e.logger.Warn("no_validated_images_found").
 			Str("message", "Nenhuma imagem pública foi validada nos registries privados").
@@ -400,6 +456,21 @@ func (e *Engine) buildAndValidatePrivateImageMap(ctx context.Context, publicImag
 	return validatedImageMap, nil
 }
 
+func (e *Engine) excludeNoMigrateImages(publicImages []*types.ImageInfo) []*types.ImageInfo {
+	migratable := make([]*types.ImageInfo, 0, len(publicImages))
+	for _, image := range publicImages {
+		if image.NoMigrate {
+			e.logger.Info("image_no_migrate_skipped").
+				Str("image", image.Image).
+				Str("namespace", image.Namespace).
+				Send()
+			continue
+		}
+		migratable = append(migratable, image)
+	}
+	return migratable
+}
+
 func (e *Engine) filterValidatedImages(publicImages []*types.ImageInfo, validatedImageMap map[string]string) []*types.ImageInfo {
 	var validated []*types.ImageInfo
 
@@ -446,8 +517,13 @@ func (e *Engine) processRepository(ctx context.Context, repoConfig types.GitHubR
 		ProcessingTime: "",
 	}
 
+	if err := ctx.Err(); err != nil {
+		result.Error = fmt.Errorf("processamento cancelado: %w", err)
+		return result
+	}
+
 	repoManager := github.NewRepositoryManager(e.githubClient)
-	if err := repoManager.ValidateRepositoryAccess(ctx, repoConfig); err != nil {
+	if err := repoManager.ValidateRepositoryAccess(ctx, repoConfig, e.config.GitOps.RequirePush && !e.scanOnly); err != nil {
 		result.Error = fmt.Errorf("falha na validação do repositório: %w", err)
 		return result
 	}
@@ -495,6 +571,10 @@ func (e *Engine) processRepository(ctx context.Context, repoConfig types.GitHubR
 		return result
 	}
 
+	if e.config.GitOps.PinDigest {
+		validatedReplacements = e.tagResolver.PinDigests(ctx, validatedReplacements)
+	}
+
 	e.logger.Info("validated_replacements_generated").
 		Str("repository", repoConfig.Name).
 		Int("validated_replacements", len(validatedReplacements)).
@@ -511,44 +591,160 @@ func (e *Engine) processRepository(ctx context.Context, repoConfig types.GitHubR
 			Send()
 	}
 
-	if e.config.Settings.DryRun {
+	if e.scanOnly {
 		result = e.simulateRepositoryChanges(result, validatedReplacements)
 		result.Success = true
 		result.ProcessingTime = time.Since(startTime).String()
 		return result
 	}
 
-	branchName := repoManager.GenerateBranchName(e.config.GitOps.BranchPrefix, fmt.Sprintf("%d-images", len(validatedReplacements)))
+	if e.config.Settings.DryRun {
+		if e.config.GitOps.DryRunPreview {
+			if err := e.previewRepositoryChanges(ctx, repoConfig, result, validatedReplacements); err != nil {
+				result.Error = err
+				return result
+			}
+		} else {
+			result = e.simulateRepositoryChanges(result, validatedReplacements)
+		}
+		e.exportDryRunPatches(ctx, repoConfig, validatedReplacements)
+		result.Success = true
+		result.ProcessingTime = time.Since(startTime).String()
+		return result
+	}
 
-	owner, repo, err := e.parseRepositoryName(repoConfig.Name)
-	if err != nil {
+	if err := e.publishRepositoryChanges(ctx, repoManager, repoConfig, result, validatedReplacements); err != nil {
 		result.Error = err
 		return result
 	}
 
-	_, defaultSHA, err := repoManager.GetDefaultBranch(ctx, owner, repo)
+	result.Success = true
+	result.ProcessingTime = time.Since(startTime).String()
+
+	e.logger.Info("repository_processed_successfully_with_validation").
+		Str("repository", repoConfig.Name).
+		Str("branch", result.Branch).
+		Int("files_changed", len(result.FilesChanged)).
+		Int("validated_images_replaced", len(validatedReplacements)).
+		Send()
+
+	return result
+}
+
+func (e *Engine) publishRepositoryChanges(ctx context.Context, repoManager *github.RepositoryManager, repoConfig types.GitHubRepositoryConfig, result *types.GitOpsResult, validatedReplacements []types.ImageReplacement) (err error) {
+	owner, repo, err := e.parseRepositoryName(repoConfig.Name)
 	if err != nil {
-		result.Error = fmt.Errorf("falha ao obter branch padrão: %w", err)
-		return result
+		return err
+	}
+
+	if repoConfig.BranchStrategy == types.BranchStrategyUseMain {
+		return e.commitToBaseBranch(ctx, repoManager, owner, repo, repoConfig, result, validatedReplacements)
+	}
+
+	var prs *prState
+	if e.config.GitOps.Incremental {
+		prs = loadPRState(e.state, repoConfig.Name)
+		defer e.savePRState(repoConfig.Name, prs)
+
+		validatedReplacements = e.skipPendingReplacements(ctx, owner, repo, prs, validatedReplacements)
+		if len(validatedReplacements) == 0 {
+			e.logger.Info("no_new_replacements_since_last_run").
+				Str("repository", repoConfig.Name).
+				Send()
+			return nil
+		}
 	}
 
-	_, err = repoManager.CreateBranch(ctx, owner, repo, branchName, defaultSHA)
+	baseBranch, baseSHA, err := repoManager.GetBaseBranch(ctx, owner, repo, repoConfig.PRSettings.BaseBranch)
 	if err != nil {
-		result.Error = fmt.Errorf("falha ao criar branch: %w", err)
-		return result
+		return fmt.Errorf("falha ao obter branch base: %w", err)
+	}
+
+	// A supplied branch that already exists carries an earlier change set, so
+	// compare against it and reuse its open pull request instead of opening one.
+	changesRef := baseBranch
+	var existingPR *types.PullRequestInfo
+	if suppliedBranch := e.config.GitOps.Branch; suppliedBranch != "" {
+		exists, err := repoManager.BranchExists(ctx, owner, repo, suppliedBranch)
+		if err != nil {
+			return fmt.Errorf("falha ao verificar branch %s: %w", suppliedBranch, err)
+		}
+		if exists {
+			changesRef = suppliedBranch
+			if e.config.GitOps.AutoPR {
+				existingPR, err = e.prManager.FindOpenPullRequest(ctx, owner, repo, suppliedBranch)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	changed, err := e.hasNetChanges(ctx, owner, repo, changesRef, validatedReplacements)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		e.logger.Info("repository_already_up_to_date").
+			Str("repository", repoConfig.Name).
+			Str("branch", changesRef).
+			Int("replacements", len(validatedReplacements)).
+			Send()
+		return nil
+	}
+
+	if existingPR != nil {
+		e.prSlots.finish(repoConfig.Name)
+	} else if !e.prSlots.reserve(repoConfig.Name) {
+		e.logger.Info("repository_deferred_max_prs").
+			Str("repository", repoConfig.Name).
+			Int("max_prs", e.config.GitOps.MaxPRs).
+			Int("pending_replacements", len(validatedReplacements)).
+			Send()
+		result.Deferred = true
+		return nil
+	}
+	defer func() {
+		if err != nil && existingPR == nil {
+			e.prSlots.release()
+		}
+	}()
+
+	branchName := e.config.GitOps.Branch
+	if branchName == "" {
+		branchName = repoManager.GenerateBranchName(e.config.GitOps.BranchPrefix, fmt.Sprintf("%d-images", len(validatedReplacements)))
+	}
+
+	branch, err := repoManager.CreateBranch(ctx, owner, repo, branchName, baseSHA)
+	if err != nil {
+		return fmt.Errorf("falha ao criar branch: %w", err)
 	}
 
 	result.Branch = branchName
 
-	fileChanges, err := e.applyValidatedReplacements(ctx, owner, repo, branchName, validatedReplacements)
+	fileChanges, err := e.applyValidatedReplacements(ctx, owner, repo, branchName, repoConfig.PRSettings.CommitPrefix, validatedReplacements)
 	if err != nil {
-		result.Error = fmt.Errorf("falha ao aplicar mudanças validadas: %w", err)
-		return result
+		e.cleanupFailedBranch(ctx, repoManager, owner, repo, branch, result)
+		return fmt.Errorf("falha ao aplicar mudanças validadas: %w", err)
 	}
 
 	result.FilesChanged = fileChanges
 	result.ImagesChanged = validatedReplacements
 
+	if existingPR != nil {
+		e.logger.Info("pull_request_reused").
+			Str("repository", repoConfig.Name).
+			Str("branch", branchName).
+			Int("pr_number", existingPR.Number).
+			Send()
+		result.PullRequest = existingPR
+
+		if prs != nil {
+			prs.record(validatedReplacements, existingPR)
+		}
+		return nil
+	}
+
 	if e.config.GitOps.AutoPR {
 		prInfo, err := e.prManager.CreatePullRequest(ctx, repoConfig, result)
 		if err != nil {
@@ -556,23 +752,84 @@ func (e *Engine) processRepository(ctx context.Context, repoConfig types.GitHubR
 				Str("repository", repoConfig.Name).
 				Err(err).
 				Send()
-			result.Error = fmt.Errorf("falha ao criar pull request: %w", err)
-			return result
+			e.cleanupFailedBranch(ctx, repoManager, owner, repo, branch, result)
+			return fmt.Errorf("falha ao criar pull request: %w", err)
 		}
 		result.PullRequest = prInfo
+
+		if prs != nil {
+			prs.record(validatedReplacements, prInfo)
+		}
 	}
 
-	result.Success = true
-	result.ProcessingTime = time.Since(startTime).String()
+	return nil
+}
 
-	e.logger.Info("repository_processed_successfully_with_validation").
+func (e *Engine) commitToBaseBranch(ctx context.Context, repoManager *github.RepositoryManager, owner, repo string, repoConfig types.GitHubRepositoryConfig, result *types.GitOpsResult, validatedReplacements []types.ImageReplacement) error {
+	permissions, err := e.githubClient.CheckPermissions(ctx, owner, repo)
+	if err != nil {
+		return fmt.Errorf("falha ao verificar permissões do repositório %s: %w", repoConfig.Name, err)
+	}
+	if !permissions.Push {
+		return fmt.Errorf("branch_strategy use_main requer permissão de escrita no repositório %s", repoConfig.Name)
+	}
+
+	baseBranch, _, err := repoManager.GetBaseBranch(ctx, owner, repo, repoConfig.PRSettings.BaseBranch)
+	if err != nil {
+		return fmt.Errorf("falha ao obter branch base: %w", err)
+	}
+
+	e.logger.Info("gitops_committing_to_base_branch").
 		Str("repository", repoConfig.Name).
-		Str("branch", branchName).
-		Int("files_changed", len(fileChanges)).
-		Int("validated_images_replaced", len(validatedReplacements)).
+		Str("branch", baseBranch).
+		Int("replacements", len(validatedReplacements)).
 		Send()
 
-	return result
+	result.Branch = baseBranch
+
+	fileChanges, err := e.applyValidatedReplacements(ctx, owner, repo, baseBranch, repoConfig.PRSettings.CommitPrefix, validatedReplacements)
+	if err != nil {
+		return fmt.Errorf("falha ao aplicar mudanças validadas: %w", err)
+	}
+
+	result.FilesChanged = fileChanges
+	result.ImagesChanged = validatedReplacements
+
+	return nil
+}
+
+func (e *Engine) savePRState(repository string, prs *prState) {
+	if err := prs.save(); err != nil {
+		e.logger.Warn("gitops_state_save_failed").
+			Str("repository", repository).
+			Err(err).
+			Send()
+	}
+}
+
+func (e *Engine) cleanupFailedBranch(ctx context.Context, repoManager *github.RepositoryManager, owner, repo string, branch *types.BranchOperation, result *types.GitOpsResult) {
+	if !branch.Created {
+		return
+	}
+
+	if e.config.GitOps.NoCleanup {
+		e.logger.Info("gitops_branch_retained").
+			Str("repository", branch.Repository).
+			Str("branch", branch.TargetBranch).
+			Send()
+		return
+	}
+
+	if err := repoManager.DeleteBranch(ctx, owner, repo, branch.TargetBranch); err != nil {
+		e.logger.Warn("gitops_branch_cleanup_failed").
+			Str("repository", branch.Repository).
+			Str("branch", branch.TargetBranch).
+			Err(err).
+			Send()
+		return
+	}
+
+	result.Branch = ""
 }
 
 func (e *Engine) generateValidatedReplacements(detections []types.ImageDetectionResult, validatedImageMap map[string]string) []types.ImageReplacement {
@@ -599,11 +856,21 @@ func (e *Engine) generateValidatedReplacements(detections []types.ImageDetection
 			continue
 		}
 
-		if validatedPrivateImage, exists := validatedImageMap[detection.FullImage]; exists {
+		validatedPrivateImage, exists := validatedImageMap[detection.FullImage]
+		if override, found := e.config.GitOps.ImageOverrides[detection.FullImage]; found && override != "" {
+			e.logger.Info("image_override_applied").
+				Str("source", detection.FullImage).
+				Str("target", override).
+				Str("discovered_target", validatedPrivateImage).
+				Send()
+			validatedPrivateImage, exists = override, true
+		}
+
+		if exists {
 			replacement := types.ImageReplacement{
 				SourceImage:    detection.FullImage,
 				TargetImage:    validatedPrivateImage,
-				FileType:       e.detectReplacementType(detection.Context),
+				FileType:       e.detectReplacementType(detection.FilePath, detection.Context),
 				FilePath:       detection.FilePath,
 				LineNumber:     detection.LineNumber,
 				Context:        detection.Context,
@@ -635,7 +902,7 @@ func (e *Engine) generateValidatedReplacements(detections []types.ImageDetection
 	return replacements
 }
 
-func (e *Engine) applyValidatedReplacements(ctx context.Context, owner, repo, branch string, validatedReplacements []types.ImageReplacement) ([]types.FileChange, error) {
+func (e *Engine) applyValidatedReplacements(ctx context.Context, owner, repo, branch, commitPrefix string, validatedReplacements []types.ImageReplacement) ([]types.FileChange, error) {
 	e.logger.Info("applying_validated_replacements").
 		Str("owner", owner).
 		Str("repo", repo).
@@ -652,7 +919,12 @@ func (e *Engine) applyValidatedReplacements(ctx context.Context, owner, repo, br
 	}
 
 	var fileChanges []types.FileChange
-	repoManager := github.NewRepositoryManager(e.githubClient)
+	repoManager := github.NewRepositoryManager(e.githubClient).
+		WithCommitter(e.config.GitOps.Committer.Name, e.config.GitOps.Committer.Email).
+		WithTrailers(e.config.GitOps.Committer.Trailers)
+	if e.config.GitOps.Committer.UseRunDate {
+		repoManager.WithCommitDate(e.runStartedAt)
+	}
 
 	for filePath, fileReplacements := range fileReplacements {
 		e.logger.Debug("processing_validated_file").
@@ -660,40 +932,12 @@ func (e *Engine) applyValidatedReplacements(ctx context.Context, owner, repo, br
 			Int("validated_replacements", len(fileReplacements)).
 			Send()
 
-		content, err := e.githubClient.GetFileContent(ctx, owner, repo, filePath, "")
+		originalContent, modifiedContent, actualReplacements, err := e.renderValidatedFile(ctx, owner, repo, branch, filePath, fileReplacements)
 		if err != nil {
-			e.logger.Error("failed_to_fetch_file").
-				Str("file", filePath).
-				Err(err).
-				Send()
-			return nil, fmt.Errorf("falha ao obter conteúdo do arquivo %s: %w", filePath, err)
+			return nil, err
 		}
 
-		originalContent, err := base64.StdEncoding.DecodeString(content.Content)
-		if err != nil {
-			e.logger.Error("failed_to_decode_file").
-				Str("file", filePath).
-				Err(err).
-				Send()
-			return nil, fmt.Errorf("falha ao decodificar arquivo %s: %w", filePath, err)
-		}
-
-		modifiedContent, actualReplacements, err := e.replacer.ReplaceImagesInContent(string(originalContent), fileReplacements)
-		if err != nil {
-			e.logger.Error("failed_to_replace_validated_images").
-				Str("file", filePath).
-				Err(err).
-				Send()
-			return nil, fmt.Errorf("falha ao substituir imagens validadas no arquivo %s: %w", filePath, err)
-		}
-
-		e.logger.Debug("validated_replacement_completed").
-			Str("file", filePath).
-			Int("actual_replacements", len(actualReplacements)).
-			Bool("content_changed", modifiedContent != string(originalContent)).
-			Send()
-
-		if modifiedContent == string(originalContent) {
+		if modifiedContent == originalContent {
 			e.logger.Warn("no_validated_changes_made").
 				Str("file", filePath).
 				Str("message", "Conteúdo não foi alterado após substituições validadas").
@@ -702,7 +946,7 @@ func (e *Engine) applyValidatedReplacements(ctx context.Context, owner, repo, br
 		}
 
 		encodedContent := base64.StdEncoding.EncodeToString([]byte(modifiedContent))
-		commitMessage := e.generateCommitMessage(fileReplacements)
+		commitMessage := e.generateCommitMessage(commitPrefix, fileReplacements)
 
 		_, err = repoManager.UpdateFile(ctx, owner, repo, filePath, encodedContent, commitMessage, branch)
 		if err != nil {
@@ -719,7 +963,7 @@ func (e *Engine) applyValidatedReplacements(ctx context.Context, owner, repo, br
 			Changes:       actualReplacements,
 			LinesChanged:  len(actualReplacements),
 			Validated:     true,
-			BackupContent: string(originalContent),
+			BackupContent: originalContent,
 		}
 
 		fileChanges = append(fileChanges, fileChange)
@@ -737,6 +981,56 @@ func (e *Engine) applyValidatedReplacements(ctx context.Context, owner, repo, br
 	return fileChanges, nil
 }
 
+func (e *Engine) hasNetChanges(ctx context.Context, owner, repo, ref string, validatedReplacements []types.ImageReplacement) (bool, error) {
+	for filePath, fileReplacements := range e.groupValidatedReplacementsByFile(validatedReplacements) {
+		originalContent, modifiedContent, _, err := e.renderValidatedFile(ctx, owner, repo, ref, filePath, fileReplacements)
+		if err != nil {
+			return false, err
+		}
+		if modifiedContent != originalContent {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (e *Engine) renderValidatedFile(ctx context.Context, owner, repo, ref, filePath string, fileReplacements []types.ImageReplacement) (string, string, []types.ImageReplacement, error) {
+	content, err := e.githubClient.GetFileContent(ctx, owner, repo, filePath, ref)
+	if err != nil {
+		e.logger.Error("failed_to_fetch_file").
+			Str("file", filePath).
+			Err(err).
+			Send()
+		return "", "", nil, fmt.Errorf("falha ao obter conteúdo do arquivo %s: %w", filePath, err)
+	}
+
+	originalContent, err := base64.StdEncoding.DecodeString(content.Content)
+	if err != nil {
+		e.logger.Error("failed_to_decode_file").
+			Str("file", filePath).
+			Err(err).
+			Send()
+		return "", "", nil, fmt.Errorf("falha ao decodificar arquivo %s: %w", filePath, err)
+	}
+
+	modifiedContent, actualReplacements, err := e.replacer.ReplaceImagesInContent(string(originalContent), fileReplacements)
+	if err != nil {
+		e.logger.Error("failed_to_replace_validated_images").
+			Str("file", filePath).
+			Err(err).
+			Send()
+		return "", "", nil, fmt.Errorf("falha ao substituir imagens validadas no arquivo %s: %w", filePath, err)
+	}
+
+	e.logger.Debug("validated_replacement_completed").
+		Str("file", filePath).
+		Int("actual_replacements", len(actualReplacements)).
+		Bool("content_changed", modifiedContent != string(originalContent)).
+		Send()
+
+	return string(originalContent), modifiedContent, actualReplacements, nil
+}
+
 func (e *Engine) groupValidatedReplacementsByFile(validatedReplacements []types.ImageReplacement) map[string][]types.ImageReplacement {
 	fileMap := make(map[string][]types.ImageReplacement)
 
@@ -777,10 +1071,14 @@ func (e *Engine) groupValidatedReplacementsByFile(validatedReplacements []types.
 	return fileMap
 }
 
-func (e *Engine) detectReplacementType(context string) string {
+func (e *Engine) detectReplacementType(filePath, context string) string {
 	context = strings.ToLower(context)
 
-	if strings.Contains(context, "registry:") && strings.Contains(context, "repository:") && strings.Contains(context, "tag:") {
+	if strings.HasSuffix(strings.ToLower(filePath), ".json") {
+		return "json"
+	} else if strings.HasPrefix(context, "from ") {
+		return "dockerfile"
+	} else if strings.Contains(context, "registry:") && strings.Contains(context, "repository:") && strings.Contains(context, "tag:") {
 		return "helm_separated"
 	} else if strings.Contains(context, "repository:") && strings.Contains(context, "tag:") && strings.Contains(context, "(combined)") {
 		return "helm_combined"
@@ -797,20 +1095,29 @@ func (e *Engine) generateReplacementKey(detection types.ImageDetectionResult) st
 	return fmt.Sprintf("%s:%d", detection.Image, detection.LineNumber)
 }
 
-func (e *Engine) generateCommitMessage(replacements []types.ImageReplacement) string {
+func (e *Engine) generateCommitMessage(commitPrefix string, replacements []types.ImageReplacement) string {
+	var message string
 	if len(replacements) == 1 {
-		return strings.ReplaceAll(e.config.GitOps.CommitMessage, "{image}", replacements[0].SourceImage)
+		message = strings.ReplaceAll(e.config.GitOps.CommitMessage, "{image}", replacements[0].SourceImage)
+	} else {
+		message = fmt.Sprintf("%s (%d validated images)",
+			strings.ReplaceAll(e.config.GitOps.CommitMessage, "{image}", "multiple"),
+			len(replacements))
 	}
 
-	return fmt.Sprintf("%s (%d validated images)",
-		strings.ReplaceAll(e.config.GitOps.CommitMessage, "{image}", "multiple"),
-		len(replacements))
+	commitPrefix = strings.TrimSpace(commitPrefix)
+	if commitPrefix == "" || strings.HasPrefix(message, commitPrefix) {
+		return message
+	}
+	return commitPrefix + " " + message
 }
 
 func (e *Engine) detectFileType(filePath string) string {
 	fileName := strings.ToLower(filePath)
 
-	if strings.Contains(fileName, "values") {
+	if strings.HasSuffix(fileName, ".json") {
+		return "json_manifest"
+	} else if strings.Contains(fileName, "values") {
 		return "helm_values"
 	} else if strings.Contains(fileName, "kustomization") {
 		return "kustomization"
@@ -855,7 +1162,7 @@ func (e *Engine) simulateRepositoryChanges(result *types.GitOpsResult, validated
 	}
 
 	result.ImagesChanged = validatedReplacements
-	result.Branch = e.config.GitOps.BranchPrefix + "simulation-validated"
+	result.Branch = github.SanitizeBranchName(e.config.GitOps.BranchPrefix + "simulation-validated")
 
 	return result
 }
@@ -870,6 +1177,8 @@ func (e *Engine) generateFallbackFilePath(fileType string) string {
 		return "application.yaml"
 	case "kubernetes_manifest":
 		return "deployment.yaml"
+	case "json":
+		return "manifest.json"
 	case "helm_values":
 		return "values.yaml"
 	default:
@@ -893,7 +1202,39 @@ func (e *Engine) getEnabledRepositories() []types.GitHubRepositoryConfig {
 	return enabled
 }
 
+func (e *Engine) aggregateResults(summary *types.GitOpsSummary, targets []repositoryTarget, results []*types.GitOpsResult) {
+	priorities := make(map[string]int, len(targets))
+	for _, target := range targets {
+		priorities[target.config.Name] = target.config.Priority
+	}
+
+	ordered := make([]*types.GitOpsResult, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			ordered = append(ordered, result)
+		}
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, pj := priorities[ordered[i].Repository], priorities[ordered[j].Repository]
+		if pi != pj {
+			return pi > pj
+		}
+		return ordered[i].Repository < ordered[j].Repository
+	})
+
+	summary.Results = ordered
+	for _, result := range ordered {
+		e.updateSummaryCounters(summary, result)
+	}
+}
+
 func (e *Engine) updateSummaryCounters(summary *types.GitOpsSummary, result *types.GitOpsResult) {
+	if result.Deferred {
+		summary.DeferredRepositories++
+		return
+	}
+
 	summary.ProcessedRepositories++
 
 	if result.Success {
@@ -913,7 +1254,7 @@ func (e *Engine) updateSummaryCounters(summary *types.GitOpsSummary, result *typ
 func (e *Engine) parseRepositoryName(repoName string) (owner, repo string, err error) {
 	parts := strings.Split(repoName, "/")
 	if len(parts) != 2 {
-		return "", "", fmt.Errorf("formato de repositório inválido: %s", repoName)
+		return "", "", fmt.Errorf("%w: %s", types.ErrInvalidRepoName, repoName)
 	}
 	return parts[0], parts[1], nil
 }
@@ -0,0 +1,932 @@
+package gitops
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/github"
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const testDeploymentManifest = "spec:\n  containers:\n    - name: web\n      image: nginx:1.25\n"
+
+type testGitHubServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string
+}
+
+func newTestGitHubServer(t *testing.T, manifest string, handlers map[string]http.HandlerFunc) *testGitHubServer {
+	routes := map[string]http.HandlerFunc{
+		"GET /": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]string{"full_name": "company/" + testRepositoryName(r), "default_branch": "main"})
+		},
+		"GET /branches": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"name": "main", "commit": {"sha": "main-sha"}}]`))
+		},
+		"POST /git/refs": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		},
+		"GET /contents/apps/deploy.yaml": func(w http.ResponseWriter, r *http.Request) {
+			writeTestGitHubFile(w, "apps/deploy.yaml", manifest)
+		},
+		"PUT /contents/apps/deploy.yaml": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"commit": {"sha": "def456"}}`))
+		},
+		"POST /pulls": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 7, "html_url": "https://github.com/company/manifests/pull/7"}`))
+		},
+	}
+	for route, handler := range handlers {
+		routes[route] = handler
+	}
+
+	server := &testGitHubServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		server.requests = append(server.requests, r.Method+" "+r.URL.Path)
+		route := r.Method + " " + testRepositoryPath(r)
+		if handler, found := routes[route]; found {
+			handler(w, r)
+			return
+		}
+		for prefix, handler := range routes {
+			if strings.HasSuffix(prefix, "/") && prefix != r.Method+" /" && strings.HasPrefix(route, prefix) {
+				handler(w, r)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *testGitHubServer) writes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var writes []string
+	for _, request := range s.requests {
+		if !strings.HasPrefix(request, http.MethodGet+" ") {
+			writes = append(writes, request)
+		}
+	}
+	return writes
+}
+
+func testRepositoryName(r *http.Request) string {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/repos/company/"), "/", 2)
+	return parts[0]
+}
+
+func testRepositoryPath(r *http.Request) string {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/repos/company/"), "/", 2)
+	if len(parts) < 2 {
+		return "/"
+	}
+	return "/" + parts[1]
+}
+
+func writeTestGitHubFile(w http.ResponseWriter, path, content string) {
+	json.NewEncoder(w).Encode(types.FileContent{Path: path, SHA: "file-sha", Content: base64.StdEncoding.EncodeToString([]byte(content))})
+}
+
+func newTestEngine(t *testing.T, server *testGitHubServer, gitopsConfig types.GitOpsConfig) *Engine {
+	t.Helper()
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{Token: "token", APIURL: server.URL},
+		GitOps: gitopsConfig,
+	}
+	log := logger.NewTest()
+	return NewEngine(github.NewClient(&config.GitHub, log), registry.NewManager(log), log, config)
+}
+
+func nginxToHarborReplacement() types.ImageReplacement {
+	return types.ImageReplacement{
+		SourceImage: "nginx:1.25",
+		TargetImage: "harbor.company.com/library/nginx:1.25",
+		FilePath:    "apps/deploy.yaml",
+		FileType:    "kubernetes",
+	}
+}
+
+func publishTestChanges(engine *Engine, repoConfig types.GitHubRepositoryConfig, replacements ...types.ImageReplacement) (*types.GitOpsResult, error) {
+	result := &types.GitOpsResult{Repository: repoConfig.Name}
+	err := engine.publishRepositoryChanges(context.Background(), github.NewRepositoryManager(engine.githubClient), repoConfig, result, replacements)
+	return result, err
+}
+
+func TestEngine_processRepository_RequirePush(t *testing.T) {
+	tests := []struct {
+		name          string
+		requirePush   bool
+		wantPushError bool
+	}{
+		{name: "missing push fails fast when required", requirePush: true, wantPushError: true},
+		{name: "missing push only warns by default", requirePush: false, wantPushError: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestGitHubServer(t, "", map[string]http.HandlerFunc{
+				"GET /": func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`{"full_name": "company/manifests", "default_branch": "main", "permissions": {"pull": true, "push": false}}`))
+				},
+			})
+
+			config := &types.Config{
+				GitHub: types.GitHubConfig{Token: "token", APIURL: server.URL},
+				GitOps: types.GitOpsConfig{RequirePush: tt.requirePush},
+			}
+			log := logger.NewTest()
+			engine := NewEngine(github.NewClient(&config.GitHub, log), registry.NewManager(log), log, config)
+
+			repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true, Paths: []string{"apps/"}}
+			result := engine.processRepository(context.Background(), repoConfig, []*types.ImageInfo{{Image: "nginx:1.25"}}, map[string]string{})
+
+			pushError := result.Error != nil && strings.Contains(result.Error.Error(), "sem permissão de escrita")
+			if pushError != tt.wantPushError {
+				t.Fatalf("result error = %v, expected push permission error: %v", result.Error, tt.wantPushError)
+			}
+
+			if tt.wantPushError {
+				if result.Success {
+					t.Error("expected repository result to fail")
+				}
+				server.mu.Lock()
+				defer server.mu.Unlock()
+				if len(server.requests) != 1 {
+					t.Errorf("expected only the permission check before failing, got requests %v", server.requests)
+				}
+			}
+		})
+	}
+}
+
+func TestEngine_processRepository_FromInventory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  template:\n    spec:\n      containers:\n        - name: web\n          image: nginx:1.25\n"
+
+	server := newTestGitHubServer(t, manifest, map[string]http.HandlerFunc{
+		"GET /": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"full_name": "company/manifests", "default_branch": "main", "permissions": map[string]bool{"pull": true, "push": true}})
+		},
+		"GET /git/trees/main-sha": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(types.Tree{SHA: "main-sha", Tree: []types.TreeEntry{{Path: "apps/web/deployment.yaml", Type: "blob", Size: len(manifest), SHA: "def456"}}})
+		},
+		"GET /contents/apps/web/deployment.yaml": func(w http.ResponseWriter, r *http.Request) {
+			writeTestGitHubFile(w, "apps/web/deployment.yaml", manifest)
+		},
+	})
+
+	data, err := reporter.NewInventory("", []*types.ImageInfo{{Image: "nginx:1.25", Namespace: "web", IsPublic: true}}).JSON()
+	if err != nil {
+		t.Fatalf("JSON() unexpected error: %v", err)
+	}
+	inventoryPath := filepath.Join(t.TempDir(), "inventory.json")
+	if err := os.WriteFile(inventoryPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	images, err := reporter.LoadInventory(inventoryPath)
+	if err != nil {
+		t.Fatalf("LoadInventory() unexpected error: %v", err)
+	}
+
+	config := &types.Config{
+		GitHub:   types.GitHubConfig{Token: "token", APIURL: server.URL},
+		Settings: types.SettingsConfig{DryRun: true},
+	}
+	log := logger.NewTest()
+	engine := NewEngine(github.NewClient(&config.GitHub, log), registry.NewManager(log), log, config)
+
+	repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true, Paths: []string{"apps/"}}
+	result := engine.processRepository(context.Background(), repoConfig, images, map[string]string{"nginx:1.25": "harbor.company.com/library/nginx:1.25"})
+
+	if result.Error != nil || !result.Success {
+		t.Fatalf("processRepository() = %+v, expected success", result)
+	}
+	if len(result.ImagesChanged) != 1 || result.ImagesChanged[0].TargetImage != "harbor.company.com/library/nginx:1.25" {
+		t.Errorf("images changed = %+v, expected nginx replaced from inventory", result.ImagesChanged)
+	}
+}
+
+func TestEngine_processRepository_ScanOnly(t *testing.T) {
+	server := newTestGitHubServer(t, testDeploymentManifest, map[string]http.HandlerFunc{
+		"GET /": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"full_name": "company/manifests", "default_branch": "main", "permissions": {"pull": true, "push": false}}`))
+		},
+		"GET /git/trees/main-sha": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(types.Tree{SHA: "main-sha", Tree: []types.TreeEntry{{Path: "apps/deploy.yaml", Type: "blob", Size: len(testDeploymentManifest), SHA: "file-sha"}}})
+		},
+	})
+
+	config := &types.Config{
+		GitHub:   types.GitHubConfig{Token: "token", APIURL: server.URL},
+		GitOps:   types.GitOpsConfig{AutoPR: true, RequirePush: true, DryRunPreview: true, ExportPatches: true},
+		Webhooks: types.WebhookConfig{Discord: types.DiscordWebhookConfig{Enabled: true, URL: server.URL + "/discord"}},
+	}
+	log := logger.NewTest()
+	engine := NewEngine(github.NewClient(&config.GitHub, log), registry.NewManager(log), log, config).ScanOnly()
+	engine.patchesDir = t.TempDir()
+
+	if engine.discordWebhook != nil {
+		t.Error("scan-only engine should not send Discord notifications")
+	}
+
+	repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true, Paths: []string{"apps/"}}
+	result := engine.processRepository(context.Background(), repoConfig, []*types.ImageInfo{{Image: "nginx:1.25"}}, map[string]string{"nginx:1.25": "harbor.company.com/library/nginx:1.25"})
+
+	if result.Error != nil || !result.Success {
+		t.Fatalf("processRepository() = %+v, expected success without push permission", result)
+	}
+	if len(result.ImagesChanged) != 1 || result.ImagesChanged[0].TargetImage != "harbor.company.com/library/nginx:1.25" {
+		t.Errorf("images changed = %+v, expected the nginx drift", result.ImagesChanged)
+	}
+	if writes := server.writes(); len(writes) != 0 {
+		t.Errorf("scan-only run wrote to GitHub: %v", writes)
+	}
+	if entries, _ := os.ReadDir(engine.patchesDir); len(entries) != 0 {
+		t.Errorf("scan-only run exported patches: %v", entries)
+	}
+}
+
+func TestEngine_aggregateResults_DeterministicOrdering(t *testing.T) {
+	targets := []repositoryTarget{
+		{config: types.GitHubRepositoryConfig{Name: "company/zeta", Priority: 1}},
+		{config: types.GitHubRepositoryConfig{Name: "company/alpha", Priority: 1}},
+		{config: types.GitHubRepositoryConfig{Name: "company/critical", Priority: 10}},
+		{config: types.GitHubRepositoryConfig{Name: "company/infra", Priority: 5}},
+	}
+	newResults := func() []*types.GitOpsResult {
+		return []*types.GitOpsResult{
+			{Repository: "company/zeta", Success: true, PullRequest: &types.PullRequestInfo{Number: 1}, FilesChanged: []types.FileChange{{}}},
+			{Repository: "company/alpha", Error: errors.New("conflict")},
+			{Repository: "company/critical", Success: true, PullRequest: &types.PullRequestInfo{Number: 2}, ImagesChanged: []types.ImageReplacement{{}, {}}},
+			{Repository: "company/infra", Success: true},
+		}
+	}
+
+	expectedOrder := []string{"company/critical", "company/infra", "company/alpha", "company/zeta"}
+	engine := &Engine{logger: logger.NewTest(), config: &types.Config{}}
+	random := rand.New(rand.NewSource(1))
+
+	for run := 0; run < 20; run++ {
+		results := newResults()
+		random.Shuffle(len(results), func(i, j int) { results[i], results[j] = results[j], results[i] })
+
+		summary := &types.GitOpsSummary{}
+		engine.aggregateResults(summary, targets, append(results, nil))
+
+		order := make([]string, 0, len(summary.Results))
+		for _, result := range summary.Results {
+			order = append(order, result.Repository)
+		}
+		if !reflect.DeepEqual(order, expectedOrder) {
+			t.Fatalf("run %d: result order = %v, expected %v", run, order, expectedOrder)
+		}
+		if summary.ProcessedRepositories != 4 || summary.SuccessfulPRs != 2 || summary.FailedOperations != 1 ||
+			summary.TotalFilesChanged != 1 || summary.TotalImagesReplaced != 2 {
+			t.Fatalf("run %d: unexpected counters %+v", run, summary)
+		}
+	}
+}
+
+func TestEngine_notifyComplete_DeferReporting(t *testing.T) {
+	var mu sync.Mutex
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		posts++
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config := &types.Config{Webhooks: types.WebhookConfig{Discord: types.DiscordWebhookConfig{Enabled: true, URL: server.URL}}}
+	summary := &types.GitOpsSummary{ProcessingTime: "1s"}
+
+	NewEngine(nil, nil, logger.NewTest(), config).DeferReporting().notifyComplete(context.Background(), summary)
+	if posts != 0 {
+		t.Fatalf("deferred engine sent %d notifications, expected 0", posts)
+	}
+
+	NewEngine(nil, nil, logger.NewTest(), config).notifyComplete(context.Background(), summary)
+	if posts != 1 {
+		t.Errorf("engine sent %d notifications, expected 1", posts)
+	}
+}
+
+func TestEngine_excludeNoMigrateImages(t *testing.T) {
+	engine := &Engine{logger: logger.NewTest(), config: &types.Config{}}
+
+	publicImages := []*types.ImageInfo{
+		{Image: "nginx:1.25", IsPublic: true},
+		{Image: "registry.k8s.io/pause:3.9", IsPublic: true, NoMigrate: true},
+	}
+	validatedImageMap := map[string]string{
+		"nginx:1.25":                "harbor.company.com/library/nginx:1.25",
+		"registry.k8s.io/pause:3.9": "harbor.company.com/pause:3.9",
+	}
+
+	available := engine.filterValidatedImages(engine.excludeNoMigrateImages(publicImages), validatedImageMap)
+
+	if len(available) != 1 || available[0].Image != "nginx:1.25" {
+		t.Fatalf("expected only nginx:1.25 to be available for replacement, got %v", available)
+	}
+}
+
+func TestEngine_generateValidatedReplacements_ImageOverrides(t *testing.T) {
+	config := &types.Config{GitOps: types.GitOpsConfig{ImageOverrides: map[string]string{
+		"nginx:1.25": "harbor.company.com/infra/nginx:1.25",
+	}}}
+	engine := &Engine{logger: logger.NewTest(), config: config}
+
+	detections := []types.ImageDetectionResult{
+		{FullImage: "nginx:1.25", FilePath: "apps/web/deployment.yaml", LineNumber: 10, Context: "image: nginx:1.25"},
+		{FullImage: "redis:7.0", FilePath: "apps/cache/deployment.yaml", LineNumber: 12, Context: "image: redis:7.0"},
+	}
+	validatedImageMap := map[string]string{
+		"nginx:1.25": "harbor.company.com/library/nginx:1.25",
+		"redis:7.0":  "harbor.company.com/library/redis:7.0",
+	}
+
+	replacements := engine.generateValidatedReplacements(detections, validatedImageMap)
+
+	targets := make(map[string]string)
+	for _, replacement := range replacements {
+		targets[replacement.SourceImage] = replacement.TargetImage
+	}
+	expected := map[string]string{
+		"nginx:1.25": "harbor.company.com/infra/nginx:1.25",
+		"redis:7.0":  "harbor.company.com/library/redis:7.0",
+	}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("replacement targets = %v, expected %v", targets, expected)
+	}
+}
+
+func TestEngine_publishRepositoryChanges_BranchCleanup(t *testing.T) {
+	tests := []struct {
+		name        string
+		noCleanup   bool
+		wantDeleted bool
+	}{
+		{name: "branch deleted when pull request fails", wantDeleted: true},
+		{name: "branch retained with no-cleanup", noCleanup: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted []string
+
+			server := newTestGitHubServer(t, testDeploymentManifest, map[string]http.HandlerFunc{
+				"POST /pulls": func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					w.Write([]byte(`{"message": "Validation Failed"}`))
+				},
+				"DELETE /git/refs/heads/": func(w http.ResponseWriter, r *http.Request) {
+					deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/repos/company/manifests/git/refs/heads/"))
+					w.WriteHeader(http.StatusNoContent)
+				},
+			})
+
+			engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/", NoCleanup: tt.noCleanup})
+
+			repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true}
+			result, err := publishTestChanges(engine, repoConfig, nginxToHarborReplacement())
+			if err == nil || !strings.Contains(err.Error(), "falha ao criar pull request") {
+				t.Fatalf("expected pull request failure, got %v", err)
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if tt.wantDeleted {
+				if len(deleted) != 1 || !strings.HasPrefix(deleted[0], "privateer/") {
+					t.Errorf("expected created branch to be deleted, got %v", deleted)
+				}
+				if result.Branch != "" {
+					t.Errorf("result branch = %q, expected it cleared after cleanup", result.Branch)
+				}
+				return
+			}
+			if len(deleted) != 0 {
+				t.Errorf("expected branch to be retained, got deletions %v", deleted)
+			}
+			if result.Branch == "" {
+				t.Error("expected retained branch on the result")
+			}
+		})
+	}
+}
+
+func TestEngine_publishRepositoryChanges_BaseBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		baseBranch string
+		wantBase   string
+		wantSHA    string
+		wantErr    bool
+	}{
+		{name: "default branch when unset", wantBase: "main", wantSHA: "main-sha"},
+		{name: "configured base branch", baseBranch: "develop", wantBase: "develop", wantSHA: "develop-sha"},
+		{name: "missing base branch", baseBranch: "staging", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var branchSHA, prBase string
+
+			server := newTestGitHubServer(t, testDeploymentManifest, map[string]http.HandlerFunc{
+				"GET /branches": func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`[{"name": "main", "commit": {"sha": "main-sha"}}, {"name": "develop", "commit": {"sha": "develop-sha"}}]`))
+				},
+				"POST /git/refs": func(w http.ResponseWriter, r *http.Request) {
+					var payload types.CreateBranchRequest
+					json.NewDecoder(r.Body).Decode(&payload)
+					branchSHA = payload.SHA
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{}`))
+				},
+				"POST /pulls": func(w http.ResponseWriter, r *http.Request) {
+					var payload types.CreatePRRequest
+					json.NewDecoder(r.Body).Decode(&payload)
+					prBase = payload.Base
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"number": 7, "html_url": "https://github.com/company/manifests/pull/7"}`))
+				},
+			})
+
+			engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/"})
+
+			repoConfig := types.GitHubRepositoryConfig{
+				Name:       "company/manifests",
+				Enabled:    true,
+				PRSettings: types.PRConfig{BaseBranch: tt.baseBranch},
+			}
+			_, err := publishTestChanges(engine, repoConfig, nginxToHarborReplacement())
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "branch base staging") {
+					t.Fatalf("expected missing base branch error, got %v", err)
+				}
+				if branchSHA != "" {
+					t.Errorf("branch should not be created when the base is missing")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("publishRepositoryChanges() unexpected error: %v", err)
+			}
+			if branchSHA != tt.wantSHA {
+				t.Errorf("branch created from %q, expected %q", branchSHA, tt.wantSHA)
+			}
+			if prBase != tt.wantBase {
+				t.Errorf("pull request base = %q, expected %q", prBase, tt.wantBase)
+			}
+		})
+	}
+}
+
+func TestEngine_publishRepositoryChanges_BranchStrategy(t *testing.T) {
+	tests := []struct {
+		name             string
+		strategy         string
+		canPush          bool
+		wantErr          bool
+		wantBranch       bool
+		wantPR           bool
+		wantCommitBranch string
+	}{
+		{name: "create_new opens a branch and PR", strategy: types.BranchStrategyCreateNew, canPush: true, wantBranch: true, wantPR: true},
+		{name: "use_main commits to the default branch", strategy: types.BranchStrategyUseMain, canPush: true, wantCommitBranch: "main"},
+		{name: "use_main requires push permission", strategy: types.BranchStrategyUseMain, canPush: false, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var branchCreated, prCreated bool
+			var committedBranches []string
+
+			server := newTestGitHubServer(t, testDeploymentManifest, map[string]http.HandlerFunc{
+				"GET /": func(w http.ResponseWriter, r *http.Request) {
+					json.NewEncoder(w).Encode(map[string]interface{}{"full_name": "company/manifests", "default_branch": "main", "permissions": map[string]bool{"pull": true, "push": tt.canPush}})
+				},
+				"POST /git/refs": func(w http.ResponseWriter, r *http.Request) {
+					branchCreated = true
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{}`))
+				},
+				"PUT /contents/apps/deploy.yaml": func(w http.ResponseWriter, r *http.Request) {
+					var payload types.UpdateFileRequest
+					json.NewDecoder(r.Body).Decode(&payload)
+					committedBranches = append(committedBranches, payload.Branch)
+					w.Write([]byte(`{"commit": {"sha": "def456"}}`))
+				},
+				"POST /pulls": func(w http.ResponseWriter, r *http.Request) {
+					prCreated = true
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"number": 7, "html_url": "https://github.com/company/manifests/pull/7"}`))
+				},
+			})
+
+			engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/"})
+
+			repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true, BranchStrategy: tt.strategy}
+			_, err := publishTestChanges(engine, repoConfig, nginxToHarborReplacement())
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "permissão de escrita") {
+					t.Fatalf("expected push permission error, got %v", err)
+				}
+				if len(committedBranches) != 0 {
+					t.Errorf("expected no commits without push permission, got %v", committedBranches)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("publishRepositoryChanges() unexpected error: %v", err)
+			}
+			if branchCreated != tt.wantBranch || prCreated != tt.wantPR {
+				t.Errorf("branch created = %v, PR created = %v, expected %v and %v", branchCreated, prCreated, tt.wantBranch, tt.wantPR)
+			}
+			if len(committedBranches) != 1 {
+				t.Fatalf("expected one commit, got %v", committedBranches)
+			}
+			if tt.wantCommitBranch != "" && committedBranches[0] != tt.wantCommitBranch {
+				t.Errorf("committed to %q, expected %q", committedBranches[0], tt.wantCommitBranch)
+			}
+			if tt.wantCommitBranch == "" && committedBranches[0] == "main" {
+				t.Error("create_new must not commit to the default branch")
+			}
+		})
+	}
+}
+
+func TestEngine_publishRepositoryChanges_SuppliedBranch(t *testing.T) {
+	withFeature := `[{"name": "main", "commit": {"sha": "main-sha"}}, {"name": "feature/images", "commit": {"sha": "feature-sha"}}]`
+	tests := []struct {
+		name        string
+		branches    string
+		openPulls   string
+		wantCreate  bool
+		wantNewPR   bool
+		wantPR      int
+		wantDiffRef string
+	}{
+		{name: "existing branch with open PR reuses it", branches: withFeature, openPulls: `[{"number": 12, "state": "open", "html_url": "https://github.com/company/manifests/pull/12"}]`, wantPR: 12, wantDiffRef: "feature/images"},
+		{name: "existing branch without PR opens one", branches: withFeature, openPulls: `[]`, wantNewPR: true, wantPR: 7, wantDiffRef: "feature/images"},
+		{name: "missing branch is created from default", branches: `[{"name": "main", "commit": {"sha": "main-sha"}}]`, wantCreate: true, wantNewPR: true, wantPR: 7, wantDiffRef: "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var createdRefs, committedBranches, readRefs []string
+			var prHead, pullsQuery string
+
+			server := newTestGitHubServer(t, testDeploymentManifest, map[string]http.HandlerFunc{
+				"GET /branches": func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(tt.branches))
+				},
+				"GET /contents/apps/deploy.yaml": func(w http.ResponseWriter, r *http.Request) {
+					readRefs = append(readRefs, r.URL.Query().Get("ref"))
+					writeTestGitHubFile(w, "apps/deploy.yaml", testDeploymentManifest)
+				},
+				"POST /git/refs": func(w http.ResponseWriter, r *http.Request) {
+					var payload types.CreateBranchRequest
+					json.NewDecoder(r.Body).Decode(&payload)
+					createdRefs = append(createdRefs, payload.Ref)
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{}`))
+				},
+				"PUT /contents/apps/deploy.yaml": func(w http.ResponseWriter, r *http.Request) {
+					var payload types.UpdateFileRequest
+					json.NewDecoder(r.Body).Decode(&payload)
+					committedBranches = append(committedBranches, payload.Branch)
+					w.Write([]byte(`{"commit": {"sha": "def456"}}`))
+				},
+				"GET /pulls": func(w http.ResponseWriter, r *http.Request) {
+					pullsQuery = r.URL.RawQuery
+					w.Write([]byte(tt.openPulls))
+				},
+				"POST /pulls": func(w http.ResponseWriter, r *http.Request) {
+					var payload types.CreatePRRequest
+					json.NewDecoder(r.Body).Decode(&payload)
+					prHead = payload.Head
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"number": 7, "html_url": "https://github.com/company/manifests/pull/7"}`))
+				},
+			})
+
+			engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/", Branch: "feature/images", MaxPRs: 1})
+			engine.prSlots = newPRSlotQueue(1, []repositoryTarget{{config: types.GitHubRepositoryConfig{Name: "company/manifests"}}})
+
+			repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true}
+			result, err := publishTestChanges(engine, repoConfig, nginxToHarborReplacement())
+			if err != nil {
+				t.Fatalf("publishRepositoryChanges() unexpected error: %v", err)
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if result.Branch != "feature/images" {
+				t.Errorf("result branch = %q, expected feature/images", result.Branch)
+			}
+			if result.PullRequest == nil || result.PullRequest.Number != tt.wantPR {
+				t.Errorf("pull request = %+v, expected #%d", result.PullRequest, tt.wantPR)
+			}
+			if tt.wantNewPR != (prHead == "feature/images") {
+				t.Errorf("PR head = %q, expected a new pull request: %v", prHead, tt.wantNewPR)
+			}
+			if !tt.wantNewPR && engine.prSlots.reserved != 0 {
+				t.Errorf("reserved slots = %d, expected a reused pull request to take no slot", engine.prSlots.reserved)
+			}
+			if !tt.wantCreate && pullsQuery != "state=open&head=company%3Afeature%2Fimages" {
+				t.Errorf("open pull request lookup query = %q, expected head=company:feature/images", pullsQuery)
+			}
+			if len(readRefs) == 0 || readRefs[0] != tt.wantDiffRef {
+				t.Errorf("changes checked against refs %v, expected %s first", readRefs, tt.wantDiffRef)
+			}
+			if !reflect.DeepEqual(committedBranches, []string{"feature/images"}) {
+				t.Errorf("committed to %v, expected only feature/images", committedBranches)
+			}
+			if tt.wantCreate {
+				if !reflect.DeepEqual(createdRefs, []string{"refs/heads/feature/images"}) {
+					t.Errorf("created refs = %v, expected the supplied branch", createdRefs)
+				}
+			} else if len(createdRefs) != 0 {
+				t.Errorf("expected no new branch, got %v", createdRefs)
+			}
+		})
+	}
+}
+
+func TestEngine_publishRepositoryChanges_PRSettings(t *testing.T) {
+	var commitMessages []string
+	var prDraft bool
+
+	server := newTestGitHubServer(t, testDeploymentManifest, map[string]http.HandlerFunc{
+		"PUT /contents/apps/deploy.yaml": func(w http.ResponseWriter, r *http.Request) {
+			var payload types.UpdateFileRequest
+			json.NewDecoder(r.Body).Decode(&payload)
+			commitMessages = append(commitMessages, payload.Message)
+			w.Write([]byte(`{"commit": {"sha": "def456"}}`))
+		},
+		"POST /pulls": func(w http.ResponseWriter, r *http.Request) {
+			var payload types.CreatePRRequest
+			json.NewDecoder(r.Body).Decode(&payload)
+			prDraft = payload.Draft
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 7, "html_url": "https://github.com/company/manifests/pull/7"}`))
+		},
+	})
+
+	engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/", CommitMessage: "Migrate {image} to private registry"})
+
+	repoConfig := types.GitHubRepositoryConfig{
+		Name:       "company/manifests",
+		Enabled:    true,
+		PRSettings: types.PRConfig{CommitPrefix: "[platform]", Draft: true},
+	}
+	if _, err := publishTestChanges(engine, repoConfig, nginxToHarborReplacement()); err != nil {
+		t.Fatalf("publishRepositoryChanges() unexpected error: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if !reflect.DeepEqual(commitMessages, []string{"[platform] Migrate nginx:1.25 to private registry"}) {
+		t.Errorf("commit messages = %q, expected the repository commit prefix", commitMessages)
+	}
+	if !prDraft {
+		t.Error("expected pull request to be created as draft")
+	}
+}
+
+func TestEngine_publishRepositoryChanges_AlreadyUpToDate(t *testing.T) {
+	server := newTestGitHubServer(t, "spec:\n  containers:\n    - name: web\n      image: harbor.company.com/library/nginx:1.25\n", map[string]http.HandlerFunc{
+		"GET /contents/apps/deploy.yaml": func(w http.ResponseWriter, r *http.Request) {
+			if ref := r.URL.Query().Get("ref"); ref != "main" {
+				t.Errorf("file read from ref %q, expected the default branch", ref)
+			}
+			writeTestGitHubFile(w, "apps/deploy.yaml", "spec:\n  containers:\n    - name: web\n      image: harbor.company.com/library/nginx:1.25\n")
+		},
+	})
+
+	engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/", MaxPRs: 1})
+
+	repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true}
+	result, err := publishTestChanges(engine, repoConfig, nginxToHarborReplacement())
+	if err != nil {
+		t.Fatalf("publishRepositoryChanges() unexpected error: %v", err)
+	}
+
+	writes := server.writes()
+	if len(writes) != 0 {
+		t.Errorf("expected no branch, commit or pull request, got %v", writes)
+	}
+	if result.Branch != "" || result.PullRequest != nil || result.Deferred || len(result.FilesChanged) != 0 {
+		t.Errorf("result = %+v, expected repository left untouched", result)
+	}
+	if engine.prSlots.reserved != 0 {
+		t.Errorf("reserved slots = %d, expected no pull request slot consumed", engine.prSlots.reserved)
+	}
+}
+
+func TestEngine_publishRepositoryChanges_MaxPRs(t *testing.T) {
+	branches := make(map[string]int)
+	pulls := make(map[string]int)
+
+	server := newTestGitHubServer(t, testDeploymentManifest, map[string]http.HandlerFunc{
+		"POST /git/refs": func(w http.ResponseWriter, r *http.Request) {
+			branches[testRepositoryName(r)]++
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		},
+		"POST /pulls": func(w http.ResponseWriter, r *http.Request) {
+			repo := testRepositoryName(r)
+			pulls[repo]++
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 1, "html_url": "https://github.com/company/` + repo + `/pull/1"}`))
+		},
+	})
+
+	engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/", MaxPRs: 2})
+
+	var targets []repositoryTarget
+	var results []*types.GitOpsResult
+	for i, name := range []string{"company/first", "company/second", "company/third"} {
+		repoConfig := types.GitHubRepositoryConfig{Name: name, Enabled: true, Priority: 10 - i}
+		result, err := publishTestChanges(engine, repoConfig, nginxToHarborReplacement())
+		if err != nil {
+			t.Fatalf("publishRepositoryChanges(%s) unexpected error: %v", name, err)
+		}
+		result.Success = true
+		targets = append(targets, repositoryTarget{config: repoConfig})
+		results = append(results, result)
+	}
+
+	for _, result := range results[:2] {
+		if result.PullRequest == nil || result.Deferred {
+			t.Errorf("%s = %+v, expected a pull request", result.Repository, result)
+		}
+	}
+	if deferred := results[2]; deferred.PullRequest != nil || !deferred.Deferred || deferred.Branch != "" {
+		t.Errorf("third repository = %+v, expected it deferred without a branch", deferred)
+	}
+
+	server.mu.Lock()
+	if branches["third"] != 0 || pulls["third"] != 0 || pulls["first"] != 1 || pulls["second"] != 1 {
+		t.Errorf("branches = %v, pulls = %v, expected PR creation to stop after two repositories", branches, pulls)
+	}
+	server.mu.Unlock()
+
+	summary := &types.GitOpsSummary{}
+	engine.aggregateResults(summary, targets, results)
+	if summary.SuccessfulPRs != 2 || summary.DeferredRepositories != 1 || summary.ProcessedRepositories != 2 {
+		t.Errorf("summary prs = %d deferred = %d processed = %d, expected 2, 1 and 2", summary.SuccessfulPRs, summary.DeferredRepositories, summary.ProcessedRepositories)
+	}
+}
+
+func TestEngine_processTargets_MaxPRsOverlap(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	secondBranched := make(chan struct{})
+	var server *testGitHubServer
+	server = newTestGitHubServer(t, testDeploymentManifest, map[string]http.HandlerFunc{
+		"GET /": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"full_name": "company/" + testRepositoryName(r), "default_branch": "main", "permissions": map[string]bool{"pull": true, "push": true}})
+		},
+		"GET /git/trees/main-sha": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(types.Tree{SHA: "main-sha", Tree: []types.TreeEntry{{Path: "apps/deploy.yaml", Type: "blob", Size: len(testDeploymentManifest), SHA: "file-sha"}}})
+		},
+		"POST /git/refs": func(w http.ResponseWriter, r *http.Request) {
+			if testRepositoryName(r) == "second" {
+				close(secondBranched)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		},
+		"POST /pulls": func(w http.ResponseWriter, r *http.Request) {
+			repo := testRepositoryName(r)
+			if repo == "first" {
+				// The server serializes requests; let the second repository
+				// through while the first one is still opening its PR.
+				server.mu.Unlock()
+				select {
+				case <-secondBranched:
+				case <-time.After(5 * time.Second):
+					t.Error("second repository waited for the first one to finish before creating its branch")
+				}
+				server.mu.Lock()
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 1, "html_url": "https://github.com/company/` + repo + `/pull/1"}`))
+		},
+	})
+
+	engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/", MaxPRs: 2})
+	engine.config.Settings.Concurrency = 2
+
+	var targets []repositoryTarget
+	for i, name := range []string{"company/first", "company/second", "company/third"} {
+		targets = append(targets, repositoryTarget{
+			config: types.GitHubRepositoryConfig{Name: name, Enabled: true, Priority: 10 - i, Paths: []string{"apps/"}},
+			images: []*types.ImageInfo{{Image: "nginx:1.25"}},
+		})
+	}
+	targets = sortRepositoryTargets(targets)
+	engine.prSlots = newPRSlotQueue(engine.config.GitOps.MaxPRs, targets)
+
+	replacement := nginxToHarborReplacement()
+	results := engine.processTargets(context.Background(), targets, map[string]string{replacement.SourceImage: replacement.TargetImage})
+
+	for _, result := range results[:2] {
+		if result.Error != nil || result.PullRequest == nil {
+			t.Errorf("%s = %+v, expected a pull request", result.Repository, result)
+		}
+	}
+	if deferred := results[2]; !deferred.Deferred || deferred.PullRequest != nil {
+		t.Errorf("third repository = %+v, expected it deferred", deferred)
+	}
+}
+
+func TestEngine_previewRepositoryChanges_MatchesRealRun(t *testing.T) {
+	original := "spec:\n  containers:\n    - name: web\n      image: nginx:1.25\n    - name: cache\n      image: redis:7.0\n"
+	var written string
+	server := newTestGitHubServer(t, original, map[string]http.HandlerFunc{
+		"PUT /contents/apps/deploy.yaml": func(w http.ResponseWriter, r *http.Request) {
+			var payload struct {
+				Content string `json:"content"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			decoded, _ := base64.StdEncoding.DecodeString(payload.Content)
+			written = string(decoded)
+			w.Write([]byte(`{"commit": {"sha": "def456"}}`))
+		},
+	})
+
+	engine := newTestEngine(t, server, types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/", DryRunPreview: true})
+	engine.config.Settings.DryRun = true
+
+	repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true}
+	replacements := []types.ImageReplacement{
+		nginxToHarborReplacement(),
+		{SourceImage: "redis:7.0", TargetImage: "harbor.company.com/library/redis:7.0", FilePath: "apps/deploy.yaml", FileType: "kubernetes"},
+	}
+
+	preview := &types.GitOpsResult{Repository: repoConfig.Name}
+	if err := engine.previewRepositoryChanges(context.Background(), repoConfig, preview, replacements); err != nil {
+		t.Fatalf("previewRepositoryChanges() unexpected error: %v", err)
+	}
+	if len(preview.FilesChanged) != 1 {
+		t.Fatalf("preview files changed = %d, expected 1", len(preview.FilesChanged))
+	}
+	if preview.FilesChanged[0].BackupContent != original {
+		t.Errorf("preview original content = %q, expected %q", preview.FilesChanged[0].BackupContent, original)
+	}
+
+	engine.config.Settings.DryRun = false
+	if _, err := publishTestChanges(engine, repoConfig, replacements...); err != nil {
+		t.Fatalf("publishRepositoryChanges() unexpected error: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if written == "" {
+		t.Fatal("real run did not write the file")
+	}
+	if preview.FilesChanged[0].PreviewContent != written {
+		t.Errorf("preview content = %q, real run wrote %q", preview.FilesChanged[0].PreviewContent, written)
+	}
+	if len(preview.ImagesChanged) != 2 {
+		t.Errorf("preview images changed = %d, expected 2", len(preview.ImagesChanged))
+	}
+}
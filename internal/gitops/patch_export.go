@@ -0,0 +1,174 @@
+package gitops
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func defaultPatchesDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".privateer", "reports", fmt.Sprintf("patches-%s", time.Now().Format("2006-01-02_15-04-05")))
+}
+
+func (e *Engine) exportDryRunPatches(ctx context.Context, repoConfig types.GitHubRepositoryConfig, validatedReplacements []types.ImageReplacement) {
+	if !e.config.GitOps.ExportPatches {
+		return
+	}
+
+	repository := repoConfig.Name
+	owner, repo, err := e.parseRepositoryName(repository)
+	if err != nil {
+		e.logger.Warn("dry_run_patch_export_failed").
+			Str("repository", repository).
+			Err(err).
+			Send()
+		return
+	}
+
+	for filePath, fileReplacements := range e.groupValidatedReplacementsByFile(validatedReplacements) {
+		content, err := e.githubClient.GetFileContent(ctx, owner, repo, filePath, repoConfig.PRSettings.BaseBranch)
+		if err != nil {
+			e.logger.Warn("dry_run_patch_export_failed").
+				Str("repository", repository).
+				Str("file", filePath).
+				Err(err).
+				Send()
+			continue
+		}
+
+		originalContent, err := base64.StdEncoding.DecodeString(content.Content)
+		if err != nil {
+			e.logger.Warn("dry_run_patch_export_failed").
+				Str("repository", repository).
+				Str("file", filePath).
+				Err(err).
+				Send()
+			continue
+		}
+
+		patchPath, err := e.writePatchFile(repository, filePath, string(originalContent), fileReplacements)
+		if err != nil {
+			e.logger.Warn("dry_run_patch_export_failed").
+				Str("repository", repository).
+				Str("file", filePath).
+				Err(err).
+				Send()
+			continue
+		}
+
+		if patchPath != "" {
+			e.logger.Info("dry_run_patch_exported").
+				Str("repository", repository).
+				Str("file", filePath).
+				Str("patch", patchPath).
+				Send()
+		}
+	}
+}
+
+func (e *Engine) writePatchFile(repository, filePath, content string, replacements []types.ImageReplacement) (string, error) {
+	previews, err := e.replacer.PreviewReplacementLines(content, replacements)
+	if err != nil {
+		return "", err
+	}
+	if len(previews) == 0 {
+		return "", nil
+	}
+
+	repoDir := filepath.Join(e.patchesDir, strings.ReplaceAll(repository, "/", "_"))
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		return "", fmt.Errorf("falha ao criar diretório de patches: %w", err)
+	}
+
+	patchPath := filepath.Join(repoDir, strings.ReplaceAll(strings.TrimPrefix(filePath, "/"), "/", "_")+".patch")
+	if err := os.WriteFile(patchPath, []byte(buildUnifiedDiff(filePath, content, previews)), 0644); err != nil {
+		return "", fmt.Errorf("falha ao escrever patch %s: %w", patchPath, err)
+	}
+
+	return patchPath, nil
+}
+
+const patchContextLines = 3
+
+// buildUnifiedDiff renders the replacements as a patch that git apply accepts
+// as is: each hunk carries its line counts and up to three lines of context,
+// and changes close enough to share context are merged into a single hunk.
+func buildUnifiedDiff(filePath, content string, previews []ReplacementPreview) string {
+	lines := strings.Split(content, "\n")
+	missingFinalNewline := lines[len(lines)-1] != ""
+	if !missingFinalNewline {
+		lines = lines[:len(lines)-1]
+	}
+
+	changed := make(map[int]string, len(previews))
+	var indexes []int
+	for _, preview := range previews {
+		index := preview.LineNumber - 1
+		if index < 0 || index >= len(lines) {
+			continue
+		}
+		if _, seen := changed[index]; !seen {
+			indexes = append(indexes, index)
+		}
+		changed[index] = preview.After
+	}
+	sort.Ints(indexes)
+
+	var diff strings.Builder
+	path := strings.TrimPrefix(filePath, "/")
+	fmt.Fprintf(&diff, "--- a/%s\n", path)
+	fmt.Fprintf(&diff, "+++ b/%s\n", path)
+
+	writeLine := func(prefix byte, index int, line string) {
+		fmt.Fprintf(&diff, "%c%s\n", prefix, line)
+		if missingFinalNewline && index == len(lines)-1 {
+			diff.WriteString("\\ No newline at end of file\n")
+		}
+	}
+
+	for i := 0; i < len(indexes); {
+		start := max(indexes[i]-patchContextLines, 0)
+		end := min(indexes[i]+patchContextLines+1, len(lines))
+		j := i + 1
+		for j < len(indexes) && indexes[j]-patchContextLines <= end {
+			end = min(indexes[j]+patchContextLines+1, len(lines))
+			j++
+		}
+
+		fmt.Fprintf(&diff, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1, end-start)
+		for line := start; line < end; {
+			if _, ok := changed[line]; !ok {
+				writeLine(' ', line, lines[line])
+				line++
+				continue
+			}
+
+			runEnd := line
+			for runEnd < end {
+				if _, ok := changed[runEnd]; !ok {
+					break
+				}
+				runEnd++
+			}
+			for k := line; k < runEnd; k++ {
+				writeLine('-', k, lines[k])
+			}
+			for k := line; k < runEnd; k++ {
+				writeLine('+', k, changed[k])
+			}
+			line = runEnd
+		}
+
+		i = j
+	}
+
+	return diff.String()
+}
@@ -0,0 +1,152 @@
+package gitops
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestEngine_writePatchFile(t *testing.T) {
+	log := logger.NewTest()
+	config := &types.Config{}
+	engine := &Engine{
+		logger:     log,
+		config:     config,
+		replacer:   NewImageReplacer(log, config),
+		patchesDir: t.TempDir(),
+	}
+
+	content := "apiVersion: apps/v1\nkind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n        - name: web\n          image: nginx:1.25\n"
+	replacements := []types.ImageReplacement{
+		{
+			SourceImage: "nginx:1.25",
+			TargetImage: "registry.company.com/nginx:1.25",
+			FilePath:    "apps/web/deployment.yaml",
+			FileType:    "kubernetes_manifest",
+		},
+	}
+
+	patchPath, err := engine.writePatchFile("company/manifests", "apps/web/deployment.yaml", content, replacements)
+	if err != nil {
+		t.Fatalf("writePatchFile() unexpected error: %v", err)
+	}
+
+	expectedPath := filepath.Join(engine.patchesDir, "company_manifests", "apps_web_deployment.yaml.patch")
+	if patchPath != expectedPath {
+		t.Errorf("patch path = %s, expected %s", patchPath, expectedPath)
+	}
+
+	data, err := os.ReadFile(patchPath)
+	if err != nil {
+		t.Fatalf("failed to read patch: %v", err)
+	}
+
+	expected := "--- a/apps/web/deployment.yaml\n" +
+		"+++ b/apps/web/deployment.yaml\n" +
+		"@@ -5,4 +5,4 @@\n" +
+		"     spec:\n" +
+		"       containers:\n" +
+		"         - name: web\n" +
+		"-          image: nginx:1.25\n" +
+		"+          image: registry.company.com/nginx:1.25\n"
+	if string(data) != expected {
+		t.Errorf("patch content = %q, expected %q", string(data), expected)
+	}
+}
+
+func TestEngine_writePatchFile_GitApply(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	log := logger.NewTest()
+	config := &types.Config{}
+	engine := &Engine{
+		logger:     log,
+		config:     config,
+		replacer:   NewImageReplacer(log, config),
+		patchesDir: t.TempDir(),
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		wantHunks int
+	}{
+		{
+			name:      "adjacent changes share one hunk",
+			content:   "services:\n  web:\n    image: nginx:1.25\n  cache:\n    image: redis:7.0\n  db:\n    image: postgres:16\n",
+			wantHunks: 1,
+		},
+		{
+			name:      "distant changes get separate hunks",
+			content:   "services:\n  web:\n    image: nginx:1.25\n" + strings.Repeat("    # comment\n", 10) + "  db:\n    image: postgres:16\n",
+			wantHunks: 2,
+		},
+		{
+			name:      "last line without newline",
+			content:   "services:\n  web:\n    image: nginx:1.25",
+			wantHunks: 1,
+		},
+	}
+
+	replacements := []types.ImageReplacement{
+		{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25", FilePath: "docker-compose.yml", FileType: "docker_compose"},
+		{SourceImage: "redis:7.0", TargetImage: "registry.company.com/redis:7.0", FilePath: "docker-compose.yml", FileType: "docker_compose"},
+		{SourceImage: "postgres:16", TargetImage: "registry.company.com/postgres:16", FilePath: "docker-compose.yml", FileType: "docker_compose"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patchPath, err := engine.writePatchFile("company/manifests", "docker-compose.yml", tt.content, replacements)
+			if err != nil {
+				t.Fatalf("writePatchFile() unexpected error: %v", err)
+			}
+			patch, err := os.ReadFile(patchPath)
+			if err != nil {
+				t.Fatalf("failed to read patch: %v", err)
+			}
+			if hunks := strings.Count(string(patch), "\n@@ "); hunks != tt.wantHunks {
+				t.Errorf("patch has %d hunks, expected %d:\n%s", hunks, tt.wantHunks, patch)
+			}
+
+			repoDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(repoDir, "docker-compose.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			for _, args := range [][]string{{"init", "-q"}, {"apply", "--check", patchPath}} {
+				cmd := exec.Command("git", args...)
+				cmd.Dir = repoDir
+				if output, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("git %s failed: %v\n%s\npatch:\n%s", strings.Join(args, " "), err, output, patch)
+				}
+			}
+		})
+	}
+}
+
+func TestEngine_writePatchFile_NoChanges(t *testing.T) {
+	log := logger.NewTest()
+	config := &types.Config{}
+	engine := &Engine{
+		logger:     log,
+		config:     config,
+		replacer:   NewImageReplacer(log, config),
+		patchesDir: t.TempDir(),
+	}
+
+	patchPath, err := engine.writePatchFile("company/manifests", "values.yaml", "image: redis:7.0\n", []types.ImageReplacement{
+		{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25"},
+	})
+	if err != nil {
+		t.Fatalf("writePatchFile() unexpected error: %v", err)
+	}
+	if patchPath != "" {
+		t.Errorf("expected no patch to be written, got %s", patchPath)
+	}
+}
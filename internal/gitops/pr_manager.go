@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -42,13 +43,18 @@ func (prm *PullRequestManager) CreatePullRequest(ctx context.Context, repoConfig
 		return nil, fmt.Errorf("falha ao obter informações do repositório: %w", err)
 	}
 
+	baseBranch := repository.DefaultBranch
+	if repoConfig.PRSettings.BaseBranch != "" {
+		baseBranch = repoConfig.PRSettings.BaseBranch
+	}
+
 	title := prm.generatePRTitle(gitopsResult)
 	body := prm.generatePRBody(repoConfig, gitopsResult)
 
 	prRequest := types.CreatePRRequest{
 		Title:               title,
 		Head:                gitopsResult.Branch,
-		Base:                repository.DefaultBranch,
+		Base:                baseBranch,
 		Body:                body,
 		MaintainerCanModify: true,
 		Draft:               repoConfig.PRSettings.Draft,
@@ -123,6 +129,58 @@ func (prm *PullRequestManager) CreatePullRequest(ctx context.Context, repoConfig
 	return prInfo, nil
 }
 
+// FindOpenPullRequest returns the open pull request whose head is branch, or
+// nil when there is none.
+func (prm *PullRequestManager) FindOpenPullRequest(ctx context.Context, owner, repo, branch string) (*types.PullRequestInfo, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/pulls?state=open&head=%s", owner, repo, url.QueryEscape(owner+":"+branch))
+	resp, err := prm.githubClient.MakeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar pull request da branch %s: %w", branch, err)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("falha ao buscar pull request da branch %s: status %d", branch, resp.StatusCode)
+	}
+
+	var prResponses []types.PullRequestResponse
+	if err := json.Unmarshal(resp.Body, &prResponses); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar pull requests: %w", err)
+	}
+	if len(prResponses) == 0 {
+		return nil, nil
+	}
+
+	prResponse := prResponses[0]
+	return &types.PullRequestInfo{
+		URL:       prResponse.HTMLURL,
+		Number:    prResponse.Number,
+		Title:     prResponse.Title,
+		Body:      prResponse.Body,
+		State:     prResponse.State,
+		CreatedAt: prResponse.CreatedAt,
+		UpdatedAt: prResponse.UpdatedAt,
+	}, nil
+}
+
+func (prm *PullRequestManager) GetPullRequestState(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, prNumber)
+	resp, err := prm.githubClient.MakeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("falha ao obter pull request #%d: %w", prNumber, err)
+	}
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("falha ao obter pull request #%d: status %d", prNumber, resp.StatusCode)
+	}
+
+	var prResponse types.PullRequestResponse
+	if err := json.Unmarshal(resp.Body, &prResponse); err != nil {
+		return "", fmt.Errorf("falha ao decodificar pull request: %w", err)
+	}
+
+	return prResponse.State, nil
+}
+
 func (prm *PullRequestManager) addReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers []string) error {
 	prm.logger.Debug("adding_reviewers").
 		Strs("reviewers", reviewers).
@@ -187,6 +245,147 @@ func (prm *PullRequestManager) addLabels(ctx context.Context, owner, repo string
 	return nil
 }
 
+func (prm *PullRequestManager) CreateTrackingIssue(ctx context.Context, repository string, results []*types.GitOpsResult) (*types.IssueResponse, error) {
+	owner, repo, err := prm.parseRepositoryName(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := prm.config.GitOps.TrackingIssue
+	title := settings.Title
+	if title == "" {
+		title = prm.generateTrackingIssueTitle(results)
+	}
+
+	issueRequest := types.CreateIssueRequest{
+		Title:  title,
+		Body:   prm.generateTrackingIssueBody(results),
+		Labels: settings.Labels,
+	}
+
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues", owner, repo)
+	payload, err := json.Marshal(issueRequest)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao codificar issue: %w", err)
+	}
+
+	resp, err := prm.githubClient.MakeRequest(ctx, "POST", endpoint, strings.NewReader(string(payload)))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar issue de acompanhamento: %w", err)
+	}
+
+	if resp.StatusCode != 201 {
+		return nil, fmt.Errorf("falha ao criar issue de acompanhamento: status %d - %s", resp.StatusCode, string(resp.Body))
+	}
+
+	var issue types.IssueResponse
+	if err := json.Unmarshal(resp.Body, &issue); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar issue: %w", err)
+	}
+
+	prm.logger.Info("tracking_issue_created").
+		Str("repository", repository).
+		Int("issue_number", issue.Number).
+		Str("url", issue.HTMLURL).
+		Int("pull_requests", len(results)).
+		Send()
+
+	return &issue, nil
+}
+
+func (prm *PullRequestManager) LinkPullRequestToIssue(ctx context.Context, result *types.GitOpsResult, issue *types.IssueResponse, totalPRs int) error {
+	owner, repo, err := prm.parseRepositoryName(result.Repository)
+	if err != nil {
+		return err
+	}
+
+	comment := types.CommentRequest{
+		Body: fmt.Sprintf("🔗 **Tracking issue**: %s\n\nThis PR is part of a coordinated migration across %d repositories. Check the tracking issue before merging.", issue.HTMLURL, totalPRs),
+	}
+
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, repo, result.PullRequest.Number)
+	payload, err := json.Marshal(comment)
+	if err != nil {
+		return fmt.Errorf("falha ao codificar comentário: %w", err)
+	}
+
+	resp, err := prm.githubClient.MakeRequest(ctx, "POST", endpoint, strings.NewReader(string(payload)))
+	if err != nil {
+		return fmt.Errorf("falha ao comentar no pull request #%d: %w", result.PullRequest.Number, err)
+	}
+
+	if resp.StatusCode != 201 {
+		return fmt.Errorf("falha ao comentar no pull request #%d: status %d", result.PullRequest.Number, resp.StatusCode)
+	}
+
+	if labels := prm.config.GitOps.TrackingIssue.Labels; len(labels) > 0 {
+		if err := prm.addLabels(ctx, owner, repo, result.PullRequest.Number, labels); err != nil {
+			return err
+		}
+		result.PullRequest.Labels = append(result.PullRequest.Labels, labels...)
+	}
+
+	return nil
+}
+
+func (prm *PullRequestManager) generateTrackingIssueTitle(results []*types.GitOpsResult) string {
+	if len(results) == 1 {
+		return fmt.Sprintf("🏴‍☠️ Track image migration in %s", results[0].Repository)
+	}
+
+	return fmt.Sprintf("🏴‍☠️ Track image migration across %d repositories", len(results))
+}
+
+func (prm *PullRequestManager) generateTrackingIssueBody(results []*types.GitOpsResult) string {
+	var body strings.Builder
+
+	body.WriteString("# 🏴‍☠️ Privateer: Coordinated Image Migration\n\n")
+	body.WriteString("This issue tracks the Pull Requests automatically generated by **Privateer** to migrate public Docker images to private registries across multiple repositories.\n\n")
+
+	body.WriteString("## 🔗 Pull Requests\n\n")
+	totalImages := 0
+	for _, result := range results {
+		body.WriteString(fmt.Sprintf("- [ ] **%s**: %s (%d images, %d files)\n",
+			result.Repository, result.PullRequest.URL, len(result.ImagesChanged), len(result.FilesChanged)))
+		totalImages += len(result.ImagesChanged)
+	}
+	body.WriteString("\n")
+
+	seen := make(map[string]bool)
+	var migrations []types.ImageReplacement
+	for _, result := range results {
+		for _, change := range result.ImagesChanged {
+			key := change.SourceImage + "=>" + change.TargetImage
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			migrations = append(migrations, change)
+		}
+	}
+
+	if len(migrations) > 0 {
+		body.WriteString("## 🔄 Image Migrations\n\n")
+		body.WriteString("| Source Image | Target Image |\n")
+		body.WriteString("|--------------|--------------|\n")
+		for _, change := range migrations {
+			body.WriteString(fmt.Sprintf("| `%s` | `%s` |\n",
+				prm.shortenImageName(change.SourceImage), prm.shortenImageName(change.TargetImage)))
+		}
+		body.WriteString("\n")
+	}
+
+	body.WriteString("## 📊 Summary\n\n")
+	body.WriteString(fmt.Sprintf("- **Pull Requests**: %d\n", len(results)))
+	body.WriteString(fmt.Sprintf("- **Image References Updated**: %d\n", totalImages))
+	body.WriteString(fmt.Sprintf("- **Generated at**: %s\n", time.Now().Format("2006-01-02 15:04:05 UTC")))
+
+	body.WriteString("\n---\n")
+	body.WriteString("*This issue was automatically created by [Privateer](https://github.com/kevinfinalboss/privateer) 🏴‍☠️*")
+
+	return body.String()
+}
+
 func (prm *PullRequestManager) generatePRTitle(gitopsResult *types.GitOpsResult) string {
 	if len(gitopsResult.ImagesChanged) == 1 {
 		return fmt.Sprintf("🏴‍☠️ Migrate %s to private registry", gitopsResult.ImagesChanged[0].SourceImage)
@@ -333,7 +532,7 @@ func (prm *PullRequestManager) ValidatePRSettings(repoConfig types.GitHubReposit
 func (prm *PullRequestManager) parseRepositoryName(repoName string) (owner, repo string, err error) {
 	parts := strings.Split(repoName, "/")
 	if len(parts) != 2 {
-		return "", "", fmt.Errorf("formato de repositório inválido: %s", repoName)
+		return "", "", fmt.Errorf("%w: %s", types.ErrInvalidRepoName, repoName)
 	}
 	return parts[0], parts[1], nil
 }
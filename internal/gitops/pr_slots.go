@@ -0,0 +1,97 @@
+package gitops
+
+import (
+	"sort"
+	"sync"
+)
+
+// prSlotQueue grants the max_prs slots in priority order. A repository only
+// waits until every higher-ranked repository has decided whether it opens a
+// pull request, not until it has finished publishing, so the remaining work
+// of the repositories still runs concurrently.
+type prSlotQueue struct {
+	mutex    sync.Mutex
+	cond     *sync.Cond
+	max      int
+	reserved int
+	ranks    map[string]int
+	decided  map[int]bool
+	next     int
+}
+
+func newPRSlotQueue(max int, targets []repositoryTarget) *prSlotQueue {
+	q := &prSlotQueue{
+		max:     max,
+		ranks:   make(map[string]int, len(targets)),
+		decided: make(map[int]bool, len(targets)),
+	}
+	q.cond = sync.NewCond(&q.mutex)
+	for rank, target := range targets {
+		q.ranks[target.config.Name] = rank
+	}
+	return q
+}
+
+func sortRepositoryTargets(targets []repositoryTarget) []repositoryTarget {
+	sorted := append([]repositoryTarget(nil), targets...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].config.Priority != sorted[j].config.Priority {
+			return sorted[i].config.Priority > sorted[j].config.Priority
+		}
+		return sorted[i].config.Name < sorted[j].config.Name
+	})
+	return sorted
+}
+
+func (q *prSlotQueue) reserve(repository string) bool {
+	if q.max <= 0 {
+		return true
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	rank, ranked := q.ranks[repository]
+	if ranked {
+		for q.next < rank {
+			q.cond.Wait()
+		}
+		defer q.decide(rank)
+	}
+
+	if q.reserved >= q.max {
+		return false
+	}
+	q.reserved++
+	return true
+}
+
+func (q *prSlotQueue) release() {
+	if q.max <= 0 {
+		return
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.reserved--
+}
+
+// finish passes the turn of a repository that does not call reserve, for
+// example because it had no changes, failed before publishing or reuses an
+// open pull request.
+func (q *prSlotQueue) finish(repository string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if rank, ranked := q.ranks[repository]; ranked {
+		q.decide(rank)
+	}
+}
+
+func (q *prSlotQueue) decide(rank int) {
+	q.decided[rank] = true
+	for q.decided[q.next] {
+		q.next++
+	}
+	q.cond.Broadcast()
+}
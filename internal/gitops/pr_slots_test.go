@@ -0,0 +1,81 @@
+package gitops
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestSortRepositoryTargets(t *testing.T) {
+	targets := sortRepositoryTargets([]repositoryTarget{
+		{config: types.GitHubRepositoryConfig{Name: "company/zeta", Priority: 1}},
+		{config: types.GitHubRepositoryConfig{Name: "company/alpha", Priority: 1}},
+		{config: types.GitHubRepositoryConfig{Name: "company/critical", Priority: 10}},
+	})
+
+	var names []string
+	for _, target := range targets {
+		names = append(names, target.config.Name)
+	}
+	expected := []string{"company/critical", "company/alpha", "company/zeta"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("sortRepositoryTargets() = %v, expected %v", names, expected)
+	}
+}
+
+func TestPRSlotQueue_ReservesInRankOrder(t *testing.T) {
+	targets := sortRepositoryTargets([]repositoryTarget{
+		{config: types.GitHubRepositoryConfig{Name: "company/low", Priority: 1}},
+		{config: types.GitHubRepositoryConfig{Name: "company/high", Priority: 10}},
+		{config: types.GitHubRepositoryConfig{Name: "company/mid", Priority: 5}},
+	})
+
+	for run := 0; run < 20; run++ {
+		queue := newPRSlotQueue(1, targets)
+
+		var mu sync.Mutex
+		granted := make(map[string]bool)
+		var wg sync.WaitGroup
+		for _, name := range []string{"company/low", "company/mid", "company/high"} {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				defer queue.finish(name)
+
+				ok := queue.reserve(name)
+				mu.Lock()
+				granted[name] = ok
+				mu.Unlock()
+			}(name)
+		}
+		wg.Wait()
+
+		expected := map[string]bool{"company/high": true, "company/mid": false, "company/low": false}
+		if !reflect.DeepEqual(granted, expected) {
+			t.Fatalf("run %d: granted = %v, expected %v", run, granted, expected)
+		}
+	}
+}
+
+func TestPRSlotQueue_ReleasedSlotGoesToNextRank(t *testing.T) {
+	targets := []repositoryTarget{
+		{config: types.GitHubRepositoryConfig{Name: "company/first"}},
+		{config: types.GitHubRepositoryConfig{Name: "company/second"}},
+	}
+	queue := newPRSlotQueue(1, targets)
+
+	if !queue.reserve("company/first") {
+		t.Fatal("first repository should get the slot")
+	}
+	queue.release()
+	queue.finish("company/first")
+
+	if !queue.reserve("company/second") {
+		t.Error("released slot should go to the next repository")
+	}
+	if !newPRSlotQueue(0, targets).reserve("company/second") {
+		t.Error("unlimited queue should always grant a slot")
+	}
+}
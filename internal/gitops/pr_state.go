@@ -0,0 +1,120 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/state"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const pullRequestsBucket = "gitops_pull_requests"
+
+type prState struct {
+	store      *state.Store
+	repository string
+	Entries    map[string]prStateEntry `json:"entries"`
+	dirty      bool
+}
+
+type prStateEntry struct {
+	FilePath    string `json:"file_path"`
+	SourceImage string `json:"source_image"`
+	TargetImage string `json:"target_image"`
+	PRNumber    int    `json:"pr_number"`
+	PRURL       string `json:"pr_url"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func loadPRState(store *state.Store, repository string) *prState {
+	prs := &prState{Entries: make(map[string]prStateEntry)}
+	if store == nil {
+		return prs
+	}
+
+	prs.store = store
+	prs.repository = repository
+
+	if found, err := store.Get(pullRequestsBucket, repository, prs); err != nil || !found || prs.Entries == nil {
+		prs.Entries = make(map[string]prStateEntry)
+	}
+
+	return prs
+}
+
+func prStateKey(replacement types.ImageReplacement) string {
+	return replacement.FilePath + "|" + replacement.SourceImage + "|" + replacement.TargetImage
+}
+
+func (s *prState) record(replacements []types.ImageReplacement, pr *types.PullRequestInfo) {
+	for _, replacement := range replacements {
+		s.Entries[prStateKey(replacement)] = prStateEntry{
+			FilePath:    replacement.FilePath,
+			SourceImage: replacement.SourceImage,
+			TargetImage: replacement.TargetImage,
+			PRNumber:    pr.Number,
+			PRURL:       pr.URL,
+			CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	s.dirty = true
+}
+
+func (s *prState) save() error {
+	if s.store == nil || !s.dirty {
+		return nil
+	}
+
+	if err := s.store.Put(pullRequestsBucket, s.repository, s); err != nil {
+		return fmt.Errorf("falha ao gravar estado do GitOps: %w", err)
+	}
+
+	s.dirty = false
+	return nil
+}
+
+func (e *Engine) skipPendingReplacements(ctx context.Context, owner, repo string, prs *prState, replacements []types.ImageReplacement) []types.ImageReplacement {
+	prOpen := make(map[int]bool)
+	pending := make([]types.ImageReplacement, 0, len(replacements))
+
+	for _, replacement := range replacements {
+		key := prStateKey(replacement)
+		entry, found := prs.Entries[key]
+		if !found {
+			pending = append(pending, replacement)
+			continue
+		}
+
+		isOpen, checked := prOpen[entry.PRNumber]
+		if !checked {
+			status, err := e.prManager.GetPullRequestState(ctx, owner, repo, entry.PRNumber)
+			if err != nil {
+				e.logger.Warn("pull_request_state_check_failed").
+					Str("repository", owner+"/"+repo).
+					Int("pr_number", entry.PRNumber).
+					Err(err).
+					Send()
+			}
+			isOpen = err != nil || status == "open"
+			prOpen[entry.PRNumber] = isOpen
+		}
+
+		if !isOpen {
+			delete(prs.Entries, key)
+			prs.dirty = true
+			pending = append(pending, replacement)
+			continue
+		}
+
+		e.logger.Info("replacement_already_proposed").
+			Str("repository", owner+"/"+repo).
+			Str("file_path", replacement.FilePath).
+			Str("source", replacement.SourceImage).
+			Int("pr_number", entry.PRNumber).
+			Str("pr_url", entry.PRURL).
+			Send()
+	}
+
+	return pending
+}
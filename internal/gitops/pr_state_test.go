@@ -0,0 +1,111 @@
+package gitops
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/github"
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/internal/state"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestEngine_publishRepositoryChanges_Incremental(t *testing.T) {
+	var mu sync.Mutex
+	pullState := "open"
+	branchesCreated, prsCreated := 0, 0
+
+	manifest := base64.StdEncoding.EncodeToString([]byte("spec:\n  containers:\n    - name: web\n      image: nginx:1.25\n"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/company/manifests":
+			w.Write([]byte(`{"full_name": "company/manifests", "default_branch": "main"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/company/manifests/branches":
+			w.Write([]byte(`[{"name": "main", "commit": {"sha": "abc123"}}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/company/manifests/git/refs":
+			branchesCreated++
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/company/manifests/contents/apps/deploy.yaml":
+			w.Write([]byte(`{"path": "apps/deploy.yaml", "sha": "file-sha", "content": "` + manifest + `"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/repos/company/manifests/contents/apps/deploy.yaml":
+			w.Write([]byte(`{"commit": {"sha": "def456"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/company/manifests/pulls":
+			prsCreated++
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 7, "state": "open", "html_url": "https://github.com/company/manifests/pull/7"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/company/manifests/pulls/7":
+			w.Write([]byte(`{"number": 7, "state": "` + pullState + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{Token: "token", APIURL: server.URL},
+		GitOps: types.GitOpsConfig{AutoPR: true, BranchPrefix: "privateer/", Incremental: true},
+	}
+	log := logger.NewTest()
+	githubClient := github.NewClient(&config.GitHub, log)
+	engine := NewEngine(githubClient, registry.NewManager(log), log, config)
+	store, err := state.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("state.Open() error = %v", err)
+	}
+	defer store.Close()
+	engine.UseState(store)
+
+	repoConfig := types.GitHubRepositoryConfig{Name: "company/manifests", Enabled: true}
+	replacements := []types.ImageReplacement{{
+		SourceImage: "nginx:1.25",
+		TargetImage: "harbor.company.com/library/nginx:1.25",
+		FilePath:    "apps/deploy.yaml",
+		FileType:    "kubernetes",
+	}}
+
+	publish := func() *types.GitOpsResult {
+		t.Helper()
+		result := &types.GitOpsResult{Repository: repoConfig.Name}
+		if err := engine.publishRepositoryChanges(context.Background(), github.NewRepositoryManager(githubClient), repoConfig, result, replacements); err != nil {
+			t.Fatalf("publishRepositoryChanges() unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if result := publish(); result.PullRequest == nil || result.PullRequest.Number != 7 {
+		t.Fatalf("first run pull request = %+v, expected #7", result.PullRequest)
+	}
+
+	result := publish()
+	if result.PullRequest != nil || result.Branch != "" || len(result.ImagesChanged) != 0 {
+		t.Errorf("second run result = %+v, expected still-open change to be skipped", result)
+	}
+
+	mu.Lock()
+	if branchesCreated != 1 || prsCreated != 1 {
+		t.Errorf("branches = %d, prs = %d after open PR rerun, expected 1 each", branchesCreated, prsCreated)
+	}
+	pullState = "closed"
+	mu.Unlock()
+
+	if result := publish(); result.PullRequest == nil {
+		t.Error("expected a new pull request once the previous one was closed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if prsCreated != 2 {
+		t.Errorf("prs = %d, expected a second pull request after closing", prsCreated)
+	}
+}
@@ -1,6 +1,7 @@
 package gitops
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -31,6 +32,14 @@ func (ir *ImageReplacer) ReplaceImagesInContent(content string, replacements []t
 	var actualReplacements []types.ImageReplacement
 
 	for _, replacement := range replacements {
+		if replacement.TargetDigest == "" && utils.SameImage(replacement.SourceImage, replacement.TargetImage) {
+			ir.logger.Debug("image_replacement_noop").
+				Str("source", replacement.SourceImage).
+				Str("target", replacement.TargetImage).
+				Send()
+			continue
+		}
+
 		newContent, wasReplaced, err := ir.replaceImageInContent(modifiedContent, replacement)
 		if err != nil {
 			return content, nil, fmt.Errorf("falha ao substituir imagem %s: %w", replacement.SourceImage, err)
@@ -69,6 +78,10 @@ func (ir *ImageReplacer) replaceImageInContent(content string, replacement types
 		return ir.replaceKustomize(content, replacement)
 	case "kubernetes_manifest":
 		return ir.replaceKubernetesManifest(content, replacement)
+	case "json":
+		return ir.replaceJSON(content, replacement)
+	case "dockerfile":
+		return ir.replaceDockerfile(content, replacement)
 	default:
 		return ir.replaceGeneric(content, replacement)
 	}
@@ -88,18 +101,50 @@ func (ir *ImageReplacer) replaceKubernetesManifest(content string, replacement t
 	if !strings.Contains(targetImage, ":") {
 		targetImage += ":" + targetTag
 	}
+	if replacement.TargetDigest != "" {
+		targetImage = utils.WithDigest(targetImage, replacement.TargetDigest)
+	}
 
+	replaced := false
 	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		if re.MatchString(content) {
-			newContent := re.ReplaceAllString(content, "${1}"+targetImage+"${2}")
-			if newContent != content {
-				return newContent, true, nil
-			}
+		if newContent, ok := ir.replaceSkippingTargetLines(content, regexp.MustCompile(pattern), targetImage); ok {
+			content = newContent
+			replaced = true
+			break
 		}
 	}
 
-	return content, false, nil
+	if newContent, ok := ir.replaceEnvValues(content, replacement.SourceImage, targetImage); ok {
+		content = newContent
+		replaced = true
+	}
+
+	return content, replaced, nil
+}
+
+func (ir *ImageReplacer) replaceEnvValues(content, sourceImage, targetImage string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	modified := false
+
+	for _, envValue := range utils.FindEnvValues(lines) {
+		if envValue.Value != sourceImage || envValue.Value == targetImage {
+			continue
+		}
+
+		lines[envValue.Line] = envValue.WithValue(targetImage)
+		modified = true
+
+		ir.logger.Debug("env_image_replaced").
+			Str("source", sourceImage).
+			Str("target", targetImage).
+			Int("line", envValue.Line+1).
+			Send()
+	}
+
+	if !modified {
+		return content, false
+	}
+	return strings.Join(lines, "\n"), true
 }
 
 func (ir *ImageReplacer) replaceHelmSeparatedPrecise(content string, replacement types.ImageReplacement) (string, bool, error) {
@@ -110,6 +155,9 @@ func (ir *ImageReplacer) replaceHelmSeparatedPrecise(content string, replacement
 	targetRegistry := utils.ExtractRegistry(replacement.TargetImage)
 	targetRepo := ir.extractTargetRepository(replacement.TargetImage)
 	targetTag := utils.ExtractTag(replacement.TargetImage)
+	if replacement.TargetDigest != "" {
+		targetTag += "@" + replacement.TargetDigest
+	}
 
 	ir.logger.Debug("helm_separated_precise_replacement").
 		Str("source_registry", sourceRegistry).
@@ -165,7 +213,7 @@ func (ir *ImageReplacer) replaceHelmSeparatedPrecise(content string, replacement
 		if imageSection.tagLine > 0 && imageSection.tagLine <= len(lines) && sourceTag != targetTag {
 			tagPattern := fmt.Sprintf(`(\s*tag:\s*["']?)%s(["']?\s*)`, regexp.QuoteMeta(sourceTag))
 			re := regexp.MustCompile(tagPattern)
-			if re.MatchString(lines[imageSection.tagLine-1]) {
+			if re.MatchString(lines[imageSection.tagLine-1]) && !strings.Contains(lines[imageSection.tagLine-1], targetTag) {
 				lines[imageSection.tagLine-1] = re.ReplaceAllString(lines[imageSection.tagLine-1], "${1}"+targetTag+"${2}")
 				modified = true
 				ir.logger.Info("helm_tag_replaced_precise").
@@ -200,6 +248,11 @@ func (ir *ImageReplacer) replaceHelmCombined(content string, replacement types.I
 	sourceParsed := utils.ParseImageName(sourceImage)
 	targetParsed := utils.ParseImageName(targetImage)
 
+	targetTag := targetParsed.Tag
+	if replacement.TargetDigest != "" {
+		targetTag += "@" + replacement.TargetDigest
+	}
+
 	ir.logger.Debug("helm_combined_replacement").
 		Str("source_image", sourceImage).
 		Str("target_image", targetImage).
@@ -207,6 +260,11 @@ func (ir *ImageReplacer) replaceHelmCombined(content string, replacement types.I
 		Str("target_combined_repo", fmt.Sprintf("%s/%s", targetParsed.Registry, targetParsed.FullRepository)).
 		Send()
 
+	targetCombinedRepo := fmt.Sprintf("%s/%s", targetParsed.Registry, targetParsed.FullRepository)
+	if targetParsed.Registry == "docker.io" {
+		targetCombinedRepo = targetParsed.FullRepository
+	}
+
 	lines := strings.Split(content, "\n")
 	modified := false
 
@@ -214,17 +272,11 @@ func (ir *ImageReplacer) replaceHelmCombined(content string, replacement types.I
 		trimmedLine := strings.TrimSpace(line)
 
 		if strings.Contains(trimmedLine, "repository:") {
-			sourceCombinedRepo := fmt.Sprintf("%s/%s", sourceParsed.Registry, sourceParsed.FullRepository)
-			if sourceParsed.Registry == "docker.io" {
-				sourceCombinedRepo = sourceParsed.FullRepository
-			}
-
-			repoPattern := fmt.Sprintf(`(\s*repository:\s*["']?)%s(["']?\s*)`, regexp.QuoteMeta(sourceCombinedRepo))
-			re := regexp.MustCompile(repoPattern)
-			if re.MatchString(line) {
-				targetCombinedRepo := fmt.Sprintf("%s/%s", targetParsed.Registry, targetParsed.FullRepository)
-				if targetParsed.Registry == "docker.io" {
-					targetCombinedRepo = targetParsed.FullRepository
+			for _, sourceCombinedRepo := range helmCombinedRepositories(sourceParsed) {
+				repoPattern := fmt.Sprintf(`^(\s*repository:\s*["']?)%s(["']?\s*(?:#.*)?)$`, regexp.QuoteMeta(sourceCombinedRepo))
+				re := regexp.MustCompile(repoPattern)
+				if !re.MatchString(line) {
+					continue
 				}
 
 				lines[i] = re.ReplaceAllString(line, "${1}"+targetCombinedRepo+"${2}")
@@ -234,18 +286,19 @@ func (ir *ImageReplacer) replaceHelmCombined(content string, replacement types.I
 					Str("new", targetCombinedRepo).
 					Int("line", i+1).
 					Send()
+				break
 			}
 		}
 
-		if strings.Contains(trimmedLine, "tag:") && sourceParsed.Tag != targetParsed.Tag {
+		if strings.Contains(trimmedLine, "tag:") && sourceParsed.Tag != targetTag && !strings.Contains(line, targetTag) {
 			tagPattern := fmt.Sprintf(`(\s*tag:\s*["']?)%s(["']?\s*)`, regexp.QuoteMeta(sourceParsed.Tag))
 			re := regexp.MustCompile(tagPattern)
 			if re.MatchString(line) {
-				lines[i] = re.ReplaceAllString(line, "${1}"+targetParsed.Tag+"${2}")
+				lines[i] = re.ReplaceAllString(line, "${1}"+targetTag+"${2}")
 				modified = true
 				ir.logger.Info("helm_combined_tag_replaced").
 					Str("old", sourceParsed.Tag).
-					Str("new", targetParsed.Tag).
+					Str("new", targetTag).
 					Int("line", i+1).
 					Send()
 			}
@@ -262,6 +315,18 @@ func (ir *ImageReplacer) replaceHelmCombined(content string, replacement types.I
 	return content, false, nil
 }
 
+func helmCombinedRepositories(parsed *types.ParsedImage) []string {
+	if parsed.Registry != "docker.io" {
+		return []string{fmt.Sprintf("%s/%s", parsed.Registry, parsed.FullRepository)}
+	}
+
+	repositories := []string{"docker.io/" + parsed.FullRepository, parsed.FullRepository}
+	if parsed.Namespace == "library" {
+		repositories = append(repositories, "docker.io/"+parsed.Repository, parsed.Repository)
+	}
+	return repositories
+}
+
 type ImageSection struct {
 	found        bool
 	startLine    int
@@ -370,10 +435,17 @@ func (ir *ImageReplacer) extractTargetRepository(imageName string) string {
 }
 
 func (ir *ImageReplacer) replaceKustomize(content string, replacement types.ImageReplacement) (string, bool, error) {
-	sourceRepo := ir.extractRepository(replacement.SourceImage)
-	sourceTag := ir.extractTag(replacement.SourceImage)
-	targetRepo := ir.extractRepository(replacement.TargetImage)
-	targetTag := ir.extractTag(replacement.TargetImage)
+	sourceRepo, sourceTag, sourceDigest := utils.SplitReference(replacement.SourceImage)
+	targetRepo, targetTag, targetDigest := utils.SplitReference(replacement.TargetImage)
+	if replacement.TargetDigest != "" {
+		targetDigest = replacement.TargetDigest
+	}
+	if sourceTag == "" && sourceDigest == "" {
+		sourceTag = "latest"
+	}
+	if targetTag == "" && targetDigest == "" {
+		targetTag = "latest"
+	}
 
 	lines := strings.Split(content, "\n")
 	modified := false
@@ -409,7 +481,14 @@ func (ir *ImageReplacer) replaceKustomize(content string, replacement types.Imag
 					}
 				}
 
-				if strings.Contains(trimmedLine, "newTag:") {
+				if strings.Contains(trimmedLine, "newTag:") && sourceTag != "" && targetDigest != "" {
+					newTagPattern := fmt.Sprintf(`(\s*)newTag:\s*["']?%s["']?(\s*)`, regexp.QuoteMeta(sourceTag))
+					re := regexp.MustCompile(newTagPattern)
+					if re.MatchString(line) {
+						lines[i] = re.ReplaceAllString(line, "${1}digest: "+targetDigest+"${2}")
+						modified = true
+					}
+				} else if strings.Contains(trimmedLine, "newTag:") && sourceTag != "" {
 					newTagPattern := fmt.Sprintf(`(\s*newTag:\s*["']?)%s(["']?\s*)`, regexp.QuoteMeta(sourceTag))
 					re := regexp.MustCompile(newTagPattern)
 					if re.MatchString(line) {
@@ -417,6 +496,19 @@ func (ir *ImageReplacer) replaceKustomize(content string, replacement types.Imag
 						modified = true
 					}
 				}
+
+				if strings.Contains(trimmedLine, "digest:") && sourceDigest != "" {
+					digestPattern := fmt.Sprintf(`(\s*)digest:\s*["']?%s["']?(\s*)`, regexp.QuoteMeta(sourceDigest))
+					re := regexp.MustCompile(digestPattern)
+					if re.MatchString(line) {
+						if targetDigest != "" {
+							lines[i] = re.ReplaceAllString(line, "${1}digest: "+targetDigest+"${2}")
+						} else {
+							lines[i] = re.ReplaceAllString(line, "${1}newTag: "+targetTag+"${2}")
+						}
+						modified = modified || lines[i] != line
+					}
+				}
 			}
 
 			if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") &&
@@ -440,19 +532,99 @@ func (ir *ImageReplacer) replaceGeneric(content string, replacement types.ImageR
 		fmt.Sprintf(`(:\s*["']?)%s(["']?)`, regexp.QuoteMeta(replacement.SourceImage)),
 	}
 
+	targetImage := replacement.TargetImage
+	if replacement.TargetDigest != "" {
+		targetImage = utils.WithDigest(targetImage, replacement.TargetDigest)
+	}
+
 	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		if re.MatchString(content) {
-			newContent := re.ReplaceAllString(content, "${1}"+replacement.TargetImage+"${2}")
-			if newContent != content {
-				return newContent, true, nil
-			}
+		if newContent, replaced := ir.replaceSkippingTargetLines(content, regexp.MustCompile(pattern), targetImage); replaced {
+			return newContent, true, nil
 		}
 	}
 
 	return content, false, nil
 }
 
+func (ir *ImageReplacer) replaceJSON(content string, replacement types.ImageReplacement) (string, bool, error) {
+	pattern := regexp.MustCompile(fmt.Sprintf(`("image"\s*:\s*")%s(")`, regexp.QuoteMeta(replacement.SourceImage)))
+
+	targetImage := replacement.TargetImage
+	if replacement.TargetDigest != "" {
+		targetImage = utils.WithDigest(targetImage, replacement.TargetDigest)
+	}
+
+	newContent, replaced := ir.replaceSkippingTargetLines(content, pattern, targetImage)
+	if !replaced {
+		return content, false, nil
+	}
+
+	if !json.Valid([]byte(newContent)) {
+		return content, false, fmt.Errorf("JSON inválido após substituir %s", replacement.SourceImage)
+	}
+
+	return newContent, true, nil
+}
+
+func (ir *ImageReplacer) replaceDockerfile(content string, replacement types.ImageReplacement) (string, bool, error) {
+	pattern := regexp.MustCompile(fmt.Sprintf(`(?mi)(^\s*FROM\s+(?:--\S+\s+)*)%s(\s|$)`, regexp.QuoteMeta(replacement.SourceImage)))
+
+	targetImage := replacement.TargetImage
+	if replacement.TargetDigest != "" {
+		targetImage = utils.WithDigest(targetImage, replacement.TargetDigest)
+	}
+
+	newContent, replaced := ir.replaceSkippingTargetLines(content, pattern, targetImage)
+	return newContent, replaced, nil
+}
+
+func (ir *ImageReplacer) replaceSkippingTargetLines(content string, re *regexp.Regexp, targetImage string) (string, bool) {
+	var result strings.Builder
+	last := 0
+	modified := false
+
+	for _, match := range re.FindAllStringSubmatchIndex(content, -1) {
+		imageStart := match[3]
+		lineStart := strings.LastIndex(content[:imageStart], "\n") + 1
+		lineEnd := len(content)
+		if idx := strings.Index(content[imageStart:], "\n"); idx >= 0 {
+			lineEnd = imageStart + idx
+		}
+
+		imageValue := content[imageStart:lineEnd]
+		if idx := strings.IndexAny(imageValue, " \t\"'"); idx >= 0 {
+			imageValue = imageValue[:idx]
+		}
+		if utils.IsTemplatedImage(imageValue) {
+			ir.logger.Debug("templated_image_skipped").
+				Int("line", strings.Count(content[:imageStart], "\n")+1).
+				Send()
+			continue
+		}
+
+		if strings.Contains(content[lineStart:lineEnd], targetImage) {
+			ir.logger.Debug("image_already_replaced").
+				Str("target", targetImage).
+				Int("line", strings.Count(content[:imageStart], "\n")+1).
+				Send()
+			continue
+		}
+
+		result.WriteString(content[last:match[0]])
+		result.Write(re.ExpandString(nil, "${1}"+targetImage+"${2}", content, match))
+		last = match[1]
+		modified = true
+	}
+
+	if !modified {
+		return content, false
+	}
+
+	result.WriteString(content[last:])
+	newContent := result.String()
+	return newContent, newContent != content
+}
+
 func (ir *ImageReplacer) validateReplacedContent(content string) error {
 	if !ir.config.GitOps.ValidationRules.ValidateYAML {
 		return nil
@@ -498,26 +670,69 @@ func (ir *ImageReplacer) extractTag(imageName string) string {
 	return utils.ExtractTag(imageName)
 }
 
-func (ir *ImageReplacer) PreviewReplacements(content string, replacements []types.ImageReplacement) ([]string, error) {
-	var previews []string
+type ReplacementPreview struct {
+	LineNumber int
+	Before     string
+	After      string
+}
 
+func (ir *ImageReplacer) PreviewReplacementLines(content string, replacements []types.ImageReplacement) ([]ReplacementPreview, error) {
+	modifiedContent := content
 	for _, replacement := range replacements {
-		lines := strings.Split(content, "\n")
-
-		for i, line := range lines {
-			if strings.Contains(line, replacement.SourceImage) {
-				preview := fmt.Sprintf("Linha %d: %s → %s",
-					i+1,
-					strings.TrimSpace(line),
-					strings.ReplaceAll(strings.TrimSpace(line), replacement.SourceImage, replacement.TargetImage))
-				previews = append(previews, preview)
-			}
+		if replacement.TargetDigest == "" && utils.SameImage(replacement.SourceImage, replacement.TargetImage) {
+			ir.logger.Debug("image_replacement_noop").
+				Str("source", replacement.SourceImage).
+				Str("target", replacement.TargetImage).
+				Send()
+			continue
+		}
+
+		newContent, wasReplaced, err := ir.replaceImageInContent(modifiedContent, replacement)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao simular substituição da imagem %s: %w", replacement.SourceImage, err)
+		}
+		if wasReplaced {
+			modifiedContent = newContent
+		}
+	}
+
+	originalLines := strings.Split(content, "\n")
+	modifiedLines := strings.Split(modifiedContent, "\n")
+	if len(originalLines) != len(modifiedLines) {
+		return nil, fmt.Errorf("substituição alterou o número de linhas (%d → %d)", len(originalLines), len(modifiedLines))
+	}
+
+	var previews []ReplacementPreview
+	for i := range originalLines {
+		if originalLines[i] != modifiedLines[i] {
+			previews = append(previews, ReplacementPreview{
+				LineNumber: i + 1,
+				Before:     originalLines[i],
+				After:      modifiedLines[i],
+			})
 		}
 	}
 
 	return previews, nil
 }
 
+func (ir *ImageReplacer) PreviewReplacements(content string, replacements []types.ImageReplacement) ([]string, error) {
+	lines, err := ir.PreviewReplacementLines(content, replacements)
+	if err != nil {
+		return nil, err
+	}
+
+	var previews []string
+	for _, preview := range lines {
+		previews = append(previews, fmt.Sprintf("Linha %d: %s → %s",
+			preview.LineNumber,
+			strings.TrimSpace(preview.Before),
+			strings.TrimSpace(preview.After)))
+	}
+
+	return previews, nil
+}
+
 func (ir *ImageReplacer) GetReplacementStats(replacements []types.ImageReplacement) map[string]int {
 	stats := make(map[string]int)
 
@@ -0,0 +1,543 @@
+package gitops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func newTestImageReplacer() *ImageReplacer {
+	return NewImageReplacer(logger.NewTest(), &types.Config{})
+}
+
+func TestImageReplacer_PreviewReplacementLines_MatchesReplacement(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		replacements []types.ImageReplacement
+		expected     []ReplacementPreview
+	}{
+		{
+			name: "kubernetes manifest ignores comments mentioning the image",
+			content: "# migrated from nginx:1.25\n" +
+				"spec:\n" +
+				"  containers:\n" +
+				"    - name: web\n" +
+				"      image: nginx:1.25\n",
+			replacements: []types.ImageReplacement{
+				{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25", FileType: "kubernetes_manifest"},
+			},
+			expected: []ReplacementPreview{
+				{LineNumber: 5, Before: "      image: nginx:1.25", After: "      image: registry.company.com/nginx:1.25"},
+			},
+		},
+		{
+			name: "helm separated values report each changed line",
+			content: "image:\n" +
+				"  registry: docker.io\n" +
+				"  repository: bitnami/redis\n" +
+				"  tag: 7.0.0\n",
+			replacements: []types.ImageReplacement{
+				{
+					SourceImage: "docker.io/bitnami/redis:7.0.0",
+					TargetImage: "harbor.company.com/bitnami/redis:7.0.0",
+					FileType:    "helm_separated",
+					LineNumber:  3,
+				},
+			},
+			expected: []ReplacementPreview{
+				{LineNumber: 2, Before: "  registry: docker.io", After: "  registry: harbor.company.com"},
+			},
+		},
+		{
+			name: "kustomize rewrites only the matching image block",
+			content: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: nginx\n" +
+				"    newTag: 1.25\n" +
+				"  - name: redis\n" +
+				"    newName: redis\n" +
+				"    newTag: 1.25\n",
+			replacements: []types.ImageReplacement{
+				{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25", FileType: "kustomize"},
+			},
+			expected: []ReplacementPreview{
+				{LineNumber: 3, Before: "    newName: nginx", After: "    newName: registry.company.com/nginx"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replacer := newTestImageReplacer()
+
+			previews, err := replacer.PreviewReplacementLines(tt.content, tt.replacements)
+			if err != nil {
+				t.Fatalf("PreviewReplacementLines() unexpected error: %v", err)
+			}
+
+			if len(previews) != len(tt.expected) {
+				t.Fatalf("PreviewReplacementLines() = %+v, expected %+v", previews, tt.expected)
+			}
+			for i := range previews {
+				if previews[i] != tt.expected[i] {
+					t.Errorf("preview[%d] = %+v, expected %+v", i, previews[i], tt.expected[i])
+				}
+			}
+
+			replaced, _, err := replacer.ReplaceImagesInContent(tt.content, tt.replacements)
+			if err != nil {
+				t.Fatalf("ReplaceImagesInContent() unexpected error: %v", err)
+			}
+
+			lines := strings.Split(tt.content, "\n")
+			for _, preview := range previews {
+				lines[preview.LineNumber-1] = preview.After
+			}
+			if applied := strings.Join(lines, "\n"); applied != replaced {
+				t.Errorf("previews applied = %q, actual replacement = %q", applied, replaced)
+			}
+		})
+	}
+}
+
+func TestImageReplacer_PreviewReplacements_Format(t *testing.T) {
+	replacer := newTestImageReplacer()
+
+	previews, err := replacer.PreviewReplacements("spec:\n  image: nginx:1.25\n", []types.ImageReplacement{
+		{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25", FileType: "kubernetes_manifest"},
+	})
+	if err != nil {
+		t.Fatalf("PreviewReplacements() unexpected error: %v", err)
+	}
+
+	expected := "Linha 2: image: nginx:1.25 → image: registry.company.com/nginx:1.25"
+	if len(previews) != 1 || previews[0] != expected {
+		t.Errorf("PreviewReplacements() = %v, expected [%s]", previews, expected)
+	}
+}
+
+func TestImageReplacer_ReplaceImagesInContent_Idempotent(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		replacement types.ImageReplacement
+		expected    string
+	}{
+		{
+			name:    "generic value pinned to digest",
+			content: "app:\n  image: \"nginx:1.25\"\n",
+			replacement: types.ImageReplacement{
+				SourceImage: "nginx:1.25",
+				TargetImage: "nginx:1.25@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac",
+				FileType:    "generic",
+			},
+			expected: "app:\n  image: \"nginx:1.25@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac\"\n",
+		},
+		{
+			name:    "kubernetes manifest with suffixed target tag",
+			content: "spec:\n  containers:\n    - name: web\n      image: nginx:1.25\n",
+			replacement: types.ImageReplacement{
+				SourceImage: "nginx:1.25",
+				TargetImage: "nginx:1.25-private",
+				FileType:    "kubernetes_manifest",
+			},
+			expected: "spec:\n  containers:\n    - name: web\n      image: nginx:1.25-private\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replacer := newTestImageReplacer()
+			replacements := []types.ImageReplacement{tt.replacement}
+
+			first, applied, err := replacer.ReplaceImagesInContent(tt.content, replacements)
+			if err != nil {
+				t.Fatalf("first pass unexpected error: %v", err)
+			}
+			if first != tt.expected {
+				t.Fatalf("first pass = %q, expected %q", first, tt.expected)
+			}
+			if len(applied) != 1 {
+				t.Errorf("first pass applied %d replacements, expected 1", len(applied))
+			}
+
+			second, applied, err := replacer.ReplaceImagesInContent(first, replacements)
+			if err != nil {
+				t.Fatalf("second pass unexpected error: %v", err)
+			}
+			if second != first {
+				t.Errorf("second pass changed content: %q", second)
+			}
+			if len(applied) != 0 {
+				t.Errorf("second pass applied %d replacements, expected 0", len(applied))
+			}
+		})
+	}
+}
+
+func TestImageReplacer_ReplaceImagesInContent_DeploymentEnv(t *testing.T) {
+	replacer := newTestImageReplacer()
+	content := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      containers:
+        - name: api
+          image: nginx:1.25
+          env:
+            - name: SIDECAR_IMAGE
+              value: "nginx:1.25"
+            - name: INIT_IMAGE
+              value: 'nginx:1.25'
+            - value: nginx:1.25
+              name: DEBUG_IMAGE
+            - name: LOG_LEVEL
+              value: info
+          args:
+            - --config
+            - /etc/nginx.conf
+`
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      containers:
+        - name: api
+          image: registry.company.com/nginx:1.25
+          env:
+            - name: SIDECAR_IMAGE
+              value: "registry.company.com/nginx:1.25"
+            - name: INIT_IMAGE
+              value: 'registry.company.com/nginx:1.25'
+            - value: registry.company.com/nginx:1.25
+              name: DEBUG_IMAGE
+            - name: LOG_LEVEL
+              value: info
+          args:
+            - --config
+            - /etc/nginx.conf
+`
+
+	replacements := []types.ImageReplacement{
+		{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25", FileType: "kubernetes_manifest"},
+	}
+	result, applied, err := replacer.ReplaceImagesInContent(content, replacements)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != expected {
+		t.Errorf("result = %s\nexpected %s", result, expected)
+	}
+	if len(applied) != 1 {
+		t.Errorf("applied %d replacements, expected 1", len(applied))
+	}
+
+	second, applied, err := replacer.ReplaceImagesInContent(result, replacements)
+	if err != nil {
+		t.Fatalf("second pass unexpected error: %v", err)
+	}
+	if second != result || len(applied) != 0 {
+		t.Errorf("second pass changed content or applied %d replacements", len(applied))
+	}
+}
+
+func TestImageReplacer_ReplaceImagesInContent_SkipsEquivalentTarget(t *testing.T) {
+	replacer := newTestImageReplacer()
+	content := "spec:\n  containers:\n    - name: web\n      image: nginx:1.25\n"
+
+	result, applied, err := replacer.ReplaceImagesInContent(content, []types.ImageReplacement{
+		{SourceImage: "nginx:1.25", TargetImage: "index.docker.io/library/nginx:1.25", FileType: "kubernetes_manifest"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != content {
+		t.Errorf("content changed for an equivalent reference: %q", result)
+	}
+	if len(applied) != 0 {
+		t.Errorf("applied %d replacements, expected 0", len(applied))
+	}
+}
+
+func TestImageReplacer_ReplaceImagesInContent_PinnedDigest(t *testing.T) {
+	digest := "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+	tests := []struct {
+		name        string
+		content     string
+		replacement types.ImageReplacement
+		expected    string
+	}{
+		{
+			name:        "generic value",
+			content:     "app:\n  image: \"nginx:1.25\"\n",
+			replacement: types.ImageReplacement{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25", FileType: "generic"},
+			expected:    "app:\n  image: \"registry.company.com/nginx@" + digest + "\"\n",
+		},
+		{
+			name: "kustomize swaps newTag for digest",
+			content: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: nginx\n" +
+				"    newTag: 1.25\n",
+			replacement: types.ImageReplacement{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25", FileType: "kustomize"},
+			expected: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: registry.company.com/nginx\n" +
+				"    digest: " + digest + "\n",
+		},
+		{
+			name: "helm separated keeps tag alongside digest",
+			content: "image:\n" +
+				"  registry: docker.io\n" +
+				"  repository: bitnami/redis\n" +
+				"  tag: 7.0.0\n",
+			replacement: types.ImageReplacement{
+				SourceImage: "docker.io/bitnami/redis:7.0.0",
+				TargetImage: "harbor.company.com/bitnami/redis:7.0.0",
+				FileType:    "helm_separated",
+				LineNumber:  3,
+			},
+			expected: "image:\n" +
+				"  registry: harbor.company.com\n" +
+				"  repository: bitnami/redis\n" +
+				"  tag: 7.0.0@" + digest + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replacer := newTestImageReplacer()
+			tt.replacement.TargetDigest = digest
+
+			replaced, _, err := replacer.ReplaceImagesInContent(tt.content, []types.ImageReplacement{tt.replacement})
+			if err != nil {
+				t.Fatalf("ReplaceImagesInContent() unexpected error: %v", err)
+			}
+			if replaced != tt.expected {
+				t.Errorf("replaced content = %q, expected %q", replaced, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImageReplacer_replaceKustomize_DigestFields(t *testing.T) {
+	sourceDigest := "sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31"
+	targetDigest := "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+	tests := []struct {
+		name        string
+		content     string
+		replacement types.ImageReplacement
+		expected    string
+	}{
+		{
+			name: "tag to digest-pinned target",
+			content: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: nginx\n" +
+				"    newTag: \"1.25\"\n",
+			replacement: types.ImageReplacement{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx@" + targetDigest, FileType: "kustomize"},
+			expected: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: registry.company.com/nginx\n" +
+				"    digest: " + targetDigest + "\n",
+		},
+		{
+			name: "digest to tag target",
+			content: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: nginx\n" +
+				"    digest: " + sourceDigest + "\n",
+			replacement: types.ImageReplacement{SourceImage: "nginx@" + sourceDigest, TargetImage: "registry.company.com/nginx:1.25", FileType: "kustomize"},
+			expected: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: registry.company.com/nginx\n" +
+				"    newTag: 1.25\n",
+		},
+		{
+			name: "digest to digest target",
+			content: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: nginx\n" +
+				"    digest: " + sourceDigest + "\n",
+			replacement: types.ImageReplacement{SourceImage: "nginx@" + sourceDigest, TargetImage: "registry.company.com/nginx@" + targetDigest, FileType: "kustomize"},
+			expected: "images:\n" +
+				"  - name: nginx\n" +
+				"    newName: registry.company.com/nginx\n" +
+				"    digest: " + targetDigest + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replacer := newTestImageReplacer()
+			replacements := []types.ImageReplacement{tt.replacement}
+
+			replaced, applied, err := replacer.ReplaceImagesInContent(tt.content, replacements)
+			if err != nil {
+				t.Fatalf("ReplaceImagesInContent() unexpected error: %v", err)
+			}
+			if replaced != tt.expected {
+				t.Fatalf("replaced content = %q, expected %q", replaced, tt.expected)
+			}
+			if len(applied) != 1 {
+				t.Errorf("applied %d replacements, expected 1", len(applied))
+			}
+			if strings.Contains(replaced, "newTag: sha256") || strings.Contains(replaced, "@sha256") {
+				t.Errorf("kustomize entry must not carry a digest inside newTag/newName: %q", replaced)
+			}
+
+			second, _, err := replacer.ReplaceImagesInContent(replaced, replacements)
+			if err != nil || second != replaced {
+				t.Errorf("second pass = %q, %v, expected no further changes", second, err)
+			}
+		})
+	}
+}
+
+func TestImageReplacer_ReplaceImagesInContent_JSON(t *testing.T) {
+	content := "{\n" +
+		"  \"kind\": \"Deployment\",\n" +
+		"  \"spec\": {\n" +
+		"    \"template\": {\n" +
+		"      \"spec\": {\n" +
+		"        \"containers\": [\n" +
+		"          {\"name\": \"web\", \"image\": \"nginx:1.25\"},\n" +
+		"          {\n" +
+		"            \"name\": \"cache\",\n" +
+		"            \"image\" : \"redis:7.0\"\n" +
+		"          }\n" +
+		"        ]\n" +
+		"      }\n" +
+		"    }\n" +
+		"  }\n" +
+		"}\n"
+
+	replacer := newTestImageReplacer()
+	result, actual, err := replacer.ReplaceImagesInContent(content, []types.ImageReplacement{
+		{SourceImage: "nginx:1.25", TargetImage: "registry.company.com/nginx:1.25", FileType: "json"},
+		{SourceImage: "redis:7.0", TargetImage: "registry.company.com/redis:7.0", FileType: "json", TargetDigest: "sha256:abc"},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceImagesInContent() unexpected error: %v", err)
+	}
+	if len(actual) != 2 {
+		t.Fatalf("expected 2 replacements, got %d", len(actual))
+	}
+
+	expected := strings.Replace(content, `"image": "nginx:1.25"`, `"image": "registry.company.com/nginx:1.25"`, 1)
+	expected = strings.Replace(expected, `"image" : "redis:7.0"`, `"image" : "registry.company.com/redis@sha256:abc"`, 1)
+	if result != expected {
+		t.Errorf("formatting was not preserved:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestImageReplacer_ReplaceImagesInContent_Dockerfile(t *testing.T) {
+	content := "FROM golang:1.22 AS builder\nRUN go build ./...\nFROM --platform=linux/amd64 alpine:3.19\nCOPY --from=builder /app /app\n"
+
+	replacer := newTestImageReplacer()
+	result, actual, err := replacer.ReplaceImagesInContent(content, []types.ImageReplacement{
+		{SourceImage: "golang:1.22", TargetImage: "registry.company.com/golang:1.22", FileType: "dockerfile"},
+		{SourceImage: "alpine:3.19", TargetImage: "registry.company.com/alpine:3.19", FileType: "dockerfile"},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceImagesInContent() unexpected error: %v", err)
+	}
+	if len(actual) != 2 {
+		t.Fatalf("expected 2 replacements, got %d", len(actual))
+	}
+
+	expected := "FROM registry.company.com/golang:1.22 AS builder\nRUN go build ./...\nFROM --platform=linux/amd64 registry.company.com/alpine:3.19\nCOPY --from=builder /app /app\n"
+	if result != expected {
+		t.Errorf("result:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestImageReplacer_ReplaceImagesInContent_SkipsTemplatedImage(t *testing.T) {
+	content := `containers:
+  - name: app
+    image: nginx:1.21{{ .Values.image.suffix }}
+  - name: web
+    image: nginx:1.21
+`
+
+	replacer := newTestImageReplacer()
+	result, actual, err := replacer.ReplaceImagesInContent(content, []types.ImageReplacement{
+		{SourceImage: "nginx:1.21", TargetImage: "registry.company.com/nginx:1.21", FileType: "kubernetes_manifest"},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceImagesInContent() unexpected error: %v", err)
+	}
+	if len(actual) != 1 {
+		t.Fatalf("expected 1 replacement, got %d", len(actual))
+	}
+
+	expected := `containers:
+  - name: app
+    image: nginx:1.21{{ .Values.image.suffix }}
+  - name: web
+    image: registry.company.com/nginx:1.21
+`
+	if result != expected {
+		t.Errorf("result:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestImageReplacer_replaceHelmCombined_DockerHubToECR(t *testing.T) {
+	ecrHost := "123456789012.dkr.ecr.us-east-1.amazonaws.com"
+
+	tests := []struct {
+		name        string
+		content     string
+		replacement types.ImageReplacement
+		expected    string
+	}{
+		{
+			name: "bare official repository gains the ECR host",
+			content: "image:\n" +
+				"  repository: nginx\n" +
+				"  tag: \"1.25\"\n" +
+				"exporter:\n" +
+				"  repository: nginx-exporter\n",
+			replacement: types.ImageReplacement{SourceImage: "nginx:1.25", TargetImage: ecrHost + "/library/nginx:1.25", FileType: "helm_combined"},
+			expected: "image:\n" +
+				"  repository: " + ecrHost + "/library/nginx\n" +
+				"  tag: \"1.25\"\n" +
+				"exporter:\n" +
+				"  repository: nginx-exporter\n",
+		},
+		{
+			name:        "namespaced repository with explicit docker.io host",
+			content:     "image:\n  repository: \"docker.io/bitnami/redis\" # upstream\n  tag: 7.0.0\n",
+			replacement: types.ImageReplacement{SourceImage: "bitnami/redis:7.0.0", TargetImage: ecrHost + "/bitnami/redis:7.0.0", FileType: "helm_combined"},
+			expected:    "image:\n  repository: \"" + ecrHost + "/bitnami/redis\" # upstream\n  tag: 7.0.0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replacer := newTestImageReplacer()
+
+			replaced, actual, err := replacer.ReplaceImagesInContent(tt.content, []types.ImageReplacement{tt.replacement})
+			if err != nil {
+				t.Fatalf("ReplaceImagesInContent() unexpected error: %v", err)
+			}
+			if len(actual) != 1 {
+				t.Fatalf("expected 1 replacement, got %d", len(actual))
+			}
+			if replaced != tt.expected {
+				t.Errorf("replaced content = %q, expected %q", replaced, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package gitops
+
+import (
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const (
+	annotationPrivateerRepository = "privateer.io/repository"
+	annotationPrivateerPath       = "privateer.io/path"
+	annotationArgoCDTrackingID    = "argocd.argoproj.io/tracking-id"
+	annotationHelmReleaseName     = "meta.helm.sh/release-name"
+)
+
+type repositoryTarget struct {
+	config types.GitHubRepositoryConfig
+	images []*types.ImageInfo
+}
+
+type repositoryTargetSet struct {
+	engine       *Engine
+	order        []string
+	targets      map[string]*repositoryTarget
+	paths        map[string][]string
+	unrestricted map[string]bool
+}
+
+func (e *Engine) resolveRepositoryTargets(enabledRepos []types.GitHubRepositoryConfig, images []*types.ImageInfo) []repositoryTarget {
+	switch e.config.GitOps.Strategy {
+	case "manual_mapping":
+		return e.resolveManualMappingTargets(images)
+	case "annotation_based":
+		return e.resolveAnnotationTargets(enabledRepos, images)
+	}
+
+	targets := make([]repositoryTarget, 0, len(enabledRepos))
+	for _, repo := range enabledRepos {
+		targets = append(targets, repositoryTarget{config: repo, images: images})
+	}
+	return targets
+}
+
+func (e *Engine) resolveManualMappingTargets(images []*types.ImageInfo) []repositoryTarget {
+	set := e.newRepositoryTargetSet()
+
+	for _, image := range images {
+		for _, rule := range e.config.GitOps.MappingRules {
+			if matchesMappingRule(rule, image) {
+				set.add(rule.Repository, rule.Path, image)
+			}
+		}
+	}
+
+	return set.list()
+}
+
+func (e *Engine) resolveAnnotationTargets(enabledRepos []types.GitHubRepositoryConfig, images []*types.ImageInfo) []repositoryTarget {
+	set := e.newRepositoryTargetSet()
+
+	for _, image := range images {
+		if repository := image.Annotations[annotationPrivateerRepository]; repository != "" {
+			set.add(repository, image.Annotations[annotationPrivateerPath], image)
+			continue
+		}
+
+		appName := annotationAppName(image.Annotations)
+		if appName == "" {
+			e.logger.Debug("image_without_gitops_annotations").
+				Str("image", image.Image).
+				Str("namespace", image.Namespace).
+				Str("resource", image.ResourceName).
+				Send()
+			continue
+		}
+
+		matched := false
+		for _, rule := range e.config.GitOps.MappingRules {
+			if rule.AppName == appName && rule.Repository != "" {
+				set.add(rule.Repository, rule.Path, image)
+				matched = true
+			}
+		}
+		if matched {
+			continue
+		}
+
+		for _, repo := range enabledRepos {
+			_, name, _ := strings.Cut(repo.Name, "/")
+			if strings.Contains(strings.ToLower(name), strings.ToLower(appName)) {
+				set.add(repo.Name, "", image)
+				matched = true
+			}
+		}
+
+		if !matched {
+			e.logger.Debug("annotation_app_without_repository").
+				Str("image", image.Image).
+				Str("app_name", appName).
+				Send()
+		}
+	}
+
+	return set.list()
+}
+
+func matchesMappingRule(rule types.RepositoryMapping, image *types.ImageInfo) bool {
+	if rule.Repository == "" || (rule.Namespace == "" && rule.AppName == "") {
+		return false
+	}
+
+	if rule.Namespace != "" && rule.Namespace != image.Namespace {
+		return false
+	}
+
+	if rule.AppName != "" && rule.AppName != image.ResourceName && rule.AppName != annotationAppName(image.Annotations) {
+		return false
+	}
+
+	return true
+}
+
+func annotationAppName(annotations map[string]string) string {
+	if trackingID := annotations[annotationArgoCDTrackingID]; trackingID != "" {
+		appName, _, _ := strings.Cut(trackingID, ":")
+		return appName
+	}
+
+	return annotations[annotationHelmReleaseName]
+}
+
+func (e *Engine) newRepositoryTargetSet() *repositoryTargetSet {
+	return &repositoryTargetSet{
+		engine:       e,
+		targets:      make(map[string]*repositoryTarget),
+		paths:        make(map[string][]string),
+		unrestricted: make(map[string]bool),
+	}
+}
+
+func (s *repositoryTargetSet) add(repository, path string, image *types.ImageInfo) {
+	target, exists := s.targets[repository]
+	if !exists {
+		config, found := s.engine.findRepositoryConfig(repository)
+		if found && !config.Enabled {
+			s.engine.logger.Debug("mapped_repository_disabled").
+				Str("repository", repository).
+				Send()
+			return
+		}
+		if !found {
+			config = types.GitHubRepositoryConfig{
+				Name:           repository,
+				Enabled:        true,
+				BranchStrategy: types.BranchStrategyCreateNew,
+			}
+		}
+
+		target = &repositoryTarget{config: config}
+		s.targets[repository] = target
+		s.order = append(s.order, repository)
+	}
+
+	if path == "" {
+		s.unrestricted[repository] = true
+	} else if !containsString(s.paths[repository], path) {
+		s.paths[repository] = append(s.paths[repository], path)
+	}
+
+	for _, existing := range target.images {
+		if existing == image {
+			return
+		}
+	}
+	target.images = append(target.images, image)
+}
+
+func (s *repositoryTargetSet) list() []repositoryTarget {
+	targets := make([]repositoryTarget, 0, len(s.order))
+	for _, repository := range s.order {
+		target := *s.targets[repository]
+		if !s.unrestricted[repository] {
+			target.config.Paths = s.paths[repository]
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+func (e *Engine) findRepositoryConfig(repository string) (types.GitHubRepositoryConfig, bool) {
+	for _, repo := range e.config.GitHub.Repositories {
+		if repo.Name == repository {
+			return repo, true
+		}
+	}
+	return types.GitHubRepositoryConfig{}, false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package gitops
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func newTestMappingEngine(config *types.Config) *Engine {
+	return &Engine{
+		logger: logger.NewTest(),
+		config: config,
+	}
+}
+
+func targetSummary(targets []repositoryTarget) map[string][]string {
+	summary := make(map[string][]string)
+	for _, target := range targets {
+		images := make([]string, 0, len(target.images))
+		for _, image := range target.images {
+			images = append(images, image.Image)
+		}
+		summary[target.config.Name] = images
+	}
+	return summary
+}
+
+func TestEngine_resolveRepositoryTargets_SmartSearch(t *testing.T) {
+	enabledRepos := []types.GitHubRepositoryConfig{
+		{Name: "company/app-manifests", Enabled: true},
+		{Name: "company/infra", Enabled: true},
+	}
+	images := []*types.ImageInfo{{Image: "nginx:1.25"}, {Image: "redis:7.0"}}
+
+	engine := newTestMappingEngine(&types.Config{GitOps: types.GitOpsConfig{Strategy: "smart_search"}})
+	targets := engine.resolveRepositoryTargets(enabledRepos, images)
+
+	expected := map[string][]string{
+		"company/app-manifests": {"nginx:1.25", "redis:7.0"},
+		"company/infra":         {"nginx:1.25", "redis:7.0"},
+	}
+	if summary := targetSummary(targets); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("targets = %v, expected %v", summary, expected)
+	}
+}
+
+func TestEngine_resolveRepositoryTargets_ManualMapping(t *testing.T) {
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Repositories: []types.GitHubRepositoryConfig{
+				{Name: "company/frontend-config", Enabled: true, Paths: []string{"charts/"}, BranchStrategy: "use_main"},
+				{Name: "company/legacy", Enabled: false},
+				{Name: "company/unmapped", Enabled: true},
+			},
+		},
+		GitOps: types.GitOpsConfig{
+			Strategy: "manual_mapping",
+			MappingRules: []types.RepositoryMapping{
+				{Namespace: "production", Repository: "company/production-manifests", Path: "apps/"},
+				{AppName: "frontend", Repository: "company/frontend-config", Path: "k8s/"},
+				{Namespace: "staging", Repository: "company/legacy"},
+			},
+		},
+	}
+
+	images := []*types.ImageInfo{
+		{Image: "nginx:1.25", Namespace: "production", ResourceName: "api"},
+		{Image: "node:20", Namespace: "web", ResourceName: "frontend"},
+		{Image: "redis:7.0", Namespace: "staging", ResourceName: "cache"},
+		{Image: "busybox:1.36", Namespace: "tools", ResourceName: "debug"},
+	}
+
+	engine := newTestMappingEngine(config)
+	targets := engine.resolveRepositoryTargets(engine.getEnabledRepositories(), images)
+
+	expected := map[string][]string{
+		"company/production-manifests": {"nginx:1.25"},
+		"company/frontend-config":      {"node:20"},
+	}
+	if summary := targetSummary(targets); !reflect.DeepEqual(summary, expected) {
+		t.Fatalf("targets = %v, expected %v", summary, expected)
+	}
+
+	for _, target := range targets {
+		switch target.config.Name {
+		case "company/production-manifests":
+			if !reflect.DeepEqual(target.config.Paths, []string{"apps/"}) || target.config.BranchStrategy != "create_new" {
+				t.Errorf("unconfigured repository config = %+v", target.config)
+			}
+		case "company/frontend-config":
+			if !reflect.DeepEqual(target.config.Paths, []string{"k8s/"}) || target.config.BranchStrategy != "use_main" {
+				t.Errorf("configured repository should keep its settings and use the rule path, got %+v", target.config)
+			}
+		}
+	}
+}
+
+func TestEngine_resolveRepositoryTargets_AnnotationBased(t *testing.T) {
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Repositories: []types.GitHubRepositoryConfig{
+				{Name: "company/checkout-manifests", Enabled: true, Paths: []string{"deploy/"}},
+				{Name: "company/platform", Enabled: true},
+			},
+		},
+		GitOps: types.GitOpsConfig{
+			Strategy: "annotation_based",
+			MappingRules: []types.RepositoryMapping{
+				{AppName: "monitoring", Repository: "company/observability", Path: "charts/"},
+			},
+		},
+	}
+
+	images := []*types.ImageInfo{
+		{Image: "nginx:1.25", Annotations: map[string]string{"argocd.argoproj.io/tracking-id": "checkout:apps/Deployment:shop/checkout"}},
+		{Image: "prom/prometheus:v2.45.0", Annotations: map[string]string{"meta.helm.sh/release-name": "monitoring"}},
+		{Image: "redis:7.0", Annotations: map[string]string{"privateer.io/repository": "company/platform", "privateer.io/path": "cache/"}},
+		{Image: "busybox:1.36"},
+	}
+
+	engine := newTestMappingEngine(config)
+	targets := engine.resolveRepositoryTargets(engine.getEnabledRepositories(), images)
+
+	expected := map[string][]string{
+		"company/checkout-manifests": {"nginx:1.25"},
+		"company/observability":      {"prom/prometheus:v2.45.0"},
+		"company/platform":           {"redis:7.0"},
+	}
+	if summary := targetSummary(targets); !reflect.DeepEqual(summary, expected) {
+		t.Fatalf("targets = %v, expected %v", summary, expected)
+	}
+
+	expectedPaths := map[string][]string{
+		"company/checkout-manifests": {"deploy/"},
+		"company/observability":      {"charts/"},
+		"company/platform":           {"cache/"},
+	}
+	for _, target := range targets {
+		if !reflect.DeepEqual(target.config.Paths, expectedPaths[target.config.Name]) {
+			t.Errorf("%s paths = %v, expected %v", target.config.Name, target.config.Paths, expectedPaths[target.config.Name])
+		}
+	}
+}
@@ -3,7 +3,9 @@ package gitops
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/internal/registry"
@@ -16,6 +18,8 @@ type TagResolver struct {
 	config          *types.Config
 	registryManager *registry.Manager
 	clusterImages   map[string]*types.ImageInfo
+	resolutions     map[string]*types.TagResolutionResult
+	resolutionMutex sync.Mutex
 }
 
 func NewTagResolver(logger *logger.Logger, config *types.Config, registryManager *registry.Manager) *TagResolver {
@@ -24,6 +28,7 @@ func NewTagResolver(logger *logger.Logger, config *types.Config, registryManager
 		config:          config,
 		registryManager: registryManager,
 		clusterImages:   make(map[string]*types.ImageInfo),
+		resolutions:     make(map[string]*types.TagResolutionResult),
 	}
 }
 
@@ -72,10 +77,35 @@ func (tr *TagResolver) ResolveEmptyTag(ctx context.Context, registry, repository
 		return result, nil
 	}
 
+	cacheKey := fmt.Sprintf("%s|%s|%s", registry, repository, tag)
+	tr.resolutionMutex.Lock()
+	cached, found := tr.resolutions[cacheKey]
+	tr.resolutionMutex.Unlock()
+	if found {
+		tr.logger.Debug("tag_resolution_cache_hit").
+			Str("registry", registry).
+			Str("repository", repository).
+			Str("resolved_tag", cached.ResolvedTag).
+			Send()
+		resolved := *cached
+		return &resolved, nil
+	}
+
+	tr.resolveEmptyTag(ctx, registry, repository, result)
+
+	tr.resolutionMutex.Lock()
+	cachedResult := *result
+	tr.resolutions[cacheKey] = &cachedResult
+	tr.resolutionMutex.Unlock()
+
+	return result, nil
+}
+
+func (tr *TagResolver) resolveEmptyTag(ctx context.Context, registry, repository string, result *types.TagResolutionResult) {
 	tr.logger.Info("resolving_empty_tag").
 		Str("registry", registry).
 		Str("repository", repository).
-		Str("original_tag", tag).
+		Str("original_tag", result.OriginalTag).
 		Send()
 
 	clusterTag, clusterImage := tr.findTagInCluster(repository)
@@ -101,7 +131,7 @@ func (tr *TagResolver) ResolveEmptyTag(ctx context.Context, registry, repository
 				Bool("should_replace", result.ShouldReplace).
 				Send()
 
-			return result, nil
+			return
 		}
 	}
 
@@ -110,10 +140,15 @@ func (tr *TagResolver) ResolveEmptyTag(ctx context.Context, registry, repository
 		commonTags = []string{"latest", "stable", "main", "v1"}
 	}
 
-	for _, commonTag := range commonTags {
-		testImage := tr.buildImageName(registry, repository, commonTag)
-		privateImage, exists := tr.validateInPrivateRegistry(ctx, testImage)
-		if exists {
+	testImages := make([]string, len(commonTags))
+	for i, commonTag := range commonTags {
+		testImages[i] = tr.buildImageName(registry, repository, commonTag)
+	}
+
+	validatedMap := tr.validateCandidatesInPrivateRegistry(ctx, testImages)
+	for i, commonTag := range commonTags {
+		testImage := testImages[i]
+		if privateImage, exists := validatedMap[testImage]; exists {
 			result.ResolvedTag = commonTag
 			result.Source = "registry_common"
 			result.Confidence = 0.7
@@ -127,7 +162,7 @@ func (tr *TagResolver) ResolveEmptyTag(ctx context.Context, registry, repository
 				Str("private_image", privateImage).
 				Send()
 
-			return result, nil
+			return
 		}
 	}
 
@@ -146,8 +181,6 @@ func (tr *TagResolver) ResolveEmptyTag(ctx context.Context, registry, repository
 		Str("repository", repository).
 		Str("fallback_tag", fallbackTag).
 		Send()
-
-	return result, nil
 }
 
 func (tr *TagResolver) findTagInCluster(repository string) (string, string) {
@@ -175,8 +208,15 @@ func (tr *TagResolver) findTagInCluster(repository string) (string, string) {
 		}
 	}
 
-	for key, clusterImage := range tr.clusterImages {
-		if strings.Contains(key, repository) || strings.Contains(repository, key) {
+	keys := make([]string, 0, len(tr.clusterImages))
+	for key := range tr.clusterImages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		clusterImage := tr.clusterImages[key]
+		if isRepositoryPathMatch(key, repository) {
 			parsed := utils.ParseImageName(clusterImage.Image)
 
 			tr.logger.Debug("cluster_image_partial_match").
@@ -193,29 +233,88 @@ func (tr *TagResolver) findTagInCluster(repository string) (string, string) {
 	return "", ""
 }
 
+func isRepositoryPathMatch(a, b string) bool {
+	a = strings.Trim(a, "/")
+	b = strings.Trim(b, "/")
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || strings.HasSuffix(a, "/"+b) || strings.HasSuffix(b, "/"+a)
+}
+
 func (tr *TagResolver) validateInPrivateRegistry(ctx context.Context, publicImage string) (string, bool) {
-	if tr.registryManager == nil {
-		return "", false
+	privateImage, exists := tr.validateCandidatesInPrivateRegistry(ctx, []string{publicImage})[publicImage]
+	return privateImage, exists
+}
+
+func (tr *TagResolver) validateCandidatesInPrivateRegistry(ctx context.Context, publicImages []string) map[string]string {
+	if tr.registryManager == nil || len(publicImages) == 0 {
+		return nil
 	}
 
-	imageInfo := &types.ImageInfo{
-		Image: publicImage,
+	imageInfos := make([]*types.ImageInfo, len(publicImages))
+	for i, publicImage := range publicImages {
+		imageInfos[i] = &types.ImageInfo{Image: publicImage}
 	}
 
-	validatedMap, err := tr.registryManager.ValidateImagesBatch(ctx, []*types.ImageInfo{imageInfo}, tr.config)
+	validatedMap, validationErrors, err := tr.registryManager.ValidateImagesBatch(ctx, imageInfos, tr.config)
 	if err != nil {
 		tr.logger.Warn("private_registry_validation_failed").
-			Str("image", publicImage).
+			Strs("images", publicImages).
 			Err(err).
 			Send()
-		return "", false
+		return nil
+	}
+
+	for _, publicImage := range publicImages {
+		if validationErr := validationErrors[publicImage]; validationErr != nil {
+			tr.logger.Warn("private_registry_validation_failed").
+				Str("image", publicImage).
+				Err(validationErr).
+				Send()
+			delete(validatedMap, publicImage)
+		}
 	}
 
-	if privateImage, exists := validatedMap[publicImage]; exists {
-		return privateImage, true
+	return validatedMap
+}
+
+func (tr *TagResolver) PinDigests(ctx context.Context, replacements []types.ImageReplacement) []types.ImageReplacement {
+	if tr.registryManager == nil {
+		return replacements
+	}
+
+	digests := make(map[string]string)
+	pinned := make([]types.ImageReplacement, len(replacements))
+
+	for i, replacement := range replacements {
+		pinned[i] = replacement
+
+		digest, resolved := digests[replacement.TargetImage]
+		if !resolved {
+			var err error
+			digest, err = tr.registryManager.ResolveDigest(ctx, replacement.TargetImage)
+			if err != nil {
+				tr.logger.Warn("digest_pin_failed").
+					Str("target", replacement.TargetImage).
+					Err(err).
+					Send()
+			}
+			digests[replacement.TargetImage] = digest
+		}
+
+		if digest == "" {
+			continue
+		}
+
+		pinned[i].TargetDigest = digest
+		tr.logger.Debug("digest_pinned").
+			Str("target", replacement.TargetImage).
+			Str("digest", digest).
+			Send()
 	}
 
-	return "", false
+	return pinned
 }
 
 func (tr *TagResolver) buildImageName(registry, repository, tag string) string {
@@ -263,7 +362,7 @@ func (tr *TagResolver) ProcessDetections(ctx context.Context, detections []types
 
 		parsed := utils.ParseImageName(detection.FullImage)
 
-		if tr.isEmptyTag(parsed.Tag) {
+		if parsed.Digest == "" && tr.isEmptyTag(parsed.Tag) {
 			tr.logger.Debug("processing_empty_tag_detection").
 				Str("original_image", detection.FullImage).
 				Str("repository", parsed.Repository).
@@ -0,0 +1,220 @@
+package gitops
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestTagResolver_findTagInCluster(t *testing.T) {
+	tests := []struct {
+		name          string
+		clusterImages []string
+		repository    string
+		expectedTag   string
+		expectedImage string
+	}{
+		{
+			name:          "exact repository match",
+			clusterImages: []string{"redis:7.0"},
+			repository:    "redis",
+			expectedTag:   "7.0",
+			expectedImage: "redis:7.0",
+		},
+		{
+			name:          "prefix does not match a different repository",
+			clusterImages: []string{"oliver006/redis-exporter:v1.50.0"},
+			repository:    "redis",
+		},
+		{
+			name:          "substring does not match a different repository",
+			clusterImages: []string{"bitnami/redis-sentinel:7.0"},
+			repository:    "bitnami/redis",
+		},
+		{
+			name:          "match on path segment boundary",
+			clusterImages: []string{"quay.io/team/monitoring/redis:6.2"},
+			repository:    "monitoring/redis",
+			expectedTag:   "6.2",
+			expectedImage: "quay.io/team/monitoring/redis:6.2",
+		},
+		{
+			name:          "exact match preferred over similar names",
+			clusterImages: []string{"oliver006/redis-exporter:v1.50.0", "redis:7.2"},
+			repository:    "redis",
+			expectedTag:   "7.2",
+			expectedImage: "redis:7.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTagResolver(logger.NewTest(), &types.Config{}, nil)
+
+			var images []*types.ImageInfo
+			for _, image := range tt.clusterImages {
+				images = append(images, &types.ImageInfo{Image: image})
+			}
+			tr.LoadClusterImages(images)
+
+			tag, image := tr.findTagInCluster(tt.repository)
+			if tag != tt.expectedTag || image != tt.expectedImage {
+				t.Errorf("findTagInCluster(%s) = (%s, %s), expected (%s, %s)",
+					tt.repository, tag, image, tt.expectedTag, tt.expectedImage)
+			}
+		})
+	}
+}
+
+func TestIsRepositoryPathMatch(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"redis", "redis", true},
+		{"library/redis", "redis", true},
+		{"redis", "library/redis", true},
+		{"redis-exporter", "redis", false},
+		{"oliver006/redis-exporter", "redis", false},
+		{"myredis", "redis", false},
+		{"", "redis", false},
+	}
+
+	for _, tt := range tests {
+		if result := isRepositoryPathMatch(tt.a, tt.b); result != tt.expected {
+			t.Errorf("isRepositoryPathMatch(%s, %s) = %v, expected %v", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+func TestTagResolver_PinDigests(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[]}`)
+	sum := sha256.Sum256(manifest)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/library/nginx/manifests/1.25" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+		w.Write(manifest)
+	}))
+	defer server.Close()
+
+	log := logger.NewTest()
+	registryManager := registry.NewManager(log)
+	if err := registryManager.AddRegistry(&types.RegistryConfig{
+		Name:      "mirror",
+		Type:      "docker",
+		URL:       server.URL,
+		Enabled:   true,
+		Anonymous: true,
+	}); err != nil {
+		t.Fatalf("AddRegistry() unexpected error: %v", err)
+	}
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	config := &types.Config{GitOps: types.GitOpsConfig{PinDigest: true}}
+	resolver := NewTagResolver(log, config, registryManager)
+
+	replacements := resolver.PinDigests(context.Background(), []types.ImageReplacement{
+		{SourceImage: "nginx:1.25", TargetImage: host + "/library/nginx:1.25", FileType: "kubernetes_manifest"},
+		{SourceImage: "redis:7.0", TargetImage: host + "/library/redis:7.0", FileType: "kubernetes_manifest"},
+	})
+
+	if replacements[0].TargetDigest != digest {
+		t.Errorf("TargetDigest = %q, expected %q", replacements[0].TargetDigest, digest)
+	}
+	if replacements[1].TargetDigest != "" {
+		t.Errorf("expected unresolvable image to stay unpinned, got %q", replacements[1].TargetDigest)
+	}
+
+	content := "spec:\n  containers:\n    - name: web\n      image: nginx:1.25\n    - name: cache\n      image: redis:7.0\n"
+	replaced, _, err := NewImageReplacer(log, config).ReplaceImagesInContent(content, replacements)
+	if err != nil {
+		t.Fatalf("ReplaceImagesInContent() unexpected error: %v", err)
+	}
+
+	expected := "spec:\n  containers:\n    - name: web\n      image: " + host + "/library/nginx@" + digest + "\n" +
+		"    - name: cache\n      image: " + host + "/library/redis:7.0\n"
+	if replaced != expected {
+		t.Errorf("replaced content = %q, expected %q", replaced, expected)
+	}
+}
+
+func TestTagResolver_ResolveEmptyTag_Cache(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		if !strings.HasSuffix(r.URL.Path, "/busybox/manifests/stable") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+		w.Header().Set("Docker-Content-Digest", "sha256:"+strings.Repeat("a", 64))
+		w.Write([]byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[]}`))
+	}))
+	defer server.Close()
+
+	log := logger.NewTest()
+	registryManager := registry.NewManager(log)
+	if err := registryManager.AddRegistry(&types.RegistryConfig{
+		Name:      "mirror",
+		Type:      "docker",
+		URL:       server.URL,
+		Enabled:   true,
+		Anonymous: true,
+	}); err != nil {
+		t.Fatalf("AddRegistry() unexpected error: %v", err)
+	}
+
+	config := &types.Config{GitOps: types.GitOpsConfig{TagResolution: types.TagResolutionConfig{
+		Enabled:         true,
+		CommonTagsToTry: []string{"latest", "stable", "main"},
+	}}}
+	resolver := NewTagResolver(log, config, registryManager)
+
+	first, err := resolver.ResolveEmptyTag(context.Background(), "docker.io", "busybox", "")
+	if err != nil {
+		t.Fatalf("ResolveEmptyTag() unexpected error: %v", err)
+	}
+	if first.ResolvedTag != "stable" || first.Source != "registry_common" || first.PrivateImage == "" {
+		t.Fatalf("first resolution = %+v, expected stable from registry_common", first)
+	}
+
+	mu.Lock()
+	requestsAfterFirst := len(requests)
+	mu.Unlock()
+	if requestsAfterFirst == 0 {
+		t.Fatal("expected the first resolution to query the registry")
+	}
+
+	second, err := resolver.ResolveEmptyTag(context.Background(), "docker.io", "busybox", "")
+	if err != nil {
+		t.Fatalf("ResolveEmptyTag() unexpected error: %v", err)
+	}
+	if *second != *first {
+		t.Errorf("cached resolution = %+v, expected %+v", second, first)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != requestsAfterFirst {
+		t.Errorf("repeated resolution made %d registry requests, expected a cache hit: %v", len(requests)-requestsAfterFirst, requests[requestsAfterFirst:])
+	}
+}
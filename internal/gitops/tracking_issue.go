@@ -0,0 +1,52 @@
+package gitops
+
+import (
+	"context"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func (e *Engine) createTrackingIssue(ctx context.Context, summary *types.GitOpsSummary) {
+	pullRequests := trackedPullRequests(summary.Results)
+	if len(pullRequests) == 0 {
+		e.logger.Debug("tracking_issue_skipped").
+			Str("reason", "nenhum pull request criado").
+			Send()
+		return
+	}
+
+	repository := e.config.GitOps.TrackingIssue.Repository
+	if repository == "" {
+		repository = pullRequests[0].Repository
+	}
+
+	issue, err := e.prManager.CreateTrackingIssue(ctx, repository, pullRequests)
+	if err != nil {
+		e.logger.Warn("tracking_issue_failed").
+			Str("repository", repository).
+			Err(err).
+			Send()
+		return
+	}
+	summary.TrackingIssueURL = issue.HTMLURL
+
+	for _, result := range pullRequests {
+		if err := e.prManager.LinkPullRequestToIssue(ctx, result, issue, len(pullRequests)); err != nil {
+			e.logger.Warn("tracking_issue_link_failed").
+				Str("repository", result.Repository).
+				Int("pr_number", result.PullRequest.Number).
+				Err(err).
+				Send()
+		}
+	}
+}
+
+func trackedPullRequests(results []*types.GitOpsResult) []*types.GitOpsResult {
+	var pullRequests []*types.GitOpsResult
+	for _, result := range results {
+		if result != nil && result.Success && result.PullRequest != nil && result.PullRequest.URL != "" {
+			pullRequests = append(pullRequests, result)
+		}
+	}
+	return pullRequests
+}
@@ -0,0 +1,106 @@
+package gitops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/github"
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestEngine_createTrackingIssue(t *testing.T) {
+	var mu sync.Mutex
+	var issueRequest types.CreateIssueRequest
+	comments := make(map[string]string)
+	labeled := make(map[string]bool)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/company/platform/issues":
+			json.Unmarshal(body, &issueRequest)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 42, "html_url": "https://github.com/company/platform/issues/42"}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/comments"):
+			var comment types.CommentRequest
+			json.Unmarshal(body, &comment)
+			comments[r.URL.Path] = comment.Body
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/labels"):
+			labeled[r.URL.Path] = true
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{Token: "token", APIURL: server.URL},
+		GitOps: types.GitOpsConfig{
+			TrackingIssue: types.TrackingIssueConfig{
+				Enabled:    true,
+				Repository: "company/platform",
+				Labels:     []string{"privateer-rollout"},
+			},
+		},
+	}
+	log := logger.NewTest()
+	engine := NewEngine(github.NewClient(&config.GitHub, log), registry.NewManager(log), log, config)
+
+	summary := &types.GitOpsSummary{Results: []*types.GitOpsResult{
+		{
+			Repository:    "company/api",
+			Success:       true,
+			PullRequest:   &types.PullRequestInfo{Number: 3, URL: "https://github.com/company/api/pull/3"},
+			ImagesChanged: []types.ImageReplacement{{SourceImage: "nginx:1.25", TargetImage: "harbor.company.com/library/nginx:1.25"}},
+		},
+		{Repository: "company/broken", Error: io.ErrUnexpectedEOF},
+		{
+			Repository:    "company/web",
+			Success:       true,
+			PullRequest:   &types.PullRequestInfo{Number: 8, URL: "https://github.com/company/web/pull/8"},
+			ImagesChanged: []types.ImageReplacement{{SourceImage: "redis:7", TargetImage: "harbor.company.com/library/redis:7"}},
+		},
+	}}
+
+	engine.createTrackingIssue(context.Background(), summary)
+
+	if summary.TrackingIssueURL != "https://github.com/company/platform/issues/42" {
+		t.Fatalf("TrackingIssueURL = %q", summary.TrackingIssueURL)
+	}
+
+	for _, url := range []string{"https://github.com/company/api/pull/3", "https://github.com/company/web/pull/8"} {
+		if !strings.Contains(issueRequest.Body, url) {
+			t.Errorf("tracking issue body missing %s:\n%s", url, issueRequest.Body)
+		}
+	}
+	if strings.Contains(issueRequest.Body, "company/broken") {
+		t.Errorf("tracking issue body lists failed repository:\n%s", issueRequest.Body)
+	}
+	if len(issueRequest.Labels) != 1 || issueRequest.Labels[0] != "privateer-rollout" {
+		t.Errorf("issue labels = %v", issueRequest.Labels)
+	}
+
+	for _, path := range []string{"/repos/company/api/issues/3", "/repos/company/web/issues/8"} {
+		if !strings.Contains(comments[path+"/comments"], summary.TrackingIssueURL) {
+			t.Errorf("comment on %s = %q, expected link to tracking issue", path, comments[path+"/comments"])
+		}
+		if !labeled[path+"/labels"] {
+			t.Errorf("expected labels on %s", path)
+		}
+	}
+}
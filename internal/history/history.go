@@ -0,0 +1,171 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/kevinfinalboss/privateer/internal/state"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const (
+	ImageStatusMigrated = "migrated"
+	ImageStatusFailed   = "failed"
+	ImageStatusSkipped  = "skipped"
+	ImageStatusReplaced = "replaced"
+)
+
+const runsBucket = "runs"
+
+type Run struct {
+	ID         string                   `json:"id"`
+	Command    string                   `json:"command"`
+	User       string                   `json:"user"`
+	StartedAt  time.Time                `json:"started_at"`
+	FinishedAt time.Time                `json:"finished_at"`
+	DryRun     bool                     `json:"dry_run"`
+	Success    bool                     `json:"success"`
+	Summary    *reporter.CommandSummary `json:"summary,omitempty"`
+	Images     []ImageRecord            `json:"images,omitempty"`
+}
+
+type ImageRecord struct {
+	SourceImage string `json:"source_image"`
+	TargetImage string `json:"target_image,omitempty"`
+	Registry    string `json:"registry,omitempty"`
+	Repository  string `json:"repository,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+type Filter struct {
+	Command string
+	Image   string
+	Limit   int
+}
+
+type Store struct {
+	state *state.Store
+}
+
+func New(store *state.Store) *Store {
+	return &Store{state: store}
+}
+
+func (s *Store) Record(run *Run) error {
+	if run.ID == "" {
+		run.ID = run.StartedAt.UTC().Format("20060102T150405.000000000Z")
+	}
+
+	if err := s.state.Put(runsBucket, run.ID, run); err != nil {
+		return fmt.Errorf("falha ao gravar execução %s no histórico: %w", run.ID, err)
+	}
+	return nil
+}
+
+func (s *Store) Get(id string) (*Run, error) {
+	run := &Run{}
+	found, err := s.state.Get(runsBucket, id, run)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler execução %s do histórico: %w", id, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("execução %s não encontrada no histórico", id)
+	}
+	return run, nil
+}
+
+func (s *Store) List(filter Filter) ([]*Run, error) {
+	var runs []*Run
+	err := s.state.Reverse(runsBucket, func(key string, data []byte) (bool, error) {
+		run := &Run{}
+		if err := json.Unmarshal(data, run); err != nil {
+			return false, fmt.Errorf("execução %s corrompida: %w", key, err)
+		}
+		if !filter.matches(run) {
+			return true, nil
+		}
+
+		runs = append(runs, run)
+		return filter.Limit <= 0 || len(runs) < filter.Limit, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar histórico: %w", err)
+	}
+	return runs, nil
+}
+
+func (f Filter) matches(run *Run) bool {
+	if f.Command != "" && !strings.HasPrefix(run.Command, f.Command) {
+		return false
+	}
+	if f.Image == "" {
+		return true
+	}
+
+	image := strings.ToLower(f.Image)
+	for _, record := range run.Images {
+		if strings.Contains(strings.ToLower(record.SourceImage), image) || strings.Contains(strings.ToLower(record.TargetImage), image) {
+			return true
+		}
+	}
+	return false
+}
+
+func MigrationImages(summary *types.MigrationSummary) []ImageRecord {
+	if summary == nil {
+		return nil
+	}
+
+	var records []ImageRecord
+	for _, result := range summary.Results {
+		if result == nil || result.Image == nil {
+			continue
+		}
+
+		record := ImageRecord{
+			SourceImage: result.Image.Image,
+			TargetImage: result.TargetImage,
+			Registry:    result.Registry,
+			Status:      ImageStatusMigrated,
+		}
+		switch {
+		case result.Skipped:
+			record.Status = ImageStatusSkipped
+			record.Error = result.Reason
+		case !result.Success:
+			record.Status = ImageStatusFailed
+			if result.Error != nil {
+				record.Error = result.Error.Error()
+			}
+		}
+		records = append(records, record)
+	}
+
+	return append(records, GitOpsImages(summary.GitOps)...)
+}
+
+func GitOpsImages(gitops *types.GitOpsSummary) []ImageRecord {
+	if gitops == nil {
+		return nil
+	}
+
+	var records []ImageRecord
+	for _, result := range gitops.Results {
+		if result == nil {
+			continue
+		}
+		for _, replacement := range result.ImagesChanged {
+			records = append(records, ImageRecord{
+				SourceImage: replacement.SourceImage,
+				TargetImage: replacement.TargetImage,
+				Repository:  result.Repository,
+				Status:      ImageStatusReplaced,
+			})
+		}
+	}
+	return records
+}
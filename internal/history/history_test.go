@@ -0,0 +1,103 @@
+package history
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/reporter"
+	"github.com/kevinfinalboss/privateer/internal/state"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestStore_RecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	db, err := state.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	store := New(db)
+
+	started := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	summary := &types.MigrationSummary{Results: []*types.MigrationResult{
+		{Image: &types.ImageInfo{Image: "nginx:1.25"}, TargetImage: "harbor.company.com/library/nginx:1.25", Registry: "harbor", Success: true},
+		{Image: &types.ImageInfo{Image: "redis:7"}, Registry: "harbor", Error: errors.New("push negado")},
+	}}
+
+	run := &Run{
+		Command:    "migrate cluster",
+		User:       "ops",
+		StartedAt:  started,
+		FinishedAt: started.Add(2 * time.Minute),
+		Success:    false,
+		Summary:    &reporter.CommandSummary{Command: "migrate cluster"},
+		Images:     MigrationImages(summary),
+	}
+	if err := store.Record(run); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := store.Record(&Run{Command: "migrate github", StartedAt: started.Add(time.Hour), Success: true}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	db.Close()
+
+	db, err = state.Open(path)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	defer db.Close()
+	store = New(db)
+
+	got, err := store.Get(run.ID)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", run.ID, err)
+	}
+	if got.Command != "migrate cluster" || got.User != "ops" || !got.StartedAt.Equal(started) || got.Summary == nil {
+		t.Errorf("Get() = %+v", got)
+	}
+	if len(got.Images) != 2 {
+		t.Fatalf("Images = %+v, expected 2 records", got.Images)
+	}
+	if got.Images[0].Status != ImageStatusMigrated || got.Images[0].TargetImage != "harbor.company.com/library/nginx:1.25" {
+		t.Errorf("Images[0] = %+v", got.Images[0])
+	}
+	if got.Images[1].Status != ImageStatusFailed || got.Images[1].Error != "push negado" {
+		t.Errorf("Images[1] = %+v", got.Images[1])
+	}
+
+	runs, err := store.List(Filter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].Command != "migrate github" {
+		t.Errorf("List() should return newest first, got %d runs", len(runs))
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected int
+	}{
+		{name: "by command", filter: Filter{Command: "migrate cluster"}, expected: 1},
+		{name: "by image", filter: Filter{Image: "REDIS"}, expected: 1},
+		{name: "unknown image", filter: Filter{Image: "postgres"}, expected: 0},
+		{name: "limit", filter: Filter{Limit: 1}, expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := store.List(tt.filter)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(runs) != tt.expected {
+				t.Errorf("List(%+v) returned %d runs, expected %d", tt.filter, len(runs), tt.expected)
+			}
+		})
+	}
+
+	if _, err := store.Get("missing"); err == nil {
+		t.Error("Get() of unknown run should fail")
+	}
+}
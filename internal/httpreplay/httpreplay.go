@@ -0,0 +1,313 @@
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	CassetteEnvVar = "PRIVATEER_HTTP_CASSETTE"
+	ModeEnvVar     = "PRIVATEER_HTTP_MODE"
+
+	ModeRecord = "record"
+	ModeReplay = "replay"
+
+	redactedValue = "REDACTED"
+)
+
+var (
+	redactedHeaders = []string{"Authorization", "Set-Cookie", "Www-Authenticate"}
+	redactedFields  = map[string]bool{
+		"token":         true,
+		"access_token":  true,
+		"refresh_token": true,
+		"id_token":      true,
+		"password":      true,
+	}
+)
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	replayed bool
+}
+
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+	path         string
+	dirty        bool
+	mutex        sync.Mutex
+}
+
+var (
+	cassettes      = make(map[string]*Cassette)
+	cassettesMutex sync.Mutex
+)
+
+// Wrap returns next unchanged unless PRIVATEER_HTTP_MODE is set explicitly,
+// so production clients never record or replay by accident.
+func Wrap(next http.RoundTripper) http.RoundTripper {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv(ModeEnvVar)))
+	if mode == "" {
+		return next
+	}
+	if mode != ModeRecord && mode != ModeReplay {
+		return failingTransport{err: fmt.Errorf("%s inválido '%s': use %s ou %s", ModeEnvVar, mode, ModeRecord, ModeReplay)}
+	}
+
+	path := os.Getenv(CassetteEnvVar)
+	if path == "" {
+		return failingTransport{err: fmt.Errorf("%s=%s requer %s", ModeEnvVar, mode, CassetteEnvVar)}
+	}
+
+	cassette, err := loadCassette(path)
+	if err != nil {
+		return failingTransport{err: err}
+	}
+
+	if mode == ModeRecord {
+		return &recorder{next: next, cassette: cassette}
+	}
+	return &replayer{cassette: cassette}
+}
+
+// Flush writes every cassette that recorded new interactions. Recording only
+// keeps interactions in memory, so callers flush once when the run ends.
+func Flush() error {
+	cassettesMutex.Lock()
+	defer cassettesMutex.Unlock()
+
+	var errs []error
+	for _, cassette := range cassettes {
+		if err := cassette.flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func loadCassette(path string) (*Cassette, error) {
+	cassettesMutex.Lock()
+	defer cassettesMutex.Unlock()
+
+	if cassette, ok := cassettes[path]; ok {
+		return cassette, nil
+	}
+
+	cassette := &Cassette{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("falha ao ler cassette %s: %w", path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, cassette); err != nil {
+			return nil, fmt.Errorf("falha ao decodificar cassette %s: %w", path, err)
+		}
+	}
+
+	cassettes[path] = cassette
+	return cassette, nil
+}
+
+func (c *Cassette) add(interaction *Interaction) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Interactions = append(c.Interactions, interaction)
+	c.dirty = true
+}
+
+func (c *Cassette) flush() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("falha ao serializar cassette %s: %w", c.path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("falha ao criar diretório do cassette %s: %w", c.path, err)
+	}
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("falha ao gravar cassette %s: %w", c.path, err)
+	}
+	// WriteFile keeps the mode of an existing cassette.
+	if err := os.Chmod(c.path, 0600); err != nil {
+		return fmt.Errorf("falha ao restringir permissões do cassette %s: %w", c.path, err)
+	}
+	c.dirty = false
+	return nil
+}
+
+func (c *Cassette) match(request Request) *Interaction {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var last *Interaction
+	for _, interaction := range c.Interactions {
+		if interaction.Request != request {
+			continue
+		}
+		if !interaction.replayed {
+			interaction.replayed = true
+			return interaction
+		}
+		last = interaction
+	}
+	return last
+}
+
+func readRequest(req *http.Request) (Request, error) {
+	request := Request{Method: req.Method, URL: req.URL.String()}
+	if req.Body == nil || req.Body == http.NoBody {
+		return request, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return request, fmt.Errorf("falha ao ler corpo da requisição %s %s: %w", req.Method, request.URL, err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	request.Body = redactBody(string(body))
+	return request, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			header[http.CanonicalHeaderKey(name)] = []string{redactedValue}
+		}
+	}
+	return header
+}
+
+// redactBody masks credential fields of JSON bodies, such as the token
+// exchange responses of registries and GHCR. Other bodies are kept as is.
+func redactBody(body string) string {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(body), &fields); err != nil {
+		return body
+	}
+
+	redacted := false
+	for key := range fields {
+		if redactedFields[strings.ToLower(key)] {
+			fields[key] = redactedValue
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
+
+type recorder struct {
+	next     http.RoundTripper
+	cassette *Cassette
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler resposta de %s %s: %w", request.Method, request.URL, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.cassette.add(&Interaction{
+		Request: request,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       redactBody(string(body)),
+		},
+	})
+
+	return resp, nil
+}
+
+type replayer struct {
+	cassette *Cassette
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := r.cassette.match(request)
+	if interaction == nil {
+		return nil, fmt.Errorf("requisição %s %s não encontrada no cassette %s", request.Method, request.URL, r.cassette.path)
+	}
+
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
@@ -0,0 +1,135 @@
+package httpreplay
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrap_WithoutModeReturnsNext(t *testing.T) {
+	t.Setenv(CassetteEnvVar, filepath.Join(t.TempDir(), "cassette.json"))
+	t.Setenv(ModeEnvVar, "")
+
+	next := http.DefaultTransport
+	if got := Wrap(next); got != next {
+		t.Errorf("Wrap() = %T, expected the next transport when %s is not set", got, ModeEnvVar)
+	}
+}
+
+func TestWrap_RecordRedactsAndReplays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Set-Cookie", "session=secret-cookie")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"token":"secret-token","access_token":"secret-access","expires_in":300}`))
+		default:
+			w.Header().Set("Www-Authenticate", `Bearer realm="https://auth.example.com/token",scope="secret-scope"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassettes", "cassette.json")
+	t.Setenv(CassetteEnvVar, path)
+	t.Setenv(ModeEnvVar, ModeRecord)
+
+	recording := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	tokenBody := get(t, recording, server.URL+"/token")
+	if !strings.Contains(tokenBody, "secret-token") {
+		t.Errorf("recorded response body = %q, expected the caller to receive the real token", tokenBody)
+	}
+	get(t, recording, server.URL+"/v2/")
+	server.Close()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("cassette written before Flush(), stat error = %v", err)
+	}
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("cassette not written: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("cassette permissions = %o, expected 600", mode)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read cassette: %v", err)
+	}
+	for _, secret := range []string{"secret-token", "secret-access", "secret-cookie", "secret-scope"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q, expected it to be redacted", secret)
+		}
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		t.Fatalf("failed to decode cassette: %v", err)
+	}
+	if len(cassette.Interactions) != 2 {
+		t.Fatalf("cassette has %d interactions, expected 2", len(cassette.Interactions))
+	}
+	if !strings.Contains(cassette.Interactions[0].Response.Body, `"expires_in":300`) {
+		t.Errorf("redacted body = %q, expected non-secret fields to be kept", cassette.Interactions[0].Response.Body)
+	}
+
+	replayPath := filepath.Join(t.TempDir(), "replay.json")
+	if err := os.WriteFile(replayPath, data, 0600); err != nil {
+		t.Fatalf("failed to copy cassette: %v", err)
+	}
+	t.Setenv(CassetteEnvVar, replayPath)
+	t.Setenv(ModeEnvVar, ModeReplay)
+
+	replaying := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	if body := get(t, replaying, server.URL+"/token"); !strings.Contains(body, `"token":"REDACTED"`) {
+		t.Errorf("replayed body = %q, expected the redacted token", body)
+	}
+	if _, err := replaying.Get(server.URL + "/missing"); err == nil || !strings.Contains(err.Error(), "não encontrada no cassette") {
+		t.Errorf("Get() for an unrecorded request = %v, expected a cassette miss", err)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "token fields", body: `{"access_token":"a","scope":"repo"}`, want: `{"access_token":"REDACTED","scope":"repo"}`},
+		{name: "no token fields", body: `{"tags":["1.0"]}`, want: `{"tags":["1.0"]}`},
+		{name: "not json", body: "plain text", want: "plain text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody(tt.body); got != tt.want {
+				t.Errorf("redactBody() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get(%s) unexpected error: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response of %s: %v", url, err)
+	}
+	return string(body)
+}
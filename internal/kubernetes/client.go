@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -15,7 +16,7 @@ import (
 )
 
 type Client struct {
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
 	config    *types.Config
 	logger    *logger.Logger
 }
@@ -23,12 +24,11 @@ type Client struct {
 func NewClient(cfg *types.Config, log *logger.Logger) (*Client, error) {
 	log.Info("connecting_k8s").Send()
 
-	kubeconfig := getKubeconfigPath()
-
-	configLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
-		&clientcmd.ConfigOverrides{CurrentContext: cfg.Kubernetes.Context},
-	)
+	configLoader, err := buildClientConfig(getKubeconfigPath(), cfg.Kubernetes.Context)
+	if err != nil {
+		log.Error("k8s_connection_failed").Err(err).Send()
+		return nil, err
+	}
 
 	restConfig, err := configLoader.ClientConfig()
 	if err != nil {
@@ -64,6 +64,28 @@ func NewClient(cfg *types.Config, log *logger.Logger) (*Client, error) {
 	return client, nil
 }
 
+func buildClientConfig(kubeconfig, kubeContext string) (clientcmd.ClientConfig, error) {
+	configLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	)
+
+	if kubeContext == "" {
+		return configLoader, nil
+	}
+
+	rawConfig, err := configLoader.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao carregar kubeconfig: %w", err)
+	}
+
+	if _, exists := rawConfig.Contexts[kubeContext]; !exists {
+		return nil, fmt.Errorf("contexto '%s' não encontrado no kubeconfig %s", kubeContext, kubeconfig)
+	}
+
+	return configLoader, nil
+}
+
 func getKubeconfigPath() string {
 	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
 		return kubeconfig
@@ -111,6 +133,6 @@ func (c *Client) GetNamespaces() ([]string, error) {
 	return namespaces, nil
 }
 
-func (c *Client) GetClient() *kubernetes.Clientset {
+func (c *Client) GetClient() kubernetes.Interface {
 	return c.clientset
 }
@@ -0,0 +1,95 @@
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev-cluster
+  cluster:
+    server: https://dev.example.com
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: dev-user
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: prod-user
+users:
+- name: dev-user
+  user:
+    token: dev-token
+- name: prod-user
+  user:
+    token: prod-token
+`
+
+func writeTestKubeconfig(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0600); err != nil {
+		t.Fatalf("falha ao escrever kubeconfig: %v", err)
+	}
+	return path
+}
+
+func TestBuildClientConfig(t *testing.T) {
+	kubeconfig := writeTestKubeconfig(t)
+
+	tests := []struct {
+		name           string
+		context        string
+		expectedServer string
+		expectError    bool
+	}{
+		{
+			name:           "current context when none given",
+			context:        "",
+			expectedServer: "https://dev.example.com",
+		},
+		{
+			name:           "context override selects cluster",
+			context:        "prod",
+			expectedServer: "https://prod.example.com",
+		},
+		{
+			name:        "unknown context fails",
+			context:     "staging",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configLoader, err := buildClientConfig(kubeconfig, tt.context)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("buildClientConfig(%s) expected error, got nil", tt.context)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildClientConfig(%s) unexpected error: %v", tt.context, err)
+			}
+
+			restConfig, err := configLoader.ClientConfig()
+			if err != nil {
+				t.Fatalf("ClientConfig() unexpected error: %v", err)
+			}
+			if restConfig.Host != tt.expectedServer {
+				t.Errorf("host = %s, expected %s", restConfig.Host, tt.expectedServer)
+			}
+		})
+	}
+}
@@ -2,29 +2,67 @@ package kubernetes
 
 import (
 	"context"
-	"strings"
 
+	"github.com/kevinfinalboss/privateer/internal/classifier"
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Scanner struct {
-	client *Client
-	logger *logger.Logger
-	config *types.Config
+	client     *Client
+	logger     *logger.Logger
+	config     *types.Config
+	classifier *classifier.Classifier
 }
 
 func NewScanner(client *Client, log *logger.Logger, cfg *types.Config) *Scanner {
 	return &Scanner{
-		client: client,
-		logger: log,
-		config: cfg,
+		client:     client,
+		logger:     log,
+		config:     cfg,
+		classifier: classifier.New(cfg, log),
 	}
 }
 
-func (s *Scanner) ScanNamespace(namespace string) ([]*types.ImageInfo, error) {
-	ctx := context.Background()
+func (s *Scanner) ScanNamespace(ctx context.Context, namespace string) ([]*types.ImageInfo, error) {
+	allImages, err := s.collectNamespaceImages(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	publicImages := s.filterPublicImages(allImages)
+
+	s.logger.Info("images_found").
+		Str("namespace", namespace).
+		Int("total_images", len(allImages)).
+		Int("public_images", len(publicImages)).
+		Send()
+
+	return publicImages, nil
+}
+
+func (s *Scanner) ScanNamespaceForRegistry(ctx context.Context, namespace, registryHost string) ([]*types.ImageInfo, error) {
+	allImages, err := s.collectNamespaceImages(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	registryImages := filterRegistryImages(allImages, registryHost)
+
+	s.logger.Info("registry_images_found").
+		Str("namespace", namespace).
+		Str("registry", registryHost).
+		Int("total_images", len(allImages)).
+		Int("registry_images", len(registryImages)).
+		Send()
+
+	return registryImages, nil
+}
+
+func (s *Scanner) collectNamespaceImages(ctx context.Context, namespace string) ([]*types.ImageInfo, error) {
 	var allImages []*types.ImageInfo
 
 	s.logger.Info("scanning_namespace").
@@ -61,6 +99,17 @@ func (s *Scanner) ScanNamespace(namespace string) ([]*types.ImageInfo, error) {
 	}
 	allImages = append(allImages, cronJobImages...)
 
+	if s.config.Kubernetes.ScanPods {
+		podImages, err := s.scanPods(ctx, namespace)
+		if err != nil {
+			s.logger.Warn("pod_scan_failed").
+				Str("namespace", namespace).
+				Err(err).
+				Send()
+		}
+		allImages = append(allImages, podImages...)
+	}
+
 	s.logger.Debug("namespace_scan_summary_before_filtering").
 		Str("namespace", namespace).
 		Int("total_images_found", len(allImages)).
@@ -75,18 +124,11 @@ func (s *Scanner) ScanNamespace(namespace string) ([]*types.ImageInfo, error) {
 			Str("resource_name", img.ResourceName).
 			Str("container", img.Container).
 			Bool("is_init_container", img.IsInitContainer).
+			Bool("is_ephemeral_container", img.IsEphemeralContainer).
 			Send()
 	}
 
-	publicImages := s.filterPublicImages(allImages)
-
-	s.logger.Info("images_found").
-		Str("namespace", namespace).
-		Int("total_images", len(allImages)).
-		Int("public_images", len(publicImages)).
-		Send()
-
-	return publicImages, nil
+	return allImages, nil
 }
 
 func (s *Scanner) scanDeployments(ctx context.Context, namespace string) ([]*types.ImageInfo, error) {
@@ -103,6 +145,7 @@ func (s *Scanner) scanDeployments(ctx context.Context, namespace string) ([]*typ
 				ResourceType: "Deployment",
 				ResourceName: deployment.Name,
 				Namespace:    namespace,
+				Annotations:  deployment.Annotations,
 				Container:    container.Name,
 			}
 			images = append(images, imageInfo)
@@ -114,6 +157,7 @@ func (s *Scanner) scanDeployments(ctx context.Context, namespace string) ([]*typ
 				ResourceType:    "Deployment",
 				ResourceName:    deployment.Name,
 				Namespace:       namespace,
+				Annotations:     deployment.Annotations,
 				Container:       container.Name,
 				IsInitContainer: true,
 			}
@@ -145,6 +189,7 @@ func (s *Scanner) scanStatefulSets(ctx context.Context, namespace string) ([]*ty
 				ResourceType: "StatefulSet",
 				ResourceName: statefulSet.Name,
 				Namespace:    namespace,
+				Annotations:  statefulSet.Annotations,
 				Container:    container.Name,
 			}
 			images = append(images, imageInfo)
@@ -156,6 +201,7 @@ func (s *Scanner) scanStatefulSets(ctx context.Context, namespace string) ([]*ty
 				ResourceType:    "StatefulSet",
 				ResourceName:    statefulSet.Name,
 				Namespace:       namespace,
+				Annotations:     statefulSet.Annotations,
 				Container:       container.Name,
 				IsInitContainer: true,
 			}
@@ -187,6 +233,7 @@ func (s *Scanner) scanDaemonSets(ctx context.Context, namespace string) ([]*type
 				ResourceType: "DaemonSet",
 				ResourceName: daemonSet.Name,
 				Namespace:    namespace,
+				Annotations:  daemonSet.Annotations,
 				Container:    container.Name,
 			}
 			images = append(images, imageInfo)
@@ -198,6 +245,7 @@ func (s *Scanner) scanDaemonSets(ctx context.Context, namespace string) ([]*type
 				ResourceType:    "DaemonSet",
 				ResourceName:    daemonSet.Name,
 				Namespace:       namespace,
+				Annotations:     daemonSet.Annotations,
 				Container:       container.Name,
 				IsInitContainer: true,
 			}
@@ -229,6 +277,7 @@ func (s *Scanner) scanJobs(ctx context.Context, namespace string) ([]*types.Imag
 				ResourceType: "Job",
 				ResourceName: job.Name,
 				Namespace:    namespace,
+				Annotations:  job.Annotations,
 				Container:    container.Name,
 			}
 			images = append(images, imageInfo)
@@ -240,6 +289,7 @@ func (s *Scanner) scanJobs(ctx context.Context, namespace string) ([]*types.Imag
 				ResourceType:    "Job",
 				ResourceName:    job.Name,
 				Namespace:       namespace,
+				Annotations:     job.Annotations,
 				Container:       container.Name,
 				IsInitContainer: true,
 			}
@@ -271,6 +321,7 @@ func (s *Scanner) scanCronJobs(ctx context.Context, namespace string) ([]*types.
 				ResourceType: "CronJob",
 				ResourceName: cronJob.Name,
 				Namespace:    namespace,
+				Annotations:  cronJob.Annotations,
 				Container:    container.Name,
 			}
 			images = append(images, imageInfo)
@@ -282,6 +333,7 @@ func (s *Scanner) scanCronJobs(ctx context.Context, namespace string) ([]*types.
 				ResourceType:    "CronJob",
 				ResourceName:    cronJob.Name,
 				Namespace:       namespace,
+				Annotations:     cronJob.Annotations,
 				Container:       container.Name,
 				IsInitContainer: true,
 			}
@@ -299,6 +351,44 @@ func (s *Scanner) scanCronJobs(ctx context.Context, namespace string) ([]*types.
 	return images, nil
 }
 
+func (s *Scanner) scanPods(ctx context.Context, namespace string) ([]*types.ImageInfo, error) {
+	pods, err := s.client.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	images := ephemeralContainerImages(namespace, pods.Items)
+
+	s.logger.Debug("resource_scanned").
+		Str("namespace", namespace).
+		Str("resource_type", "Pod").
+		Int("resource_count", len(pods.Items)).
+		Int("image_count", len(images)).
+		Send()
+
+	return images, nil
+}
+
+func ephemeralContainerImages(namespace string, pods []corev1.Pod) []*types.ImageInfo {
+	var images []*types.ImageInfo
+	for _, pod := range pods {
+		for _, container := range pod.Spec.EphemeralContainers {
+			imageInfo := &types.ImageInfo{
+				Image:                container.Image,
+				ResourceType:         "Pod",
+				ResourceName:         pod.Name,
+				Namespace:            namespace,
+				Annotations:          pod.Annotations,
+				Container:            container.Name,
+				IsEphemeralContainer: true,
+			}
+			images = append(images, imageInfo)
+		}
+	}
+
+	return images
+}
+
 func (s *Scanner) filterPublicImages(images []*types.ImageInfo) []*types.ImageInfo {
 	var publicImages []*types.ImageInfo
 
@@ -314,7 +404,7 @@ func (s *Scanner) filterPublicImages(images []*types.ImageInfo) []*types.ImageIn
 			Str("resource", image.ResourceName).
 			Send()
 
-		isPublic := s.isPublicImage(image.Image)
+		isPublic := s.classifier.IsPublic(image.Image)
 
 		s.logger.Debug("image_publicity_result").
 			Str("image", image.Image).
@@ -322,8 +412,24 @@ func (s *Scanner) filterPublicImages(images []*types.ImageInfo) []*types.ImageIn
 			Bool("is_public", isPublic).
 			Send()
 
+		if isPublic && !s.classifier.MatchesIncludeOnly(image.Image) {
+			s.logger.Debug("image_excluded_from_public_list").
+				Str("image", image.Image).
+				Str("namespace", image.Namespace).
+				Str("reason", "not_in_include_only").
+				Send()
+			continue
+		}
+
 		if isPublic {
 			image.IsPublic = true
+			if s.classifier.MatchesNoMigrate(image.Image) {
+				image.NoMigrate = true
+				s.logger.Debug("image_marked_no_migrate").
+					Str("image", image.Image).
+					Str("namespace", image.Namespace).
+					Send()
+			}
 			publicImages = append(publicImages, image)
 
 			s.logger.Debug("image_added_to_public_list").
@@ -348,294 +454,20 @@ func (s *Scanner) filterPublicImages(images []*types.ImageInfo) []*types.ImageIn
 	return publicImages
 }
 
-func (s *Scanner) isPublicImage(imageName string) bool {
-	imageLower := strings.ToLower(imageName)
-
-	s.logger.Debug("starting_image_classification").
-		Str("image", imageName).
-		Str("image_lower", imageLower).
-		Send()
-
-	if s.shouldIgnoreRegistry(imageName) {
-		s.logger.Debug("image_classification_result").
-			Str("image", imageName).
-			Str("decision", "ignored").
-			Str("reason", "registry_in_ignore_list").
-			Bool("is_public", false).
-			Send()
-		return false
-	}
-
-	if s.isCustomPrivateRegistry(imageName) {
-		s.logger.Debug("image_classification_result").
-			Str("image", imageName).
-			Str("decision", "private").
-			Str("reason", "custom_private_registry").
-			Bool("is_public", false).
-			Send()
-		return false
-	}
-
-	if s.isCustomPublicRegistry(imageName) {
-		s.logger.Debug("image_classification_result").
-			Str("image", imageName).
-			Str("decision", "public").
-			Str("reason", "custom_public_registry").
-			Bool("is_public", true).
-			Send()
-		return true
-	}
-
-	knownPrivateRegistries := []string{
-		"localhost",
-		"127.0.0.1",
-	}
-
-	for _, registry := range knownPrivateRegistries {
-		if strings.HasPrefix(imageLower, registry) {
-			s.logger.Debug("image_classification_result").
-				Str("image", imageName).
-				Str("decision", "private").
-				Str("reason", "known_private_registry").
-				Str("matched_registry", registry).
-				Bool("is_public", false).
-				Send()
-			return false
-		}
-	}
-
-	if s.isPrivateRegistry(imageName) {
-		s.logger.Debug("image_classification_result").
-			Str("image", imageName).
-			Str("decision", "private").
-			Str("reason", "detected_as_private_registry").
-			Bool("is_public", false).
-			Send()
-		return false
-	}
-
-	s.logger.Debug("image_classification_result").
-		Str("image", imageName).
-		Str("decision", "public").
-		Str("reason", "default_public_classification").
-		Bool("is_public", true).
-		Send()
-	return true
-}
-
-func (s *Scanner) shouldIgnoreRegistry(imageName string) bool {
-	if s.config == nil || len(s.config.ImageDetection.IgnoreRegistries) == 0 {
-		s.logger.Debug("ignore_registry_check").
-			Str("image", imageName).
-			Bool("has_config", s.config != nil).
-			Int("ignore_list_size", 0).
-			Bool("should_ignore", false).
-			Send()
-		return false
-	}
+func filterRegistryImages(images []*types.ImageInfo, registryHost string) []*types.ImageInfo {
+	host := utils.CanonicalHost(registryHost)
 
-	imageLower := strings.ToLower(imageName)
-	for _, ignored := range s.config.ImageDetection.IgnoreRegistries {
-		if strings.HasPrefix(imageLower, strings.ToLower(ignored)) {
-			s.logger.Debug("ignore_registry_check").
-				Str("image", imageName).
-				Str("matched_ignore_pattern", ignored).
-				Bool("should_ignore", true).
-				Send()
-			return true
+	var registryImages []*types.ImageInfo
+	for _, image := range images {
+		ref, err := utils.CanonicalRef(image.Image)
+		if err != nil {
+			continue
 		}
-	}
-
-	s.logger.Debug("ignore_registry_check").
-		Str("image", imageName).
-		Int("ignore_list_size", len(s.config.ImageDetection.IgnoreRegistries)).
-		Bool("should_ignore", false).
-		Send()
-	return false
-}
-
-func (s *Scanner) isCustomPrivateRegistry(imageName string) bool {
-	if s.config == nil || len(s.config.ImageDetection.CustomPrivateRegistries) == 0 {
-		s.logger.Debug("custom_private_registry_check").
-			Str("image", imageName).
-			Bool("has_config", s.config != nil).
-			Int("private_list_size", 0).
-			Bool("is_custom_private", false).
-			Send()
-		return false
-	}
-
-	imageLower := strings.ToLower(imageName)
-	for _, privateReg := range s.config.ImageDetection.CustomPrivateRegistries {
-		if strings.HasPrefix(imageLower, strings.ToLower(privateReg)) {
-			s.logger.Debug("custom_private_registry_check").
-				Str("image", imageName).
-				Str("matched_private_pattern", privateReg).
-				Bool("is_custom_private", true).
-				Send()
-			return true
+		if ref.Registry == host {
+			image.IsPublic = false
+			registryImages = append(registryImages, image)
 		}
 	}
 
-	s.logger.Debug("custom_private_registry_check").
-		Str("image", imageName).
-		Int("private_list_size", len(s.config.ImageDetection.CustomPrivateRegistries)).
-		Bool("is_custom_private", false).
-		Send()
-	return false
-}
-
-func (s *Scanner) isCustomPublicRegistry(imageName string) bool {
-	if s.config == nil || len(s.config.ImageDetection.CustomPublicRegistries) == 0 {
-		s.logger.Debug("custom_public_registry_check").
-			Str("image", imageName).
-			Bool("has_config", s.config != nil).
-			Int("public_list_size", 0).
-			Bool("is_custom_public", false).
-			Send()
-		return false
-	}
-
-	imageLower := strings.ToLower(imageName)
-	for _, publicReg := range s.config.ImageDetection.CustomPublicRegistries {
-		if strings.HasPrefix(imageLower, strings.ToLower(publicReg)) {
-			s.logger.Debug("custom_public_registry_check").
-				Str("image", imageName).
-				Str("matched_public_pattern", publicReg).
-				Bool("is_custom_public", true).
-				Send()
-			return true
-		}
-	}
-
-	s.logger.Debug("custom_public_registry_check").
-		Str("image", imageName).
-		Int("public_list_size", len(s.config.ImageDetection.CustomPublicRegistries)).
-		Bool("is_custom_public", false).
-		Send()
-	return false
-}
-
-func (s *Scanner) isPrivateRegistry(imageName string) bool {
-	imageLower := strings.ToLower(imageName)
-
-	s.logger.Debug("private_registry_detection_start").
-		Str("image", imageName).
-		Send()
-
-	if strings.Contains(imageLower, ".dkr.ecr.") &&
-		strings.Contains(imageLower, ".amazonaws.com") &&
-		!strings.HasPrefix(imageLower, "public.ecr.aws") {
-		s.logger.Debug("private_registry_detection").
-			Str("image", imageName).
-			Str("type", "ecr_private").
-			Bool("is_private", true).
-			Send()
-		return true
-	}
-
-	if strings.Contains(imageLower, ".azurecr.io") &&
-		!strings.Contains(imageLower, "mcr.microsoft.com") {
-		s.logger.Debug("private_registry_detection").
-			Str("image", imageName).
-			Str("type", "azure_private").
-			Bool("is_private", true).
-			Send()
-		return true
-	}
-
-	if (strings.Contains(imageLower, ".gcr.io") ||
-		strings.Contains(imageLower, ".pkg.dev")) &&
-		!strings.HasPrefix(imageLower, "gcr.io/google-containers") &&
-		!strings.HasPrefix(imageLower, "k8s.gcr.io") &&
-		!strings.HasPrefix(imageLower, "registry.k8s.io") {
-		s.logger.Debug("private_registry_detection").
-			Str("image", imageName).
-			Str("type", "gcp_private").
-			Bool("is_private", true).
-			Send()
-		return true
-	}
-
-	if strings.HasPrefix(imageLower, "ghcr.io/") {
-		parts := strings.Split(imageName, "/")
-		if len(parts) >= 3 {
-			s.logger.Debug("private_registry_detection").
-				Str("image", imageName).
-				Str("type", "ghcr_private").
-				Int("parts_count", len(parts)).
-				Bool("is_private", true).
-				Send()
-			return true
-		} else {
-			s.logger.Debug("private_registry_detection").
-				Str("image", imageName).
-				Str("type", "ghcr_insufficient_parts").
-				Int("parts_count", len(parts)).
-				Bool("is_private", false).
-				Send()
-			return false
-		}
-	}
-
-	knownPublicRegistries := []string{
-		"docker.io",
-		"index.docker.io",
-		"registry-1.docker.io",
-		"quay.io",
-		"registry.k8s.io",
-		"k8s.gcr.io",
-		"gcr.io/google-containers",
-		"mcr.microsoft.com",
-		"public.ecr.aws",
-	}
-
-	for _, publicReg := range knownPublicRegistries {
-		if strings.HasPrefix(imageLower, publicReg) ||
-			strings.Contains(imageLower, publicReg) {
-			s.logger.Debug("private_registry_detection").
-				Str("image", imageName).
-				Str("type", "known_public_registry").
-				Str("matched_registry", publicReg).
-				Bool("is_private", false).
-				Send()
-			return false
-		}
-	}
-
-	parts := strings.Split(imageName, "/")
-	if len(parts) >= 2 && strings.Contains(parts[0], ".") &&
-		!strings.Contains(parts[0], "docker.io") &&
-		!strings.Contains(parts[0], "index.docker.io") &&
-		!strings.Contains(parts[0], "registry-1.docker.io") {
-
-		firstPart := strings.ToLower(parts[0])
-		for _, publicReg := range knownPublicRegistries {
-			if strings.Contains(firstPart, strings.ToLower(publicReg)) {
-				s.logger.Debug("private_registry_detection").
-					Str("image", imageName).
-					Str("type", "public_registry_in_custom_domain_check").
-					Str("detected_public", publicReg).
-					Bool("is_private", false).
-					Send()
-				return false
-			}
-		}
-
-		s.logger.Debug("private_registry_detection").
-			Str("image", imageName).
-			Str("type", "custom_domain_private").
-			Str("detected_registry", parts[0]).
-			Int("parts_count", len(parts)).
-			Bool("is_private", true).
-			Send()
-		return true
-	}
-
-	s.logger.Debug("private_registry_detection").
-		Str("image", imageName).
-		Str("type", "not_detected_as_private").
-		Bool("is_private", false).
-		Send()
-	return false
+	return registryImages
 }
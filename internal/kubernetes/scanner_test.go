@@ -1,494 +1,264 @@
 package kubernetes
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
-func TestScanner_isPublicImage(t *testing.T) {
+func TestScanner_filterPublicImages(t *testing.T) {
 	log := logger.NewTest()
 	config := &types.Config{
 		ImageDetection: types.ImageDetectionConfig{
-			IgnoreRegistries:        []string{"ignore.local"},
 			CustomPrivateRegistries: []string{"private.company.com"},
-			CustomPublicRegistries:  []string{"custom-public.io"},
 		},
 	}
 
-	scanner := &Scanner{
-		logger: log,
-		config: config,
-	}
+	scanner := NewScanner(nil, log, config)
 
-	tests := []struct {
-		name     string
-		image    string
-		expected bool
-	}{
-		{
-			name:     "docker hub public image",
-			image:    "nginx:latest",
-			expected: true,
-		},
-		{
-			name:     "docker hub with explicit registry",
-			image:    "docker.io/nginx:latest",
-			expected: true,
-		},
-		{
-			name:     "localhost image should be private",
-			image:    "localhost:5000/myapp:latest",
-			expected: false,
-		},
-		{
-			name:     "127.0.0.1 image should be private",
-			image:    "127.0.0.1:5000/myapp:latest",
-			expected: false,
-		},
-		{
-			name:     "aws ecr private registry",
-			image:    "123456789012.dkr.ecr.us-east-1.amazonaws.com/myapp:latest",
-			expected: false,
-		},
-		{
-			name:     "aws ecr public registry",
-			image:    "public.ecr.aws/nginx/nginx:latest",
-			expected: true,
-		},
-		{
-			name:     "azure private registry",
-			image:    "myregistry.azurecr.io/myapp:latest",
-			expected: false,
-		},
-		{
-			name:     "azure public registry (mcr)",
-			image:    "mcr.microsoft.com/dotnet/core/runtime:3.1",
-			expected: true,
-		},
-		{
-			name:     "google private registry (gcr)",
-			image:    "gcr.io/my-project/myapp:latest",
-			expected: false,
-		},
-		{
-			name:     "google public registry (k8s)",
-			image:    "registry.k8s.io/pause:3.5",
-			expected: true,
-		},
-		{
-			name:     "github container registry private",
-			image:    "ghcr.io/owner/repo:latest",
-			expected: false,
-		},
-		{
-			name:     "custom private registry",
-			image:    "private.company.com/myapp:latest",
-			expected: false,
-		},
+	inputImages := []*types.ImageInfo{
 		{
-			name:     "custom public registry",
-			image:    "custom-public.io/myapp:latest",
-			expected: true,
+			Image:        "nginx:latest",
+			ResourceType: "Deployment",
+			ResourceName: "web",
+			Namespace:    "default",
 		},
 		{
-			name:     "ignored registry",
-			image:    "ignore.local/myapp:latest",
-			expected: false,
+			Image:        "private.company.com/myapp:latest",
+			ResourceType: "Deployment",
+			ResourceName: "app",
+			Namespace:    "default",
 		},
 		{
-			name:     "custom domain registry",
-			image:    "registry.example.com/myapp:latest",
-			expected: false,
+			Image:        "docker.io/redis:6",
+			ResourceType: "StatefulSet",
+			ResourceName: "cache",
+			Namespace:    "default",
 		},
 		{
-			name:     "quay.io public registry",
-			image:    "quay.io/prometheus/prometheus:latest",
-			expected: true,
+			Image:        "localhost:5000/local:latest",
+			ResourceType: "Job",
+			ResourceName: "migration",
+			Namespace:    "default",
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := scanner.isPublicImage(tt.image)
-			if result != tt.expected {
-				t.Errorf("isPublicImage(%q) = %v, expected %v", tt.image, result, tt.expected)
-			}
-		})
-	}
-}
+	result := scanner.filterPublicImages(inputImages)
 
-func TestScanner_shouldIgnoreRegistry(t *testing.T) {
-	log := logger.NewTest()
+	expectedPublicCount := 2
+	if len(result) != expectedPublicCount {
+		t.Errorf("filterPublicImages() returned %d images, expected %d", len(result), expectedPublicCount)
+	}
 
-	tests := []struct {
-		name           string
-		config         *types.Config
-		image          string
-		expectedIgnore bool
-	}{
-		{
-			name:           "nil config should not ignore",
-			config:         nil,
-			image:          "nginx:latest",
-			expectedIgnore: false,
-		},
-		{
-			name: "empty ignore list should not ignore",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					IgnoreRegistries: []string{},
-				},
-			},
-			image:          "nginx:latest",
-			expectedIgnore: false,
-		},
-		{
-			name: "image in ignore list should be ignored",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					IgnoreRegistries: []string{"localhost", "registry.local"},
-				},
-			},
-			image:          "localhost:5000/myapp:latest",
-			expectedIgnore: true,
-		},
-		{
-			name: "image not in ignore list should not be ignored",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					IgnoreRegistries: []string{"localhost", "registry.local"},
-				},
-			},
-			image:          "docker.io/nginx:latest",
-			expectedIgnore: false,
-		},
-		{
-			name: "case insensitive matching",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					IgnoreRegistries: []string{"Registry.Local"},
-				},
-			},
-			image:          "registry.local/myapp:latest",
-			expectedIgnore: true,
-		},
+	publicImages := make(map[string]bool)
+	for _, img := range result {
+		publicImages[img.Image] = true
+		if !img.IsPublic {
+			t.Errorf("Image %s should be marked as public but IsPublic=false", img.Image)
+		}
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			scanner := &Scanner{
-				logger: log,
-				config: tt.config,
-			}
+	expectedPublicImages := []string{"nginx:latest", "docker.io/redis:6"}
+	for _, expected := range expectedPublicImages {
+		if !publicImages[expected] {
+			t.Errorf("Expected public image %s not found in results", expected)
+		}
+	}
 
-			result := scanner.shouldIgnoreRegistry(tt.image)
-			if result != tt.expectedIgnore {
-				t.Errorf("shouldIgnoreRegistry(%q) = %v, expected %v", tt.image, result, tt.expectedIgnore)
-			}
-		})
+	unexpectedPublicImages := []string{"private.company.com/myapp:latest", "localhost:5000/local:latest"}
+	for _, unexpected := range unexpectedPublicImages {
+		if publicImages[unexpected] {
+			t.Errorf("Unexpected public image %s found in results", unexpected)
+		}
 	}
 }
 
-func TestScanner_isCustomPrivateRegistry(t *testing.T) {
-	log := logger.NewTest()
-
+func TestScanner_filterPublicImages_IncludeOnly(t *testing.T) {
 	tests := []struct {
-		name            string
-		config          *types.Config
-		image           string
-		expectedPrivate bool
+		name        string
+		includeOnly []string
+		expected    []string
 	}{
 		{
-			name:            "nil config should return false",
-			config:          nil,
-			image:           "private.company.com/myapp:latest",
-			expectedPrivate: false,
-		},
-		{
-			name: "empty private list should return false",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					CustomPrivateRegistries: []string{},
-				},
-			},
-			image:           "private.company.com/myapp:latest",
-			expectedPrivate: false,
+			name:     "empty include_only keeps every public image",
+			expected: []string{"nginx:1.25", "bitnami/redis:7.0", "quay.io/prometheus/node-exporter:v1.6.0", "ghcr.io/acme/tool:1.0"},
 		},
 		{
-			name: "image in private list should return true",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					CustomPrivateRegistries: []string{"private.company.com", "internal.registry.io"},
-				},
-			},
-			image:           "private.company.com/myapp:latest",
-			expectedPrivate: true,
+			name:        "docker hub namespaces",
+			includeOnly: []string{"bitnami/*", "library/*"},
+			expected:    []string{"nginx:1.25", "bitnami/redis:7.0"},
 		},
 		{
-			name: "image not in private list should return false",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					CustomPrivateRegistries: []string{"private.company.com", "internal.registry.io"},
-				},
-			},
-			image:           "docker.io/nginx:latest",
-			expectedPrivate: false,
+			name:        "registry qualified glob and regex",
+			includeOnly: []string{"quay.io/prometheus/*", "regex:^ghcr\\.io/acme/"},
+			expected:    []string{"quay.io/prometheus/node-exporter:v1.6.0", "ghcr.io/acme/tool:1.0"},
 		},
 		{
-			name: "case insensitive matching",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					CustomPrivateRegistries: []string{"Private.Company.Com"},
-				},
-			},
-			image:           "private.company.com/myapp:latest",
-			expectedPrivate: true,
+			name:        "private and ignored images stay excluded even when included",
+			includeOnly: []string{"private.company.com/*", "ignore.local/*", "regex:.*"},
+			expected:    []string{"nginx:1.25", "bitnami/redis:7.0", "quay.io/prometheus/node-exporter:v1.6.0", "ghcr.io/acme/tool:1.0"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			scanner := &Scanner{
-				logger: log,
-				config: tt.config,
-			}
-
-			result := scanner.isCustomPrivateRegistry(tt.image)
-			if result != tt.expectedPrivate {
-				t.Errorf("isCustomPrivateRegistry(%q) = %v, expected %v", tt.image, result, tt.expectedPrivate)
-			}
-		})
-	}
-}
-
-func TestScanner_isCustomPublicRegistry(t *testing.T) {
-	log := logger.NewTest()
-
-	tests := []struct {
-		name           string
-		config         *types.Config
-		image          string
-		expectedPublic bool
-	}{
-		{
-			name:           "nil config should return false",
-			config:         nil,
-			image:          "custom-public.io/myapp:latest",
-			expectedPublic: false,
-		},
-		{
-			name: "empty public list should return false",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					CustomPublicRegistries: []string{},
-				},
-			},
-			image:          "custom-public.io/myapp:latest",
-			expectedPublic: false,
-		},
-		{
-			name: "image in public list should return true",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					CustomPublicRegistries: []string{"custom-public.io", "public.registry.com"},
-				},
-			},
-			image:          "custom-public.io/myapp:latest",
-			expectedPublic: true,
-		},
-		{
-			name: "image not in public list should return false",
-			config: &types.Config{
-				ImageDetection: types.ImageDetectionConfig{
-					CustomPublicRegistries: []string{"custom-public.io", "public.registry.com"},
-				},
-			},
-			image:          "docker.io/nginx:latest",
-			expectedPublic: false,
-		},
-		{
-			name: "case insensitive matching",
-			config: &types.Config{
+			scanner := NewScanner(nil, logger.NewTest(), &types.Config{
 				ImageDetection: types.ImageDetectionConfig{
-					CustomPublicRegistries: []string{"Custom-Public.IO"},
+					IgnoreRegistries:        []string{"ignore.local"},
+					CustomPrivateRegistries: []string{"private.company.com"},
+					CustomPublicRegistries:  []string{"ghcr.io/acme"},
+					IncludeOnly:             tt.includeOnly,
 				},
-			},
-			image:          "custom-public.io/myapp:latest",
-			expectedPublic: true,
-		},
-	}
+			})
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			scanner := &Scanner{
-				logger: log,
-				config: tt.config,
+			images := []*types.ImageInfo{
+				{Image: "nginx:1.25"},
+				{Image: "bitnami/redis:7.0"},
+				{Image: "quay.io/prometheus/node-exporter:v1.6.0"},
+				{Image: "ghcr.io/acme/tool:1.0"},
+				{Image: "private.company.com/app:1.0"},
+				{Image: "ignore.local/debug:1.0"},
+			}
+
+			var result []string
+			for _, image := range scanner.filterPublicImages(images) {
+				result = append(result, image.Image)
 			}
 
-			result := scanner.isCustomPublicRegistry(tt.image)
-			if result != tt.expectedPublic {
-				t.Errorf("isCustomPublicRegistry(%q) = %v, expected %v", tt.image, result, tt.expectedPublic)
+			if strings.Join(result, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("filterPublicImages() = %v, expected %v", result, tt.expected)
 			}
 		})
 	}
 }
 
-func TestScanner_isPrivateRegistry(t *testing.T) {
-	log := logger.NewTest()
-	scanner := &Scanner{
-		logger: log,
+func TestScanner_filterPublicImages_NoMigrate(t *testing.T) {
+	scanner := NewScanner(nil, logger.NewTest(), &types.Config{
+		ImageDetection: types.ImageDetectionConfig{
+			NoMigrate: []string{"registry.k8s.io/*", "docker.io/bitnami/*"},
+		},
+	})
+
+	images := []*types.ImageInfo{
+		{Image: "nginx:1.25"},
+		{Image: "registry.k8s.io/ingress-nginx/controller:v1.9.4"},
+		{Image: "bitnami/redis:7.0"},
 	}
 
-	tests := []struct {
-		name            string
-		image           string
-		expectedPrivate bool
-	}{
-		{
-			name:            "AWS ECR private registry",
-			image:           "123456789012.dkr.ecr.us-east-1.amazonaws.com/myapp:latest",
-			expectedPrivate: true,
-		},
-		{
-			name:            "AWS ECR public registry should not be private",
-			image:           "public.ecr.aws/nginx/nginx:latest",
-			expectedPrivate: false,
-		},
-		{
-			name:            "Azure Container Registry private",
-			image:           "myregistry.azurecr.io/myapp:latest",
-			expectedPrivate: true,
-		},
-		{
-			name:            "Microsoft Container Registry should not be private",
-			image:           "mcr.microsoft.com/dotnet/core/runtime:3.1",
-			expectedPrivate: false,
-		},
-		{
-			name:            "Google Container Registry private",
-			image:           "gcr.io/my-project/myapp:latest",
-			expectedPrivate: true,
-		},
-		{
-			name:            "Google Artifact Registry private",
-			image:           "us-central1-docker.pkg.dev/my-project/my-repo/myapp:latest",
-			expectedPrivate: true,
-		},
-		{
-			name:            "Kubernetes public registry should not be private",
-			image:           "registry.k8s.io/pause:3.5",
-			expectedPrivate: false,
-		},
-		{
-			name:            "GitHub Container Registry private",
-			image:           "ghcr.io/owner/repo:latest",
-			expectedPrivate: true,
-		},
-		{
-			name:            "GitHub Container Registry with insufficient parts",
-			image:           "ghcr.io/repo",
-			expectedPrivate: false,
-		},
-		{
-			name:            "Custom domain registry",
-			image:           "registry.company.com/myapp:latest",
-			expectedPrivate: true,
-		},
-		{
-			name:            "Docker Hub should not be private",
-			image:           "docker.io/nginx:latest",
-			expectedPrivate: false,
-		},
-		{
-			name:            "Registry with index.docker.io should not be private",
-			image:           "index.docker.io/nginx:latest",
-			expectedPrivate: false,
-		},
-		{
-			name:            "Simple image name should not be private",
-			image:           "nginx:latest",
-			expectedPrivate: false,
-		},
+	result := scanner.filterPublicImages(images)
+	if len(result) != 3 {
+		t.Fatalf("filterPublicImages() returned %d images, expected no_migrate images to still be reported", len(result))
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := scanner.isPrivateRegistry(tt.image)
-			if result != tt.expectedPrivate {
-				t.Errorf("isPrivateRegistry(%q) = %v, expected %v", tt.image, result, tt.expectedPrivate)
-			}
-		})
+	expected := map[string]bool{
+		"nginx:1.25": false,
+		"registry.k8s.io/ingress-nginx/controller:v1.9.4": true,
+		"bitnami/redis:7.0": true,
+	}
+	for _, image := range result {
+		if image.NoMigrate != expected[image.Image] {
+			t.Errorf("image %s NoMigrate = %v, expected %v", image.Image, image.NoMigrate, expected[image.Image])
+		}
 	}
 }
 
-func TestScanner_filterPublicImages(t *testing.T) {
-	log := logger.NewTest()
-	config := &types.Config{
-		ImageDetection: types.ImageDetectionConfig{
-			CustomPrivateRegistries: []string{"private.company.com"},
-		},
+func TestFilterRegistryImages(t *testing.T) {
+	images := []*types.ImageInfo{
+		{Image: "old-harbor.company.com/legacy/app:1.0"},
+		{Image: "nginx:1.25", IsPublic: true},
+		{Image: "new-harbor.company.com/legacy/app:1.0"},
+		{Image: "Old-Harbor.company.com/legacy/worker:2.0"},
 	}
 
-	scanner := &Scanner{
-		logger: log,
-		config: config,
+	var result []string
+	for _, image := range filterRegistryImages(images, "https://old-harbor.company.com/") {
+		result = append(result, image.Image)
+		if image.IsPublic {
+			t.Errorf("image %s should not be classified as public", image.Image)
+		}
 	}
 
-	inputImages := []*types.ImageInfo{
-		{
-			Image:        "nginx:latest",
-			ResourceType: "Deployment",
-			ResourceName: "web",
-			Namespace:    "default",
-		},
-		{
-			Image:        "private.company.com/myapp:latest",
-			ResourceType: "Deployment",
-			ResourceName: "app",
-			Namespace:    "default",
-		},
-		{
-			Image:        "docker.io/redis:6",
-			ResourceType: "StatefulSet",
-			ResourceName: "cache",
-			Namespace:    "default",
+	expected := []string{"old-harbor.company.com/legacy/app:1.0", "Old-Harbor.company.com/legacy/worker:2.0"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Errorf("filterRegistryImages() = %v, expected %v", result, expected)
+	}
+}
+
+func TestEphemeralContainerImages(t *testing.T) {
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-7d9f8"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "web", Image: "nginx:1.25"}},
+				EphemeralContainers: []corev1.EphemeralContainer{
+					{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox:1.36"}},
+				},
+			},
 		},
 		{
-			Image:        "localhost:5000/local:latest",
-			ResourceType: "Job",
-			ResourceName: "migration",
-			Namespace:    "default",
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-5c4b2"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "worker", Image: "redis:7.0"}},
+			},
 		},
 	}
 
-	result := scanner.filterPublicImages(inputImages)
+	images := ephemeralContainerImages("default", pods)
 
-	expectedPublicCount := 2
-	if len(result) != expectedPublicCount {
-		t.Errorf("filterPublicImages() returned %d images, expected %d", len(result), expectedPublicCount)
+	if len(images) != 1 {
+		t.Fatalf("ephemeralContainerImages() returned %d images, expected 1", len(images))
 	}
 
-	publicImages := make(map[string]bool)
-	for _, img := range result {
-		publicImages[img.Image] = true
-		if !img.IsPublic {
-			t.Errorf("Image %s should be marked as public but IsPublic=false", img.Image)
-		}
+	image := images[0]
+	if image.Image != "busybox:1.36" || image.Container != "debugger" || image.ResourceType != "Pod" ||
+		image.ResourceName != "web-7d9f8" || image.Namespace != "default" {
+		t.Errorf("unexpected ephemeral image %+v", image)
+	}
+	if !image.IsEphemeralContainer || image.IsInitContainer {
+		t.Errorf("expected only IsEphemeralContainer to be set, got %+v", image)
 	}
 
-	expectedPublicImages := []string{"nginx:latest", "docker.io/redis:6"}
-	for _, expected := range expectedPublicImages {
-		if !publicImages[expected] {
-			t.Errorf("Expected public image %s not found in results", expected)
-		}
+	scanner := NewScanner(nil, logger.NewTest(), &types.Config{})
+	if public := scanner.filterPublicImages(images); len(public) != 1 {
+		t.Errorf("expected ephemeral image to be reported as public, got %d images", len(public))
 	}
+}
 
-	unexpectedPublicImages := []string{"private.company.com/myapp:latest", "localhost:5000/local:latest"}
-	for _, unexpected := range unexpectedPublicImages {
-		if publicImages[unexpected] {
-			t.Errorf("Unexpected public image %s found in results", unexpected)
-		}
+func TestScanner_collectNamespaceImages_PodListForbidden(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.25"}},
+				},
+			},
+		},
+	})
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("pods is forbidden"))
+	})
+
+	config := &types.Config{Kubernetes: types.KubernetesConfig{ScanPods: true}}
+	scanner := NewScanner(&Client{clientset: clientset, config: config}, logger.NewTest(), config)
+
+	images, err := scanner.collectNamespaceImages(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("collectNamespaceImages() error = %v, expected the workload images despite the pod list error", err)
+	}
+	if len(images) != 1 || images[0].Image != "nginx:1.25" || images[0].ResourceType != "Deployment" {
+		t.Errorf("collectNamespaceImages() = %+v, expected the Deployment image only", images)
 	}
 }
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -41,8 +42,12 @@ func New() *Logger {
 }
 
 func NewWithConfig(cfg *types.Config) *Logger {
+	return NewWithWriter(cfg, os.Stdout)
+}
+
+func NewWithWriter(cfg *types.Config, out io.Writer) *Logger {
 	output := zerolog.ConsoleWriter{
-		Out:        os.Stdout,
+		Out:        out,
 		TimeFormat: time.RFC3339,
 		FormatLevel: func(i interface{}) string {
 			return strings.ToUpper(fmt.Sprintf("%-6s", i))
@@ -0,0 +1,113 @@
+package migration
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+)
+
+var (
+	tagVersionPattern = regexp.MustCompile(`^(v?)(\d+)(?:\.(\d+))?(?:\.(\d+))?(.*)$`)
+	validTagPattern   = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+)
+
+func (e *Engine) copyImageWithTags(ctx context.Context, reg registry.Registry, image *types.ImageInfo, targetImage, registryName string) ([]string, error) {
+	if err := e.copyImage(ctx, reg, image, targetImage, registryName); err != nil {
+		return nil, err
+	}
+
+	pushedTags := []string{primaryTag(targetImage)}
+	for _, tag := range e.additionalTags(image.Image) {
+		additionalTarget := utils.WithTag(targetImage, tag)
+		if err := e.copyImage(ctx, reg, image, additionalTarget, registryName); err != nil {
+			e.logger.Warn("additional_tag_push_failed").
+				Str("source", image.Image).
+				Str("target", additionalTarget).
+				Str("registry", registryName).
+				Err(err).
+				Send()
+			continue
+		}
+		pushedTags = append(pushedTags, tag)
+	}
+
+	return pushedTags, nil
+}
+
+func (e *Engine) plannedTags(image *types.ImageInfo, targetImage string) []string {
+	return append([]string{primaryTag(targetImage)}, e.additionalTags(image.Image)...)
+}
+
+func primaryTag(imageName string) string {
+	parsed := utils.ParseImageName(imageName)
+	if parsed.Digest != "" && !parsed.HasExplicitTag() {
+		return parsed.Digest
+	}
+	return parsed.Tag
+}
+
+func (e *Engine) additionalTags(imageName string) []string {
+	if len(e.config.Settings.AdditionalTags) == 0 {
+		return nil
+	}
+
+	parsed := utils.ParseImageName(imageName)
+	if parsed.Digest != "" && !parsed.HasExplicitTag() {
+		e.logger.Debug("additional_tags_skipped_digest").
+			Str("image", imageName).
+			Send()
+		return nil
+	}
+
+	var tags []string
+	seen := map[string]bool{parsed.Tag: true}
+
+	for _, template := range e.config.Settings.AdditionalTags {
+		tag, ok := renderTagTemplate(template, parsed.Tag)
+		if !ok || !validTagPattern.MatchString(tag) {
+			e.logger.Debug("additional_tag_not_applicable").
+				Str("image", imageName).
+				Str("template", template).
+				Str("tag", tag).
+				Send()
+			continue
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+func renderTagTemplate(template, tag string) (string, bool) {
+	values := map[string]string{"{tag}": tag}
+
+	if matches := tagVersionPattern.FindStringSubmatch(tag); matches != nil {
+		values["{v}"] = matches[1]
+		values["{major}"] = matches[2]
+		values["{minor}"] = matches[3]
+		values["{patch}"] = matches[4]
+		values["{suffix}"] = matches[5]
+	}
+
+	rendered := template
+	for _, placeholder := range []string{"{tag}", "{v}", "{major}", "{minor}", "{patch}", "{suffix}"} {
+		if !strings.Contains(rendered, placeholder) {
+			continue
+		}
+		value, exists := values[placeholder]
+		if !exists || (value == "" && placeholder != "{v}" && placeholder != "{suffix}") {
+			return "", false
+		}
+		rendered = strings.ReplaceAll(rendered, placeholder, value)
+	}
+
+	return rendered, rendered != ""
+}
@@ -0,0 +1,86 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_copyImageWithTags(t *testing.T) {
+	tests := []struct {
+		name           string
+		image          string
+		additionalTags []string
+		targetImage    string
+		expectedCopies []string
+		expectedTags   []string
+	}{
+		{
+			name:           "no additional tags",
+			image:          "nginx:1.21.6",
+			targetImage:    "registry.example.com/library/nginx:1.21.6",
+			expectedCopies: []string{"registry.example.com/library/nginx:1.21.6"},
+			expectedTags:   []string{"1.21.6"},
+		},
+		{
+			name:           "primary plus each additional tag",
+			image:          "nginx:1.21.6",
+			additionalTags: []string{"{major}.{minor}", "{major}", "{tag}-mirror"},
+			targetImage:    "registry.example.com/library/nginx:1.21.6",
+			expectedCopies: []string{
+				"registry.example.com/library/nginx:1.21.6",
+				"registry.example.com/library/nginx:1.21",
+				"registry.example.com/library/nginx:1",
+				"registry.example.com/library/nginx:1.21.6-mirror",
+			},
+			expectedTags: []string{"1.21.6", "1.21", "1", "1.21.6-mirror"},
+		},
+		{
+			name:           "v prefix and suffix are preserved",
+			image:          "quay.io/prometheus/prometheus:v2.45.0-rc.1",
+			additionalTags: []string{"{v}{major}.{minor}{suffix}", "{v}{major}"},
+			targetImage:    "registry.example.com/prometheus/prometheus:v2.45.0-rc.1",
+			expectedCopies: []string{
+				"registry.example.com/prometheus/prometheus:v2.45.0-rc.1",
+				"registry.example.com/prometheus/prometheus:v2.45-rc.1",
+				"registry.example.com/prometheus/prometheus:v2",
+			},
+			expectedTags: []string{"v2.45.0-rc.1", "v2.45-rc.1", "v2"},
+		},
+		{
+			name:           "non semver tags skip version templates and duplicates",
+			image:          "redis:latest",
+			additionalTags: []string{"{major}", "{tag}", "stable"},
+			targetImage:    "registry.example.com/library/redis:latest",
+			expectedCopies: []string{
+				"registry.example.com/library/redis:latest",
+				"registry.example.com/library/redis:stable",
+			},
+			expectedTags: []string{"latest", "stable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{
+				logger: logger.NewTest(),
+				config: &types.Config{Settings: types.SettingsConfig{AdditionalTags: tt.additionalTags}},
+			}
+
+			mockReg := &MockRegistry{}
+			for _, target := range tt.expectedCopies {
+				mockReg.On("Copy", context.Background(), tt.image, target).Return(nil).Once()
+			}
+
+			tags, err := engine.copyImageWithTags(context.Background(), mockReg, &types.ImageInfo{Image: tt.image}, tt.targetImage, "docker-registry")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTags, tags)
+			mockReg.AssertExpectations(t)
+			mockReg.AssertNumberOfCalls(t, "Copy", len(tt.expectedCopies))
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package migration
 
 import (
+	"context"
+
+	"github.com/kevinfinalboss/privateer/internal/registry"
 	"github.com/kevinfinalboss/privateer/pkg/types"
 )
 
-func (e *Engine) dryRunMigration(images []*types.ImageInfo, targetRegistries []types.RegistryConfig) *types.MigrationSummary {
+func (e *Engine) dryRunMigration(ctx context.Context, images []*types.ImageInfo, targetRegistries []types.RegistryConfig) *types.MigrationSummary {
 	e.logger.Info("dry_run_migration_preserve_namespace").
 		Int("total_images", len(images)).
 		Int("target_registries", len(targetRegistries)).
@@ -23,18 +26,22 @@ func (e *Engine) dryRunMigration(images []*types.ImageInfo, targetRegistries []t
 		Results:      make([]*types.MigrationResult, 0, totalOperations),
 	}
 
+	if e.config.Settings.NamespaceScheduling.Enabled {
+		images = e.orderImagesByNamespace(images)
+	}
+
 	for _, image := range images {
 		if e.config.Settings.MultipleRegistries {
-			e.processDryRunForMultipleRegistries(image, targetRegistries, summary)
+			e.processDryRunForMultipleRegistries(ctx, image, targetRegistries, summary)
 		} else {
-			e.processDryRunForSingleRegistry(image, targetRegistries[0], summary)
+			e.processDryRunForSingleRegistry(ctx, image, e.selectRegistryForImage(image, targetRegistries), summary)
 		}
 	}
 
 	return summary
 }
 
-func (e *Engine) processDryRunForMultipleRegistries(image *types.ImageInfo, targetRegistries []types.RegistryConfig, summary *types.MigrationSummary) {
+func (e *Engine) processDryRunForMultipleRegistries(ctx context.Context, image *types.ImageInfo, targetRegistries []types.RegistryConfig, summary *types.MigrationSummary) {
 	for _, regConfig := range targetRegistries {
 		reg, err := e.registryManager.GetRegistry(regConfig.Name)
 		if err != nil {
@@ -45,6 +52,10 @@ func (e *Engine) processDryRunForMultipleRegistries(image *types.ImageInfo, targ
 			continue
 		}
 
+		if e.recordDryRunSinceSkip(ctx, reg, image, regConfig.Name, summary) {
+			continue
+		}
+
 		targetImage, err := e.generateTargetImageName(image, reg)
 		if err != nil {
 			e.logger.Error("target_image_generation_failed_dry_run").
@@ -66,11 +77,12 @@ func (e *Engine) processDryRunForMultipleRegistries(image *types.ImageInfo, targ
 			TargetImage: targetImage,
 			Registry:    regConfig.Name,
 			Success:     true,
+			Tags:        e.plannedTags(image, targetImage),
 		})
 	}
 }
 
-func (e *Engine) processDryRunForSingleRegistry(image *types.ImageInfo, registry types.RegistryConfig, summary *types.MigrationSummary) {
+func (e *Engine) processDryRunForSingleRegistry(ctx context.Context, image *types.ImageInfo, registry types.RegistryConfig, summary *types.MigrationSummary) {
 	reg, err := e.registryManager.GetRegistry(registry.Name)
 	if err != nil {
 		e.logger.Error("registry_not_found_dry_run").
@@ -80,6 +92,10 @@ func (e *Engine) processDryRunForSingleRegistry(image *types.ImageInfo, registry
 		return
 	}
 
+	if e.recordDryRunSinceSkip(ctx, reg, image, registry.Name, summary) {
+		return
+	}
+
 	targetImage, err := e.generateTargetImageName(image, reg)
 	if err != nil {
 		e.logger.Error("target_image_generation_failed_dry_run").
@@ -102,5 +118,31 @@ func (e *Engine) processDryRunForSingleRegistry(image *types.ImageInfo, registry
 		TargetImage: targetImage,
 		Registry:    registry.Name,
 		Success:     true,
+		Tags:        e.plannedTags(image, targetImage),
+	})
+}
+
+func (e *Engine) recordDryRunSinceSkip(ctx context.Context, reg registry.Registry, image *types.ImageInfo, registryName string, summary *types.MigrationSummary) bool {
+	reasonCode, reason := e.sinceSkipReason(ctx, reg, image)
+	if reasonCode == "" {
+		return false
+	}
+
+	e.logger.Info("dry_run_would_skip_since").
+		Str("image", image.Image).
+		Str("registry", registryName).
+		Str("reason_code", reasonCode).
+		Str("reason", reason).
+		Send()
+
+	summary.SuccessCount--
+	summary.SkippedCount++
+	summary.Results = append(summary.Results, &types.MigrationResult{
+		Image:      image,
+		Registry:   registryName,
+		Skipped:    true,
+		ReasonCode: reasonCode,
+		Reason:     reason,
 	})
+	return true
 }
@@ -3,6 +3,8 @@ package migration
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/internal/registry"
@@ -16,8 +18,15 @@ type Engine struct {
 	logger          *logger.Logger
 	config          *types.Config
 	concurrency     int
-	discordWebhook  *webhook.DiscordWebhook
+	discordWebhook  *webhook.DiscordNotifier
 	htmlReporter    *reporter.HTMLReporter
+	deferReporting  bool
+	since           *sinceThreshold
+	privateMove     *privateMove
+
+	registryCursor      atomic.Uint64
+	registryMutex       sync.Mutex
+	registryAssignments map[string]int
 }
 
 func NewEngine(registryManager *registry.Manager, logger *logger.Logger, cfg *types.Config) *Engine {
@@ -34,22 +43,35 @@ func NewEngine(registryManager *registry.Manager, logger *logger.Logger, cfg *ty
 		htmlReporter:    reporter.NewHTMLReporter(logger),
 	}
 
-	if cfg.Webhooks.Discord.Enabled && cfg.Webhooks.Discord.URL != "" {
-		engine.discordWebhook = webhook.NewDiscordWebhook(cfg.Webhooks.Discord, logger)
+	if notifier := webhook.NewDiscordNotifier(cfg.Webhooks, logger); notifier != nil {
+		engine.discordWebhook = notifier
 		engine.logger.Info("discord_webhook_enabled").
 			Str("url", maskWebhookURL(cfg.Webhooks.Discord.URL)).
+			Int("targets", notifier.TargetCount()).
 			Send()
 	}
 
 	return engine
 }
 
+func (e *Engine) DeferReporting() *Engine {
+	e.deferReporting = true
+	return e
+}
+
 func (e *Engine) MigrateImages(ctx context.Context, images []*types.ImageInfo) (*types.MigrationSummary, error) {
+	images = e.excludeNoMigrateImages(images)
 	if len(images) == 0 {
 		e.logger.Info("no_images_to_migrate").Send()
 		return &types.MigrationSummary{}, nil
 	}
 
+	since, err := parseSinceThreshold(e.config.Settings.Since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	e.since = since
+
 	e.logInputAnalysis(images)
 
 	targetRegistries := e.selectTargetRegistries()
@@ -57,6 +79,10 @@ func (e *Engine) MigrateImages(ctx context.Context, images []*types.ImageInfo) (
 		return e.handleNoRegistriesError(ctx)
 	}
 
+	if err := e.preparePrivateMove(ctx); err != nil {
+		return nil, err
+	}
+
 	e.logMigrationStart(images, targetRegistries)
 
 	if e.discordWebhook != nil {
@@ -70,6 +96,8 @@ func (e *Engine) MigrateImages(ctx context.Context, images []*types.ImageInfo) (
 		return e.executeDryRun(ctx, images, targetRegistries)
 	}
 
+	e.inspectSourceImages(ctx, images, targetRegistries[0].Name)
+
 	return e.executeRealMigration(ctx, images, targetRegistries)
 }
 
@@ -79,10 +107,27 @@ func (e *Engine) executeRealMigration(ctx context.Context, images []*types.Image
 		Results:     make([]*types.MigrationResult, 0),
 	}
 
-	semaphore := make(chan struct{}, e.concurrency)
-	var wg sync.WaitGroup
 	var mu sync.Mutex
 
+	if e.config.Settings.NamespaceScheduling.Enabled {
+		e.migrateByNamespace(ctx, images, targetRegistries, &mu, summary)
+	} else {
+		semaphore := make(chan struct{}, e.concurrency)
+		var wg sync.WaitGroup
+		e.scheduleImages(ctx, images, targetRegistries, semaphore, &wg, &mu, summary)
+		wg.Wait()
+	}
+
+	e.logMigrationComplete(summary)
+	if !e.deferReporting {
+		e.sendDiscordComplete(ctx, summary, false)
+		e.generateReport(summary, false)
+	}
+
+	return summary, nil
+}
+
+func (e *Engine) scheduleImages(ctx context.Context, images []*types.ImageInfo, targetRegistries []types.RegistryConfig, semaphore chan struct{}, wg *sync.WaitGroup, mu *sync.Mutex, summary *types.MigrationSummary) {
 	for _, image := range images {
 		e.logger.Debug("processing_image_for_migration").
 			Str("image", image.Image).
@@ -91,39 +136,19 @@ func (e *Engine) executeRealMigration(ctx context.Context, images []*types.Image
 			Send()
 
 		if e.config.Settings.MultipleRegistries {
-			e.processImageForMultipleRegistries(ctx, image, targetRegistries, semaphore, &wg, &mu, summary)
+			e.processImageForMultipleRegistries(ctx, image, targetRegistries, semaphore, wg, mu, summary)
 		} else {
-			e.processImageForSingleRegistry(ctx, image, targetRegistries[0], semaphore, &wg, &mu, summary)
+			e.processImageForSingleRegistry(ctx, image, e.selectRegistryForImage(image, targetRegistries), semaphore, wg, mu, summary)
 		}
 	}
-
-	wg.Wait()
-
-	e.logMigrationComplete(summary)
-	e.sendDiscordComplete(ctx, summary)
-	e.generateReport(summary, false)
-
-	return summary, nil
 }
 
 func (e *Engine) executeDryRun(ctx context.Context, images []*types.ImageInfo, targetRegistries []types.RegistryConfig) (*types.MigrationSummary, error) {
-	summary := e.dryRunMigration(images, targetRegistries)
-
-	if e.discordWebhook != nil {
-		err := e.discordWebhook.SendMigrationComplete(ctx, summary, true)
-		if err != nil {
-			e.logger.Warn("discord_webhook_failed").Err(err).Send()
-		}
-	}
+	summary := e.dryRunMigration(ctx, images, targetRegistries)
 
-	reportPath, err := e.htmlReporter.GenerateReport(summary, e.config, true)
-	if err != nil {
-		e.logger.Warn("html_report_failed").Err(err).Send()
-	} else {
-		e.logger.Info("html_report_ready").
-			Str("path", reportPath).
-			Str("message", "Relatório HTML de simulação gerado").
-			Send()
+	if !e.deferReporting {
+		e.sendDiscordComplete(ctx, summary, true)
+		e.generateReport(summary, true)
 	}
 
 	return summary, nil
@@ -0,0 +1,57 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_MigrateImages_NoMigrate(t *testing.T) {
+	engine := &Engine{logger: logger.NewTest(), config: &types.Config{}}
+
+	images := []*types.ImageInfo{
+		{Image: "registry.k8s.io/ingress-nginx/controller:v1.9.4", IsPublic: true, NoMigrate: true},
+		{Image: "registry.k8s.io/coredns/coredns:v1.11.1", IsPublic: true, NoMigrate: true},
+	}
+
+	summary, err := engine.MigrateImages(context.Background(), images)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.TotalImages)
+	assert.Empty(t, summary.Results)
+}
+
+func TestEngine_excludeNoMigrateImages(t *testing.T) {
+	engine := &Engine{logger: logger.NewTest(), config: &types.Config{}}
+
+	images := []*types.ImageInfo{
+		{Image: "nginx:1.25", IsPublic: true},
+		{Image: "registry.k8s.io/pause:3.9", IsPublic: true, NoMigrate: true},
+		{Image: "bitnami/redis:7.0", IsPublic: true},
+	}
+
+	var result []string
+	for _, image := range engine.excludeNoMigrateImages(images) {
+		result = append(result, image.Image)
+	}
+
+	assert.Equal(t, []string{"nginx:1.25", "bitnami/redis:7.0"}, result)
+}
+
+func TestEngine_migrateImageToRegistry_ContextCancelled(t *testing.T) {
+	engine := &Engine{logger: logger.NewTest(), config: &types.Config{}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	image := &types.ImageInfo{Image: "nginx:1.25", IsPublic: true}
+	result := engine.migrateImageToRegistry(ctx, image, "harbor-prod")
+
+	assert.False(t, result.Success)
+	assert.True(t, errors.Is(result.Error, context.Canceled))
+	assert.Equal(t, "harbor-prod", result.Registry)
+}
@@ -47,11 +47,29 @@ func (e *Engine) logInputAnalysis(images []*types.ImageInfo) {
 			Str("resource_type", img.ResourceType).
 			Bool("is_public", img.IsPublic).
 			Bool("is_init_container", img.IsInitContainer).
+			Bool("is_ephemeral_container", img.IsEphemeralContainer).
 			Str("container", img.Container).
 			Send()
 	}
 }
 
+func (e *Engine) excludeNoMigrateImages(images []*types.ImageInfo) []*types.ImageInfo {
+	migratable := make([]*types.ImageInfo, 0, len(images))
+	for _, image := range images {
+		if image.NoMigrate {
+			e.logger.Info("image_no_migrate_skipped").
+				Str("image", image.Image).
+				Str("reason_code", types.SkipReasonNoMigrate).
+				Str("namespace", image.Namespace).
+				Str("resource_name", image.ResourceName).
+				Send()
+			continue
+		}
+		migratable = append(migratable, image)
+	}
+	return migratable
+}
+
 func (e *Engine) logMigrationStart(images []*types.ImageInfo, targetRegistries []types.RegistryConfig) {
 	e.logger.Info("migration_started_preserve_namespace").
 		Int("total_images", len(images)).
@@ -83,9 +101,9 @@ func (e *Engine) handleNoRegistriesError(ctx context.Context) (*types.MigrationS
 	return nil, err
 }
 
-func (e *Engine) sendDiscordComplete(ctx context.Context, summary *types.MigrationSummary) {
+func (e *Engine) sendDiscordComplete(ctx context.Context, summary *types.MigrationSummary, isDryRun bool) {
 	if e.discordWebhook != nil {
-		err := e.discordWebhook.SendMigrationComplete(ctx, summary, false)
+		err := e.discordWebhook.SendMigrationComplete(ctx, summary, isDryRun)
 		if err != nil {
 			e.logger.Warn("discord_webhook_failed").Err(err).Send()
 		}
@@ -122,3 +140,27 @@ func maskWebhookURL(url string) string {
 	}
 	return url[:20] + "***"
 }
+
+func (e *Engine) ReportCombined(ctx context.Context, summary *types.MigrationSummary, gitopsSummary *types.GitOpsSummary) *types.MigrationSummary {
+	if summary == nil {
+		summary = &types.MigrationSummary{}
+	}
+	summary.GitOps = gitopsSummary
+
+	isDryRun := e.config.Settings.DryRun
+	e.sendDiscordComplete(ctx, summary, isDryRun)
+
+	reportPath, jsonPath, err := e.htmlReporter.GenerateCombinedReport(summary, e.config, isDryRun)
+	if err != nil {
+		e.logger.Warn("combined_report_failed").Err(err).Send()
+		return summary
+	}
+
+	e.logger.Info("combined_report_ready").
+		Str("path", reportPath).
+		Str("json_path", jsonPath).
+		Str("message", "Relatório consolidado da migração completa gerado").
+		Send()
+
+	return summary
+}
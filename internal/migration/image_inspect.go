@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func (e *Engine) inspectSourceImages(ctx context.Context, images []*types.ImageInfo, registryName string) {
+	reg, err := e.registryManager.GetRegistry(registryName)
+	if err != nil {
+		return
+	}
+
+	inspector, ok := reg.(registry.ImageInspector)
+	if !ok {
+		return
+	}
+
+	byName := make(map[string][]*types.ImageInfo)
+	for _, image := range images {
+		if image.Digest == "" {
+			byName[image.Image] = append(byName[image.Image], image)
+		}
+	}
+
+	semaphore := make(chan struct{}, e.concurrency)
+	var wg sync.WaitGroup
+
+	for _, group := range byName {
+		wg.Add(1)
+		go func(group []*types.ImageInfo) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			e.inspectSourceImage(ctx, inspector, group)
+		}(group)
+	}
+
+	wg.Wait()
+}
+
+func (e *Engine) inspectSourceImage(ctx context.Context, inspector registry.ImageInspector, images []*types.ImageInfo) {
+	digest, size, err := inspector.InspectImage(ctx, images[0].Image)
+	if err != nil {
+		e.logger.Debug("source_image_inspect_failed").
+			Str("image", images[0].Image).
+			Err(err).
+			Send()
+		return
+	}
+
+	for _, image := range images {
+		image.Digest = digest
+		image.SizeBytes = size
+	}
+
+	e.logger.Debug("source_image_inspected").
+		Str("image", images[0].Image).
+		Str("digest", digest).
+		Int64("size_bytes", size).
+		Send()
+}
@@ -0,0 +1,63 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubImageInspector struct {
+	digest string
+	size   int64
+	err    error
+	calls  int
+}
+
+func (s *stubImageInspector) InspectImage(ctx context.Context, imageName string) (string, int64, error) {
+	s.calls++
+	return s.digest, s.size, s.err
+}
+
+func TestEngine_inspectSourceImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		inspector      *stubImageInspector
+		expectedDigest string
+		expectedSize   int64
+	}{
+		{
+			name:           "fills digest and size on every image of the group",
+			inspector:      &stubImageInspector{digest: "sha256:abc123", size: 52428800},
+			expectedDigest: "sha256:abc123",
+			expectedSize:   52428800,
+		},
+		{
+			name:      "leaves fields empty when inspection fails",
+			inspector: &stubImageInspector{err: errors.New("manifest unknown")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.NewTest()
+			engine := NewEngine(registry.NewManager(log), log, &types.Config{})
+			images := []*types.ImageInfo{
+				{Image: "nginx:1.25", Namespace: "default"},
+				{Image: "nginx:1.25", Namespace: "staging"},
+			}
+
+			engine.inspectSourceImage(context.Background(), tt.inspector, images)
+
+			assert.Equal(t, 1, tt.inspector.calls)
+			for _, image := range images {
+				assert.Equal(t, tt.expectedDigest, image.Digest)
+				assert.Equal(t, tt.expectedSize, image.SizeBytes)
+			}
+		})
+	}
+}
@@ -16,6 +16,15 @@ func (e *Engine) migrateImageToRegistry(ctx context.Context, image *types.ImageI
 		Str("resource", image.ResourceName).
 		Send()
 
+	if err := ctx.Err(); err != nil {
+		return &types.MigrationResult{
+			Image:    image,
+			Registry: registryName,
+			Success:  false,
+			Error:    fmt.Errorf("migração cancelada: %w", err),
+		}
+	}
+
 	reg, err := e.registryManager.GetRegistry(registryName)
 	if err != nil {
 		e.logger.Error("registry_not_found").
@@ -37,6 +46,22 @@ func (e *Engine) migrateImageToRegistry(ctx context.Context, image *types.ImageI
 		Str("image", image.Image).
 		Send()
 
+	if reasonCode, reason := e.sinceSkipReason(ctx, reg, image); reasonCode != "" {
+		e.logger.Info("image_skipped_since").
+			Str("image", image.Image).
+			Str("registry", registryName).
+			Str("reason_code", reasonCode).
+			Str("reason", reason).
+			Send()
+		return &types.MigrationResult{
+			Image:      image,
+			Registry:   registryName,
+			Skipped:    true,
+			ReasonCode: reasonCode,
+			Reason:     reason,
+		}
+	}
+
 	targetImage, err := e.generateTargetImageName(image, reg)
 	if err != nil {
 		e.logger.Error("target_image_generation_failed").
@@ -65,6 +90,7 @@ func (e *Engine) migrateImageToRegistry(ctx context.Context, image *types.ImageI
 			Registry:    registryName,
 			Success:     false,
 			Skipped:     true,
+			ReasonCode:  types.SkipReasonAlreadyExists,
 			Reason:      "Imagem já existe no registry",
 			Error:       err,
 		}
@@ -79,7 +105,8 @@ func (e *Engine) migrateImageToRegistry(ctx context.Context, image *types.ImageI
 		}
 	}
 
-	if err := e.copyImage(ctx, reg, image, targetImage, registryName); err != nil {
+	pushedTags, err := e.copyImageWithTags(ctx, reg, image, targetImage, registryName)
+	if err != nil {
 		return &types.MigrationResult{
 			Image:       image,
 			TargetImage: targetImage,
@@ -96,6 +123,7 @@ func (e *Engine) migrateImageToRegistry(ctx context.Context, image *types.ImageI
 		Str("target", targetImage).
 		Str("registry", registryName).
 		Str("namespace", image.Namespace).
+		Strs("tags", pushedTags).
 		Send()
 
 	return &types.MigrationResult{
@@ -103,6 +131,7 @@ func (e *Engine) migrateImageToRegistry(ctx context.Context, image *types.ImageI
 		TargetImage: targetImage,
 		Registry:    registryName,
 		Success:     true,
+		Tags:        pushedTags,
 	}
 }
 
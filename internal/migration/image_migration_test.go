@@ -9,6 +9,7 @@ import (
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/internal/registry"
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -22,7 +23,7 @@ type RegistryManagerInterface interface {
 	CheckImageExists(ctx context.Context, imageName string) (map[string]bool, error)
 	ListRegistries() []string
 	GetEnabledRegistries() []registry.Registry
-	ValidateImagesBatch(ctx context.Context, images []*types.ImageInfo, config *types.Config) (map[string]string, error)
+	ValidateImagesBatch(ctx context.Context, images []*types.ImageInfo, config *types.Config) (map[string]string, map[string]error, error)
 	FindImageInRegistries(ctx context.Context, publicImage *types.ImageInfo, config *types.Config) (string, string, error)
 	GetRegistryCount() int
 }
@@ -74,9 +75,9 @@ func (m *MockRegistryManager) GetEnabledRegistries() []registry.Registry {
 	return args.Get(0).([]registry.Registry)
 }
 
-func (m *MockRegistryManager) ValidateImagesBatch(ctx context.Context, images []*types.ImageInfo, config *types.Config) (map[string]string, error) {
+func (m *MockRegistryManager) ValidateImagesBatch(ctx context.Context, images []*types.ImageInfo, config *types.Config) (map[string]string, map[string]error, error) {
 	args := m.Called(ctx, images, config)
-	return args.Get(0).(map[string]string), args.Error(1)
+	return args.Get(0).(map[string]string), args.Get(1).(map[string]error), args.Error(2)
 }
 
 func (m *MockRegistryManager) FindImageInRegistries(ctx context.Context, publicImage *types.ImageInfo, config *types.Config) (string, string, error) {
@@ -167,6 +168,7 @@ func (e *TestEngine) migrateImageToRegistry(ctx context.Context, image *types.Im
 			Registry:    registryName,
 			Success:     false,
 			Skipped:     true,
+			ReasonCode:  types.SkipReasonAlreadyExists,
 			Reason:      "Imagem já existe no registry",
 			Error:       err,
 		}
@@ -202,30 +204,26 @@ func (e *TestEngine) migrateImageToRegistry(ctx context.Context, image *types.Im
 }
 
 func (e *TestEngine) generateTargetImageName(image *types.ImageInfo, reg registry.Registry) (string, error) {
-	parsed := types.ParseImageName(image.Image)
+	parsed := utils.ParseImageName(image.Image)
 	targetRepository := parsed.FullRepository
-	targetTag := parsed.Tag
-
-	if parsed.Digest != "" {
-		targetTag = fmt.Sprintf("%s@%s", targetTag, parsed.Digest)
-	}
+	targetReference := parsed.Reference()
 
 	switch reg.GetType() {
 	case "docker":
 		registryURL := e.getRegistryURL(reg.GetName())
-		return fmt.Sprintf("%s/%s:%s", registryURL, targetRepository, targetTag), nil
+		return fmt.Sprintf("%s/%s%s", registryURL, targetRepository, targetReference), nil
 	case "harbor":
 		registryURL := e.getRegistryURL(reg.GetName())
 		project := e.getHarborProject(reg.GetName())
-		return fmt.Sprintf("%s/%s/%s:%s", registryURL, project, targetRepository, targetTag), nil
+		return fmt.Sprintf("%s/%s/%s%s", registryURL, project, targetRepository, targetReference), nil
 	case "ecr":
 		ecrURL := e.getECRURL(reg.GetName())
-		return fmt.Sprintf("%s/%s:%s", ecrURL, targetRepository, targetTag), nil
+		return fmt.Sprintf("%s/%s%s", ecrURL, targetRepository, targetReference), nil
 	case "ghcr":
 		organization := e.getGHCROrganization(reg.GetName())
-		return fmt.Sprintf("ghcr.io/%s/%s:%s", organization, targetRepository, targetTag), nil
+		return fmt.Sprintf("ghcr.io/%s/%s%s", organization, targetRepository, targetReference), nil
 	default:
-		return fmt.Sprintf("%s/%s:%s", reg.GetName(), targetRepository, targetTag), nil
+		return fmt.Sprintf("%s/%s%s", reg.GetName(), targetRepository, targetReference), nil
 	}
 }
 
@@ -399,6 +397,7 @@ func TestEngine_migrateImageToRegistry_ImageDuplicationDetected(t *testing.T) {
 	assert.False(t, result.Success)
 	assert.True(t, result.Skipped)
 	assert.NotNil(t, result.Error)
+	assert.Equal(t, types.SkipReasonAlreadyExists, result.ReasonCode)
 	assert.Equal(t, "Imagem já existe no registry", result.Reason)
 	assert.Equal(t, image, result.Image)
 	assert.Equal(t, "test-registry", result.Registry)
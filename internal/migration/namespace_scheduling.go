@@ -0,0 +1,85 @@
+package migration
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+type namespaceGroup struct {
+	namespace string
+	images    []*types.ImageInfo
+}
+
+func (e *Engine) groupImagesByNamespace(images []*types.ImageInfo) []namespaceGroup {
+	priority := make(map[string]int)
+	for i, namespace := range e.config.Settings.NamespaceScheduling.Order {
+		if _, exists := priority[namespace]; !exists {
+			priority[namespace] = i
+		}
+	}
+
+	index := make(map[string]int)
+	var groups []namespaceGroup
+	for _, image := range images {
+		if i, exists := index[image.Namespace]; exists {
+			groups[i].images = append(groups[i].images, image)
+			continue
+		}
+		index[image.Namespace] = len(groups)
+		groups = append(groups, namespaceGroup{namespace: image.Namespace, images: []*types.ImageInfo{image}})
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		pi, iOrdered := priority[groups[i].namespace]
+		pj, jOrdered := priority[groups[j].namespace]
+		switch {
+		case iOrdered && jOrdered:
+			return pi < pj
+		case iOrdered != jOrdered:
+			return iOrdered
+		default:
+			return groups[i].namespace < groups[j].namespace
+		}
+	})
+
+	return groups
+}
+
+func (e *Engine) orderImagesByNamespace(images []*types.ImageInfo) []*types.ImageInfo {
+	ordered := make([]*types.ImageInfo, 0, len(images))
+	for _, group := range e.groupImagesByNamespace(images) {
+		ordered = append(ordered, group.images...)
+	}
+	return ordered
+}
+
+func (e *Engine) namespaceConcurrency() int {
+	if concurrency := e.config.Settings.NamespaceScheduling.Concurrency; concurrency > 0 {
+		return concurrency
+	}
+	return e.concurrency
+}
+
+func (e *Engine) migrateByNamespace(ctx context.Context, images []*types.ImageInfo, targetRegistries []types.RegistryConfig, mu *sync.Mutex, summary *types.MigrationSummary) {
+	concurrency := e.namespaceConcurrency()
+
+	for _, group := range e.groupImagesByNamespace(images) {
+		e.logger.Info("namespace_migration_started").
+			Str("namespace", group.namespace).
+			Int("images", len(group.images)).
+			Int("concurrency", concurrency).
+			Send()
+
+		semaphore := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		e.scheduleImages(ctx, group.images, targetRegistries, semaphore, &wg, mu, summary)
+		wg.Wait()
+
+		e.logger.Info("namespace_migration_completed").
+			Str("namespace", group.namespace).
+			Send()
+	}
+}
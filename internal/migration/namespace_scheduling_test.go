@@ -0,0 +1,77 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_groupImagesByNamespace(t *testing.T) {
+	images := []*types.ImageInfo{
+		{Image: "nginx:1.25", Namespace: "web"},
+		{Image: "redis:7", Namespace: "cache"},
+		{Image: "coredns:1.11", Namespace: "kube-system"},
+		{Image: "nginx-exporter:0.11", Namespace: "web"},
+		{Image: "postgres:16", Namespace: "database"},
+		{Image: "kube-proxy:1.29", Namespace: "kube-system"},
+	}
+
+	tests := []struct {
+		name               string
+		order              []string
+		expectedNamespaces []string
+		expectedImages     []string
+	}{
+		{
+			name:               "alphabetical without configured order",
+			expectedNamespaces: []string{"cache", "database", "kube-system", "web"},
+			expectedImages:     []string{"redis:7", "postgres:16", "coredns:1.11", "kube-proxy:1.29", "nginx:1.25", "nginx-exporter:0.11"},
+		},
+		{
+			name:               "configured order first, remaining alphabetical",
+			order:              []string{"kube-system", "web", "missing"},
+			expectedNamespaces: []string{"kube-system", "web", "cache", "database"},
+			expectedImages:     []string{"coredns:1.11", "kube-proxy:1.29", "nginx:1.25", "nginx-exporter:0.11", "redis:7", "postgres:16"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.NewTest()
+			config := &types.Config{Settings: types.SettingsConfig{
+				NamespaceScheduling: types.NamespaceScheduling{Enabled: true, Order: tt.order},
+			}}
+			engine := NewEngine(registry.NewManager(log), log, config)
+
+			groups := engine.groupImagesByNamespace(images)
+
+			var namespaces []string
+			for _, group := range groups {
+				namespaces = append(namespaces, group.namespace)
+				for _, image := range group.images {
+					assert.Equal(t, group.namespace, image.Namespace)
+				}
+			}
+			assert.Equal(t, tt.expectedNamespaces, namespaces)
+
+			var ordered []string
+			for _, image := range engine.orderImagesByNamespace(images) {
+				ordered = append(ordered, image.Image)
+			}
+			assert.Equal(t, tt.expectedImages, ordered)
+		})
+	}
+}
+
+func TestEngine_namespaceConcurrency(t *testing.T) {
+	log := logger.NewTest()
+	config := &types.Config{Settings: types.SettingsConfig{Concurrency: 5}}
+	engine := NewEngine(registry.NewManager(log), log, config)
+	assert.Equal(t, 5, engine.namespaceConcurrency())
+
+	config.Settings.NamespaceScheduling.Concurrency = 2
+	assert.Equal(t, 2, engine.namespaceConcurrency())
+}
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+type privateMove struct {
+	source string
+	target string
+}
+
+func (e *Engine) WithPrivateMove(sourceRegistry, targetRegistry string) *Engine {
+	e.privateMove = &privateMove{source: sourceRegistry, target: targetRegistry}
+	return e
+}
+
+func (e *Engine) privateMoveTargets() []types.RegistryConfig {
+	for _, regConfig := range e.config.Registries {
+		if regConfig.Name == e.privateMove.target && regConfig.Enabled && regConfig.IsPushTarget() {
+			e.logger.Info("private_move_target_selected").
+				Str("source", e.privateMove.source).
+				Str("target", regConfig.Name).
+				Send()
+			return []types.RegistryConfig{regConfig}
+		}
+	}
+
+	e.logger.Error("private_move_target_not_found").
+		Str("target", e.privateMove.target).
+		Send()
+	return []types.RegistryConfig{}
+}
+
+func (e *Engine) preparePrivateMove(ctx context.Context) error {
+	if e.privateMove == nil {
+		return nil
+	}
+
+	if err := e.registryManager.SetCopySource(e.privateMove.target, e.privateMove.source); err != nil {
+		return fmt.Errorf("falha ao preparar movimentação entre registries privados: %w", err)
+	}
+
+	if e.config.Settings.DryRun {
+		return nil
+	}
+
+	source, err := e.registryManager.GetRegistry(e.privateMove.source)
+	if err != nil {
+		return err
+	}
+
+	if err := e.authenticateRegistry(ctx, source, e.privateMove.source); err != nil {
+		return fmt.Errorf("falha na autenticação do registry de origem %s: %w", e.privateMove.source, err)
+	}
+
+	return nil
+}
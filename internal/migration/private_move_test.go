@@ -0,0 +1,49 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_MigrateImages_PrivateMove(t *testing.T) {
+	config := &types.Config{
+		Registries: []types.RegistryConfig{
+			{Name: "old-harbor", Type: "docker", URL: "old-harbor.company.com", Enabled: true, Priority: 10},
+			{Name: "new-harbor", Type: "docker", URL: "new-harbor.company.com", Enabled: true, Priority: 1},
+		},
+		Settings: types.SettingsConfig{DryRun: true},
+	}
+
+	log := logger.NewTest()
+	manager := registry.NewManager(log)
+	for i := range config.Registries {
+		assert.NoError(t, manager.AddRegistry(&config.Registries[i]))
+	}
+
+	engine := NewEngine(manager, log, config).DeferReporting().WithPrivateMove("old-harbor", "new-harbor")
+	images := []*types.ImageInfo{{Image: "old-harbor.company.com/legacy/app:1.0", Namespace: "default"}}
+
+	summary, err := engine.MigrateImages(context.Background(), images)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(summary.Results))
+	assert.Equal(t, "new-harbor", summary.Results[0].Registry)
+	assert.Equal(t, "new-harbor.company.com/legacy/app:1.0", summary.Results[0].TargetImage)
+}
+
+func TestEngine_selectTargetRegistries_PrivateMoveUnknownTarget(t *testing.T) {
+	config := &types.Config{
+		Registries: []types.RegistryConfig{
+			{Name: "old-harbor", Type: "docker", URL: "old-harbor.company.com", Enabled: true},
+		},
+	}
+
+	engine := (&Engine{logger: logger.NewTest(), config: config}).WithPrivateMove("old-harbor", "new-harbor")
+
+	assert.Empty(t, engine.selectTargetRegistries())
+}
@@ -1,12 +1,19 @@
 package migration
 
 import (
+	"path"
 	"sort"
+	"strings"
 
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
 )
 
 func (e *Engine) selectTargetRegistries() []types.RegistryConfig {
+	if e.privateMove != nil {
+		return e.privateMoveTargets()
+	}
+
 	var enabledRegistries []types.RegistryConfig
 
 	e.logger.Debug("selecting_target_registries").
@@ -20,8 +27,16 @@ func (e *Engine) selectTargetRegistries() []types.RegistryConfig {
 			Str("type", regConfig.Type).
 			Bool("enabled", regConfig.Enabled).
 			Int("priority", regConfig.Priority).
+			Str("role", regConfig.Role).
 			Send()
 
+		if regConfig.Enabled && !regConfig.IsPushTarget() {
+			e.logger.Debug("registry_validate_only_skipped").
+				Str("name", regConfig.Name).
+				Send()
+			continue
+		}
+
 		if regConfig.Enabled {
 			enabledRegistries = append(enabledRegistries, regConfig)
 			e.logger.Debug("registry_added_to_enabled_list").
@@ -51,6 +66,14 @@ func (e *Engine) selectTargetRegistries() []types.RegistryConfig {
 		return enabledRegistries
 	}
 
+	if selection := e.config.Settings.RegistrySelection; selection != "" && selection != types.RegistrySelectionPriority {
+		e.logger.Info("single_registry_mode").
+			Str("selection", selection).
+			Int("candidates", len(enabledRegistries)).
+			Send()
+		return enabledRegistries
+	}
+
 	e.logger.Info("single_registry_mode").
 		Str("selected", enabledRegistries[0].Name).
 		Int("priority", enabledRegistries[0].Priority).
@@ -58,3 +81,82 @@ func (e *Engine) selectTargetRegistries() []types.RegistryConfig {
 
 	return []types.RegistryConfig{enabledRegistries[0]}
 }
+
+func (e *Engine) selectRegistryForImage(image *types.ImageInfo, candidates []types.RegistryConfig) types.RegistryConfig {
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	selected := candidates[0]
+	switch e.config.Settings.RegistrySelection {
+	case types.RegistrySelectionRoundRobin:
+		index := e.registryCursor.Add(1) - 1
+		selected = candidates[index%uint64(len(candidates))]
+	case types.RegistrySelectionRoundRobinBalanced:
+		e.registryMutex.Lock()
+		if e.registryAssignments == nil {
+			e.registryAssignments = make(map[string]int)
+		}
+		for _, candidate := range candidates[1:] {
+			if e.registryAssignments[candidate.Name] < e.registryAssignments[selected.Name] {
+				selected = candidate
+			}
+		}
+		e.registryAssignments[selected.Name]++
+		e.registryMutex.Unlock()
+	case types.RegistrySelectionByRule:
+		if registry, found := e.registryByRule(image.Image, candidates); found {
+			selected = registry
+		}
+	}
+
+	e.logger.Debug("registry_selected_for_image").
+		Str("image", image.Image).
+		Str("registry", selected.Name).
+		Str("selection", e.config.Settings.RegistrySelection).
+		Send()
+
+	return selected
+}
+
+func (e *Engine) registryByRule(imageName string, candidates []types.RegistryConfig) (types.RegistryConfig, bool) {
+	for _, rule := range e.config.Settings.RegistryRules {
+		if !matchesRegistryRule(rule.Pattern, imageName) {
+			continue
+		}
+		for _, candidate := range candidates {
+			if candidate.Name == rule.Registry {
+				return candidate, true
+			}
+		}
+		e.logger.Warn("registry_rule_target_unavailable").
+			Str("image", imageName).
+			Str("pattern", rule.Pattern).
+			Str("registry", rule.Registry).
+			Send()
+	}
+	return types.RegistryConfig{}, false
+}
+
+func matchesRegistryRule(pattern, imageName string) bool {
+	pattern = strings.ToLower(pattern)
+	parsed := utils.ParseImageName(strings.ToLower(imageName))
+	candidates := []string{
+		strings.ToLower(imageName),
+		parsed.FullRepository,
+		parsed.Registry + "/" + parsed.FullRepository,
+	}
+
+	for _, candidate := range candidates {
+		if !strings.Contains(pattern, "*") {
+			if strings.HasPrefix(candidate, pattern) {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, candidate); matched {
+			return true
+		}
+	}
+	return false
+}
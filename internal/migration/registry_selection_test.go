@@ -1,9 +1,13 @@
 package migration
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
 	"github.com/kevinfinalboss/privateer/pkg/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -375,3 +379,124 @@ func TestEngine_selectTargetRegistries_AllDisabledRegistries(t *testing.T) {
 	assert.Equal(t, 0, len(result))
 	assert.Empty(t, result)
 }
+
+func TestEngine_selectRegistryForImage_RoundRobin(t *testing.T) {
+	config := &types.Config{
+		Settings: types.SettingsConfig{RegistrySelection: types.RegistrySelectionRoundRobin},
+		Registries: []types.RegistryConfig{
+			{Name: "harbor-b", Enabled: true, Priority: 5},
+			{Name: "harbor-a", Enabled: true, Priority: 10},
+			{Name: "harbor-disabled", Enabled: false, Priority: 20},
+		},
+	}
+	engine := &Engine{logger: logger.NewTest(), config: config}
+
+	candidates := engine.selectTargetRegistries()
+	assert.Equal(t, 2, len(candidates))
+
+	var selected []string
+	for _, image := range []string{"nginx:1.25", "redis:7", "busybox:1.36", "alpine:3.19"} {
+		selected = append(selected, engine.selectRegistryForImage(&types.ImageInfo{Image: image}, candidates).Name)
+	}
+
+	assert.Equal(t, []string{"harbor-a", "harbor-b", "harbor-a", "harbor-b"}, selected)
+}
+
+func TestEngine_selectRegistryForImage_ByRule(t *testing.T) {
+	config := &types.Config{
+		Settings: types.SettingsConfig{
+			RegistrySelection: types.RegistrySelectionByRule,
+			RegistryRules: []types.RegistryRule{
+				{Pattern: "bitnami/*", Registry: "harbor-dr"},
+				{Pattern: "quay.io/", Registry: "ecr-prod"},
+				{Pattern: "docker.io/library/redis", Registry: "harbor-disabled"},
+			},
+		},
+		Registries: []types.RegistryConfig{
+			{Name: "harbor-main", Enabled: true, Priority: 100},
+			{Name: "harbor-dr", Enabled: true, Priority: 10},
+			{Name: "ecr-prod", Enabled: true, Priority: 1},
+			{Name: "harbor-disabled", Enabled: false, Priority: 200},
+		},
+	}
+	engine := &Engine{logger: logger.NewTest(), config: config}
+	candidates := engine.selectTargetRegistries()
+
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{image: "bitnami/postgresql:16.1.0", expected: "harbor-dr"},
+		{image: "docker.io/bitnami/redis:7.2", expected: "harbor-dr"},
+		{image: "quay.io/prometheus/node-exporter:v1.7.0", expected: "ecr-prod"},
+		{image: "redis:7", expected: "harbor-main"},
+		{image: "nginx:1.25", expected: "harbor-main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			selected := engine.selectRegistryForImage(&types.ImageInfo{Image: tt.image}, candidates)
+			assert.Equal(t, tt.expected, selected.Name)
+		})
+	}
+}
+
+func TestEngine_selectRegistryForImage_RoundRobinBalanced(t *testing.T) {
+	config := &types.Config{
+		Settings: types.SettingsConfig{RegistrySelection: types.RegistrySelectionRoundRobinBalanced},
+		Registries: []types.RegistryConfig{
+			{Name: "harbor-a", Enabled: true, Priority: 10},
+			{Name: "harbor-b", Enabled: true, Priority: 5},
+			{Name: "harbor-c", Enabled: true, Priority: 1},
+		},
+	}
+	engine := &Engine{logger: logger.NewTest(), config: config}
+	candidates := engine.selectTargetRegistries()
+
+	counts := make(map[string]int)
+	for i := 0; i < 7; i++ {
+		counts[engine.selectRegistryForImage(&types.ImageInfo{Image: "nginx:1.25"}, candidates).Name]++
+	}
+
+	assert.Equal(t, map[string]int{"harbor-a": 3, "harbor-b": 2, "harbor-c": 2}, counts)
+}
+
+func TestEngine_MigrateImages_ValidateOnlyRegistry(t *testing.T) {
+	mirrorDir := t.TempDir()
+	mirrorIndex := `{"schemaVersion":2,"manifests":[{"mediaType":"application/vnd.oci.image.index.v1+json","digest":"sha256:0000000000000000000000000000000000000000000000000000000000000000","size":1,"annotations":{"org.opencontainers.image.ref.name":"library/nginx:1.25"}}]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(mirrorDir, "index.json"), []byte(mirrorIndex), 0644))
+
+	config := &types.Config{
+		Registries: []types.RegistryConfig{
+			{Name: "readonly-mirror", Type: "oci-layout", Path: mirrorDir, Enabled: true, Priority: 100, Role: types.RegistryRoleValidate},
+			{Name: "airgap", Type: "oci-layout", Path: t.TempDir(), Enabled: true, Priority: 1, Role: types.RegistryRoleTarget},
+		},
+		Settings: types.SettingsConfig{DryRun: true, MultipleRegistries: true},
+	}
+
+	log := logger.NewTest()
+	manager := registry.NewManager(log)
+	for i := range config.Registries {
+		assert.NoError(t, manager.AddRegistry(&config.Registries[i]))
+	}
+
+	images := []*types.ImageInfo{{Image: "nginx:1.25", Namespace: "web", IsPublic: true}}
+
+	validated, validationErrors, err := manager.ValidateImagesBatch(context.Background(), images, config)
+	assert.NoError(t, err)
+	assert.Empty(t, validationErrors)
+	assert.Equal(t, "readonly-mirror/library/nginx:1.25", validated["nginx:1.25"])
+
+	engine := NewEngine(manager, log, config).DeferReporting()
+	summary, err := engine.MigrateImages(context.Background(), images)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(summary.Results))
+	for _, result := range summary.Results {
+		assert.Equal(t, "airgap", result.Registry)
+	}
+
+	data, err := os.ReadFile(filepath.Join(mirrorDir, "index.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, mirrorIndex, string(data))
+}
@@ -0,0 +1,135 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+)
+
+var (
+	sinceAgePattern     = regexp.MustCompile(`^(\d+)([dw])$`)
+	sinceVersionPattern = regexp.MustCompile(`^(v?)(\d+)(?:\.(\d+))?(?:\.(\d+))?$`)
+)
+
+type sinceThreshold struct {
+	raw        string
+	after      time.Time
+	minVersion []int
+}
+
+func parseSinceThreshold(value string, now time.Time) (*sinceThreshold, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	threshold := &sinceThreshold{raw: value}
+
+	if matches := sinceAgePattern.FindStringSubmatch(value); matches != nil {
+		amount, _ := strconv.Atoi(matches[1])
+		days := amount
+		if matches[2] == "w" {
+			days = amount * 7
+		}
+		threshold.after = now.AddDate(0, 0, -days)
+		return threshold, nil
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+		threshold.after = now.Add(-duration)
+		return threshold, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			threshold.after = date
+			return threshold, nil
+		}
+	}
+
+	if version, ok := parseTagVersion(value, sinceVersionPattern); ok {
+		threshold.minVersion = version
+		return threshold, nil
+	}
+
+	return nil, fmt.Errorf("valor inválido para since: %s (use 30d, 2w, 72h, 2024-01-01 ou uma versão como v1.2.0)", value)
+}
+
+func parseTagVersion(tag string, pattern *regexp.Regexp) ([]int, bool) {
+	matches := pattern.FindStringSubmatch(tag)
+	if matches == nil {
+		return nil, false
+	}
+
+	version := make([]int, 3)
+	for i := 0; i < 3; i++ {
+		if matches[i+2] != "" {
+			version[i], _ = strconv.Atoi(matches[i+2])
+		}
+	}
+	return version, true
+}
+
+func compareVersions(a, b []int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func (e *Engine) sinceSkipReason(ctx context.Context, reg registry.Registry, image *types.ImageInfo) (string, string) {
+	if e.since == nil {
+		return "", ""
+	}
+
+	if e.since.minVersion != nil {
+		tag := utils.ParseImageName(image.Image).Tag
+		version, ok := parseTagVersion(tag, tagVersionPattern)
+		if !ok {
+			e.logger.Debug("since_tag_not_comparable").
+				Str("image", image.Image).
+				Str("since", e.since.raw).
+				Send()
+			return "", ""
+		}
+		if compareVersions(version, e.since.minVersion) < 0 {
+			return types.SkipReasonSinceVersion, fmt.Sprintf("Tag %s anterior a since %s", tag, e.since.raw)
+		}
+		return "", ""
+	}
+
+	resolver, ok := reg.(registry.ImageCreationResolver)
+	if !ok {
+		e.logger.Debug("since_created_unsupported").
+			Str("image", image.Image).
+			Str("registry_type", reg.GetType()).
+			Send()
+		return "", ""
+	}
+
+	created, err := resolver.ImageCreated(ctx, image.Image)
+	if err != nil {
+		e.logger.Warn("since_created_unavailable").
+			Str("image", image.Image).
+			Err(err).
+			Send()
+		return "", ""
+	}
+
+	if created.Before(e.since.after) {
+		return types.SkipReasonSinceCreated, fmt.Sprintf("Imagem criada em %s, anterior a since %s", created.Format("2006-01-02"), e.since.raw)
+	}
+	return "", ""
+}
@@ -0,0 +1,118 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type datedMockRegistry struct {
+	*MockRegistry
+	created map[string]time.Time
+}
+
+func (m *datedMockRegistry) ImageCreated(ctx context.Context, imageName string) (time.Time, error) {
+	created, ok := m.created[imageName]
+	if !ok {
+		return time.Time{}, errors.New("manifest not found")
+	}
+	return created, nil
+}
+
+func TestParseSinceThreshold(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value      string
+		after      time.Time
+		minVersion []int
+		wantErr    bool
+	}{
+		{value: "30d", after: now.AddDate(0, 0, -30)},
+		{value: "2w", after: now.AddDate(0, 0, -14)},
+		{value: "72h", after: now.Add(-72 * time.Hour)},
+		{value: "2024-01-15", after: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{value: "v1.2.0", minVersion: []int{1, 2, 0}},
+		{value: "2.5", minVersion: []int{2, 5, 0}},
+		{value: "last-month", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			threshold, err := parseSinceThreshold(tt.value, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.after, threshold.after)
+			assert.Equal(t, tt.minVersion, threshold.minVersion)
+		})
+	}
+}
+
+func TestEngine_sinceSkipReason(t *testing.T) {
+	now := time.Now()
+	reg := &datedMockRegistry{
+		MockRegistry: &MockRegistry{},
+		created: map[string]time.Time{
+			"nginx:1.25":   now.AddDate(0, 0, -5),
+			"redis:6.0":    now.AddDate(0, 0, -400),
+			"busybox:1.36": now.AddDate(0, 0, -29),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		since    string
+		image    string
+		wantCode string
+	}{
+		{name: "recent image is migrated", since: "30d", image: "nginx:1.25"},
+		{name: "old image is skipped", since: "30d", image: "redis:6.0", wantCode: types.SkipReasonSinceCreated},
+		{name: "image just inside the window", since: "30d", image: "busybox:1.36"},
+		{name: "unknown creation date is migrated", since: "30d", image: "alpine:3.19"},
+		{name: "tag above version floor", since: "v1.20.0", image: "nginx:1.25"},
+		{name: "tag below version floor", since: "v1.20.0", image: "busybox:1.19.4", wantCode: types.SkipReasonSinceVersion},
+		{name: "non semver tag is migrated", since: "v1.20.0", image: "nginx:latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, err := parseSinceThreshold(tt.since, now)
+			assert.NoError(t, err)
+
+			engine := &Engine{logger: logger.NewTest(), config: &types.Config{}, since: threshold}
+			code, reason := engine.sinceSkipReason(context.Background(), reg, &types.ImageInfo{Image: tt.image})
+
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantCode != "", reason != "")
+		})
+	}
+}
+
+func TestEngine_recordDryRunSinceSkip(t *testing.T) {
+	now := time.Now()
+	reg := &datedMockRegistry{
+		MockRegistry: &MockRegistry{},
+		created:      map[string]time.Time{"redis:6.0": now.AddDate(0, 0, -400)},
+	}
+	threshold, err := parseSinceThreshold("30d", now)
+	assert.NoError(t, err)
+
+	engine := &Engine{logger: logger.NewTest(), config: &types.Config{}, since: threshold}
+	summary := &types.MigrationSummary{SuccessCount: 1}
+
+	skipped := engine.recordDryRunSinceSkip(context.Background(), reg, &types.ImageInfo{Image: "redis:6.0"}, "harbor", summary)
+
+	assert.True(t, skipped)
+	assert.Equal(t, 0, summary.SuccessCount)
+	assert.Equal(t, 1, summary.SkippedCount)
+	assert.Len(t, summary.Results, 1)
+	assert.Equal(t, types.SkipReasonSinceCreated, summary.Results[0].ReasonCode)
+}
@@ -2,19 +2,23 @@ package migration
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/kevinfinalboss/privateer/internal/registry"
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
 )
 
+var ecrHostPattern = regexp.MustCompile(`^(\d{12})\.dkr\.ecr\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
 func (e *Engine) generateTargetImageName(image *types.ImageInfo, reg registry.Registry) (string, error) {
 	e.logger.Debug("parsing_image_name").
 		Str("image", image.Image).
 		Str("registry_type", reg.GetType()).
 		Send()
 
-	parsed := types.ParseImageName(image.Image)
+	parsed := utils.ParseImageName(image.Image)
 
 	e.logger.Debug("image_parsing_result").
 		Str("original_image", parsed.OriginalImage).
@@ -27,146 +31,81 @@ func (e *Engine) generateTargetImageName(image *types.ImageInfo, reg registry.Re
 		Send()
 
 	targetRepository := parsed.FullRepository
-	targetTag := parsed.Tag
+	targetReference := parsed.Reference()
 
 	if parsed.Digest != "" {
-		targetTag = fmt.Sprintf("%s@%s", targetTag, parsed.Digest)
 		e.logger.Debug("digest_detected_in_target").
-			Str("target_tag", targetTag).
+			Str("target_reference", targetReference).
 			Send()
 	}
 
-	var targetImage string
-	var err error
-
-	switch reg.GetType() {
-	case "docker":
-		targetImage = e.generateDockerTargetImage(reg.GetName(), targetRepository, targetTag)
-	case "harbor":
-		targetImage = e.generateHarborTargetImage(reg.GetName(), targetRepository, targetTag)
-	case "ecr":
-		targetImage = e.generateECRTargetImage(reg.GetName(), targetRepository, targetTag)
-	case "ghcr":
-		targetImage = e.generateGHCRTargetImage(reg.GetName(), targetRepository, targetTag)
-	default:
-		targetImage = e.generateDefaultTargetImage(reg.GetName(), targetRepository, targetTag)
+	targetPrefix := registry.TargetPrefixFor(reg, e.config)
+	if resolver, ok := reg.(interface{ GetRegistryURL() string }); ok && reg.GetType() == "ecr" && targetPrefix == reg.GetName() {
+		targetPrefix = resolver.GetRegistryURL()
 	}
+	targetImage := fmt.Sprintf("%s/%s%s", targetPrefix, targetRepository, targetReference)
 
-	if targetImage == "" {
-		err = fmt.Errorf("falha ao gerar nome da imagem de destino para %s", image.Image)
-		e.logger.Error("target_image_generation_empty").
+	e.logger.Debug("target_image_generated").
+		Str("registry", reg.GetName()).
+		Str("registry_type", reg.GetType()).
+		Str("target_prefix", targetPrefix).
+		Str("target_image", targetImage).
+		Send()
+
+	if err := utils.ValidateImageReference(targetImage); err != nil {
+		e.logger.Error("target_image_invalid_reference").
 			Str("source_image", image.Image).
+			Str("target_image", targetImage).
+			Str("registry", reg.GetName()).
 			Str("registry_type", reg.GetType()).
+			Err(err).
 			Send()
-		return "", err
+		return "", fmt.Errorf("imagem de destino inválida %q gerada para %s pelo registry %s (verifique url/project/username na configuração): %w", targetImage, image.Image, reg.GetName(), err)
 	}
 
-	return targetImage, nil
-}
-
-func (e *Engine) generateDockerTargetImage(registryName, targetRepository, targetTag string) string {
-	registryURL := e.getRegistryURL(registryName)
-	targetImage := fmt.Sprintf("%s/%s:%s", registryURL, targetRepository, targetTag)
-
-	e.logger.Debug("docker_target_image_generated").
-		Str("registry_url", registryURL).
-		Str("target_image", targetImage).
-		Send()
-
-	return targetImage
-}
-
-func (e *Engine) generateHarborTargetImage(registryName, targetRepository, targetTag string) string {
-	registryURL := e.getRegistryURL(registryName)
-	project := e.getHarborProject(registryName)
-	targetImage := fmt.Sprintf("%s/%s/%s:%s", registryURL, project, targetRepository, targetTag)
-
-	e.logger.Debug("harbor_target_image_generated").
-		Str("registry_url", registryURL).
-		Str("project", project).
-		Str("target_image", targetImage).
-		Send()
-
-	return targetImage
-}
-
-func (e *Engine) generateECRTargetImage(registryName, targetRepository, targetTag string) string {
-	ecrURL := e.getECRURL(registryName)
-	targetImage := fmt.Sprintf("%s/%s:%s", ecrURL, targetRepository, targetTag)
-
-	e.logger.Debug("ecr_target_image_generated").
-		Str("ecr_url", ecrURL).
-		Str("target_image", targetImage).
-		Send()
-
-	return targetImage
-}
-
-func (e *Engine) generateGHCRTargetImage(registryName, targetRepository, targetTag string) string {
-	organization := e.getGHCROrganization(registryName)
-	targetImage := fmt.Sprintf("ghcr.io/%s/%s:%s", organization, targetRepository, targetTag)
-
-	e.logger.Debug("ghcr_target_image_generated").
-		Str("organization", organization).
-		Str("target_image", targetImage).
-		Send()
-
-	return targetImage
-}
-
-func (e *Engine) generateDefaultTargetImage(registryName, targetRepository, targetTag string) string {
-	targetImage := fmt.Sprintf("%s/%s:%s", registryName, targetRepository, targetTag)
-
-	e.logger.Debug("default_target_image_generated").
-		Str("target_image", targetImage).
-		Send()
-
-	return targetImage
-}
-
-func (e *Engine) getRegistryURL(registryName string) string {
-	for _, regConfig := range e.config.Registries {
-		if regConfig.Name == registryName {
-			url := regConfig.URL
-			if strings.HasPrefix(url, "http://") {
-				url = strings.TrimPrefix(url, "http://")
-			} else if strings.HasPrefix(url, "https://") {
-				url = strings.TrimPrefix(url, "https://")
-			}
-			return url
+	if reg.GetType() == "ecr" {
+		if err := e.validateECRTarget(reg.GetName(), image.Image, targetImage); err != nil {
+			return "", err
 		}
 	}
-	return registryName
-}
 
-func (e *Engine) getHarborProject(registryName string) string {
-	for _, regConfig := range e.config.Registries {
-		if regConfig.Name == registryName && regConfig.Project != "" {
-			return regConfig.Project
-		}
-	}
-	return "library"
+	return targetImage, nil
 }
 
-func (e *Engine) getECRURL(registryName string) string {
-	for _, regConfig := range e.config.Registries {
-		if regConfig.Name == registryName && regConfig.Type == "ecr" {
-			if regConfig.AccountID != "" {
-				return fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com", regConfig.AccountID, regConfig.Region)
-			}
-		}
+func (e *Engine) validateECRTarget(registryName, sourceImage, targetImage string) error {
+	host, _, _ := strings.Cut(targetImage, "/")
+	matches := ecrHostPattern.FindStringSubmatch(host)
+	if matches == nil {
+		return fmt.Errorf("imagem de destino %s gerada para %s não aponta para um endpoint ECR: configure account_id e region no registry %s", targetImage, sourceImage, registryName)
 	}
-	return registryName
-}
+	accountID, region := matches[1], matches[2]
 
-func (e *Engine) getGHCROrganization(registryName string) string {
 	for _, regConfig := range e.config.Registries {
-		if regConfig.Name == registryName {
-			if regConfig.Project != "" {
-				return regConfig.Project
-			}
-			return regConfig.Username
+		if regConfig.Name != registryName {
+			continue
+		}
+		if (regConfig.AccountID != "" && regConfig.AccountID != accountID) || (regConfig.Region != "" && regConfig.Region != region) {
+			e.logger.Error("ecr_target_mismatch").
+				Str("source_image", sourceImage).
+				Str("target_image", targetImage).
+				Str("registry", registryName).
+				Str("expected_account_id", regConfig.AccountID).
+				Str("expected_region", regConfig.Region).
+				Str("account_id", accountID).
+				Str("region", region).
+				Send()
+			return fmt.Errorf("imagem de destino %s (conta %s, região %s) não corresponde ao registry ECR %s (conta %s, região %s)", targetImage, accountID, region, registryName, regConfig.AccountID, regConfig.Region)
 		}
+		break
 	}
-	return "unknown"
+
+	e.logger.Info("ecr_target_routed").
+		Str("source_image", sourceImage).
+		Str("target_image", targetImage).
+		Str("registry", registryName).
+		Str("account_id", accountID).
+		Str("region", region).
+		Send()
+
+	return nil
 }
@@ -1,9 +1,11 @@
 package migration
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/registry"
 	"github.com/kevinfinalboss/privateer/pkg/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -37,6 +39,25 @@ func TestEngine_generateTargetImageName(t *testing.T) {
 			expectedResult: "registry.example.com/library/nginx:latest",
 			expectError:    false,
 		},
+		{
+			name: "Docker registry with a host:port source keeps the full repository and tag",
+			image: &types.ImageInfo{
+				Image: "registry.local:5000/team/app:1.0",
+			},
+			registryType: "docker",
+			registryName: "docker-registry",
+			config: &types.Config{
+				Registries: []types.RegistryConfig{
+					{
+						Name: "docker-registry",
+						Type: "docker",
+						URL:  "registry.example.com",
+					},
+				},
+			},
+			expectedResult: "registry.example.com/team/app:1.0",
+			expectError:    false,
+		},
 		{
 			name: "Docker registry with namespaced image",
 			image: &types.ImageInfo{
@@ -700,6 +721,16 @@ func TestEngine_generateTargetImageName_WithDigest(t *testing.T) {
 			image:    "nginx:latest",
 			expected: "registry.example.com/library/nginx:latest",
 		},
+		{
+			name:     "Digest-only image keeps digest reference",
+			image:    "nginx@sha256:abcd1234",
+			expected: "registry.example.com/library/nginx@sha256:abcd1234",
+		},
+		{
+			name:     "Namespaced digest-only image",
+			image:    "mycompany/myapp@sha256:efgh5678",
+			expected: "registry.example.com/mycompany/myapp@sha256:efgh5678",
+		},
 	}
 
 	for _, tt := range tests {
@@ -721,3 +752,172 @@ func TestEngine_generateTargetImageName_WithDigest(t *testing.T) {
 		})
 	}
 }
+
+func TestEngine_generateTargetImageName_InvalidReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		regType  string
+		config   types.RegistryConfig
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "valid harbor project",
+			regType:  "harbor",
+			config:   types.RegistryConfig{Name: "harbor-prod", URL: "https://harbor.company.com", Project: "platform"},
+			expected: "harbor.company.com/platform/library/nginx:1.25",
+		},
+		{
+			name:    "harbor project with trailing slash",
+			regType: "harbor",
+			config:  types.RegistryConfig{Name: "harbor-prod", URL: "https://harbor.company.com", Project: "platform/"},
+			wantErr: true,
+		},
+		{
+			name:    "docker url with trailing slash",
+			regType: "docker",
+			config:  types.RegistryConfig{Name: "docker-prod", URL: "https://registry.company.com/"},
+			wantErr: true,
+		},
+		{
+			name:    "ghcr uppercase username",
+			regType: "ghcr",
+			config:  types.RegistryConfig{Name: "ghcr-prod", Username: "MyOrg"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.NewTest()
+			config := &types.Config{Registries: []types.RegistryConfig{tt.config}}
+			engine := NewEngine(registry.NewManager(log), log, config)
+
+			mockReg := &MockRegistry{}
+			mockReg.On("GetType").Return(tt.regType)
+			mockReg.On("GetName").Return(tt.config.Name)
+
+			result, err := engine.generateTargetImageName(&types.ImageInfo{Image: "nginx:1.25"}, mockReg)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, strings.Contains(err.Error(), tt.config.Name))
+				assert.Equal(t, "", result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEngine_generateTargetImageName_DockerNamespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  string
+		image    string
+		expected string
+	}{
+		{"root without project", "", "nginx:1.25", "registry.company.com/library/nginx:1.25"},
+		{"project prefix", "mirrors", "nginx:1.25", "registry.company.com/mirrors/library/nginx:1.25"},
+		{"nested project prefix", "platform/mirrors", "bitnami/redis:7.0", "registry.company.com/platform/mirrors/bitnami/redis:7.0"},
+		{"project prefix with digest", "mirrors", "quay.io/prometheus/node-exporter@sha256:abcd1234", "registry.company.com/mirrors/prometheus/node-exporter@sha256:abcd1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.NewTest()
+			config := &types.Config{Registries: []types.RegistryConfig{
+				{Name: "docker-prod", Type: "docker", URL: "https://registry.company.com", Project: tt.project},
+			}}
+			engine := NewEngine(registry.NewManager(log), log, config)
+
+			mockReg := &MockRegistry{}
+			mockReg.On("GetType").Return("docker")
+			mockReg.On("GetName").Return("docker-prod")
+
+			result, err := engine.generateTargetImageName(&types.ImageInfo{Image: tt.image}, mockReg)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			assert.True(t, strings.HasPrefix(result, registry.TargetPrefix(&config.Registries[0])+"/"))
+		})
+	}
+}
+
+func TestEngine_generateTargetImageName_DockerHubAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		expected string
+	}{
+		{"index.docker.io official image", "index.docker.io/library/nginx:1.25", "harbor.company.com/mirrors/library/nginx:1.25"},
+		{"index.docker.io without library", "index.docker.io/nginx:1.25", "harbor.company.com/mirrors/library/nginx:1.25"},
+		{"registry-1.docker.io namespaced image", "registry-1.docker.io/bitnami/redis:7.0", "harbor.company.com/mirrors/bitnami/redis:7.0"},
+		{"registry-1.docker.io without library", "registry-1.docker.io/busybox:1.36", "harbor.company.com/mirrors/library/busybox:1.36"},
+		{"docker.io without library", "docker.io/nginx:1.25", "harbor.company.com/mirrors/library/nginx:1.25"},
+		{"registry.hub.docker.com with digest", "registry.hub.docker.com/library/nginx@sha256:abcd1234", "harbor.company.com/mirrors/library/nginx@sha256:abcd1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.NewTest()
+			config := &types.Config{Registries: []types.RegistryConfig{
+				{Name: "harbor-prod", Type: "harbor", URL: "https://harbor.company.com", Project: "mirrors"},
+			}}
+			engine := NewEngine(registry.NewManager(log), log, config)
+
+			mockReg := &MockRegistry{}
+			mockReg.On("GetType").Return("harbor")
+			mockReg.On("GetName").Return("harbor-prod")
+
+			result, err := engine.generateTargetImageName(&types.ImageInfo{Image: tt.image}, mockReg)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			assert.NotContains(t, result, "docker.io")
+		})
+	}
+}
+
+func TestEngine_generateTargetImageName_ECRRouting(t *testing.T) {
+	log := logger.NewTest()
+	config := &types.Config{Registries: []types.RegistryConfig{
+		{Name: "ecr-us", Type: "ecr", AccountID: "111111111111", Region: "us-east-1"},
+		{Name: "ecr-eu", Type: "ecr", AccountID: "222222222222", Region: "eu-west-1"},
+		{Name: "ecr-unknown-account", Type: "ecr", Region: "sa-east-1"},
+	}}
+	engine := NewEngine(registry.NewManager(log), log, config)
+
+	for _, regConfig := range config.Registries[:2] {
+		t.Run(regConfig.Name, func(t *testing.T) {
+			mockReg := &MockRegistry{}
+			mockReg.On("GetType").Return("ecr")
+			mockReg.On("GetName").Return(regConfig.Name)
+
+			result, err := engine.generateTargetImageName(&types.ImageInfo{Image: "nginx:1.25"}, mockReg)
+
+			assert.NoError(t, err)
+			host, _, _ := strings.Cut(result, "/")
+			matches := ecrHostPattern.FindStringSubmatch(host)
+			if assert.NotNil(t, matches, "target %s is not an ECR endpoint", result) {
+				assert.Equal(t, regConfig.AccountID, matches[1])
+				assert.Equal(t, regConfig.Region, matches[2])
+			}
+		})
+	}
+
+	t.Run("missing account", func(t *testing.T) {
+		mockReg := &MockRegistry{}
+		mockReg.On("GetType").Return("ecr")
+		mockReg.On("GetName").Return("ecr-unknown-account")
+
+		_, err := engine.generateTargetImageName(&types.ImageInfo{Image: "nginx:1.25"}, mockReg)
+		assert.ErrorContains(t, err, "account_id")
+	})
+
+	t.Run("target from another account", func(t *testing.T) {
+		err := engine.validateECRTarget("ecr-eu", "nginx:1.25", "111111111111.dkr.ecr.us-east-1.amazonaws.com/library/nginx:1.25")
+		assert.ErrorContains(t, err, "ecr-eu")
+	})
+}
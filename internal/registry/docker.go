@@ -18,16 +18,19 @@ type DockerRegistry struct {
 
 func NewDockerRegistry(config *types.RegistryConfig, logger *logger.Logger) (*DockerRegistry, error) {
 	base := &BaseRegistry{
-		Name:     config.Name,
-		Type:     "docker",
-		Logger:   logger,
-		Username: config.Username,
-		Password: config.Password,
-		URL:      config.URL,
-		Insecure: config.Insecure,
+		Name:          config.Name,
+		Type:          "docker",
+		Logger:        logger,
+		Username:      config.Username,
+		Password:      config.Password,
+		URL:           config.URL,
+		Insecure:      config.Insecure,
+		InsecureHosts: config.InsecureHosts,
+		Timeout:       config.Timeout,
+		Anonymous:     config.Anonymous,
 	}
 
-	httpClient := createHTTPClient(config.Insecure)
+	httpClient := createHTTPClient(config.Insecure, config.Timeout)
 
 	return &DockerRegistry{
 		BaseRegistry: base,
@@ -36,6 +39,10 @@ func NewDockerRegistry(config *types.RegistryConfig, logger *logger.Logger) (*Do
 }
 
 func (r *DockerRegistry) Login(ctx context.Context) error {
+	if r.skipLogin() {
+		return nil
+	}
+
 	r.Logger.Debug("registry_login_start").
 		Str("registry", r.Name).
 		Str("url", r.URL).
@@ -73,8 +80,7 @@ func (r *DockerRegistry) Pull(ctx context.Context, imageName string) error {
 		Str("image", imageName).
 		Send()
 
-	cmd := exec.CommandContext(ctx, "docker", "pull", imageName)
-	output, err := cmd.CombinedOutput()
+	output, err := r.pullWithMirrors(ctx, imageName)
 	if err != nil {
 		r.Logger.Error("image_pull_failed").
 			Str("image", imageName).
@@ -128,11 +134,18 @@ func (r *DockerRegistry) Push(ctx context.Context, image *types.ImageInfo, targe
 }
 
 func (r *DockerRegistry) Copy(ctx context.Context, sourceImage, targetImage string) error {
+	ctx, cancel := r.withCopyTimeout(ctx)
+	defer cancel()
+
 	r.Logger.Debug("image_copy_start").
 		Str("source", sourceImage).
 		Str("target", targetImage).
 		Send()
 
+	if r.useOCIClient(sourceImage) {
+		return r.copyPreservingAnnotations(ctx, sourceImage, targetImage)
+	}
+
 	if err := r.Pull(ctx, sourceImage); err != nil {
 		return err
 	}
@@ -185,9 +198,7 @@ func (r *DockerRegistry) IsHealthy(ctx context.Context) error {
 		return err
 	}
 
-	if r.Username != "" && r.Password != "" {
-		req.SetBasicAuth(r.Username, r.Password)
-	}
+	r.setBasicAuth(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -234,9 +245,7 @@ func (r *DockerRegistry) HasImage(ctx context.Context, imageName string) (bool,
 		return false, err
 	}
 
-	if r.Username != "" && r.Password != "" {
-		req.SetBasicAuth(r.Username, r.Password)
-	}
+	r.setBasicAuth(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -244,5 +253,9 @@ func (r *DockerRegistry) HasImage(ctx context.Context, imageName string) (bool,
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return false, fmt.Errorf("registry retornou status %d ao verificar %s: %w", resp.StatusCode, imageName, types.ErrRegistryUnavailable)
+	}
+
 	return resp.StatusCode == http.StatusOK, nil
 }
@@ -0,0 +1,171 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/httpreplay"
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestDockerRegistry_HasImage_Anonymous(t *testing.T) {
+	tests := []struct {
+		name          string
+		anonymous     bool
+		expectAuthHdr bool
+	}{
+		{name: "anonymous registry sends no credentials", anonymous: true, expectAuthHdr: false},
+		{name: "authenticated registry sends basic auth", anonymous: false, expectAuthHdr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			reg, err := NewDockerRegistry(&types.RegistryConfig{
+				Name:      "public-mirror",
+				Type:      "docker",
+				URL:       server.URL,
+				Username:  "user",
+				Password:  "secret",
+				Anonymous: tt.anonymous,
+			}, logger.NewTest())
+			if err != nil {
+				t.Fatalf("NewDockerRegistry() unexpected error: %v", err)
+			}
+
+			exists, err := reg.HasImage(context.Background(), "public-mirror/library/nginx:1.25")
+			if err != nil {
+				t.Fatalf("HasImage() unexpected error: %v", err)
+			}
+			if !exists {
+				t.Errorf("HasImage() = false, expected true")
+			}
+
+			if (authorization != "") != tt.expectAuthHdr {
+				t.Errorf("Authorization header = %q, expected present = %v", authorization, tt.expectAuthHdr)
+			}
+		})
+	}
+}
+
+func TestDockerRegistry_HasImage_Status(t *testing.T) {
+	tests := []struct {
+		status    int
+		exists    bool
+		transient bool
+		wantErr   bool
+	}{
+		{status: http.StatusOK, exists: true},
+		{status: http.StatusNotFound},
+		{status: http.StatusTooManyRequests, transient: true, wantErr: true},
+		{status: http.StatusServiceUnavailable, transient: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			reg, err := NewDockerRegistry(&types.RegistryConfig{Name: "mirror", Type: "docker", URL: server.URL, Anonymous: true}, logger.NewTest())
+			if err != nil {
+				t.Fatalf("NewDockerRegistry() unexpected error: %v", err)
+			}
+
+			exists, err := reg.HasImage(context.Background(), "mirror/library/nginx:1.25")
+			if (err != nil) != tt.wantErr || exists != tt.exists {
+				t.Fatalf("HasImage() = (%v, %v), expected exists=%v wantErr=%v", exists, err, tt.exists, tt.wantErr)
+			}
+			if isTransientRegistryError(err) != tt.transient {
+				t.Errorf("isTransientRegistryError(%v) = %v, expected %v", err, !tt.transient, tt.transient)
+			}
+		})
+	}
+}
+
+func TestDockerRegistry_Login_Anonymous(t *testing.T) {
+	reg, err := NewDockerRegistry(&types.RegistryConfig{
+		Name:      "public-mirror",
+		Type:      "docker",
+		URL:       "registry.example.com",
+		Anonymous: true,
+	}, logger.NewTest())
+	if err != nil {
+		t.Fatalf("NewDockerRegistry() unexpected error: %v", err)
+	}
+
+	if err := reg.Login(context.Background()); err != nil {
+		t.Errorf("Login() unexpected error for anonymous registry: %v", err)
+	}
+}
+
+func TestDockerRegistry_HasImage_ReplaysCassette(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/library/nginx/manifests/1.25" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	config := &types.RegistryConfig{Name: "mirror", Type: "docker", URL: server.URL, Anonymous: true}
+	ctx := context.Background()
+
+	recordedPath := filepath.Join(t.TempDir(), "cassettes", "registry.json")
+	t.Setenv(httpreplay.CassetteEnvVar, recordedPath)
+	t.Setenv(httpreplay.ModeEnvVar, httpreplay.ModeRecord)
+
+	recording, err := NewDockerRegistry(config, logger.NewTest())
+	if err != nil {
+		t.Fatalf("NewDockerRegistry() unexpected error: %v", err)
+	}
+	if exists, err := recording.HasImage(ctx, "mirror/library/nginx:1.25"); err != nil || !exists {
+		t.Fatalf("recording HasImage() = %v, %v, expected true", exists, err)
+	}
+	if exists, err := recording.HasImage(ctx, "mirror/library/redis:7"); err != nil || exists {
+		t.Fatalf("recording HasImage() = %v, %v, expected false", exists, err)
+	}
+	server.Close()
+
+	if err := httpreplay.Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+	cassette, err := os.ReadFile(recordedPath)
+	if err != nil {
+		t.Fatalf("cassette not written: %v", err)
+	}
+	replayPath := filepath.Join(t.TempDir(), "registry.json")
+	if err := os.WriteFile(replayPath, cassette, 0644); err != nil {
+		t.Fatalf("failed to copy cassette: %v", err)
+	}
+
+	t.Setenv(httpreplay.CassetteEnvVar, replayPath)
+	t.Setenv(httpreplay.ModeEnvVar, httpreplay.ModeReplay)
+
+	replaying, err := NewDockerRegistry(config, logger.NewTest())
+	if err != nil {
+		t.Fatalf("NewDockerRegistry() unexpected error: %v", err)
+	}
+	if exists, err := replaying.HasImage(ctx, "mirror/library/nginx:1.25"); err != nil || !exists {
+		t.Errorf("replayed HasImage() = %v, %v, expected true", exists, err)
+	}
+	if exists, err := replaying.HasImage(ctx, "mirror/library/redis:7"); err != nil || exists {
+		t.Errorf("replayed HasImage() = %v, %v, expected false", exists, err)
+	}
+	if _, err := replaying.HasImage(ctx, "mirror/library/postgres:16"); err == nil || !strings.Contains(err.Error(), "não encontrada no cassette") {
+		t.Errorf("HasImage() for an unrecorded request = %v, expected a cassette miss", err)
+	}
+}
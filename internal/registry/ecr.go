@@ -5,43 +5,72 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
 	ecrTypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
 )
 
+const defaultECRSessionName = "privateer"
+
+type ecrAPI interface {
+	GetAuthorizationToken(ctx context.Context, params *ecr.GetAuthorizationTokenInput, optFns ...func(*ecr.Options)) (*ecr.GetAuthorizationTokenOutput, error)
+	BatchGetImage(ctx context.Context, params *ecr.BatchGetImageInput, optFns ...func(*ecr.Options)) (*ecr.BatchGetImageOutput, error)
+	DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error)
+	CreateRepository(ctx context.Context, params *ecr.CreateRepositoryInput, optFns ...func(*ecr.Options)) (*ecr.CreateRepositoryOutput, error)
+	GetLifecyclePolicy(ctx context.Context, params *ecr.GetLifecyclePolicyInput, optFns ...func(*ecr.Options)) (*ecr.GetLifecyclePolicyOutput, error)
+	PutLifecyclePolicy(ctx context.Context, params *ecr.PutLifecyclePolicyInput, optFns ...func(*ecr.Options)) (*ecr.PutLifecyclePolicyOutput, error)
+}
+
 type ECRRegistry struct {
 	*BaseRegistry
-	Region    string
-	AccountID string
-	Profiles  []string
-	AccessKey string
-	SecretKey string
-	awsConfig aws.Config
-	ecrClient *ecr.Client
+	Region          string
+	AccountID       string
+	Profiles        []string
+	AccessKey       string
+	SecretKey       string
+	RoleARN         string
+	ExternalID      string
+	SessionName     string
+	ScanOnPush      bool
+	LifecyclePolicy string
+	RepositoryTags  map[string]string
+	awsConfig       aws.Config
+	ecrClient       ecrAPI
 }
 
 func NewECRRegistry(config *types.RegistryConfig, logger *logger.Logger) (*ECRRegistry, error) {
 	base := &BaseRegistry{
-		Name:   config.Name,
-		Type:   "ecr",
-		Logger: logger,
+		Name:          config.Name,
+		Type:          "ecr",
+		Logger:        logger,
+		InsecureHosts: config.InsecureHosts,
+		Timeout:       config.Timeout,
 	}
 
 	registry := &ECRRegistry{
-		BaseRegistry: base,
-		Region:       config.Region,
-		AccountID:    config.AccountID,
-		Profiles:     config.Profiles,
-		AccessKey:    config.AccessKey,
-		SecretKey:    config.SecretKey,
+		BaseRegistry:    base,
+		Region:          config.Region,
+		AccountID:       config.AccountID,
+		Profiles:        config.Profiles,
+		AccessKey:       config.AccessKey,
+		SecretKey:       config.SecretKey,
+		RoleARN:         config.RoleARN,
+		ExternalID:      config.ExternalID,
+		SessionName:     config.SessionName,
+		ScanOnPush:      config.ScanOnPush,
+		LifecyclePolicy: config.LifecyclePolicy,
+		RepositoryTags:  config.RepositoryTags,
 	}
 
 	if err := registry.initAWSConfig(context.Background()); err != nil {
@@ -110,6 +139,20 @@ func (r *ECRRegistry) initAWSConfig(ctx context.Context) error {
 		return fmt.Errorf("falha ao carregar configuração AWS: %w", err)
 	}
 
+	if r.Timeout > 0 {
+		cfg.HTTPClient = awshttp.NewBuildableClient().WithTimeout(r.Timeout)
+	}
+
+	if r.RoleARN != "" {
+		r.Logger.Debug("ecr_assuming_role").
+			Str("role_arn", r.RoleARN).
+			Str("session_name", r.sessionName()).
+			Bool("external_id", r.ExternalID != "").
+			Send()
+
+		cfg.Credentials = aws.NewCredentialsCache(r.newAssumeRoleProvider(sts.NewFromConfig(cfg)))
+	}
+
 	r.awsConfig = cfg
 	r.ecrClient = ecr.NewFromConfig(cfg)
 
@@ -122,6 +165,22 @@ func (r *ECRRegistry) initAWSConfig(ctx context.Context) error {
 	return nil
 }
 
+func (r *ECRRegistry) newAssumeRoleProvider(client stscreds.AssumeRoleAPIClient) *stscreds.AssumeRoleProvider {
+	return stscreds.NewAssumeRoleProvider(client, r.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = r.sessionName()
+		if r.ExternalID != "" {
+			o.ExternalID = aws.String(r.ExternalID)
+		}
+	})
+}
+
+func (r *ECRRegistry) sessionName() string {
+	if r.SessionName != "" {
+		return r.SessionName
+	}
+	return defaultECRSessionName
+}
+
 func (r *ECRRegistry) discoverAccountID(ctx context.Context) error {
 	stsClient := sts.NewFromConfig(r.awsConfig)
 
@@ -172,6 +231,8 @@ func (r *ECRRegistry) Login(ctx context.Context) error {
 	registryURL := *authData.ProxyEndpoint
 	username := parts[0]
 	password := parts[1]
+	r.Username = username
+	r.Password = password
 
 	cmd := exec.CommandContext(ctx, "docker", "login", registryURL, "-u", username, "--password-stdin")
 	cmd.Stdin = strings.NewReader(password)
@@ -199,8 +260,7 @@ func (r *ECRRegistry) Pull(ctx context.Context, imageName string) error {
 		Str("image", imageName).
 		Send()
 
-	cmd := exec.CommandContext(ctx, "docker", "pull", imageName)
-	output, err := cmd.CombinedOutput()
+	output, err := r.pullWithMirrors(ctx, imageName)
 	if err != nil {
 		r.Logger.Error("ecr_pull_failed").
 			Str("image", imageName).
@@ -223,6 +283,10 @@ func (r *ECRRegistry) Push(ctx context.Context, image *types.ImageInfo, targetTa
 		Str("target", targetTag).
 		Send()
 
+	if err := r.checkTargetOwnership(targetTag); err != nil {
+		return err
+	}
+
 	repositoryName := r.extractRepositoryName(targetTag)
 	if err := r.ensureRepositoryExists(ctx, repositoryName); err != nil {
 		r.Logger.Warn("ecr_repository_create_failed").
@@ -262,11 +326,18 @@ func (r *ECRRegistry) Push(ctx context.Context, image *types.ImageInfo, targetTa
 }
 
 func (r *ECRRegistry) Copy(ctx context.Context, sourceImage, targetImage string) error {
+	ctx, cancel := r.withCopyTimeout(ctx)
+	defer cancel()
+
 	r.Logger.Debug("ecr_copy_start").
 		Str("source", sourceImage).
 		Str("target", targetImage).
 		Send()
 
+	if err := r.checkTargetOwnership(targetImage); err != nil {
+		return err
+	}
+
 	repositoryName := r.extractRepositoryName(targetImage)
 	if err := r.ensureRepositoryExists(ctx, repositoryName); err != nil {
 		r.Logger.Warn("ecr_repository_create_failed").
@@ -275,6 +346,10 @@ func (r *ECRRegistry) Copy(ctx context.Context, sourceImage, targetImage string)
 			Send()
 	}
 
+	if r.useOCIClient(sourceImage) {
+		return r.copyPreservingAnnotations(ctx, sourceImage, targetImage)
+	}
+
 	if err := r.Pull(ctx, sourceImage); err != nil {
 		return err
 	}
@@ -373,7 +448,25 @@ func (r *ECRRegistry) ensureRepositoryExists(ctx context.Context, repositoryName
 		Str("repository", repositoryName).
 		Send()
 
-	return nil
+	return r.ensureLifecyclePolicy(ctx, repositoryName)
+}
+
+func (r *ECRRegistry) ensureLifecyclePolicy(ctx context.Context, repositoryName string) error {
+	if r.LifecyclePolicy == "" {
+		return nil
+	}
+
+	_, err := r.ecrClient.GetLifecyclePolicy(ctx, &ecr.GetLifecyclePolicyInput{
+		RepositoryName: aws.String(repositoryName),
+	})
+	if err == nil {
+		return nil
+	}
+	if !strings.Contains(err.Error(), "LifecyclePolicyNotFound") {
+		return fmt.Errorf("falha ao consultar lifecycle policy do repositório ECR %s: %w", repositoryName, err)
+	}
+
+	return r.putLifecyclePolicy(ctx, repositoryName)
 }
 
 func (r *ECRRegistry) createRepository(ctx context.Context, repositoryName string) error {
@@ -383,6 +476,10 @@ func (r *ECRRegistry) createRepository(ctx context.Context, repositoryName strin
 
 	_, err := r.ecrClient.CreateRepository(ctx, &ecr.CreateRepositoryInput{
 		RepositoryName: aws.String(repositoryName),
+		ImageScanningConfiguration: &ecrTypes.ImageScanningConfiguration{
+			ScanOnPush: r.ScanOnPush,
+		},
+		Tags: r.buildRepositoryTags(),
 	})
 
 	if err != nil {
@@ -390,12 +487,57 @@ func (r *ECRRegistry) createRepository(ctx context.Context, repositoryName strin
 			r.Logger.Debug("ecr_repository_exists").
 				Str("repository", repositoryName).
 				Send()
-			return nil
+			return r.ensureLifecyclePolicy(ctx, repositoryName)
 		}
 		return fmt.Errorf("falha ao criar repositório ECR %s: %w", repositoryName, err)
 	}
 
 	r.Logger.Info("ecr_repository_created").
+		Str("repository", repositoryName).
+		Bool("scan_on_push", r.ScanOnPush).
+		Send()
+
+	if r.LifecyclePolicy != "" {
+		if err := r.putLifecyclePolicy(ctx, repositoryName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ECRRegistry) buildRepositoryTags() []ecrTypes.Tag {
+	if len(r.RepositoryTags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(r.RepositoryTags))
+	for key := range r.RepositoryTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := make([]ecrTypes.Tag, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, ecrTypes.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(r.RepositoryTags[key]),
+		})
+	}
+
+	return tags
+}
+
+func (r *ECRRegistry) putLifecyclePolicy(ctx context.Context, repositoryName string) error {
+	_, err := r.ecrClient.PutLifecyclePolicy(ctx, &ecr.PutLifecyclePolicyInput{
+		RepositoryName:      aws.String(repositoryName),
+		LifecyclePolicyText: aws.String(r.LifecyclePolicy),
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao aplicar lifecycle policy no repositório ECR %s: %w", repositoryName, err)
+	}
+
+	r.Logger.Info("ecr_lifecycle_policy_applied").
 		Str("repository", repositoryName).
 		Send()
 
@@ -406,6 +548,24 @@ func (r *ECRRegistry) GetRegistryURL() string {
 	return fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com", r.AccountID, r.Region)
 }
 
+func (r *ECRRegistry) ownsImage(imageName string) bool {
+	return utils.CanonicalHost(parseOCIReference(imageName).Host) == utils.CanonicalHost(r.GetRegistryURL())
+}
+
+func (r *ECRRegistry) checkTargetOwnership(targetImage string) error {
+	if r.ownsImage(targetImage) {
+		return nil
+	}
+
+	r.Logger.Error("ecr_target_not_owned").
+		Str("registry", r.Name).
+		Str("target", targetImage).
+		Str("account_id", r.AccountID).
+		Str("region", r.Region).
+		Send()
+	return fmt.Errorf("imagem de destino %s não pertence ao ECR %s (conta %s, região %s): verifique account_id/region ou as registry_rules", targetImage, r.GetRegistryURL(), r.AccountID, r.Region)
+}
+
 func (r *ECRRegistry) extractRepositoryName(imageName string) string {
 	parts := strings.Split(imageName, "/")
 	if len(parts) < 2 {
@@ -0,0 +1,344 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/kevinfinalboss/privateer/internal/logger"
+)
+
+type fakeAssumeRoleClient struct {
+	input *sts.AssumeRoleInput
+}
+
+func (c *fakeAssumeRoleClient) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	c.input = params
+	return &sts.AssumeRoleOutput{
+		Credentials: &stsTypes.Credentials{
+			AccessKeyId:     aws.String("ASSUMED"),
+			SecretAccessKey: aws.String("secret"),
+			SessionToken:    aws.String("session"),
+			Expiration:      aws.Time(time.Now().Add(time.Hour)),
+		},
+	}, nil
+}
+
+func TestECRRegistry_newAssumeRoleProvider(t *testing.T) {
+	tests := []struct {
+		name                string
+		registry            *ECRRegistry
+		expectedSessionName string
+		expectedExternalID  string
+	}{
+		{
+			name: "role with external id and session name",
+			registry: &ECRRegistry{
+				BaseRegistry: &BaseRegistry{Name: "ecr", Logger: logger.NewTest()},
+				RoleARN:      "arn:aws:iam::210987654321:role/privateer-ecr",
+				ExternalID:   "ext-123",
+				SessionName:  "ci-run",
+			},
+			expectedSessionName: "ci-run",
+			expectedExternalID:  "ext-123",
+		},
+		{
+			name: "role with default session name",
+			registry: &ECRRegistry{
+				BaseRegistry: &BaseRegistry{Name: "ecr", Logger: logger.NewTest()},
+				RoleARN:      "arn:aws:iam::210987654321:role/privateer-ecr",
+			},
+			expectedSessionName: defaultECRSessionName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeAssumeRoleClient{}
+			provider := tt.registry.newAssumeRoleProvider(client)
+
+			creds, err := provider.Retrieve(context.Background())
+			if err != nil {
+				t.Fatalf("Retrieve() unexpected error: %v", err)
+			}
+			if creds.AccessKeyID != "ASSUMED" {
+				t.Errorf("AccessKeyID = %s, expected assumed credentials", creds.AccessKeyID)
+			}
+
+			if aws.ToString(client.input.RoleArn) != tt.registry.RoleARN {
+				t.Errorf("RoleArn = %s, expected %s", aws.ToString(client.input.RoleArn), tt.registry.RoleARN)
+			}
+			if aws.ToString(client.input.RoleSessionName) != tt.expectedSessionName {
+				t.Errorf("RoleSessionName = %s, expected %s", aws.ToString(client.input.RoleSessionName), tt.expectedSessionName)
+			}
+			if aws.ToString(client.input.ExternalId) != tt.expectedExternalID {
+				t.Errorf("ExternalId = %s, expected %s", aws.ToString(client.input.ExternalId), tt.expectedExternalID)
+			}
+		})
+	}
+}
+
+const testAssumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASSUMED</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::210987654321:assumed-role/privateer-ecr/ci-run</Arn>
+      <AssumedRoleId>AROATEST:ci-run</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+func TestECRRegistry_initAWSConfig_AssumeRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		roleARN     string
+		externalID  string
+		expectedKey string
+	}{
+		{
+			name:        "role_arn and external_id assume the role",
+			roleARN:     "arn:aws:iam::210987654321:role/privateer-ecr",
+			externalID:  "ext-123",
+			expectedKey: "ASSUMED",
+		},
+		{
+			name:        "static credentials without role_arn",
+			expectedKey: "AKIASTATICKEY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				form = r.PostForm
+				w.Header().Set("Content-Type", "text/xml")
+				w.Write([]byte(testAssumeRoleResponse))
+			}))
+			defer server.Close()
+
+			dir := t.TempDir()
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+			t.Setenv("AWS_ENDPOINT_URL", server.URL)
+
+			reg := &ECRRegistry{
+				BaseRegistry: &BaseRegistry{Name: "ecr", Type: "ecr", Logger: logger.NewTest()},
+				Region:       "us-east-1",
+				AccountID:    "123456789012",
+				AccessKey:    "AKIASTATICKEY",
+				SecretKey:    "static-secret",
+				RoleARN:      tt.roleARN,
+				ExternalID:   tt.externalID,
+				SessionName:  "ci-run",
+			}
+			if err := reg.initAWSConfig(context.Background()); err != nil {
+				t.Fatalf("initAWSConfig() unexpected error: %v", err)
+			}
+
+			cache, ok := reg.awsConfig.Credentials.(*aws.CredentialsCache)
+			assumesRole := ok && cache.IsCredentialsProvider(&stscreds.AssumeRoleProvider{})
+			if assumesRole != (tt.roleARN != "") {
+				t.Fatalf("credentials provider %T assumes role = %v, expected %v", reg.awsConfig.Credentials, assumesRole, tt.roleARN != "")
+			}
+
+			creds, err := reg.awsConfig.Credentials.Retrieve(context.Background())
+			if err != nil {
+				t.Fatalf("Retrieve() unexpected error: %v", err)
+			}
+			if creds.AccessKeyID != tt.expectedKey {
+				t.Errorf("AccessKeyID = %s, expected %s", creds.AccessKeyID, tt.expectedKey)
+			}
+
+			if tt.roleARN == "" {
+				if form != nil {
+					t.Errorf("expected no STS call without role_arn, got %v", form)
+				}
+				return
+			}
+			if form.Get("Action") != "AssumeRole" || form.Get("RoleArn") != tt.roleARN {
+				t.Errorf("STS request = %v, expected AssumeRole for %s", form, tt.roleARN)
+			}
+			if form.Get("ExternalId") != tt.externalID || form.Get("RoleSessionName") != "ci-run" {
+				t.Errorf("STS request = %v, expected external id %s and session ci-run", form, tt.externalID)
+			}
+		})
+	}
+}
+
+type fakeECRClient struct {
+	existing        map[string]bool
+	policies        map[string]string
+	createInputs    []*ecr.CreateRepositoryInput
+	lifecycleInputs []*ecr.PutLifecyclePolicyInput
+}
+
+func (c *fakeECRClient) GetAuthorizationToken(ctx context.Context, params *ecr.GetAuthorizationTokenInput, optFns ...func(*ecr.Options)) (*ecr.GetAuthorizationTokenOutput, error) {
+	return &ecr.GetAuthorizationTokenOutput{}, nil
+}
+
+func (c *fakeECRClient) BatchGetImage(ctx context.Context, params *ecr.BatchGetImageInput, optFns ...func(*ecr.Options)) (*ecr.BatchGetImageOutput, error) {
+	return &ecr.BatchGetImageOutput{}, nil
+}
+
+func (c *fakeECRClient) DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	if c.existing[params.RepositoryNames[0]] {
+		return &ecr.DescribeRepositoriesOutput{}, nil
+	}
+	return nil, errors.New("RepositoryNotFoundException: repository does not exist")
+}
+
+func (c *fakeECRClient) CreateRepository(ctx context.Context, params *ecr.CreateRepositoryInput, optFns ...func(*ecr.Options)) (*ecr.CreateRepositoryOutput, error) {
+	c.createInputs = append(c.createInputs, params)
+	return &ecr.CreateRepositoryOutput{}, nil
+}
+
+func (c *fakeECRClient) GetLifecyclePolicy(ctx context.Context, params *ecr.GetLifecyclePolicyInput, optFns ...func(*ecr.Options)) (*ecr.GetLifecyclePolicyOutput, error) {
+	if policy, ok := c.policies[aws.ToString(params.RepositoryName)]; ok {
+		return &ecr.GetLifecyclePolicyOutput{LifecyclePolicyText: aws.String(policy)}, nil
+	}
+	return nil, errors.New("LifecyclePolicyNotFoundException: lifecycle policy does not exist")
+}
+
+func (c *fakeECRClient) PutLifecyclePolicy(ctx context.Context, params *ecr.PutLifecyclePolicyInput, optFns ...func(*ecr.Options)) (*ecr.PutLifecyclePolicyOutput, error) {
+	c.lifecycleInputs = append(c.lifecycleInputs, params)
+	return &ecr.PutLifecyclePolicyOutput{}, nil
+}
+
+func newTestECRRegistry(client *fakeECRClient) *ECRRegistry {
+	return &ECRRegistry{
+		BaseRegistry: &BaseRegistry{Name: "ecr", Type: "ecr", Logger: logger.NewTest()},
+		Region:       "us-east-1",
+		AccountID:    "123456789012",
+		ecrClient:    client,
+	}
+}
+
+func TestECRRegistry_createRepository_ScanOnPushAndLifecycle(t *testing.T) {
+	policy := `{"rules":[{"rulePriority":1,"selection":{"tagStatus":"untagged","countType":"imageCountMoreThan","countNumber":10},"action":{"type":"expire"}}]}`
+
+	client := &fakeECRClient{}
+	reg := newTestECRRegistry(client)
+	reg.ScanOnPush = true
+	reg.LifecyclePolicy = policy
+
+	if err := reg.ensureRepositoryExists(context.Background(), "library/nginx"); err != nil {
+		t.Fatalf("ensureRepositoryExists() unexpected error: %v", err)
+	}
+
+	if len(client.createInputs) != 1 {
+		t.Fatalf("expected 1 CreateRepository call, got %d", len(client.createInputs))
+	}
+	input := client.createInputs[0]
+	if input.ImageScanningConfiguration == nil || !input.ImageScanningConfiguration.ScanOnPush {
+		t.Errorf("expected scan on push to be enabled on create")
+	}
+
+	if len(client.lifecycleInputs) != 1 {
+		t.Fatalf("expected 1 PutLifecyclePolicy call, got %d", len(client.lifecycleInputs))
+	}
+	if aws.ToString(client.lifecycleInputs[0].RepositoryName) != "library/nginx" {
+		t.Errorf("lifecycle policy repository = %s, expected library/nginx", aws.ToString(client.lifecycleInputs[0].RepositoryName))
+	}
+	if aws.ToString(client.lifecycleInputs[0].LifecyclePolicyText) != policy {
+		t.Errorf("lifecycle policy text was not forwarded")
+	}
+}
+
+func TestECRRegistry_createRepository_WithoutLifecycle(t *testing.T) {
+	client := &fakeECRClient{existing: map[string]bool{"library/redis": true}}
+	reg := newTestECRRegistry(client)
+
+	if err := reg.ensureRepositoryExists(context.Background(), "library/redis"); err != nil {
+		t.Fatalf("ensureRepositoryExists() unexpected error: %v", err)
+	}
+	if len(client.createInputs) != 0 {
+		t.Errorf("expected existing repository not to be created")
+	}
+
+	if err := reg.ensureRepositoryExists(context.Background(), "library/nginx"); err != nil {
+		t.Fatalf("ensureRepositoryExists() unexpected error: %v", err)
+	}
+	if len(client.createInputs) != 1 || client.createInputs[0].ImageScanningConfiguration.ScanOnPush {
+		t.Errorf("expected repository to be created with scan on push disabled")
+	}
+	if len(client.lifecycleInputs) != 0 {
+		t.Errorf("expected no lifecycle policy when none is configured")
+	}
+}
+
+func TestECRRegistry_ensureRepositoryExists_ReconcilesLifecycle(t *testing.T) {
+	policy := `{"rules":[{"rulePriority":1,"selection":{"tagStatus":"any","countType":"imageCountMoreThan","countNumber":50},"action":{"type":"expire"}}]}`
+
+	client := &fakeECRClient{
+		existing: map[string]bool{"library/nginx": true, "library/redis": true},
+		policies: map[string]string{"library/redis": `{"rules":[]}`},
+	}
+	reg := newTestECRRegistry(client)
+	reg.LifecyclePolicy = policy
+
+	for _, repository := range []string{"library/nginx", "library/redis"} {
+		if err := reg.ensureRepositoryExists(context.Background(), repository); err != nil {
+			t.Fatalf("ensureRepositoryExists(%s) unexpected error: %v", repository, err)
+		}
+	}
+
+	if len(client.createInputs) != 0 {
+		t.Errorf("expected existing repositories not to be created, got %d creates", len(client.createInputs))
+	}
+	if len(client.lifecycleInputs) != 1 {
+		t.Fatalf("expected 1 PutLifecyclePolicy call, got %d", len(client.lifecycleInputs))
+	}
+	if aws.ToString(client.lifecycleInputs[0].RepositoryName) != "library/nginx" {
+		t.Errorf("lifecycle policy applied to %s, expected only library/nginx (no policy yet)", aws.ToString(client.lifecycleInputs[0].RepositoryName))
+	}
+	if aws.ToString(client.lifecycleInputs[0].LifecyclePolicyText) != policy {
+		t.Errorf("lifecycle policy text was not forwarded")
+	}
+}
+
+func TestECRRegistry_createRepository_Tags(t *testing.T) {
+	client := &fakeECRClient{}
+	reg := newTestECRRegistry(client)
+	reg.RepositoryTags = map[string]string{
+		"team":        "platform",
+		"cost-center": "1234",
+	}
+
+	if err := reg.ensureRepositoryExists(context.Background(), "library/nginx"); err != nil {
+		t.Fatalf("ensureRepositoryExists() unexpected error: %v", err)
+	}
+	if len(client.createInputs) != 1 {
+		t.Fatalf("expected 1 CreateRepository call, got %d", len(client.createInputs))
+	}
+
+	tags := client.createInputs[0].Tags
+	expected := [][2]string{{"cost-center", "1234"}, {"team", "platform"}}
+	if len(tags) != len(expected) {
+		t.Fatalf("expected %d tags, got %d", len(expected), len(tags))
+	}
+	for i, tag := range tags {
+		if aws.ToString(tag.Key) != expected[i][0] || aws.ToString(tag.Value) != expected[i][1] {
+			t.Errorf("tag[%d] = %s=%s, expected %s=%s", i, aws.ToString(tag.Key), aws.ToString(tag.Value), expected[i][0], expected[i][1])
+		}
+	}
+}
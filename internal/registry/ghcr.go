@@ -2,30 +2,51 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os/exec"
 	"strings"
+	"sync"
 
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/pkg/types"
 )
 
+const ghcrBaseURL = "https://ghcr.io"
+
+var manifestAcceptTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
 type GHCRRegistry struct {
 	*BaseRegistry
 	Organization string
 	httpClient   *http.Client
+	baseURL      string
+	tokens       map[string]string
+	tokensMutex  sync.Mutex
+}
+
+type ghcrTokenResponse struct {
+	Token string `json:"token"`
 }
 
 func NewGHCRRegistry(config *types.RegistryConfig, logger *logger.Logger) (*GHCRRegistry, error) {
 	base := &BaseRegistry{
-		Name:     config.Name,
-		Type:     "ghcr",
-		Logger:   logger,
-		Username: config.Username,
-		Password: config.Password,
-		URL:      "ghcr.io",
-		Insecure: false,
+		Name:          config.Name,
+		Type:          "ghcr",
+		Logger:        logger,
+		Username:      config.Username,
+		Password:      config.Password,
+		URL:           "ghcr.io",
+		Insecure:      false,
+		InsecureHosts: config.InsecureHosts,
+		Timeout:       config.Timeout,
+		Anonymous:     config.Anonymous,
 	}
 
 	organization := config.Username
@@ -33,16 +54,22 @@ func NewGHCRRegistry(config *types.RegistryConfig, logger *logger.Logger) (*GHCR
 		organization = config.Project
 	}
 
-	httpClient := createHTTPClient(false)
+	httpClient := createHTTPClient(false, config.Timeout)
 
 	return &GHCRRegistry{
 		BaseRegistry: base,
 		Organization: organization,
 		httpClient:   httpClient,
+		baseURL:      ghcrBaseURL,
+		tokens:       make(map[string]string),
 	}, nil
 }
 
 func (r *GHCRRegistry) Login(ctx context.Context) error {
+	if r.skipLogin() {
+		return nil
+	}
+
 	r.Logger.Debug("ghcr_login_start").
 		Str("registry", r.Name).
 		Str("organization", r.Organization).
@@ -73,8 +100,7 @@ func (r *GHCRRegistry) Pull(ctx context.Context, imageName string) error {
 		Str("image", imageName).
 		Send()
 
-	cmd := exec.CommandContext(ctx, "docker", "pull", imageName)
-	output, err := cmd.CombinedOutput()
+	output, err := r.pullWithMirrors(ctx, imageName)
 	if err != nil {
 		r.Logger.Error("ghcr_pull_failed").
 			Str("image", imageName).
@@ -128,11 +154,18 @@ func (r *GHCRRegistry) Push(ctx context.Context, image *types.ImageInfo, targetT
 }
 
 func (r *GHCRRegistry) Copy(ctx context.Context, sourceImage, targetImage string) error {
+	ctx, cancel := r.withCopyTimeout(ctx)
+	defer cancel()
+
 	r.Logger.Debug("ghcr_copy_start").
 		Str("source", sourceImage).
 		Str("target", targetImage).
 		Send()
 
+	if r.useOCIClient(sourceImage) {
+		return r.copyPreservingAnnotations(ctx, sourceImage, targetImage)
+	}
+
 	if err := r.Pull(ctx, sourceImage); err != nil {
 		return err
 	}
@@ -169,16 +202,14 @@ func (r *GHCRRegistry) Copy(ctx context.Context, sourceImage, targetImage string
 }
 
 func (r *GHCRRegistry) IsHealthy(ctx context.Context) error {
-	url := "https://ghcr.io/v2/"
+	url := r.baseURL + "/v2/"
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	if r.Username != "" && r.Password != "" {
-		req.SetBasicAuth(r.Username, r.Password)
-	}
+	r.setBasicAuth(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -194,37 +225,116 @@ func (r *GHCRRegistry) IsHealthy(ctx context.Context) error {
 }
 
 func (r *GHCRRegistry) HasImage(ctx context.Context, imageName string) (bool, error) {
-	parts := strings.Split(imageName, "/")
-	if len(parts) < 3 {
-		return false, fmt.Errorf("formato de imagem GHCR inválido: %s", imageName)
+	repositoryName, reference, err := r.parseImageReference(imageName)
+	if err != nil {
+		return false, err
 	}
 
-	repositoryName := strings.Join(parts[2:], "/")
-	if strings.Contains(repositoryName, ":") {
-		repositoryName = strings.Split(repositoryName, ":")[0]
+	token, err := r.fetchPullToken(ctx, repositoryName)
+	if err != nil {
+		return false, err
 	}
 
-	imageTag := "latest"
-	if strings.Contains(imageName, ":") {
-		imageTag = strings.Split(imageName, ":")[1]
+	url := fmt.Sprintf("%s/v2/%s/manifests/%s", r.baseURL, repositoryName, reference)
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return false, err
 	}
 
-	url := fmt.Sprintf("https://ghcr.io/v2/%s/%s/manifests/%s", r.Organization, repositoryName, imageTag)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", strings.Join(manifestAcceptTypes, ", "))
 
-	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	resp, err := r.httpClient.Do(req)
 	if err != nil {
 		return false, err
 	}
+	defer resp.Body.Close()
 
-	if r.Username != "" && r.Password != "" {
-		req.SetBasicAuth(r.Username, r.Password)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return false, fmt.Errorf("GHCR retornou status %d ao verificar %s: %w", resp.StatusCode, imageName, types.ErrRegistryUnavailable)
+	default:
+		return false, fmt.Errorf("GHCR retornou status %d ao verificar %s", resp.StatusCode, imageName)
 	}
+}
+
+func (r *GHCRRegistry) parseImageReference(imageName string) (string, string, error) {
+	parts := strings.Split(imageName, "/")
+	if len(parts) < 3 {
+		return "", "", fmt.Errorf("formato de imagem GHCR inválido: %s", imageName)
+	}
+
+	owner := parts[1]
+	repositoryPath := strings.Join(parts[2:], "/")
+	reference := "latest"
+
+	if name, digest, found := strings.Cut(repositoryPath, "@"); found {
+		repositoryPath = name
+		reference = digest
+	} else if idx := strings.LastIndex(repositoryPath, ":"); idx >= 0 {
+		reference = repositoryPath[idx+1:]
+		repositoryPath = repositoryPath[:idx]
+	}
+
+	if name, _, found := strings.Cut(repositoryPath, ":"); found {
+		repositoryPath = name
+	}
+
+	return strings.ToLower(owner + "/" + repositoryPath), reference, nil
+}
+
+func (r *GHCRRegistry) fetchPullToken(ctx context.Context, repositoryName string) (string, error) {
+	r.tokensMutex.Lock()
+	token, cached := r.tokens[repositoryName]
+	r.tokensMutex.Unlock()
+	if cached {
+		return token, nil
+	}
+
+	url := fmt.Sprintf("%s/token?scope=repository:%s:pull&service=ghcr.io", r.baseURL, repositoryName)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	r.setBasicAuth(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("falha ao obter token do GHCR: %w", err)
 	}
 	defer resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK, nil
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", fmt.Errorf("%w: GHCR recusou as credenciais para %s", types.ErrTokenUnauthorized, repositoryName)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("GHCR retornou status %d ao obter token para %s: %w", resp.StatusCode, repositoryName, types.ErrRegistryUnavailable)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GHCR retornou status %d ao obter token para %s", resp.StatusCode, repositoryName)
+	}
+
+	var tokenResp ghcrTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("falha ao decodificar token do GHCR: %w", err)
+	}
+
+	if tokenResp.Token == "" {
+		return "", fmt.Errorf("GHCR retornou token vazio para %s", repositoryName)
+	}
+
+	r.tokensMutex.Lock()
+	r.tokens[repositoryName] = tokenResp.Token
+	r.tokensMutex.Unlock()
+
+	return tokenResp.Token, nil
 }
@@ -0,0 +1,140 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func newTestGHCRServer(t *testing.T, existing map[string]bool) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("service") != "ghcr.io" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"token":"` + r.URL.Query().Get("scope") + `"}`))
+			return
+		}
+
+		repository, _, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+		if r.Method != http.MethodHead || !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer repository:"+repository+":pull" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if !existing[r.URL.Path] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestGHCRRegistry_HasImage(t *testing.T) {
+	server := newTestGHCRServer(t, map[string]bool{
+		"/v2/my-org/nginx/manifests/1.25":             true,
+		"/v2/my-user/tools/redis/manifests/7.0":       true,
+		"/v2/my-org/nginx/manifests/sha256:abc123def": true,
+	})
+	defer server.Close()
+
+	reg, err := NewGHCRRegistry(&types.RegistryConfig{
+		Name:     "ghcr",
+		Type:     "ghcr",
+		Username: "my-user",
+		Password: "token",
+		Project:  "my-org",
+	}, logger.NewTest())
+	if err != nil {
+		t.Fatalf("NewGHCRRegistry() unexpected error: %v", err)
+	}
+	reg.baseURL = server.URL
+
+	tests := []struct {
+		name     string
+		image    string
+		expected bool
+	}{
+		{"organization image exists", "ghcr.io/my-org/nginx:1.25", true},
+		{"user image with nested path exists", "ghcr.io/my-user/tools/redis:7.0", true},
+		{"digest reference exists", "ghcr.io/my-org/nginx@sha256:abc123def", true},
+		{"missing tag", "ghcr.io/my-org/nginx:9.9", false},
+		{"owner casing normalized", "ghcr.io/My-Org/nginx:1.25", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := reg.HasImage(context.Background(), tt.image)
+			if err != nil {
+				t.Fatalf("HasImage(%s) unexpected error: %v", tt.image, err)
+			}
+			if exists != tt.expected {
+				t.Errorf("HasImage(%s) = %v, expected %v", tt.image, exists, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGHCRRegistry_HasImage_InvalidFormat(t *testing.T) {
+	reg, _ := NewGHCRRegistry(&types.RegistryConfig{Name: "ghcr", Type: "ghcr"}, logger.NewTest())
+
+	if _, err := reg.HasImage(context.Background(), "ghcr.io/nginx"); err == nil {
+		t.Errorf("HasImage() expected error for image without owner")
+	}
+}
+
+func TestGHCRRegistry_HasImage_TokenUnavailable(t *testing.T) {
+	var tokenRequests atomic.Int32
+	backend := newTestGHCRServer(t, map[string]bool{"/v2/my-org/nginx/manifests/1.25": true})
+	defer backend.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" && tokenRequests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		backend.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	reg, err := NewGHCRRegistry(&types.RegistryConfig{Name: "ghcr", Type: "ghcr", Project: "my-org"}, logger.NewTest())
+	if err != nil {
+		t.Fatalf("NewGHCRRegistry() unexpected error: %v", err)
+	}
+	reg.baseURL = server.URL
+
+	if _, err := reg.HasImage(context.Background(), "ghcr.io/my-org/nginx:1.25"); !errors.Is(err, types.ErrRegistryUnavailable) {
+		t.Fatalf("HasImage() error = %v, expected ErrRegistryUnavailable for a 503 token response", err)
+	}
+
+	manager := NewManager(logger.NewTest())
+	manager.retryDelay = time.Millisecond
+	exists, err := manager.hasImageWithRetry(context.Background(), reg, "ghcr.io/my-org/nginx:1.25")
+	if err != nil || !exists {
+		t.Fatalf("hasImageWithRetry() = %v, %v, expected the image to be found once the token endpoint recovers", exists, err)
+	}
+
+	if _, err := reg.HasImage(context.Background(), "ghcr.io/my-org/nginx:9.9"); err != nil {
+		t.Fatalf("HasImage() unexpected error: %v", err)
+	}
+	if got := tokenRequests.Load(); got != 2 {
+		t.Errorf("token requests = %d, expected 2 (the token must be cached per repository)", got)
+	}
+}
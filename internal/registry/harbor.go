@@ -19,13 +19,16 @@ type HarborRegistry struct {
 
 func NewHarborRegistry(config *types.RegistryConfig, logger *logger.Logger) (*HarborRegistry, error) {
 	base := &BaseRegistry{
-		Name:     config.Name,
-		Type:     "harbor",
-		Logger:   logger,
-		Username: config.Username,
-		Password: config.Password,
-		URL:      config.URL,
-		Insecure: config.Insecure,
+		Name:          config.Name,
+		Type:          "harbor",
+		Logger:        logger,
+		Username:      config.Username,
+		Password:      config.Password,
+		URL:           config.URL,
+		Insecure:      config.Insecure,
+		InsecureHosts: config.InsecureHosts,
+		Timeout:       config.Timeout,
+		Anonymous:     config.Anonymous,
 	}
 
 	project := config.Project
@@ -33,7 +36,7 @@ func NewHarborRegistry(config *types.RegistryConfig, logger *logger.Logger) (*Ha
 		project = "library"
 	}
 
-	httpClient := createHTTPClient(config.Insecure)
+	httpClient := createHTTPClient(config.Insecure, config.Timeout)
 
 	return &HarborRegistry{
 		BaseRegistry: base,
@@ -43,6 +46,10 @@ func NewHarborRegistry(config *types.RegistryConfig, logger *logger.Logger) (*Ha
 }
 
 func (r *HarborRegistry) Login(ctx context.Context) error {
+	if r.skipLogin() {
+		return nil
+	}
+
 	r.Logger.Debug("harbor_login_start").
 		Str("registry", r.Name).
 		Str("url", r.URL).
@@ -81,8 +88,7 @@ func (r *HarborRegistry) Pull(ctx context.Context, imageName string) error {
 		Str("image", imageName).
 		Send()
 
-	cmd := exec.CommandContext(ctx, "docker", "pull", imageName)
-	output, err := cmd.CombinedOutput()
+	output, err := r.pullWithMirrors(ctx, imageName)
 	if err != nil {
 		r.Logger.Error("harbor_pull_failed").
 			Str("image", imageName).
@@ -136,11 +142,18 @@ func (r *HarborRegistry) Push(ctx context.Context, image *types.ImageInfo, targe
 }
 
 func (r *HarborRegistry) Copy(ctx context.Context, sourceImage, targetImage string) error {
+	ctx, cancel := r.withCopyTimeout(ctx)
+	defer cancel()
+
 	r.Logger.Debug("harbor_copy_start").
 		Str("source", sourceImage).
 		Str("target", targetImage).
 		Send()
 
+	if r.useOCIClient(sourceImage) {
+		return r.copyPreservingAnnotations(ctx, sourceImage, targetImage)
+	}
+
 	if err := r.Pull(ctx, sourceImage); err != nil {
 		return err
 	}
@@ -238,9 +251,7 @@ func (r *HarborRegistry) HasImage(ctx context.Context, imageName string) (bool,
 		return false, err
 	}
 
-	if r.Username != "" && r.Password != "" {
-		req.SetBasicAuth(r.Username, r.Password)
-	}
+	r.setBasicAuth(req)
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
@@ -248,5 +259,9 @@ func (r *HarborRegistry) HasImage(ctx context.Context, imageName string) (bool,
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return false, fmt.Errorf("Harbor retornou status %d ao verificar %s: %w", resp.StatusCode, imageName, types.ErrRegistryUnavailable)
+	}
+
 	return resp.StatusCode == http.StatusOK, nil
 }
@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const healthCacheTTL = 2 * time.Minute
+
+type HealthCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]time.Time
+}
+
+func NewHealthCache() *HealthCache {
+	return newHealthCache(healthCacheTTL)
+}
+
+func newHealthCache(ttl time.Duration) *HealthCache {
+	return &HealthCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]time.Time),
+	}
+}
+
+func healthCacheKey(config *types.RegistryConfig) string {
+	return strings.Join([]string{
+		config.Type,
+		config.Name,
+		config.URL,
+		config.Username,
+		config.Region,
+		config.AccountID,
+		config.RoleARN,
+		config.Path,
+		fmt.Sprintf("%t", config.Insecure),
+	}, "|")
+}
+
+func (c *HealthCache) healthy(key string) bool {
+	if key == "" || c.ttl <= 0 {
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	checkedAt, found := c.entries[key]
+	if !found {
+		return false
+	}
+	if c.now().Sub(checkedAt) > c.ttl {
+		delete(c.entries, key)
+		return false
+	}
+	return true
+}
+
+func (c *HealthCache) markHealthy(key string) {
+	if key == "" {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[key] = c.now()
+}
+
+func (c *HealthCache) invalidate(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+}
@@ -0,0 +1,64 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type ImageCreationResolver interface {
+	ImageCreated(ctx context.Context, imageName string) (time.Time, error)
+}
+
+func (r *BaseRegistry) ImageCreated(ctx context.Context, imageName string) (time.Time, error) {
+	ref := parseOCIReference(imageName)
+
+	client := newOCIClient(newTLSPolicy(false, r.InsecureHosts...))
+	if r.ownsImage(imageName) {
+		client = r.newRegistryOCIClient(ref.Host)
+	}
+
+	created, err := client.imageCreated(ctx, ref)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("falha ao obter data de criação de %s: %w", imageName, err)
+	}
+	return created, nil
+}
+
+func (c *ociClient) imageCreated(ctx context.Context, ref ociReference) (time.Time, error) {
+	manifest, err := c.fetchManifest(ctx, ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if manifest.Config == nil && len(manifest.Manifests) > 0 {
+		platformRef := ref
+		platformRef.Reference = manifest.Manifests[0].Digest
+		if manifest, err = c.fetchManifest(ctx, platformRef); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if manifest.Config == nil {
+		return time.Time{}, fmt.Errorf("manifest %s:%s sem config", ref.Repository, ref.Reference)
+	}
+
+	resp, err := c.fetchBlob(ctx, ref, manifest.Config.Digest)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	var config struct {
+		Created *time.Time `json:"created"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return time.Time{}, fmt.Errorf("falha ao decodificar config da imagem: %w", err)
+	}
+	if config.Created == nil || config.Created.IsZero() {
+		return time.Time{}, fmt.Errorf("config da imagem %s:%s não informa data de criação", ref.Repository, ref.Reference)
+	}
+
+	return *config.Created, nil
+}
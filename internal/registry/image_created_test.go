@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+)
+
+func TestBaseRegistry_ImageCreated(t *testing.T) {
+	reg, host := newTestOCIServer(t)
+	seedTestOCIImage(reg, "team/app", "1.0.0")
+
+	config := []byte(`{"architecture":"amd64","os":"linux","created":"2024-03-05T10:00:00Z"}`)
+	reg.blobs["team/dated@"+ociDigest(config)] = config
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json",`+
+		`"config":{"mediaType":"application/vnd.oci.image.config.v1+json","digest":"%s","size":%d},"layers":[]}`,
+		ociDigest(config), len(config)))
+	reg.putManifest("team/dated", "2.0.0", "application/vnd.oci.image.manifest.v1+json", manifest)
+
+	base := &BaseRegistry{Name: "local", Type: "docker", Logger: logger.NewTest(), URL: "registry.company.com"}
+
+	created, err := base.ImageCreated(context.Background(), host+"/team/dated:2.0.0")
+	if err != nil {
+		t.Fatalf("ImageCreated() unexpected error: %v", err)
+	}
+	if expected := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC); !created.Equal(expected) {
+		t.Errorf("ImageCreated() = %v, expected %v", created, expected)
+	}
+
+	if _, err := base.ImageCreated(context.Background(), host+"/team/app:1.0.0"); err == nil {
+		t.Error("expected error when image config has no created date")
+	}
+}
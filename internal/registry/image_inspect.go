@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+type ImageInspector interface {
+	InspectImage(ctx context.Context, imageName string) (digest string, sizeBytes int64, err error)
+}
+
+func (r *BaseRegistry) InspectImage(ctx context.Context, imageName string) (string, int64, error) {
+	ref := parseOCIReference(imageName)
+
+	client := newOCIClient(newTLSPolicy(false, r.InsecureHosts...))
+	if r.ownsImage(imageName) {
+		client = r.newRegistryOCIClient(ref.Host)
+	}
+
+	digest, size, err := client.inspectImage(ctx, ref)
+	if err != nil {
+		return "", 0, fmt.Errorf("falha ao inspecionar manifest de %s: %w", imageName, err)
+	}
+	return digest, size, nil
+}
+
+func (c *ociClient) inspectImage(ctx context.Context, ref ociReference) (string, int64, error) {
+	body, _, err := c.getManifest(ctx, ref)
+	if err != nil {
+		return "", 0, err
+	}
+
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return "", 0, fmt.Errorf("falha ao decodificar manifest: %w", err)
+	}
+
+	if manifest.Config == nil && len(manifest.Manifests) > 0 {
+		platformRef := ref
+		platformRef.Reference = manifest.Manifests[0].Digest
+		platformManifest, err := c.fetchManifest(ctx, platformRef)
+		if err != nil {
+			return "", 0, err
+		}
+		manifest = *platformManifest
+	}
+
+	var size int64
+	if manifest.Config != nil {
+		size += manifest.Config.Size
+	}
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+
+	return digest, size, nil
+}
@@ -0,0 +1,30 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+)
+
+func TestBaseRegistry_InspectImage(t *testing.T) {
+	reg, host := newTestOCIServer(t)
+	seeded := seedTestOCIImage(reg, "team/app", "1.0.0")
+
+	base := &BaseRegistry{Name: "local", Type: "docker", Logger: logger.NewTest(), URL: "registry.company.com"}
+
+	digest, size, err := base.InspectImage(context.Background(), host+"/team/app:1.0.0")
+	if err != nil {
+		t.Fatalf("InspectImage() unexpected error: %v", err)
+	}
+	if expected := ociDigest(seeded.index); digest != expected {
+		t.Errorf("InspectImage() digest = %s, expected %s", digest, expected)
+	}
+	if expected := int64(len(seeded.config) + len(seeded.layer)); size != expected {
+		t.Errorf("InspectImage() size = %d, expected %d", size, expected)
+	}
+
+	if _, _, err := base.InspectImage(context.Background(), host+"/team/missing:1.0.0"); err == nil {
+		t.Error("expected error when manifest does not exist")
+	}
+}
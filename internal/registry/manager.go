@@ -3,15 +3,34 @@ package registry
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os/exec"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/kevinfinalboss/privateer/internal/httpreplay"
 	"github.com/kevinfinalboss/privateer/internal/logger"
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+)
+
+const (
+	defaultHTTPTimeout    = 30 * time.Second
+	maxIdleConns          = 100
+	maxIdleConnsPerHost   = 10
+	idleConnTimeout       = 90 * time.Second
+	dialTimeout           = 10 * time.Second
+	dialKeepAlive         = 30 * time.Second
+	tlsHandshakeTimeout   = 10 * time.Second
+	expectContinueTimeout = 1 * time.Second
+	validationMaxAttempts = 3
+	validationRetryDelay  = 500 * time.Millisecond
 )
 
 type Registry interface {
@@ -26,13 +45,26 @@ type Registry interface {
 }
 
 type BaseRegistry struct {
-	Name     string
-	Type     string
-	Logger   *logger.Logger
-	Username string
-	Password string
-	URL      string
-	Insecure bool
+	Name                string
+	Type                string
+	Logger              *logger.Logger
+	Username            string
+	Password            string
+	URL                 string
+	Insecure            bool
+	InsecureHosts       []string
+	Timeout             time.Duration
+	PullMirrors         map[string][]string
+	PreserveAnnotations bool
+	Anonymous           bool
+	copySource          *BaseRegistry
+	peers               func() []*BaseRegistry
+	bandwidth           *bandwidthLimiter
+	runCommand          func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+func (r *BaseRegistry) base() *BaseRegistry {
+	return r
 }
 
 func (r *BaseRegistry) GetType() string {
@@ -43,19 +75,131 @@ func (r *BaseRegistry) GetName() string {
 	return r.Name
 }
 
+type settingsAware interface {
+	applySettings(settings *types.SettingsConfig)
+}
+
+func (r *BaseRegistry) applySettings(settings *types.SettingsConfig) {
+	r.PullMirrors = settings.PullMirrors
+	r.PreserveAnnotations = settings.PreserveAnnotations
+}
+
+type baseProvider interface {
+	base() *BaseRegistry
+}
+
+type digestResolver interface {
+	ownsImage(imageName string) bool
+	resolveDigest(ctx context.Context, imageName string) (string, error)
+}
+
+func (r *BaseRegistry) skipLogin() bool {
+	if !r.Anonymous {
+		return false
+	}
+
+	r.Logger.Debug("registry_login_skipped").
+		Str("registry", r.Name).
+		Str("reason", "anonymous").
+		Send()
+
+	return true
+}
+
+func (r *BaseRegistry) setBasicAuth(req *http.Request) {
+	if r.Anonymous || r.Username == "" || r.Password == "" {
+		return
+	}
+	req.SetBasicAuth(r.Username, r.Password)
+}
+
+func (r *BaseRegistry) withCopyTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.Timeout > 0 {
+		return context.WithTimeout(ctx, r.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
 type Manager struct {
-	registries map[string]Registry
-	logger     *logger.Logger
-	mutex      sync.RWMutex
+	registries  map[string]Registry
+	healthKeys  map[string]string
+	healthCache *HealthCache
+	logger      *logger.Logger
+	mutex       sync.RWMutex
+	settings    *types.SettingsConfig
+	bandwidth   *bandwidthLimiter
+	retryDelay  time.Duration
 }
 
 func NewManager(logger *logger.Logger) *Manager {
 	return &Manager{
-		registries: make(map[string]Registry),
-		logger:     logger,
+		registries:  make(map[string]Registry),
+		healthKeys:  make(map[string]string),
+		healthCache: NewHealthCache(),
+		logger:      logger,
+		retryDelay:  validationRetryDelay,
+	}
+}
+
+func (m *Manager) UseHealthCache(cache *HealthCache) *Manager {
+	if cache != nil {
+		m.healthCache = cache
+	}
+	return m
+}
+
+func (m *Manager) ApplySettings(settings *types.SettingsConfig) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.settings = settings
+	m.bandwidth = newBandwidthLimiter(settings.MaxBandwidth)
+	if m.bandwidth != nil && !settings.PreserveAnnotations {
+		m.logger.Warn("max_bandwidth_requires_library_backend").
+			Int64("max_bandwidth", settings.MaxBandwidth).
+			Str("message", "O limite de banda só se aplica às cópias via cliente OCI (preserve_annotations) e aos layouts OCI; cópias via docker CLI não são limitadas").
+			Send()
+	}
+
+	for _, registry := range m.registries {
+		if aware, ok := registry.(settingsAware); ok {
+			aware.applySettings(settings)
+		}
+		m.attachBandwidth(registry)
 	}
 }
 
+func (m *Manager) attachBandwidth(registry Registry) {
+	if provider, ok := registry.(baseProvider); ok {
+		provider.base().bandwidth = m.bandwidth
+	}
+}
+
+func (m *Manager) attachPeers(registry Registry) {
+	if provider, ok := registry.(baseProvider); ok {
+		provider.base().peers = m.registryBases
+	}
+}
+
+func (m *Manager) registryBases() []*BaseRegistry {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	names := make([]string, 0, len(m.registries))
+	for name := range m.registries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var bases []*BaseRegistry
+	for _, name := range names {
+		if provider, ok := m.registries[name].(baseProvider); ok {
+			bases = append(bases, provider.base())
+		}
+	}
+	return bases
+}
+
 func (m *Manager) AddRegistry(config *types.RegistryConfig) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -79,6 +223,8 @@ func (m *Manager) AddRegistry(config *types.RegistryConfig) error {
 		registry, err = NewECRRegistry(config, m.logger)
 	case "ghcr":
 		registry, err = NewGHCRRegistry(config, m.logger)
+	case "oci-layout":
+		registry, err = NewOCILayoutRegistry(config, m.logger)
 	default:
 		return fmt.Errorf("tipo de registry não suportado: %s", config.Type)
 	}
@@ -87,7 +233,14 @@ func (m *Manager) AddRegistry(config *types.RegistryConfig) error {
 		return fmt.Errorf("falha ao criar registry %s: %w", config.Name, err)
 	}
 
+	if aware, ok := registry.(settingsAware); ok && m.settings != nil {
+		aware.applySettings(m.settings)
+	}
+	m.attachBandwidth(registry)
+	m.attachPeers(registry)
+
 	m.registries[config.Name] = registry
+	m.healthKeys[config.Name] = healthCacheKey(config)
 
 	m.logger.Info("registry_added").
 		Str("name", config.Name).
@@ -97,13 +250,46 @@ func (m *Manager) AddRegistry(config *types.RegistryConfig) error {
 	return nil
 }
 
+func (m *Manager) SetCopySource(targetName, sourceName string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if targetName == sourceName {
+		return fmt.Errorf("registry de origem e destino são o mesmo: %s", targetName)
+	}
+
+	target, exists := m.registries[targetName]
+	if !exists {
+		return fmt.Errorf("%w: %s", types.ErrRegistryNotFound, targetName)
+	}
+	source, exists := m.registries[sourceName]
+	if !exists {
+		return fmt.Errorf("%w: %s", types.ErrRegistryNotFound, sourceName)
+	}
+
+	targetBase, targetOK := target.(baseProvider)
+	sourceBase, sourceOK := source.(baseProvider)
+	if !targetOK || !sourceOK {
+		return fmt.Errorf("cópia entre %s e %s não suportada", sourceName, targetName)
+	}
+
+	targetBase.base().copySource = sourceBase.base()
+
+	m.logger.Info("registry_copy_source_set").
+		Str("source", sourceName).
+		Str("target", targetName).
+		Send()
+
+	return nil
+}
+
 func (m *Manager) GetRegistry(name string) (Registry, error) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
 	registry, exists := m.registries[name]
 	if !exists {
-		return nil, fmt.Errorf("registry %s não encontrado", name)
+		return nil, fmt.Errorf("%w: %s", types.ErrRegistryNotFound, name)
 	}
 
 	return registry, nil
@@ -134,12 +320,40 @@ func (m *Manager) GetEnabledRegistries() []Registry {
 }
 
 func (m *Manager) HealthCheck(ctx context.Context) error {
+	failures := m.CheckHealth(ctx)
+	if len(failures) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("registry %s: %w", name, failures[name]))
+	}
+
+	return fmt.Errorf("falhas no health check: %v", errs)
+}
+
+func (m *Manager) CheckHealth(ctx context.Context) map[string]error {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	var errors []error
+	failures := make(map[string]error)
 
 	for name, registry := range m.registries {
+		key := m.healthKeys[name]
+		if m.healthCache.healthy(key) {
+			m.logger.Debug("registry_health_check_cached").
+				Str("name", name).
+				Send()
+			continue
+		}
+
 		m.logger.Debug("registry_health_check").
 			Str("name", name).
 			Send()
@@ -149,19 +363,17 @@ func (m *Manager) HealthCheck(ctx context.Context) error {
 				Str("name", name).
 				Err(err).
 				Send()
-			errors = append(errors, fmt.Errorf("registry %s: %w", name, err))
+			m.healthCache.invalidate(key)
+			failures[name] = err
 		} else {
 			m.logger.Info("registry_health_check_success").
 				Str("name", name).
 				Send()
+			m.healthCache.markHealthy(key)
 		}
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("falhas no health check: %v", errors)
-	}
-
-	return nil
+	return failures
 }
 
 func (m *Manager) CheckImageExists(ctx context.Context, imageName string) (map[string]bool, error) {
@@ -218,16 +430,21 @@ func (m *Manager) ValidateImageDuplication(ctx context.Context, targetImage stri
 	return nil
 }
 
-func (m *Manager) ValidateImagesBatch(ctx context.Context, images []*types.ImageInfo, config *types.Config) (map[string]string, error) {
+func (m *Manager) ValidateImagesBatch(ctx context.Context, images []*types.ImageInfo, config *types.Config) (map[string]string, map[string]error, error) {
 	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+	registries := make([]Registry, 0, len(m.registries))
+	for _, registry := range m.registries {
+		registries = append(registries, registry)
+	}
+	m.mutex.RUnlock()
 
 	m.logger.Info("validating_images_batch").
 		Int("images", len(images)).
-		Int("registries", len(m.registries)).
+		Int("registries", len(registries)).
 		Send()
 
 	validatedMap := make(map[string]string)
+	validationErrors := make(map[string]error)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
@@ -241,7 +458,8 @@ func (m *Manager) ValidateImagesBatch(ctx context.Context, images []*types.Image
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			for _, registry := range m.registries {
+			var checkErr error
+			for _, registry := range registries {
 				targetImage := m.generateTargetImageName(img, registry, config)
 
 				m.logger.Debug("batch_validating_image").
@@ -250,13 +468,14 @@ func (m *Manager) ValidateImagesBatch(ctx context.Context, images []*types.Image
 					Str("registry", registry.GetName()).
 					Send()
 
-				exists, err := registry.HasImage(ctx, targetImage)
+				exists, err := m.hasImageWithRetry(ctx, registry, targetImage)
 				if err != nil {
 					m.logger.Warn("batch_validation_failed").
 						Str("image", targetImage).
 						Str("registry", registry.GetName()).
 						Err(err).
 						Send()
+					checkErr = fmt.Errorf("falha ao verificar %s no registry %s: %w", targetImage, registry.GetName(), err)
 					continue
 				}
 
@@ -274,6 +493,13 @@ func (m *Manager) ValidateImagesBatch(ctx context.Context, images []*types.Image
 				}
 			}
 
+			if checkErr != nil {
+				mu.Lock()
+				validationErrors[img.Image] = checkErr
+				mu.Unlock()
+				return
+			}
+
 			m.logger.Debug("batch_image_not_found").
 				Str("public_image", img.Image).
 				Send()
@@ -284,10 +510,53 @@ func (m *Manager) ValidateImagesBatch(ctx context.Context, images []*types.Image
 
 	m.logger.Info("batch_validation_completed").
 		Int("validated", len(validatedMap)).
+		Int("unverified", len(validationErrors)).
 		Int("total", len(images)).
 		Send()
 
-	return validatedMap, nil
+	return validatedMap, validationErrors, nil
+}
+
+func (m *Manager) hasImageWithRetry(ctx context.Context, registry Registry, imageName string) (bool, error) {
+	var lastErr error
+	for attempt := 0; attempt < validationMaxAttempts; attempt++ {
+		if attempt > 0 {
+			m.logger.Debug("batch_validation_retry").
+				Str("image", imageName).
+				Str("registry", registry.GetName()).
+				Int("attempt", attempt+1).
+				Send()
+
+			select {
+			case <-time.After(m.retryDelay * time.Duration(attempt)):
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+		}
+
+		exists, err := registry.HasImage(ctx, imageName)
+		if err == nil {
+			return exists, nil
+		}
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if !isTransientRegistryError(err) {
+			return false, err
+		}
+		lastErr = err
+	}
+
+	return false, fmt.Errorf("falha após %d tentativas: %w", validationMaxAttempts, lastErr)
+}
+
+func isTransientRegistryError(err error) bool {
+	if errors.Is(err, types.ErrRegistryUnavailable) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
 }
 
 func (m *Manager) FindImageInRegistries(ctx context.Context, publicImage *types.ImageInfo, config *types.Config) (string, string, error) {
@@ -324,82 +593,67 @@ func (m *Manager) FindImageInRegistries(ctx context.Context, publicImage *types.
 	return "", "", fmt.Errorf("imagem %s não encontrada em nenhum registry", publicImage.Image)
 }
 
-func (m *Manager) generateTargetImageName(image *types.ImageInfo, reg Registry, config *types.Config) string {
-	parsed := types.ParseImageName(image.Image)
-	targetRepository := parsed.FullRepository
-	targetTag := parsed.Tag
-
-	if parsed.Digest != "" {
-		targetTag = fmt.Sprintf("%s@%s", targetTag, parsed.Digest)
+func (m *Manager) ResolveDigest(ctx context.Context, imageName string) (string, error) {
+	m.mutex.RLock()
+	var resolver digestResolver
+	for _, registry := range m.registries {
+		if candidate, ok := registry.(digestResolver); ok && candidate.ownsImage(imageName) {
+			resolver = candidate
+			break
+		}
 	}
+	m.mutex.RUnlock()
 
-	switch reg.GetType() {
-	case "docker":
-		registryURL := m.getRegistryURL(reg.GetName(), config)
-		return fmt.Sprintf("%s/%s:%s", registryURL, targetRepository, targetTag)
-
-	case "harbor":
-		registryURL := m.getRegistryURL(reg.GetName(), config)
-		project := m.getHarborProject(reg.GetName(), config)
-		return fmt.Sprintf("%s/%s/%s:%s", registryURL, project, targetRepository, targetTag)
-
-	case "ecr":
-		ecrURL := m.getECRURL(reg.GetName(), config)
-		return fmt.Sprintf("%s/%s:%s", ecrURL, targetRepository, targetTag)
-
-	case "ghcr":
-		organization := m.getGHCROrganization(reg.GetName(), config)
-		return fmt.Sprintf("ghcr.io/%s/%s:%s", organization, targetRepository, targetTag)
+	if resolver == nil {
+		return "", fmt.Errorf("nenhum registry configurado para a imagem %s", imageName)
 	}
 
-	return fmt.Sprintf("%s/%s:%s", reg.GetName(), targetRepository, targetTag)
+	return resolver.resolveDigest(ctx, imageName)
 }
 
-func (m *Manager) getRegistryURL(registryName string, config *types.Config) string {
-	for _, regConfig := range config.Registries {
-		if regConfig.Name == registryName {
-			url := regConfig.URL
-			if strings.HasPrefix(url, "http://") {
-				url = strings.TrimPrefix(url, "http://")
-			} else if strings.HasPrefix(url, "https://") {
-				url = strings.TrimPrefix(url, "https://")
-			}
-			return url
-		}
-	}
-	return registryName
+func (m *Manager) generateTargetImageName(image *types.ImageInfo, reg Registry, config *types.Config) string {
+	parsed := utils.ParseImageName(image.Image)
+	return fmt.Sprintf("%s/%s%s", TargetPrefixFor(reg, config), parsed.FullRepository, parsed.Reference())
 }
 
-func (m *Manager) getHarborProject(registryName string, config *types.Config) string {
+func TargetPrefixFor(reg Registry, config *types.Config) string {
 	for _, regConfig := range config.Registries {
-		if regConfig.Name == registryName && regConfig.Project != "" {
-			return regConfig.Project
+		if regConfig.Name == reg.GetName() {
+			regConfig.Type = reg.GetType()
+			return TargetPrefix(&regConfig)
 		}
 	}
-	return "library"
+	return reg.GetName()
 }
 
-func (m *Manager) getECRURL(registryName string, config *types.Config) string {
-	for _, regConfig := range config.Registries {
-		if regConfig.Name == registryName && regConfig.Type == "ecr" {
-			if regConfig.AccountID != "" {
-				return fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com", regConfig.AccountID, regConfig.Region)
-			}
-		}
-	}
-	return registryName
-}
+func TargetPrefix(config *types.RegistryConfig) string {
+	url := strings.TrimPrefix(strings.TrimPrefix(config.URL, "http://"), "https://")
 
-func (m *Manager) getGHCROrganization(registryName string, config *types.Config) string {
-	for _, regConfig := range config.Registries {
-		if regConfig.Name == registryName {
-			if regConfig.Project != "" {
-				return regConfig.Project
-			}
-			return regConfig.Username
+	switch config.Type {
+	case "docker":
+		if config.Project != "" {
+			return fmt.Sprintf("%s/%s", url, config.Project)
 		}
+		return url
+	case "harbor":
+		project := config.Project
+		if project == "" {
+			project = "library"
+		}
+		return fmt.Sprintf("%s/%s", url, project)
+	case "ecr":
+		if config.AccountID != "" {
+			return fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com", config.AccountID, config.Region)
+		}
+	case "ghcr":
+		organization := config.Project
+		if organization == "" {
+			organization = config.Username
+		}
+		return fmt.Sprintf("ghcr.io/%s", organization)
 	}
-	return "unknown"
+
+	return config.Name
 }
 
 func (m *Manager) GetRegistryCount() int {
@@ -408,13 +662,34 @@ func (m *Manager) GetRegistryCount() int {
 	return len(m.registries)
 }
 
-func createHTTPClient(insecure bool) *http.Client {
+func createHTTPClient(insecure bool, timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
 	return &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: insecure,
-			},
+		Timeout:   timeout,
+		Transport: httpreplay.Wrap(newHTTPTransport(insecure)),
+	}
+}
+
+func newHTTPTransport(insecure bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: dialKeepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: expectContinueTimeout,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: insecure,
 		},
 	}
 }
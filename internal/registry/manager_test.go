@@ -0,0 +1,418 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestRegistryTimeout_HTTPClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *types.RegistryConfig
+		expected time.Duration
+	}{
+		{
+			name:     "default timeout",
+			config:   &types.RegistryConfig{Name: "docker", Type: "docker", URL: "registry.example.com"},
+			expected: defaultHTTPTimeout,
+		},
+		{
+			name:     "configured timeout",
+			config:   &types.RegistryConfig{Name: "docker", Type: "docker", URL: "registry.example.com", Timeout: 90 * time.Second},
+			expected: 90 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docker, err := NewDockerRegistry(tt.config, logger.NewTest())
+			if err != nil {
+				t.Fatalf("NewDockerRegistry() unexpected error: %v", err)
+			}
+			if docker.httpClient.Timeout != tt.expected {
+				t.Errorf("docker http timeout = %v, expected %v", docker.httpClient.Timeout, tt.expected)
+			}
+
+			harbor, err := NewHarborRegistry(tt.config, logger.NewTest())
+			if err != nil {
+				t.Fatalf("NewHarborRegistry() unexpected error: %v", err)
+			}
+			if harbor.httpClient.Timeout != tt.expected {
+				t.Errorf("harbor http timeout = %v, expected %v", harbor.httpClient.Timeout, tt.expected)
+			}
+
+			ghcr, err := NewGHCRRegistry(tt.config, logger.NewTest())
+			if err != nil {
+				t.Fatalf("NewGHCRRegistry() unexpected error: %v", err)
+			}
+			if ghcr.httpClient.Timeout != tt.expected {
+				t.Errorf("ghcr http timeout = %v, expected %v", ghcr.httpClient.Timeout, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBaseRegistry_withCopyTimeout(t *testing.T) {
+	r := &BaseRegistry{Timeout: 2 * time.Minute}
+
+	ctx, cancel := r.withCopyTimeout(context.Background())
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatalf("expected copy context to have a deadline")
+	}
+	if remaining := time.Until(deadline); remaining > 2*time.Minute || remaining < time.Minute {
+		t.Errorf("copy deadline in %v, expected about 2m", remaining)
+	}
+
+	unbounded := &BaseRegistry{}
+	ctx, cancel = unbounded.withCopyTimeout(context.Background())
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("expected no deadline when timeout is not configured")
+	}
+}
+
+func TestCreateHTTPClient_TransportTuning(t *testing.T) {
+	client := createHTTPClient(true, 0)
+
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.Transport)
+	}
+
+	if transport.MaxIdleConns != maxIdleConns {
+		t.Errorf("MaxIdleConns = %d, expected %d", transport.MaxIdleConns, maxIdleConns)
+	}
+	if transport.MaxIdleConnsPerHost != maxIdleConnsPerHost {
+		t.Errorf("MaxIdleConnsPerHost = %d, expected %d", transport.MaxIdleConnsPerHost, maxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != idleConnTimeout {
+		t.Errorf("IdleConnTimeout = %v, expected %v", transport.IdleConnTimeout, idleConnTimeout)
+	}
+	if transport.TLSHandshakeTimeout != tlsHandshakeTimeout {
+		t.Errorf("TLSHandshakeTimeout = %v, expected %v", transport.TLSHandshakeTimeout, tlsHandshakeTimeout)
+	}
+	if transport.DialContext == nil || transport.Proxy == nil {
+		t.Errorf("expected dialer and proxy to be configured")
+	}
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("expected insecure flag to be preserved")
+	}
+}
+
+type flakyRegistry struct {
+	BaseRegistry
+	mu        sync.Mutex
+	failures  map[string]int
+	permanent map[string]error
+	images    map[string]bool
+	calls     map[string]int
+}
+
+func (r *flakyRegistry) Login(ctx context.Context) error { return nil }
+func (r *flakyRegistry) Push(ctx context.Context, image *types.ImageInfo, targetTag string) error {
+	return nil
+}
+func (r *flakyRegistry) Pull(ctx context.Context, imageName string) error { return nil }
+func (r *flakyRegistry) Copy(ctx context.Context, sourceImage, targetImage string) error {
+	return nil
+}
+func (r *flakyRegistry) IsHealthy(ctx context.Context) error { return nil }
+
+func (r *flakyRegistry) HasImage(ctx context.Context, imageName string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls[imageName]++
+	if err := r.permanent[imageName]; err != nil {
+		return false, err
+	}
+	if r.failures[imageName] > 0 {
+		r.failures[imageName]--
+		return false, fmt.Errorf("registry retornou status 503 ao verificar %s: %w", imageName, types.ErrRegistryUnavailable)
+	}
+	return r.images[imageName], nil
+}
+
+func TestManager_ValidateImagesBatch_Retry(t *testing.T) {
+	flaky := &flakyRegistry{
+		BaseRegistry: BaseRegistry{Name: "flaky", Type: "fake"},
+		failures: map[string]int{
+			"flaky/library/nginx:1.25":    validationMaxAttempts - 1,
+			"flaky/library/redis:7.0":     validationMaxAttempts,
+			"flaky/library/busybox:1.36":  0,
+			"flaky/library/postgres:16.0": 0,
+		},
+		permanent: map[string]error{
+			"flaky/library/alpine:3.19": errors.New("GHCR retornou status 404 ao verificar flaky/library/alpine:3.19"),
+		},
+		images: map[string]bool{
+			"flaky/library/nginx:1.25":    true,
+			"flaky/library/redis:7.0":     true,
+			"flaky/library/postgres:16.0": true,
+		},
+		calls: make(map[string]int),
+	}
+
+	manager := NewManager(logger.NewTest())
+	manager.retryDelay = time.Millisecond
+	manager.registries["flaky"] = flaky
+
+	validated, validationErrors, err := manager.ValidateImagesBatch(context.Background(), []*types.ImageInfo{
+		{Image: "nginx:1.25"},
+		{Image: "redis:7.0"},
+		{Image: "busybox:1.36"},
+		{Image: "postgres:16.0"},
+		{Image: "alpine:3.19"},
+	}, &types.Config{})
+	if err != nil {
+		t.Fatalf("ValidateImagesBatch() unexpected error: %v", err)
+	}
+
+	expectedValidated := map[string]string{
+		"nginx:1.25":    "flaky/library/nginx:1.25",
+		"postgres:16.0": "flaky/library/postgres:16.0",
+	}
+	if !reflect.DeepEqual(validated, expectedValidated) {
+		t.Errorf("validated = %v, expected %v", validated, expectedValidated)
+	}
+
+	if len(validationErrors) != 2 || validationErrors["redis:7.0"] == nil || validationErrors["alpine:3.19"] == nil {
+		t.Fatalf("validation errors = %v, expected redis:7.0 and alpine:3.19", validationErrors)
+	}
+	if !strings.Contains(validationErrors["redis:7.0"].Error(), "status 503") {
+		t.Errorf("validation error should wrap the registry error, got %v", validationErrors["redis:7.0"])
+	}
+	if _, missing := validationErrors["busybox:1.36"]; missing {
+		t.Error("an image that is simply absent must not be reported as a validation error")
+	}
+
+	expectedCalls := map[string]int{
+		"flaky/library/nginx:1.25":    validationMaxAttempts,
+		"flaky/library/redis:7.0":     validationMaxAttempts,
+		"flaky/library/busybox:1.36":  1,
+		"flaky/library/postgres:16.0": 1,
+		"flaky/library/alpine:3.19":   1,
+	}
+	if !reflect.DeepEqual(flaky.calls, expectedCalls) {
+		t.Errorf("HasImage calls = %v, expected %v", flaky.calls, expectedCalls)
+	}
+}
+
+func TestManager_GetRegistry_NotFound(t *testing.T) {
+	manager := NewManager(logger.NewTest())
+
+	_, err := manager.GetRegistry("missing-registry")
+	if !errors.Is(err, types.ErrRegistryNotFound) {
+		t.Fatalf("error = %v, expected errors.Is(types.ErrRegistryNotFound)", err)
+	}
+	if !strings.Contains(err.Error(), "missing-registry") {
+		t.Errorf("error %q should name the missing registry", err)
+	}
+}
+
+func TestManager_CheckHealth_ReportsUnhealthyRegistries(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+
+	manager := NewManager(logger.NewTest())
+	for _, config := range []types.RegistryConfig{
+		{Name: "healthy", Type: "docker", URL: healthy.URL, Enabled: true},
+		{Name: "down", Type: "docker", URL: unhealthy.URL, Enabled: true},
+	} {
+		if err := manager.AddRegistry(&config); err != nil {
+			t.Fatalf("AddRegistry(%s) unexpected error: %v", config.Name, err)
+		}
+	}
+
+	failures := manager.CheckHealth(context.Background())
+	if len(failures) != 1 || failures["down"] == nil {
+		t.Fatalf("CheckHealth() = %v, expected only the down registry", failures)
+	}
+	if !strings.Contains(failures["down"].Error(), "503") {
+		t.Errorf("failure = %v, expected the registry status", failures["down"])
+	}
+
+	if err := manager.HealthCheck(context.Background()); err == nil || !strings.Contains(err.Error(), "registry down") {
+		t.Errorf("HealthCheck() = %v, expected error naming the down registry", err)
+	}
+}
+
+func TestManager_SetCopySource_PrivateToPrivateCopy(t *testing.T) {
+	sourceReg := newTestOCIRegistry()
+	seeded := seedTestOCIImage(sourceReg, "legacy/app", "1.0.0")
+	sourceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "old-user" || pass != "old-pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="old-harbor"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		sourceReg.handler(func() string { return "" }).ServeHTTP(w, r)
+	}))
+	defer sourceServer.Close()
+	sourceHost := strings.TrimPrefix(sourceServer.URL, "http://")
+
+	targetReg, targetHost := newTestOCIServer(t)
+
+	manager := NewManager(logger.NewTest())
+	manager.ApplySettings(&types.SettingsConfig{PreserveAnnotations: true})
+	for _, config := range []*types.RegistryConfig{
+		{Name: "old-harbor", Type: "docker", URL: sourceHost, Username: "old-user", Password: "old-pass", Enabled: true},
+		{Name: "new-harbor", Type: "docker", URL: targetHost, Enabled: true},
+	} {
+		if err := manager.AddRegistry(config); err != nil {
+			t.Fatalf("AddRegistry(%s) unexpected error: %v", config.Name, err)
+		}
+	}
+
+	target, err := manager.GetRegistry("new-harbor")
+	if err != nil {
+		t.Fatalf("GetRegistry() unexpected error: %v", err)
+	}
+
+	sourceImage := sourceHost + "/legacy/app:1.0.0"
+	targetImage := targetHost + "/legacy/app:1.0.0"
+	if err := target.Copy(context.Background(), sourceImage, targetImage); err != nil {
+		t.Fatalf("Copy() with credentials from the configured source registry unexpected error: %v", err)
+	}
+
+	if err := manager.SetCopySource("new-harbor", "old-harbor"); err != nil {
+		t.Fatalf("SetCopySource() unexpected error: %v", err)
+	}
+	if err := target.Copy(context.Background(), sourceImage, targetImage); err != nil {
+		t.Fatalf("Copy() unexpected error: %v", err)
+	}
+
+	if got := targetReg.manifests["legacy/app@1.0.0"]; string(got) != string(seeded.index) {
+		t.Errorf("target index = %s, expected %s", got, seeded.index)
+	}
+	if string(targetReg.blobs["legacy/app@"+ociDigest(seeded.layer)]) != string(seeded.layer) {
+		t.Errorf("layer blob was not copied to target")
+	}
+
+	if err := manager.SetCopySource("new-harbor", "new-harbor"); err == nil {
+		t.Error("expected error when source and target are the same registry")
+	}
+	if err := manager.SetCopySource("new-harbor", "missing"); !errors.Is(err, types.ErrRegistryNotFound) {
+		t.Errorf("error = %v, expected errors.Is(types.ErrRegistryNotFound)", err)
+	}
+}
+
+func TestManager_CheckHealth_CachedWithinTTL(t *testing.T) {
+	var mu sync.Mutex
+	pings := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		pings++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	cache := newHealthCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	newManager := func() *Manager {
+		manager := NewManager(logger.NewTest()).UseHealthCache(cache)
+		if err := manager.AddRegistry(&types.RegistryConfig{Name: "harbor", Type: "docker", URL: server.URL, Enabled: true}); err != nil {
+			t.Fatalf("AddRegistry() unexpected error: %v", err)
+		}
+		return manager
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := newManager().HealthCheck(context.Background()); err != nil {
+			t.Fatalf("HealthCheck() unexpected error: %v", err)
+		}
+	}
+	mu.Lock()
+	if pings != 1 {
+		t.Errorf("registry pinged %d times, expected the second check to be served from cache", pings)
+	}
+	mu.Unlock()
+
+	now = now.Add(2 * time.Minute)
+	if err := newManager().HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck() unexpected error: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if pings != 2 {
+		t.Errorf("registry pinged %d times, expected a new check after the TTL", pings)
+	}
+}
+
+func TestManager_CheckHealth_CacheNotSharedBetweenRuns(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		manager := NewManager(logger.NewTest())
+		if err := manager.AddRegistry(&types.RegistryConfig{Name: "harbor", Type: "docker", URL: server.URL, Enabled: true}); err != nil {
+			t.Fatalf("AddRegistry() unexpected error: %v", err)
+		}
+		if err := manager.HealthCheck(context.Background()); err != nil {
+			t.Fatalf("HealthCheck() unexpected error: %v", err)
+		}
+	}
+
+	if got := pings.Load(); got != 2 {
+		t.Errorf("registry pinged %d times, expected managers without a shared cache to check independently", got)
+	}
+}
+
+func TestTargetPrefixFor(t *testing.T) {
+	config := &types.Config{Registries: []types.RegistryConfig{
+		{Name: "docker-prod", URL: "https://registry.company.com", Project: "mirror"},
+		{Name: "harbor-prod", URL: "https://harbor.company.com"},
+		{Name: "ecr-prod", AccountID: "123456789012", Region: "us-east-1"},
+		{Name: "ghcr-prod", Username: "my-user"},
+	}}
+
+	tests := []struct {
+		name     string
+		regType  string
+		expected string
+	}{
+		{"docker-prod", "docker", "registry.company.com/mirror"},
+		{"harbor-prod", "harbor", "harbor.company.com/library"},
+		{"ecr-prod", "ecr", "123456789012.dkr.ecr.us-east-1.amazonaws.com"},
+		{"ghcr-prod", "ghcr", "ghcr.io/my-user"},
+		{"unknown", "docker", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := &flakyRegistry{BaseRegistry: BaseRegistry{Name: tt.name, Type: tt.regType}}
+			if got := TargetPrefixFor(reg, config); got != tt.expected {
+				t.Errorf("TargetPrefixFor(%s) = %s, expected %s", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+)
+
+func (r *BaseRegistry) runDocker(ctx context.Context, args ...string) ([]byte, error) {
+	if r.runCommand != nil {
+		return r.runCommand(ctx, args...)
+	}
+	return exec.CommandContext(ctx, "docker", args...).CombinedOutput()
+}
+
+func (r *BaseRegistry) pullWithMirrors(ctx context.Context, imageName string) ([]byte, error) {
+	for _, mirrorImage := range r.mirrorCandidates(imageName) {
+		r.Logger.Debug("image_pull_from_mirror").
+			Str("image", imageName).
+			Str("mirror_image", mirrorImage).
+			Send()
+
+		if output, err := r.runDocker(ctx, "pull", mirrorImage); err != nil {
+			r.Logger.Warn("image_pull_mirror_failed").
+				Str("mirror_image", mirrorImage).
+				Str("output", string(output)).
+				Err(err).
+				Send()
+			continue
+		}
+
+		if output, err := r.runDocker(ctx, "tag", mirrorImage, imageName); err != nil {
+			r.Logger.Warn("image_pull_mirror_tag_failed").
+				Str("mirror_image", mirrorImage).
+				Str("output", string(output)).
+				Err(err).
+				Send()
+			continue
+		}
+
+		r.Logger.Info("image_pulled_from_mirror").
+			Str("image", imageName).
+			Str("mirror_image", mirrorImage).
+			Send()
+
+		return nil, nil
+	}
+
+	return r.runDocker(ctx, "pull", imageName)
+}
+
+func (r *BaseRegistry) mirrorCandidates(imageName string) []string {
+	if len(r.PullMirrors) == 0 {
+		return nil
+	}
+
+	host, path := utils.SplitHost(imageName)
+
+	var candidates []string
+	for _, mirror := range r.PullMirrors[host] {
+		mirror = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(mirror, "https://"), "http://"), "/")
+		if mirror == "" {
+			continue
+		}
+		candidates = append(candidates, mirror+"/"+path)
+	}
+
+	return candidates
+}
@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+)
+
+func TestBaseRegistry_mirrorCandidates(t *testing.T) {
+	r := &BaseRegistry{
+		Logger: logger.NewTest(),
+		PullMirrors: map[string][]string{
+			"docker.io": {"https://mirror.gcr.io/", "harbor.company.com/dockerhub-proxy"},
+			"quay.io":   {"quay-mirror.company.com"},
+		},
+	}
+
+	tests := []struct {
+		image    string
+		expected []string
+	}{
+		{"nginx:1.25", []string{"mirror.gcr.io/library/nginx:1.25", "harbor.company.com/dockerhub-proxy/library/nginx:1.25"}},
+		{"bitnami/redis:7.0", []string{"mirror.gcr.io/bitnami/redis:7.0", "harbor.company.com/dockerhub-proxy/bitnami/redis:7.0"}},
+		{"docker.io/nginx:1.25", []string{"mirror.gcr.io/library/nginx:1.25", "harbor.company.com/dockerhub-proxy/library/nginx:1.25"}},
+		{"quay.io/prometheus/node-exporter:v1.6.0", []string{"quay-mirror.company.com/prometheus/node-exporter:v1.6.0"}},
+		{"ghcr.io/org/app:1.0", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if result := r.mirrorCandidates(tt.image); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("mirrorCandidates(%s) = %v, expected %v", tt.image, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBaseRegistry_pullWithMirrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		failing       map[string]bool
+		expectedCalls []string
+		expectError   bool
+	}{
+		{
+			name: "pulls from first mirror and retags to source",
+			expectedCalls: []string{
+				"pull mirror.gcr.io/library/nginx:1.25",
+				"tag mirror.gcr.io/library/nginx:1.25 nginx:1.25",
+			},
+		},
+		{
+			name:    "falls back to next mirror on miss",
+			failing: map[string]bool{"pull mirror.gcr.io/library/nginx:1.25": true},
+			expectedCalls: []string{
+				"pull mirror.gcr.io/library/nginx:1.25",
+				"pull harbor.company.com/proxy/library/nginx:1.25",
+				"tag harbor.company.com/proxy/library/nginx:1.25 nginx:1.25",
+			},
+		},
+		{
+			name: "falls back to original when all mirrors miss",
+			failing: map[string]bool{
+				"pull mirror.gcr.io/library/nginx:1.25":            true,
+				"pull harbor.company.com/proxy/library/nginx:1.25": true,
+			},
+			expectedCalls: []string{
+				"pull mirror.gcr.io/library/nginx:1.25",
+				"pull harbor.company.com/proxy/library/nginx:1.25",
+				"pull nginx:1.25",
+			},
+		},
+		{
+			name: "returns original error when everything fails",
+			failing: map[string]bool{
+				"pull mirror.gcr.io/library/nginx:1.25":            true,
+				"pull harbor.company.com/proxy/library/nginx:1.25": true,
+				"pull nginx:1.25": true,
+			},
+			expectedCalls: []string{
+				"pull mirror.gcr.io/library/nginx:1.25",
+				"pull harbor.company.com/proxy/library/nginx:1.25",
+				"pull nginx:1.25",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			r := &BaseRegistry{
+				Logger: logger.NewTest(),
+				PullMirrors: map[string][]string{
+					"docker.io": {"mirror.gcr.io", "harbor.company.com/proxy"},
+				},
+				runCommand: func(ctx context.Context, args ...string) ([]byte, error) {
+					call := strings.Join(args, " ")
+					calls = append(calls, call)
+					if tt.failing[call] {
+						return []byte("manifest unknown"), errors.New("exit status 1")
+					}
+					return nil, nil
+				},
+			}
+
+			_, err := r.pullWithMirrors(context.Background(), "nginx:1.25")
+			if tt.expectError != (err != nil) {
+				t.Errorf("pullWithMirrors() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !reflect.DeepEqual(calls, tt.expectedCalls) {
+				t.Errorf("docker calls = %v, expected %v", calls, tt.expectedCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,610 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/kevinfinalboss/privateer/internal/httpreplay"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+)
+
+type ociReference struct {
+	Host       string
+	Repository string
+	Reference  string
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	MediaType   string            `json:"mediaType"`
+	Config      *ociDescriptor    `json:"config,omitempty"`
+	Layers      []ociDescriptor   `json:"layers,omitempty"`
+	Manifests   []ociDescriptor   `json:"manifests,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociCredentials struct {
+	Username string
+	Password string
+}
+
+type ociClient struct {
+	httpClient  *http.Client
+	credentials map[string]ociCredentials
+	plainHTTP   map[string]bool
+	authCache   map[string]string
+	limiter     *bandwidthLimiter
+	mutex       sync.Mutex
+}
+
+func newOCIClient(policy tlsPolicy) *ociClient {
+	return &ociClient{
+		httpClient: &http.Client{
+			Transport: httpreplay.Wrap(policy.transport()),
+		},
+		credentials: make(map[string]ociCredentials),
+		plainHTTP:   make(map[string]bool),
+		authCache:   make(map[string]string),
+	}
+}
+
+func (r *BaseRegistry) copyPreservingAnnotations(ctx context.Context, sourceImage, targetImage string) error {
+	target := parseOCIReference(targetImage)
+
+	client := r.newRegistryOCIClient(target.Host)
+	source := r.resolveOCISource(ctx, client, sourceImage)
+
+	r.Logger.Debug("oci_copy_start").
+		Str("source", sourceImage).
+		Str("target", targetImage).
+		Send()
+
+	if err := client.copyImage(ctx, source, target); err != nil {
+		return fmt.Errorf("falha ao copiar imagem %s preservando anotações: %w", sourceImage, err)
+	}
+
+	if err := client.verifyAnnotations(ctx, source, target); err != nil {
+		r.Logger.Warn("oci_annotations_mismatch").
+			Str("source", sourceImage).
+			Str("target", targetImage).
+			Err(err).
+			Send()
+		return err
+	}
+
+	r.Logger.Info("oci_copy_success").
+		Str("source", sourceImage).
+		Str("target", targetImage).
+		Send()
+
+	return nil
+}
+
+func (r *BaseRegistry) useOCIClient(sourceImage string) bool {
+	if r.PreserveAnnotations {
+		return true
+	}
+	if len(r.InsecureHosts) == 0 {
+		return false
+	}
+
+	policy := newTLSPolicy(false, r.InsecureHosts...)
+	hosts := []string{parseOCIReference(sourceImage).Host}
+	for _, mirrorImage := range r.mirrorCandidates(sourceImage) {
+		hosts = append(hosts, parseOCIReference(mirrorImage).Host)
+	}
+
+	for _, host := range hosts {
+		if policy.insecureFor(host) {
+			r.Logger.Debug("oci_copy_for_insecure_host").
+				Str("image", sourceImage).
+				Str("host", host).
+				Send()
+			return true
+		}
+	}
+	return false
+}
+
+func (r *BaseRegistry) resolveOCISource(ctx context.Context, client *ociClient, sourceImage string) ociReference {
+	for _, mirrorImage := range r.mirrorCandidates(sourceImage) {
+		mirror := parseOCIReference(mirrorImage)
+		if _, _, err := client.getManifest(ctx, mirror); err != nil {
+			r.Logger.Warn("image_pull_mirror_failed").
+				Str("mirror_image", mirrorImage).
+				Err(err).
+				Send()
+			continue
+		}
+
+		r.Logger.Info("image_pulled_from_mirror").
+			Str("image", sourceImage).
+			Str("mirror_image", mirrorImage).
+			Send()
+
+		return mirror
+	}
+
+	return parseOCIReference(sourceImage)
+}
+
+func (r *BaseRegistry) newRegistryOCIClient(host string) *ociClient {
+	insecureHosts := r.InsecureHosts
+	if r.Insecure {
+		insecureHosts = append([]string{host}, insecureHosts...)
+	}
+
+	var sources []*BaseRegistry
+	if r.copySource != nil {
+		sources = append(sources, r.copySource)
+		insecureHosts = append(insecureHosts, r.copySource.InsecureHosts...)
+	}
+	if r.peers != nil {
+		sources = append(sources, r.peers()...)
+	}
+	for _, source := range sources {
+		if source.Insecure && source.host() != "" {
+			insecureHosts = append(insecureHosts, source.host())
+		}
+	}
+
+	client := newOCIClient(newTLSPolicy(false, insecureHosts...))
+	client.limiter = r.bandwidth
+	for _, source := range sources {
+		if sourceHost := source.host(); sourceHost != "" && sourceHost != host {
+			client.addRegistry(sourceHost, source)
+		}
+	}
+	client.addRegistry(host, r)
+
+	return client
+}
+
+func (c *ociClient) addRegistry(host string, registry *BaseRegistry) {
+	if _, exists := c.credentials[host]; !exists && !registry.Anonymous && registry.Username != "" {
+		c.credentials[host] = ociCredentials{Username: registry.Username, Password: registry.Password}
+	}
+	if registry.Insecure || strings.HasPrefix(registry.URL, "http://") {
+		c.plainHTTP[host] = true
+	}
+}
+
+func (r *BaseRegistry) host() string {
+	host := strings.TrimPrefix(strings.TrimPrefix(r.URL, "https://"), "http://")
+	return strings.TrimSuffix(host, "/")
+}
+
+func (r *BaseRegistry) ownsImage(imageName string) bool {
+	host := r.host()
+	return host != "" && utils.CanonicalHost(parseOCIReference(imageName).Host) == utils.CanonicalHost(host)
+}
+
+func (r *BaseRegistry) resolveDigest(ctx context.Context, imageName string) (string, error) {
+	ref := parseOCIReference(imageName)
+
+	body, _, err := r.newRegistryOCIClient(ref.Host).getManifest(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("falha ao resolver digest de %s: %w", imageName, err)
+	}
+
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+func parseOCIReference(imageName string) ociReference {
+	ref := ociReference{Reference: "latest"}
+
+	name, tag, digest := utils.SplitReference(imageName)
+	if digest != "" {
+		ref.Reference = digest
+	} else if tag != "" {
+		ref.Reference = tag
+	}
+
+	host, repository := utils.SplitHost(name)
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+
+	ref.Host = host
+	ref.Repository = repository
+	return ref
+}
+
+func (c *ociClient) copyImage(ctx context.Context, source, target ociReference) error {
+	body, mediaType, err := c.getManifest(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return fmt.Errorf("falha ao decodificar manifest de %s: %w", source.Repository, err)
+	}
+
+	for _, child := range manifest.Manifests {
+		childSource := ociReference{Host: source.Host, Repository: source.Repository, Reference: child.Digest}
+		childTarget := ociReference{Host: target.Host, Repository: target.Repository, Reference: child.Digest}
+		if err := c.copyImage(ctx, childSource, childTarget); err != nil {
+			return err
+		}
+	}
+
+	var blobs []ociDescriptor
+	if manifest.Config != nil {
+		blobs = append(blobs, *manifest.Config)
+	}
+	blobs = append(blobs, manifest.Layers...)
+
+	for _, blob := range blobs {
+		if err := c.copyBlob(ctx, source, target, blob); err != nil {
+			return err
+		}
+	}
+
+	return c.putManifest(ctx, target, body, mediaType)
+}
+
+func (c *ociClient) verifyAnnotations(ctx context.Context, source, target ociReference) error {
+	sourceManifest, err := c.fetchManifest(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	targetManifest, err := c.fetchManifest(ctx, target)
+	if err != nil {
+		return fmt.Errorf("falha ao ler manifest enviado para %s:%s: %w", target.Repository, target.Reference, err)
+	}
+
+	for key, value := range sourceManifest.Annotations {
+		if targetValue, ok := targetManifest.Annotations[key]; !ok || targetValue != value {
+			return fmt.Errorf("anotação %s ausente ou divergente no manifest de destino %s:%s", key, target.Repository, target.Reference)
+		}
+	}
+
+	for _, child := range sourceManifest.Manifests {
+		childSource := ociReference{Host: source.Host, Repository: source.Repository, Reference: child.Digest}
+		childTarget := ociReference{Host: target.Host, Repository: target.Repository, Reference: child.Digest}
+		if err := c.verifyAnnotations(ctx, childSource, childTarget); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *ociClient) fetchManifest(ctx context.Context, ref ociReference) (*ociManifest, error) {
+	body, _, err := c.getManifest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+func (c *ociClient) getManifest(ctx context.Context, ref ociReference) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url(ref.Host, "/v2/%s/manifests/%s", ref.Repository, ref.Reference), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", strings.Join(manifestAcceptTypes, ", "))
+
+	resp, err := c.do(req, ref)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("registry %s retornou status %d para manifest %s:%s", ref.Host, resp.StatusCode, ref.Repository, ref.Reference)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+func (c *ociClient) putManifest(ctx context.Context, ref ociReference, body []byte, mediaType string) error {
+	req, err := http.NewRequestWithContext(ctx, "PUT", c.url(ref.Host, "/v2/%s/manifests/%s", ref.Repository, ref.Reference), strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mediaType)
+
+	resp, err := c.do(req, ref)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry %s retornou status %d ao enviar manifest %s:%s", ref.Host, resp.StatusCode, ref.Repository, ref.Reference)
+	}
+
+	return nil
+}
+
+func (c *ociClient) copyBlob(ctx context.Context, source, target ociReference, blob ociDescriptor) error {
+	exists, err := c.blobExists(ctx, target, blob.Digest)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	getResp, err := c.fetchBlob(ctx, source, blob.Digest)
+	if err != nil {
+		return err
+	}
+	defer getResp.Body.Close()
+
+	uploadURL, err := c.startUpload(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	separator := "?"
+	if strings.Contains(uploadURL, "?") {
+		separator = "&"
+	}
+
+	putReq, err := http.NewRequestWithContext(ctx, "PUT", uploadURL+separator+"digest="+url.QueryEscape(blob.Digest), c.limiter.reader(ctx, getResp.Body))
+	if err != nil {
+		return err
+	}
+	putReq.ContentLength = getResp.ContentLength
+	if putReq.ContentLength < 0 {
+		putReq.ContentLength = blob.Size
+	}
+	putReq.Header.Set("Content-Type", "application/octet-stream")
+
+	putResp, err := c.do(putReq, target)
+	if err != nil {
+		return err
+	}
+	defer putResp.Body.Close()
+
+	if putResp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("registry %s retornou status %d ao enviar blob %s", target.Host, putResp.StatusCode, blob.Digest)
+	}
+
+	return nil
+}
+
+func (c *ociClient) fetchBlob(ctx context.Context, ref ociReference, digest string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url(ref.Host, "/v2/%s/blobs/%s", ref.Repository, digest), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry %s retornou status %d para blob %s", ref.Host, resp.StatusCode, digest)
+	}
+
+	return resp, nil
+}
+
+func (c *ociClient) blobExists(ctx context.Context, ref ociReference, digest string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", c.url(ref.Host, "/v2/%s/blobs/%s", ref.Repository, digest), nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.do(req, ref)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+func (c *ociClient) startUpload(ctx context.Context, ref ociReference) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url(ref.Host, "/v2/%s/blobs/uploads/", ref.Repository), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(req, ref)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("registry %s retornou status %d ao iniciar upload", ref.Host, resp.StatusCode)
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("registry %s não retornou location do upload: %w", ref.Host, err)
+	}
+
+	return location.String(), nil
+}
+
+func (c *ociClient) url(host, pathFormat string, args ...interface{}) string {
+	scheme := "https"
+	if c.plainHTTP[host] || strings.HasPrefix(host, "localhost") || strings.HasPrefix(host, "127.0.0.1") {
+		scheme = "http"
+	}
+	return scheme + "://" + host + fmt.Sprintf(pathFormat, args...)
+}
+
+func (c *ociClient) do(req *http.Request, ref ociReference) (*http.Response, error) {
+	cacheKey := ref.Host + "/" + ref.Repository
+
+	c.mutex.Lock()
+	authorization := c.authCache[cacheKey]
+	c.mutex.Unlock()
+
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	authorization, err = c.authorize(req.Context(), ref.Host, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.authCache[cacheKey] = authorization
+	c.mutex.Unlock()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set("Authorization", authorization)
+
+	return c.httpClient.Do(retry)
+}
+
+func (c *ociClient) authorize(ctx context.Context, host, challenge string) (string, error) {
+	creds := c.credentials[host]
+
+	scheme, params := parseAuthChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.SetBasicAuth(creds.Username, creds.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("desafio de autenticação não suportado em %s: %s", host, challenge)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("realm de autenticação inválido em %s", host)
+	}
+
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope := params["scope"]; scope != "" {
+		query.Set("scope", scope)
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if creds.Username != "" && creds.Password != "" {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("falha ao obter token de %s: %w", host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", fmt.Errorf("%w: servidor de token de %s recusou as credenciais", types.ErrTokenUnauthorized, host)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("servidor de token de %s retornou status %d", host, resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("falha ao decodificar token de %s: %w", host, err)
+	}
+
+	token := tokenResp.Token
+	if token == "" {
+		token = tokenResp.AccessToken
+	}
+
+	return "Bearer " + token, nil
+}
+
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	for _, part := range splitChallengeParams(rest) {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		params[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+
+	return scheme, params
+}
+
+func splitChallengeParams(params string) []string {
+	var parts []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, ch := range params {
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(ch)
+		case ch == ',' && !inQuotes:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(ch)
+		}
+	}
+
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+
+	return parts
+}
@@ -0,0 +1,331 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const (
+	ociLayoutVersion       = "1.0.0"
+	ociIndexMediaType      = "application/vnd.oci.image.index.v1+json"
+	ociRefNameAnnotation   = "org.opencontainers.image.ref.name"
+	ociLayoutFileName      = "oci-layout"
+	ociLayoutIndexFileName = "index.json"
+)
+
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+type OCILayoutRegistry struct {
+	*BaseRegistry
+	Path  string
+	mutex sync.Mutex
+}
+
+func NewOCILayoutRegistry(config *types.RegistryConfig, logger *logger.Logger) (*OCILayoutRegistry, error) {
+	path := config.Path
+	if path == "" {
+		path = config.URL
+	}
+	if path == "" {
+		return nil, fmt.Errorf("caminho do layout OCI não configurado para o registry %s", config.Name)
+	}
+
+	base := &BaseRegistry{
+		Name:          config.Name,
+		Type:          "oci-layout",
+		Logger:        logger,
+		URL:           path,
+		Insecure:      config.Insecure,
+		InsecureHosts: config.InsecureHosts,
+		Timeout:       config.Timeout,
+	}
+
+	return &OCILayoutRegistry{
+		BaseRegistry: base,
+		Path:         path,
+	}, nil
+}
+
+func (r *OCILayoutRegistry) Login(ctx context.Context) error {
+	r.Logger.Debug("registry_login_skipped").
+		Str("registry", r.Name).
+		Str("reason", "oci_layout").
+		Send()
+	return nil
+}
+
+func (r *OCILayoutRegistry) Pull(ctx context.Context, imageName string) error {
+	r.Logger.Debug("oci_layout_pull_skipped").
+		Str("image", imageName).
+		Send()
+	return nil
+}
+
+func (r *OCILayoutRegistry) Push(ctx context.Context, image *types.ImageInfo, targetTag string) error {
+	return r.Copy(ctx, image.Image, targetTag)
+}
+
+func (r *OCILayoutRegistry) Copy(ctx context.Context, sourceImage, targetImage string) error {
+	ctx, cancel := r.withCopyTimeout(ctx)
+	defer cancel()
+
+	r.Logger.Debug("oci_layout_copy_start").
+		Str("source", sourceImage).
+		Str("target", targetImage).
+		Str("path", r.Path).
+		Send()
+
+	if err := r.ensureLayout(); err != nil {
+		return err
+	}
+
+	client := newOCIClient(newTLSPolicy(r.Insecure, r.InsecureHosts...))
+	client.limiter = r.bandwidth
+	descriptor, err := r.writeImage(ctx, client, parseOCIReference(sourceImage))
+	if err != nil {
+		r.Logger.Error("oci_layout_copy_failed").
+			Str("source", sourceImage).
+			Str("target", targetImage).
+			Err(err).
+			Send()
+		return fmt.Errorf("falha ao exportar imagem %s para layout OCI: %w", sourceImage, err)
+	}
+
+	if err := r.addToIndex(descriptor, r.refName(targetImage)); err != nil {
+		return err
+	}
+
+	r.Logger.Info("oci_layout_copy_success").
+		Str("source", sourceImage).
+		Str("target", targetImage).
+		Str("digest", descriptor.Digest).
+		Send()
+
+	return nil
+}
+
+func (r *OCILayoutRegistry) IsHealthy(ctx context.Context) error {
+	if err := r.ensureLayout(); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(r.Path)
+	if err != nil {
+		return fmt.Errorf("falha ao acessar layout OCI %s: %w", r.Path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("caminho do layout OCI %s não é um diretório", r.Path)
+	}
+
+	return nil
+}
+
+func (r *OCILayoutRegistry) HasImage(ctx context.Context, imageName string) (bool, error) {
+	index, err := r.readIndex()
+	if err != nil {
+		return false, err
+	}
+
+	refName := r.refName(imageName)
+	for _, manifest := range index.Manifests {
+		if manifest.Annotations[ociRefNameAnnotation] == refName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *OCILayoutRegistry) refName(targetImage string) string {
+	return strings.TrimPrefix(targetImage, r.Name+"/")
+}
+
+func (r *OCILayoutRegistry) ensureLayout() error {
+	if err := os.MkdirAll(filepath.Join(r.Path, "blobs", "sha256"), 0755); err != nil {
+		return fmt.Errorf("falha ao criar layout OCI %s: %w", r.Path, err)
+	}
+
+	layoutFile := filepath.Join(r.Path, ociLayoutFileName)
+	if _, err := os.Stat(layoutFile); err == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(map[string]string{"imageLayoutVersion": ociLayoutVersion})
+	if err := os.WriteFile(layoutFile, data, 0644); err != nil {
+		return fmt.Errorf("falha ao escrever %s: %w", layoutFile, err)
+	}
+
+	return nil
+}
+
+func (r *OCILayoutRegistry) writeImage(ctx context.Context, client *ociClient, source ociReference) (ociDescriptor, error) {
+	body, mediaType, err := client.getManifest(ctx, source)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return ociDescriptor{}, fmt.Errorf("falha ao decodificar manifest de %s: %w", source.Repository, err)
+	}
+
+	for _, child := range manifest.Manifests {
+		childSource := ociReference{Host: source.Host, Repository: source.Repository, Reference: child.Digest}
+		if _, err := r.writeImage(ctx, client, childSource); err != nil {
+			return ociDescriptor{}, err
+		}
+	}
+
+	var blobs []ociDescriptor
+	if manifest.Config != nil {
+		blobs = append(blobs, *manifest.Config)
+	}
+	blobs = append(blobs, manifest.Layers...)
+
+	for _, blob := range blobs {
+		if err := r.writeBlob(ctx, client, source, blob.Digest); err != nil {
+			return ociDescriptor{}, err
+		}
+	}
+
+	digest, err := r.writeBlobBytes(body)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	if manifest.MediaType != "" {
+		mediaType = manifest.MediaType
+	}
+
+	return ociDescriptor{
+		MediaType: mediaType,
+		Digest:    digest,
+		Size:      int64(len(body)),
+	}, nil
+}
+
+func (r *OCILayoutRegistry) blobPath(digest string) (string, error) {
+	algorithm, hash, found := strings.Cut(digest, ":")
+	if !found || algorithm != "sha256" || hash == "" || strings.ContainsAny(hash, `/\.`) {
+		return "", fmt.Errorf("digest inválido: %s", digest)
+	}
+	return filepath.Join(r.Path, "blobs", algorithm, hash), nil
+}
+
+func (r *OCILayoutRegistry) writeBlob(ctx context.Context, client *ociClient, source ociReference, digest string) error {
+	path, err := r.blobPath(digest)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	resp, err := client.fetchBlob(ctx, source, digest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "blob-*")
+	if err != nil {
+		return fmt.Errorf("falha ao criar arquivo temporário: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hasher), client.limiter.reader(ctx, resp.Body)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("falha ao baixar blob %s: %w", digest, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if actual := "sha256:" + hex.EncodeToString(hasher.Sum(nil)); actual != digest {
+		return fmt.Errorf("digest do blob diverge: esperado %s, obtido %s", digest, actual)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (r *OCILayoutRegistry) writeBlobBytes(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	path, err := r.blobPath(digest)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("falha ao escrever blob %s: %w", digest, err)
+	}
+
+	return digest, nil
+}
+
+func (r *OCILayoutRegistry) readIndex() (*ociIndex, error) {
+	index := &ociIndex{SchemaVersion: 2, MediaType: ociIndexMediaType, Manifests: []ociDescriptor{}}
+
+	data, err := os.ReadFile(filepath.Join(r.Path, ociLayoutIndexFileName))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler index do layout OCI: %w", err)
+	}
+
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar index do layout OCI: %w", err)
+	}
+
+	return index, nil
+}
+
+func (r *OCILayoutRegistry) addToIndex(descriptor ociDescriptor, refName string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	index, err := r.readIndex()
+	if err != nil {
+		return err
+	}
+
+	manifests := index.Manifests[:0]
+	for _, manifest := range index.Manifests {
+		if manifest.Annotations[ociRefNameAnnotation] != refName {
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	descriptor.Annotations = map[string]string{ociRefNameAnnotation: refName}
+	index.Manifests = append(manifests, descriptor)
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(r.Path, ociLayoutIndexFileName), data, 0644); err != nil {
+		return fmt.Errorf("falha ao escrever index do layout OCI: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestOCILayoutRegistry_Copy(t *testing.T) {
+	reg, host := newTestOCIServer(t)
+	seeded := seedTestOCIImage(reg, "team/app", "1.0.0")
+
+	layoutDir := t.TempDir()
+	layout, err := NewOCILayoutRegistry(&types.RegistryConfig{
+		Name: "airgap",
+		Type: "oci-layout",
+		Path: layoutDir,
+	}, logger.NewTest())
+	if err != nil {
+		t.Fatalf("NewOCILayoutRegistry() unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := layout.Copy(ctx, host+"/team/app:1.0.0", "airgap/team/app:1.0.0"); err != nil {
+		t.Fatalf("Copy() unexpected error: %v", err)
+	}
+
+	layoutFile, err := os.ReadFile(filepath.Join(layoutDir, "oci-layout"))
+	if err != nil {
+		t.Fatalf("oci-layout file missing: %v", err)
+	}
+	if !strings.Contains(string(layoutFile), `"imageLayoutVersion":"1.0.0"`) {
+		t.Errorf("oci-layout = %s", layoutFile)
+	}
+
+	indexData, err := os.ReadFile(filepath.Join(layoutDir, "index.json"))
+	if err != nil {
+		t.Fatalf("index.json missing: %v", err)
+	}
+
+	var index ociIndex
+	if err := json.Unmarshal(indexData, &index); err != nil {
+		t.Fatalf("index.json is not valid JSON: %v", err)
+	}
+	if index.SchemaVersion != 2 || len(index.Manifests) != 1 {
+		t.Fatalf("unexpected index: %s", indexData)
+	}
+
+	entry := index.Manifests[0]
+	if entry.Digest != ociDigest(seeded.index) || entry.MediaType != "application/vnd.oci.image.index.v1+json" {
+		t.Errorf("index entry = %+v, expected digest %s", entry, ociDigest(seeded.index))
+	}
+	if entry.Annotations["org.opencontainers.image.ref.name"] != "team/app:1.0.0" {
+		t.Errorf("ref name annotation = %q", entry.Annotations["org.opencontainers.image.ref.name"])
+	}
+
+	for _, blob := range [][]byte{seeded.index, seeded.image, seeded.config, seeded.layer} {
+		path := filepath.Join(layoutDir, "blobs", "sha256", strings.TrimPrefix(ociDigest(blob), "sha256:"))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("blob %s missing: %v", ociDigest(blob), err)
+			continue
+		}
+		if string(data) != string(blob) {
+			t.Errorf("blob %s content mismatch", ociDigest(blob))
+		}
+	}
+
+	exists, err := layout.HasImage(ctx, "airgap/team/app:1.0.0")
+	if err != nil || !exists {
+		t.Errorf("HasImage() = %v, %v, expected true", exists, err)
+	}
+
+	if err := layout.Copy(ctx, host+"/team/app:1.0.0", "airgap/team/app:1.0.0"); err != nil {
+		t.Fatalf("second Copy() unexpected error: %v", err)
+	}
+	indexData, _ = os.ReadFile(filepath.Join(layoutDir, "index.json"))
+	if err := json.Unmarshal(indexData, &index); err != nil || len(index.Manifests) != 1 {
+		t.Errorf("expected re-export to replace the existing index entry, got %s", indexData)
+	}
+}
+
+func TestNewOCILayoutRegistry_RequiresPath(t *testing.T) {
+	if _, err := NewOCILayoutRegistry(&types.RegistryConfig{Name: "airgap", Type: "oci-layout"}, logger.NewTest()); err == nil {
+		t.Error("expected error when no path is configured")
+	}
+}
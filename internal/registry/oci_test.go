@@ -0,0 +1,287 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+type testOCIRegistry struct {
+	mutex     sync.Mutex
+	manifests map[string][]byte
+	types     map[string]string
+	blobs     map[string][]byte
+	uploads   int
+	token     string
+
+	stripAnnotations bool
+}
+
+func ociDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newTestOCIRegistry() *testOCIRegistry {
+	return &testOCIRegistry{
+		manifests: make(map[string][]byte),
+		types:     make(map[string]string),
+		blobs:     make(map[string][]byte),
+		token:     "push-token",
+	}
+}
+
+func (reg *testOCIRegistry) putManifest(repository, reference, mediaType string, body []byte) {
+	for _, ref := range []string{reference, ociDigest(body)} {
+		reg.manifests[repository+"@"+ref] = body
+		reg.types[repository+"@"+ref] = mediaType
+	}
+}
+
+func (reg *testOCIRegistry) handler(serverURL func() string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reg.mutex.Lock()
+		defer reg.mutex.Unlock()
+
+		if r.URL.Path == "/token" {
+			json.NewEncoder(w).Encode(map[string]string{"token": reg.token})
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, "/v2/")
+		if strings.HasPrefix(path, "mirror/") && r.Header.Get("Authorization") != "Bearer "+reg.token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:mirror:pull,push"`, serverURL()))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case strings.Contains(path, "/manifests/"):
+			idx := strings.Index(path, "/manifests/")
+			key := path[:idx] + "@" + path[idx+len("/manifests/"):]
+			switch r.Method {
+			case "GET":
+				body, ok := reg.manifests[key]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", reg.types[key])
+				w.Write(body)
+			case "PUT":
+				body, _ := io.ReadAll(r.Body)
+				if reg.stripAnnotations {
+					var manifest map[string]interface{}
+					json.Unmarshal(body, &manifest)
+					delete(manifest, "annotations")
+					body, _ = json.Marshal(manifest)
+				}
+				reg.putManifest(path[:idx], path[idx+len("/manifests/"):], r.Header.Get("Content-Type"), body)
+				w.WriteHeader(http.StatusCreated)
+			}
+		case strings.HasSuffix(path, "/blobs/uploads/") && r.Method == "POST":
+			reg.uploads++
+			w.Header().Set("Location", fmt.Sprintf("/v2/%supload-%d?_state=abc", path, reg.uploads))
+			w.WriteHeader(http.StatusAccepted)
+		case strings.Contains(path, "/blobs/uploads/") && r.Method == "PUT":
+			body, _ := io.ReadAll(r.Body)
+			digest := r.URL.Query().Get("digest")
+			if digest != ociDigest(body) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			reg.blobs[path[:strings.Index(path, "/blobs/")]+"@"+digest] = body
+			w.WriteHeader(http.StatusCreated)
+		case strings.Contains(path, "/blobs/"):
+			idx := strings.Index(path, "/blobs/")
+			body, ok := reg.blobs[path[:idx]+"@"+path[idx+len("/blobs/"):]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+			if r.Method == "GET" {
+				w.Write(body)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+type testOCIImage struct {
+	index  []byte
+	image  []byte
+	config []byte
+	layer  []byte
+}
+
+func newTestOCIServer(t *testing.T) (*testOCIRegistry, string) {
+	t.Helper()
+
+	reg := newTestOCIRegistry()
+	var server *httptest.Server
+	server = httptest.NewServer(reg.handler(func() string { return server.URL }))
+	t.Cleanup(server.Close)
+
+	return reg, strings.TrimPrefix(server.URL, "http://")
+}
+
+func seedTestOCIImage(reg *testOCIRegistry, repository, tag string) testOCIImage {
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	layer := []byte("layer-content")
+	reg.blobs[repository+"@"+ociDigest(config)] = config
+	reg.blobs[repository+"@"+ociDigest(layer)] = layer
+
+	image := []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json",`+
+		`"config":{"mediaType":"application/vnd.oci.image.config.v1+json","digest":"%s","size":%d},`+
+		`"layers":[{"mediaType":"application/vnd.oci.image.layer.v1.tar+gzip","digest":"%s","size":%d}],`+
+		`"annotations":{"org.opencontainers.image.source":"https://github.com/example/app"}}`,
+		ociDigest(config), len(config), ociDigest(layer), len(layer)))
+	reg.putManifest(repository, ociDigest(image), "application/vnd.oci.image.manifest.v1+json", image)
+
+	index := []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.index.v1+json",`+
+		`"manifests":[{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"%s","size":%d,"platform":{"architecture":"amd64","os":"linux"}}],`+
+		`"annotations":{"org.opencontainers.image.created":"2024-01-01T00:00:00Z","com.example.team":"platform"}}`,
+		ociDigest(image), len(image)))
+	reg.putManifest(repository, tag, "application/vnd.oci.image.index.v1+json", index)
+
+	return testOCIImage{index: index, image: image, config: config, layer: layer}
+}
+
+func TestBaseRegistry_copyPreservingAnnotations(t *testing.T) {
+	reg, host := newTestOCIServer(t)
+	seeded := seedTestOCIImage(reg, "team/app", "1.0.0")
+
+	base := &BaseRegistry{Name: "local", Type: "docker", Logger: logger.NewTest(), URL: host}
+	err := base.copyPreservingAnnotations(context.Background(), host+"/team/app:1.0.0", host+"/mirror/app:1.0.0")
+	if err != nil {
+		t.Fatalf("copyPreservingAnnotations() unexpected error: %v", err)
+	}
+
+	if got := reg.manifests["mirror/app@1.0.0"]; string(got) != string(seeded.index) {
+		t.Errorf("target index = %s, expected %s", got, seeded.index)
+	}
+	if got := reg.types["mirror/app@1.0.0"]; got != "application/vnd.oci.image.index.v1+json" {
+		t.Errorf("target index media type = %s", got)
+	}
+	if got := reg.manifests["mirror/app@"+ociDigest(seeded.image)]; string(got) != string(seeded.image) {
+		t.Errorf("target image manifest = %s, expected %s", got, seeded.image)
+	}
+	if string(reg.blobs["mirror/app@"+ociDigest(seeded.config)]) != string(seeded.config) {
+		t.Errorf("config blob was not copied to target")
+	}
+	if string(reg.blobs["mirror/app@"+ociDigest(seeded.layer)]) != string(seeded.layer) {
+		t.Errorf("layer blob was not copied to target")
+	}
+}
+
+func TestBaseRegistry_copyPreservingAnnotations_MissingAnnotation(t *testing.T) {
+	reg, host := newTestOCIServer(t)
+	seedTestOCIImage(reg, "team/app", "1.0.0")
+	reg.stripAnnotations = true
+
+	base := &BaseRegistry{Name: "local", Type: "docker", Logger: logger.NewTest(), URL: host}
+	err := base.copyPreservingAnnotations(context.Background(), host+"/team/app:1.0.0", host+"/mirror/app:1.0.0")
+	if err == nil {
+		t.Fatal("copyPreservingAnnotations() expected error when the target drops annotations")
+	}
+	if !strings.Contains(err.Error(), "org.opencontainers.image.created") && !strings.Contains(err.Error(), "com.example.team") {
+		t.Errorf("error = %v, expected it to name the missing annotation", err)
+	}
+}
+
+func TestManager_OCIClientUsesConfiguredSourceCredentials(t *testing.T) {
+	manager := NewManager(logger.NewTest())
+	for _, config := range []types.RegistryConfig{
+		{Name: "target", Type: "harbor", Enabled: true, URL: "harbor.company.com", Username: "robot", Password: "push"},
+		{Name: "source", Type: "docker", Enabled: true, URL: "http://registry.internal:5000", Username: "reader", Password: "pull"},
+		{Name: "public", Type: "docker", Enabled: true, URL: "mirror.company.com", Anonymous: true},
+	} {
+		if err := manager.AddRegistry(&config); err != nil {
+			t.Fatalf("AddRegistry(%s) unexpected error: %v", config.Name, err)
+		}
+	}
+
+	target, _ := manager.GetRegistry("target")
+	client := target.(baseProvider).base().newRegistryOCIClient("harbor.company.com")
+
+	if got := client.credentials["harbor.company.com"]; got != (ociCredentials{Username: "robot", Password: "push"}) {
+		t.Errorf("target credentials = %+v", got)
+	}
+	if got := client.credentials["registry.internal:5000"]; got != (ociCredentials{Username: "reader", Password: "pull"}) {
+		t.Errorf("source credentials = %+v", got)
+	}
+	if !client.plainHTTP["registry.internal:5000"] {
+		t.Error("source registry configured with http:// should use plain HTTP")
+	}
+	if _, exists := client.credentials["mirror.company.com"]; exists {
+		t.Error("anonymous registry should not provide credentials")
+	}
+}
+
+func TestBaseRegistry_copyPreservingAnnotations_PullMirror(t *testing.T) {
+	reg, host := newTestOCIServer(t)
+	seeded := seedTestOCIImage(reg, "proxy/team/app", "1.0.0")
+
+	base := &BaseRegistry{
+		Name:   "local",
+		Type:   "docker",
+		Logger: logger.NewTest(),
+		URL:    host,
+		PullMirrors: map[string][]string{
+			"upstream.invalid": {"http://" + host + "/missing", host + "/proxy"},
+		},
+	}
+	err := base.copyPreservingAnnotations(context.Background(), "upstream.invalid/team/app:1.0.0", host+"/mirror/app:1.0.0")
+	if err != nil {
+		t.Fatalf("copyPreservingAnnotations() unexpected error: %v", err)
+	}
+
+	if got := reg.manifests["mirror/app@1.0.0"]; string(got) != string(seeded.index) {
+		t.Errorf("target index = %s, expected %s", got, seeded.index)
+	}
+	if string(reg.blobs["mirror/app@"+ociDigest(seeded.layer)]) != string(seeded.layer) {
+		t.Errorf("layer blob was not copied from the mirror")
+	}
+}
+
+func TestParseOCIReference(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected ociReference
+	}{
+		{"nginx", ociReference{Host: "registry-1.docker.io", Repository: "library/nginx", Reference: "latest"}},
+		{"nginx:1.25", ociReference{Host: "registry-1.docker.io", Repository: "library/nginx", Reference: "1.25"}},
+		{"localhost:5000/team/app:v1", ociReference{Host: "localhost:5000", Repository: "team/app", Reference: "v1"}},
+		{"ghcr.io/org/app@sha256:abc", ociReference{Host: "ghcr.io", Repository: "org/app", Reference: "sha256:abc"}},
+	}
+
+	for _, tt := range tests {
+		if result := parseOCIReference(tt.image); result != tt.expected {
+			t.Errorf("parseOCIReference(%s) = %+v, expected %+v", tt.image, result, tt.expected)
+		}
+	}
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.example.com/token",service="registry",scope="repository:a/b:pull,push"`)
+
+	if scheme != "Bearer" {
+		t.Errorf("scheme = %s, expected Bearer", scheme)
+	}
+	if params["realm"] != "https://auth.example.com/token" || params["service"] != "registry" || params["scope"] != "repository:a/b:pull,push" {
+		t.Errorf("unexpected params: %v", params)
+	}
+}
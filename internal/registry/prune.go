@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func PrunePrefixes(registries []types.RegistryConfig) []string {
+	var prefixes []string
+	seen := make(map[string]bool)
+
+	for i := range registries {
+		regConfig := &registries[i]
+		if !regConfig.Enabled || !regConfig.IsPushTarget() {
+			continue
+		}
+		switch regConfig.Type {
+		case "docker", "harbor", "ghcr":
+		case "ecr":
+			if regConfig.AccountID == "" {
+				continue
+			}
+		default:
+			continue
+		}
+
+		prefix := strings.ToLower(strings.TrimSuffix(TargetPrefix(regConfig), "/"))
+		if prefix == "" || seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes
+}
+
+func SelectPrunableImages(localImages, prefixes []string) []string {
+	var prunable []string
+	seen := make(map[string]bool)
+
+	for _, image := range localImages {
+		image = strings.TrimSpace(image)
+		if image == "" || seen[image] || strings.Contains(image, "<none>") {
+			continue
+		}
+
+		lower := strings.ToLower(image)
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(lower, prefix+"/") {
+				seen[image] = true
+				prunable = append(prunable, image)
+				break
+			}
+		}
+	}
+
+	sort.Strings(prunable)
+	return prunable
+}
+
+func ListLocalImages(ctx context.Context) ([]string, error) {
+	output, err := exec.CommandContext(ctx, "docker", "images", "--format", "{{.Repository}}:{{.Tag}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar imagens locais do docker: %w", err)
+	}
+	return strings.Split(strings.TrimSpace(string(output)), "\n"), nil
+}
+
+func RemoveLocalImage(ctx context.Context, image string) error {
+	output, err := exec.CommandContext(ctx, "docker", "rmi", image).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("falha ao remover imagem local %s: %s: %w", image, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestSelectPrunableImages(t *testing.T) {
+	registries := []types.RegistryConfig{
+		{Name: "harbor", Type: "harbor", Enabled: true, URL: "https://Harbor.Company.com", Project: "apps"},
+		{Name: "ecr", Type: "ecr", Enabled: true, AccountID: "123456789012", Region: "us-east-1"},
+		{Name: "ecr-no-account", Type: "ecr", Enabled: true, Region: "us-east-1"},
+		{Name: "ghcr", Type: "ghcr", Enabled: false, Project: "acme"},
+		{Name: "source", Type: "docker", Enabled: true, URL: "registry.internal", Role: types.RegistryRoleValidate},
+	}
+
+	prefixes := PrunePrefixes(registries)
+	expectedPrefixes := []string{"harbor.company.com/apps", "123456789012.dkr.ecr.us-east-1.amazonaws.com"}
+	if !reflect.DeepEqual(prefixes, expectedPrefixes) {
+		t.Fatalf("PrunePrefixes() = %v, expected %v", prefixes, expectedPrefixes)
+	}
+
+	localImages := []string{
+		"harbor.company.com/apps/nginx:1.25",
+		"Harbor.Company.com/apps/redis:7",
+		"harbor.company.com/apps-legacy/nginx:1.25",
+		"harbor.company.com/apps/<none>:<none>",
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com/api:v2",
+		"ghcr.io/acme/tool:1.0",
+		"registry.internal/base:1.0",
+		"nginx:1.25",
+		"",
+		"harbor.company.com/apps/nginx:1.25",
+	}
+
+	got := SelectPrunableImages(localImages, prefixes)
+	expected := []string{
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com/api:v2",
+		"Harbor.Company.com/apps/redis:7",
+		"harbor.company.com/apps/nginx:1.25",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("SelectPrunableImages() = %v, expected %v", got, expected)
+	}
+}
@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+const maxThrottleChunk = 32 * 1024
+
+type bandwidthLimiter struct {
+	bytesPerSecond int64
+	mutex          sync.Mutex
+	next           time.Time
+	now            func() time.Time
+	sleep          func(ctx context.Context, d time.Duration) error
+}
+
+func newBandwidthLimiter(bytesPerSecond int64) *bandwidthLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	return &bandwidthLimiter{
+		bytesPerSecond: bytesPerSecond,
+		now:            time.Now,
+		sleep:          sleepContext,
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (l *bandwidthLimiter) chunkSize() int {
+	if l.bytesPerSecond < maxThrottleChunk {
+		return int(l.bytesPerSecond)
+	}
+	return maxThrottleChunk
+}
+
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	l.mutex.Lock()
+	now := l.now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.bytesPerSecond))
+	delay := l.next.Sub(now)
+	l.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	return l.sleep(ctx, delay)
+}
+
+func (l *bandwidthLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, reader: r, limiter: l}
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *bandwidthLimiter
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if chunk := r.limiter.chunkSize(); len(p) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.wait(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBandwidthLimiter_CapsThroughput(t *testing.T) {
+	const (
+		bytesPerSecond = 64 * 1024
+		transferSize   = 512 * 1024
+	)
+
+	clock := time.Unix(0, 0)
+	limiter := newBandwidthLimiter(bytesPerSecond)
+	limiter.now = func() time.Time { return clock }
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		clock = clock.Add(d)
+		return nil
+	}
+
+	payload := bytes.Repeat([]byte("x"), transferSize)
+	var received bytes.Buffer
+	n, err := io.Copy(&received, limiter.reader(context.Background(), bytes.NewReader(payload)))
+	if err != nil {
+		t.Fatalf("io.Copy() unexpected error: %v", err)
+	}
+	if n != transferSize || !bytes.Equal(received.Bytes(), payload) {
+		t.Fatalf("transferred %d bytes, expected %d unchanged bytes", n, transferSize)
+	}
+
+	elapsed := clock.Sub(time.Unix(0, 0))
+	expected := time.Duration(transferSize/bytesPerSecond) * time.Second
+	if elapsed < expected {
+		t.Errorf("transfer took %v, expected at least %v at %d bytes/s", elapsed, expected, bytesPerSecond)
+	}
+	if throughput := float64(transferSize) / elapsed.Seconds(); throughput > bytesPerSecond {
+		t.Errorf("throughput = %.0f bytes/s, expected at most %d", throughput, bytesPerSecond)
+	}
+}
+
+func TestBandwidthLimiter_Disabled(t *testing.T) {
+	limiter := newBandwidthLimiter(0)
+	if limiter != nil {
+		t.Fatalf("newBandwidthLimiter(0) = %v, expected nil", limiter)
+	}
+
+	source := bytes.NewReader([]byte("payload"))
+	if reader := limiter.reader(context.Background(), source); reader != source {
+		t.Errorf("disabled limiter should return the original reader")
+	}
+}
+
+func TestBandwidthLimiter_ContextCancelled(t *testing.T) {
+	limiter := newBandwidthLimiter(1024)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := io.Copy(io.Discard, limiter.reader(ctx, bytes.NewReader(make([]byte, 4096))))
+	if err != context.Canceled {
+		t.Errorf("io.Copy() error = %v, expected context.Canceled", err)
+	}
+}
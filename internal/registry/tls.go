@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+type tlsPolicy struct {
+	insecureAll   bool
+	insecureHosts map[string]bool
+}
+
+func newTLSPolicy(insecureAll bool, hosts ...string) tlsPolicy {
+	policy := tlsPolicy{
+		insecureAll:   insecureAll,
+		insecureHosts: make(map[string]bool),
+	}
+	for _, host := range hosts {
+		if normalized := normalizeTLSHost(host); normalized != "" {
+			policy.insecureHosts[normalized] = true
+		}
+	}
+	return policy
+}
+
+func normalizeTLSHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(host), "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	return strings.ToLower(host)
+}
+
+func (p tlsPolicy) insecureFor(host string) bool {
+	if p.insecureAll {
+		return true
+	}
+
+	host = normalizeTLSHost(host)
+	if p.insecureHosts[host] {
+		return true
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return p.insecureHosts[hostname]
+	}
+	return false
+}
+
+func (p tlsPolicy) transport() http.RoundTripper {
+	if p.insecureAll || len(p.insecureHosts) == 0 {
+		return newHTTPTransport(p.insecureAll)
+	}
+
+	return &hostTLSTransport{
+		policy:   p,
+		verified: newHTTPTransport(false),
+		insecure: newHTTPTransport(true),
+	}
+}
+
+type hostTLSTransport struct {
+	policy   tlsPolicy
+	verified *http.Transport
+	insecure *http.Transport
+}
+
+func (t *hostTLSTransport) transportFor(host string) *http.Transport {
+	if t.policy.insecureFor(host) {
+		return t.insecure
+	}
+	return t.verified
+}
+
+func (t *hostTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transportFor(req.URL.Host).RoundTrip(req)
+}
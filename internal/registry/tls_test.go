@@ -0,0 +1,133 @@
+package registry
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+)
+
+func TestTLSPolicy_InsecureFor(t *testing.T) {
+	policy := newTLSPolicy(false, "http://mirror.local:5000/", "Cache.Internal")
+
+	tests := []struct {
+		host     string
+		insecure bool
+	}{
+		{"mirror.local:5000", true},
+		{"cache.internal", true},
+		{"cache.internal:443", true},
+		{"mirror.local", false},
+		{"registry-1.docker.io", false},
+		{"registry.company.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := policy.insecureFor(tt.host); got != tt.insecure {
+				t.Errorf("insecureFor(%q) = %v, expected %v", tt.host, got, tt.insecure)
+			}
+		})
+	}
+}
+
+func TestTLSPolicy_Transport(t *testing.T) {
+	if transport, ok := newTLSPolicy(false).transport().(*http.Transport); !ok || transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("expected verified *http.Transport without insecure hosts")
+	}
+	if transport, ok := newTLSPolicy(true).transport().(*http.Transport); !ok || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("expected insecure *http.Transport when everything is insecure")
+	}
+
+	transport, ok := newTLSPolicy(false, "mirror.local:5000").transport().(*hostTLSTransport)
+	if !ok {
+		t.Fatalf("expected *hostTLSTransport for per-host policy")
+	}
+	if !transport.transportFor("mirror.local:5000").TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("expected mirror to skip TLS verification")
+	}
+	if transport.transportFor("registry.company.com").TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("expected target registry to keep TLS verification")
+	}
+}
+
+func TestBaseRegistry_newRegistryOCIClient_InsecureHosts(t *testing.T) {
+	tests := []struct {
+		name           string
+		registry       *BaseRegistry
+		targetInsecure bool
+		mirrorInsecure bool
+	}{
+		{
+			name:           "verified target with insecure mirror",
+			registry:       &BaseRegistry{URL: "https://registry.company.com", InsecureHosts: []string{"mirror.local:5000"}},
+			mirrorInsecure: true,
+		},
+		{
+			name:           "insecure target does not leak to sources",
+			registry:       &BaseRegistry{URL: "registry.company.com", Insecure: true},
+			targetInsecure: true,
+		},
+		{
+			name:     "fully verified",
+			registry: &BaseRegistry{URL: "https://registry.company.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := tt.registry.newRegistryOCIClient("registry.company.com")
+
+			insecureFor := func(host string) bool {
+				switch transport := client.httpClient.Transport.(type) {
+				case *hostTLSTransport:
+					return transport.transportFor(host).TLSClientConfig.InsecureSkipVerify
+				case *http.Transport:
+					return transport.TLSClientConfig.InsecureSkipVerify
+				}
+				t.Fatalf("unexpected transport %T", client.httpClient.Transport)
+				return false
+			}
+
+			if got := insecureFor("registry.company.com"); got != tt.targetInsecure {
+				t.Errorf("target insecure = %v, expected %v", got, tt.targetInsecure)
+			}
+			if got := insecureFor("mirror.local:5000"); got != tt.mirrorInsecure {
+				t.Errorf("mirror insecure = %v, expected %v", got, tt.mirrorInsecure)
+			}
+			if insecureFor("registry-1.docker.io") {
+				t.Errorf("expected public source to keep TLS verification")
+			}
+		})
+	}
+}
+
+func TestBaseRegistry_useOCIClient(t *testing.T) {
+	r := &BaseRegistry{
+		Logger:        logger.NewTest(),
+		InsecureHosts: []string{"mirror.local:5000", "cache.internal"},
+		PullMirrors:   map[string][]string{"quay.io": {"cache.internal/quay"}},
+	}
+
+	tests := []struct {
+		image    string
+		expected bool
+	}{
+		{"mirror.local:5000/team/app:1.0", true},
+		{"quay.io/prometheus/node-exporter:v1.6.0", true},
+		{"nginx:1.25", false},
+		{"registry.company.com/team/app:1.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := r.useOCIClient(tt.image); got != tt.expected {
+				t.Errorf("useOCIClient(%s) = %v, expected %v", tt.image, got, tt.expected)
+			}
+		})
+	}
+
+	if !(&BaseRegistry{Logger: logger.NewTest(), PreserveAnnotations: true}).useOCIClient("nginx:1.25") {
+		t.Error("preserve_annotations should always use the OCI client")
+	}
+}
@@ -0,0 +1,168 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+type CombinedReport struct {
+	Timestamp     string             `json:"timestamp"`
+	ExecutionMode string             `json:"execution_mode"`
+	Success       bool               `json:"success"`
+	Cluster       ClusterPhaseReport `json:"cluster"`
+	GitOps        *GitOpsPhaseReport `json:"gitops,omitempty"`
+}
+
+type ClusterPhaseReport struct {
+	TotalImages  int      `json:"total_images"`
+	SuccessCount int      `json:"success_count"`
+	FailureCount int      `json:"failure_count"`
+	SkippedCount int      `json:"skipped_count"`
+	Failures     []string `json:"failures,omitempty"`
+}
+
+type GitOpsPhaseReport struct {
+	TotalRepositories     int                      `json:"total_repositories"`
+	ProcessedRepositories int                      `json:"processed_repositories"`
+	SuccessfulPRs         int                      `json:"successful_prs"`
+	FailedOperations      int                      `json:"failed_operations"`
+	TotalFilesChanged     int                      `json:"total_files_changed"`
+	TotalImagesReplaced   int                      `json:"total_images_replaced"`
+	DeferredRepositories  int                      `json:"deferred_repositories,omitempty"`
+	ProcessingTime        string                   `json:"processing_time"`
+	Repositories          []GitOpsRepositoryReport `json:"repositories,omitempty"`
+}
+
+type GitOpsRepositoryReport struct {
+	Repository     string `json:"repository"`
+	Success        bool   `json:"success"`
+	Deferred       bool   `json:"deferred,omitempty"`
+	PullRequestURL string `json:"pull_request_url,omitempty"`
+	FilesChanged   int    `json:"files_changed"`
+	ImagesChanged  int    `json:"images_changed"`
+	Error          string `json:"error,omitempty"`
+}
+
+func BuildCombinedReport(summary *types.MigrationSummary, isDryRun bool, timestamp time.Time) *CombinedReport {
+	report := &CombinedReport{
+		Timestamp:     timestamp.Format(time.RFC3339),
+		ExecutionMode: getExecutionMode(isDryRun),
+		Cluster:       buildClusterPhase(summary),
+		GitOps:        buildGitOpsPhase(summary.GitOps),
+	}
+
+	report.Success = summary.FailureCount == 0
+	if report.GitOps != nil {
+		report.Success = report.Success && report.GitOps.FailedOperations == 0
+	}
+
+	return report
+}
+
+func buildClusterPhase(summary *types.MigrationSummary) ClusterPhaseReport {
+	phase := ClusterPhaseReport{
+		TotalImages:  summary.TotalImages,
+		SuccessCount: summary.SuccessCount,
+		FailureCount: summary.FailureCount,
+		SkippedCount: summary.SkippedCount,
+	}
+
+	for _, result := range summary.Results {
+		if result.Success || result.Skipped || result.Image == nil {
+			continue
+		}
+		failure := fmt.Sprintf("%s -> %s", result.Image.Image, result.Registry)
+		if result.Error != nil {
+			failure += ": " + result.Error.Error()
+		}
+		phase.Failures = append(phase.Failures, failure)
+	}
+
+	return phase
+}
+
+func buildGitOpsPhase(gitops *types.GitOpsSummary) *GitOpsPhaseReport {
+	if gitops == nil {
+		return nil
+	}
+
+	phase := &GitOpsPhaseReport{
+		TotalRepositories:     gitops.TotalRepositories,
+		ProcessedRepositories: gitops.ProcessedRepositories,
+		SuccessfulPRs:         gitops.SuccessfulPRs,
+		FailedOperations:      gitops.FailedOperations,
+		TotalFilesChanged:     gitops.TotalFilesChanged,
+		TotalImagesReplaced:   gitops.TotalImagesReplaced,
+		DeferredRepositories:  gitops.DeferredRepositories,
+		ProcessingTime:        gitops.ProcessingTime,
+	}
+
+	for _, result := range gitops.Results {
+		repository := GitOpsRepositoryReport{
+			Repository:    result.Repository,
+			Success:       result.Success,
+			Deferred:      result.Deferred,
+			FilesChanged:  len(result.FilesChanged),
+			ImagesChanged: len(result.ImagesChanged),
+		}
+		if result.PullRequest != nil {
+			repository.PullRequestURL = result.PullRequest.URL
+		}
+		if result.Error != nil {
+			repository.Error = result.Error.Error()
+		}
+		phase.Repositories = append(phase.Repositories, repository)
+	}
+
+	return phase
+}
+
+func (c *CombinedReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+func (r *HTMLReporter) GenerateCombinedReport(summary *types.MigrationSummary, config *types.Config, isDryRun bool) (string, string, error) {
+	timestamp := time.Now()
+	baseName := fmt.Sprintf("privateer-full-%s", timestamp.Format("2006-01-02_15-04-05"))
+	if isDryRun {
+		baseName = fmt.Sprintf("privateer-full-dryrun-%s", timestamp.Format("2006-01-02_15-04-05"))
+	}
+
+	data := r.buildReportData(summary, config, isDryRun, timestamp)
+	data.Title += " Completa"
+
+	htmlContent, err := r.generateHTML(data)
+	if err != nil {
+		return "", "", fmt.Errorf("falha ao gerar HTML: %w", err)
+	}
+
+	htmlPath := filepath.Join(r.reportsDir, baseName+".html")
+	if err := os.WriteFile(htmlPath, []byte(htmlContent), 0644); err != nil {
+		return "", "", fmt.Errorf("falha ao salvar relatório: %w", err)
+	}
+
+	jsonContent, err := BuildCombinedReport(summary, isDryRun, timestamp).JSON()
+	if err != nil {
+		return "", "", fmt.Errorf("falha ao gerar JSON: %w", err)
+	}
+
+	jsonPath := filepath.Join(r.reportsDir, baseName+".json")
+	if err := os.WriteFile(jsonPath, jsonContent, 0644); err != nil {
+		return "", "", fmt.Errorf("falha ao salvar relatório: %w", err)
+	}
+
+	r.logger.Info("combined_report_generated").
+		Str("file", htmlPath).
+		Str("json_file", jsonPath).
+		Str("mode", getExecutionMode(isDryRun)).
+		Int("total_images", summary.TotalImages).
+		Bool("gitops", summary.GitOps != nil).
+		Send()
+
+	return htmlPath, jsonPath, nil
+}
@@ -0,0 +1,138 @@
+package reporter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func newTestCombinedSummary() *types.MigrationSummary {
+	return &types.MigrationSummary{
+		TotalImages:  3,
+		SuccessCount: 1,
+		FailureCount: 1,
+		SkippedCount: 1,
+		Results: []*types.MigrationResult{
+			{Image: &types.ImageInfo{Image: "nginx:1.25"}, Registry: "harbor", Success: true},
+			{Image: &types.ImageInfo{Image: "redis:7.0"}, Registry: "harbor", Error: errors.New("timeout")},
+			{Image: &types.ImageInfo{Image: "busybox:1.36"}, Registry: "harbor", Skipped: true},
+		},
+		GitOps: &types.GitOpsSummary{
+			TotalRepositories:     2,
+			ProcessedRepositories: 2,
+			SuccessfulPRs:         1,
+			FailedOperations:      1,
+			TotalFilesChanged:     3,
+			TotalImagesReplaced:   4,
+			Results: []*types.GitOpsResult{
+				{
+					Repository:    "company/manifests",
+					Success:       true,
+					PullRequest:   &types.PullRequestInfo{URL: "https://github.com/company/manifests/pull/7", Number: 7},
+					FilesChanged:  []types.FileChange{{FilePath: "a.yaml"}, {FilePath: "b.yaml"}, {FilePath: "c.yaml"}},
+					ImagesChanged: []types.ImageReplacement{{}, {}, {}, {}},
+				},
+				{Repository: "company/infra", Error: errors.New("permission denied")},
+			},
+		},
+	}
+}
+
+func TestBuildCombinedReport(t *testing.T) {
+	report := BuildCombinedReport(newTestCombinedSummary(), false, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	expectedCluster := ClusterPhaseReport{TotalImages: 3, SuccessCount: 1, FailureCount: 1, SkippedCount: 1}
+	if report.Cluster.TotalImages != expectedCluster.TotalImages || report.Cluster.SuccessCount != expectedCluster.SuccessCount ||
+		report.Cluster.FailureCount != expectedCluster.FailureCount || report.Cluster.SkippedCount != expectedCluster.SkippedCount {
+		t.Errorf("cluster phase = %+v, expected %+v", report.Cluster, expectedCluster)
+	}
+	if len(report.Cluster.Failures) != 1 || report.Cluster.Failures[0] != "redis:7.0 -> harbor: timeout" {
+		t.Errorf("cluster failures = %v", report.Cluster.Failures)
+	}
+
+	if report.GitOps == nil {
+		t.Fatal("expected gitops phase in combined report")
+	}
+	if report.GitOps.ProcessedRepositories != 2 || report.GitOps.SuccessfulPRs != 1 || report.GitOps.FailedOperations != 1 ||
+		report.GitOps.TotalFilesChanged != 3 || report.GitOps.TotalImagesReplaced != 4 {
+		t.Errorf("gitops phase = %+v", report.GitOps)
+	}
+	if len(report.GitOps.Repositories) != 2 {
+		t.Fatalf("gitops repositories = %+v", report.GitOps.Repositories)
+	}
+	if repo := report.GitOps.Repositories[0]; repo.PullRequestURL != "https://github.com/company/manifests/pull/7" || repo.FilesChanged != 3 || repo.ImagesChanged != 4 {
+		t.Errorf("repository report = %+v", repo)
+	}
+	if repo := report.GitOps.Repositories[1]; repo.Success || repo.Error != "permission denied" {
+		t.Errorf("failed repository report = %+v", repo)
+	}
+	if report.Success {
+		t.Error("combined report should not be successful when a phase has failures")
+	}
+
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatalf("JSON() unexpected error: %v", err)
+	}
+	var decoded struct {
+		Cluster map[string]interface{} `json:"cluster"`
+		GitOps  map[string]interface{} `json:"gitops"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("generated JSON is invalid: %v", err)
+	}
+	if decoded.Cluster["success_count"] != float64(1) || decoded.GitOps["successful_prs"] != float64(1) {
+		t.Errorf("decoded JSON = %v", decoded)
+	}
+}
+
+func TestBuildCombinedReport_WithoutGitOps(t *testing.T) {
+	summary := newTestCombinedSummary()
+	summary.GitOps = nil
+	summary.FailureCount = 0
+
+	report := BuildCombinedReport(summary, true, time.Now())
+	if report.GitOps != nil {
+		t.Errorf("expected no gitops phase, got %+v", report.GitOps)
+	}
+	if !report.Success {
+		t.Error("expected successful report without failures")
+	}
+}
+
+func TestHTMLReporter_GenerateCombinedReport(t *testing.T) {
+	r := &HTMLReporter{logger: logger.NewTest(), reportsDir: t.TempDir()}
+
+	htmlPath, jsonPath, err := r.GenerateCombinedReport(newTestCombinedSummary(), &types.Config{}, false)
+	if err != nil {
+		t.Fatalf("GenerateCombinedReport() unexpected error: %v", err)
+	}
+
+	htmlContent, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("failed to read html report: %v", err)
+	}
+	for _, expected := range []string{"Atualização de Repositórios GitOps", "company/manifests", "https://github.com/company/manifests/pull/7"} {
+		if !strings.Contains(string(htmlContent), expected) {
+			t.Errorf("html report missing %q", expected)
+		}
+	}
+
+	jsonContent, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("failed to read json report: %v", err)
+	}
+	var report CombinedReport
+	if err := json.Unmarshal(jsonContent, &report); err != nil {
+		t.Fatalf("json report is invalid: %v", err)
+	}
+	if report.Cluster.TotalImages != 3 || report.GitOps == nil || report.GitOps.SuccessfulPRs != 1 {
+		t.Errorf("json report = %+v", report)
+	}
+}
@@ -0,0 +1,131 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ReportFormatHTML = "html"
+	ReportFormatJSON = "json"
+)
+
+func ValidateReportFormat(format string) error {
+	switch format {
+	case "", ReportFormatHTML, ReportFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("formato de relatório inválido: %s (use html ou json)", format)
+	}
+}
+
+func (r *HTMLReporter) GenerateCommandReport(summary *CommandSummary, format string) (string, error) {
+	if format == "" || summary == nil {
+		return "", nil
+	}
+	if err := ValidateReportFormat(format); err != nil {
+		return "", err
+	}
+
+	timestamp := time.Now()
+	baseName := fmt.Sprintf("privateer-%s-%s", strings.ReplaceAll(summary.Command, " ", "-"), timestamp.Format("2006-01-02_15-04-05"))
+	if summary.DryRun {
+		baseName = fmt.Sprintf("privateer-%s-dryrun-%s", strings.ReplaceAll(summary.Command, " ", "-"), timestamp.Format("2006-01-02_15-04-05"))
+	}
+
+	var content bytes.Buffer
+	switch format {
+	case ReportFormatJSON:
+		if err := summary.Write(&content, OutputFormatJSON); err != nil {
+			return "", err
+		}
+	case ReportFormatHTML:
+		if err := commandReportTemplate.Execute(&content, struct {
+			*CommandSummary
+			Timestamp     string
+			ExecutionMode string
+		}{summary, timestamp.Format("02/01/2006 15:04:05"), getExecutionMode(summary.DryRun)}); err != nil {
+			return "", fmt.Errorf("falha ao gerar HTML: %w", err)
+		}
+	}
+
+	reportPath := filepath.Join(r.reportsDir, baseName+"."+format)
+	if err := os.WriteFile(reportPath, content.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("falha ao salvar relatório: %w", err)
+	}
+
+	r.logger.Info("command_report_generated").
+		Str("file", reportPath).
+		Str("command", summary.Command).
+		Str("format", format).
+		Send()
+
+	return reportPath, nil
+}
+
+var commandReportTemplate = template.Must(template.New("command").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <title>Privateer - {{.Command}} - {{.Timestamp}}</title>
+    <style>
+        body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; background: #f5f7fa; color: #333; line-height: 1.6; margin: 0; }
+        .container { max-width: 1200px; margin: 0 auto; padding: 20px; }
+        .header { background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: white; padding: 30px; border-radius: 10px; margin-bottom: 30px; }
+        .section { background: white; margin-bottom: 30px; border-radius: 10px; overflow: hidden; box-shadow: 0 5px 15px rgba(0,0,0,0.08); }
+        .section-header { background: #667eea; color: white; padding: 20px; font-size: 1.3rem; font-weight: 600; }
+        .section-content { padding: 25px; }
+        .table { width: 100%; border-collapse: collapse; }
+        .table th, .table td { padding: 12px; text-align: left; border-bottom: 1px solid #eee; }
+        .badge { padding: 4px 12px; border-radius: 20px; font-size: 0.85rem; font-weight: 500; }
+        .badge.success { background: #d4edda; color: #155724; }
+        .badge.danger { background: #f8d7da; color: #721c24; }
+    </style>
+</head>
+<body>
+<div class="container">
+    <div class="header">
+        <h1>🏴‍☠️ privateer {{.Command}}</h1>
+        <p>{{.ExecutionMode}} • {{.Timestamp}} •
+            {{if .TimedOut}}<span class="badge danger">tempo esgotado (parcial)</span>{{else if .Success}}<span class="badge success">sucesso</span>{{else}}<span class="badge danger">falhas</span>{{end}}</p>
+    </div>
+    {{with .Scan}}
+    <div class="section">
+        <div class="section-header">🔍 Scan</div>
+        <div class="section-content">
+            <p>Imagens: {{.TotalImages}} ocorrências ({{.UniqueImages}} únicas) • Disponíveis no privado: {{.AvailableInPrivate}} ({{.UniqueAvailableInPrivate}} únicas) • Não disponíveis: {{len .NotAvailable}}</p>
+            {{if .NotAvailable}}<table class="table"><tr><th>Imagem não disponível</th></tr>{{range .NotAvailable}}<tr><td>{{.}}</td></tr>{{end}}</table>{{end}}
+            {{if .Repositories}}<table class="table"><tr><th>Repositório</th><th>Detecções</th><th>Erro</th></tr>
+            {{range .Repositories}}<tr><td>{{.Repository}}</td><td>{{.Detections}}</td><td>{{.Error}}</td></tr>{{end}}</table>{{end}}
+            {{if .RepoOnlyImages}}<table class="table"><tr><th>Somente nos repositórios</th></tr>{{range .RepoOnlyImages}}<tr><td>{{.}}</td></tr>{{end}}</table>{{end}}
+        </div>
+    </div>
+    {{end}}
+    {{with .Migration}}
+    <div class="section">
+        <div class="section-header">📦 Migração</div>
+        <div class="section-content">
+            <p>Total: {{.TotalImages}} • Sucesso: {{.SuccessCount}} • Falhas: {{.FailureCount}} • Ignoradas: {{.SkippedCount}}</p>
+            {{if .Failures}}<table class="table"><tr><th>Falha</th></tr>{{range .Failures}}<tr><td>{{.}}</td></tr>{{end}}</table>{{end}}
+        </div>
+    </div>
+    {{end}}
+    {{with .GitOps}}
+    <div class="section">
+        <div class="section-header">🔄 GitOps</div>
+        <div class="section-content">
+            <p>Repositórios: {{.ProcessedRepositories}} • PRs: {{.SuccessfulPRs}} • Falhas: {{.FailedOperations}} • Arquivos: {{.TotalFilesChanged}} • Imagens: {{.TotalImagesReplaced}}</p>
+            <table class="table"><tr><th>Repositório</th><th>Pull Request</th><th>Erro</th></tr>
+            {{range .Repositories}}<tr><td>{{.Repository}}</td><td>{{if .PullRequestURL}}<a href="{{.PullRequestURL}}">{{.PullRequestURL}}</a>{{end}}</td><td>{{.Error}}</td></tr>{{end}}</table>
+        </div>
+    </div>
+    {{end}}
+</div>
+</body>
+</html>
+`))
@@ -0,0 +1,76 @@
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+)
+
+func TestHTMLReporter_GenerateCommandReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		contains string
+	}{
+		{name: "report flag absent", format: ""},
+		{name: "html report", format: ReportFormatHTML, contains: "https://github.com/company/manifests/pull/7"},
+		{name: "json report", format: ReportFormatJSON, contains: `"command": "migrate all"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &HTMLReporter{logger: logger.NewTest(), reportsDir: t.TempDir()}
+			summary := NewMigrationCommandSummary("migrate all", newTestCombinedSummary(), false)
+
+			reportPath, err := r.GenerateCommandReport(summary, tt.format)
+			if err != nil {
+				t.Fatalf("GenerateCommandReport() unexpected error: %v", err)
+			}
+
+			entries, err := os.ReadDir(r.reportsDir)
+			if err != nil {
+				t.Fatalf("failed to list reports dir: %v", err)
+			}
+
+			if tt.format == "" {
+				if reportPath != "" || len(entries) != 0 {
+					t.Errorf("expected no report, got path %q and %d files", reportPath, len(entries))
+				}
+				return
+			}
+
+			if len(entries) != 1 || filepath.Base(reportPath) != entries[0].Name() || !strings.HasPrefix(entries[0].Name(), "privateer-migrate-all-") {
+				t.Fatalf("report path = %q, files = %v", reportPath, entries)
+			}
+			if !strings.HasSuffix(reportPath, "."+tt.format) {
+				t.Errorf("report path %q should end with .%s", reportPath, tt.format)
+			}
+
+			content, err := os.ReadFile(reportPath)
+			if err != nil {
+				t.Fatalf("failed to read report: %v", err)
+			}
+			if !strings.Contains(string(content), tt.contains) {
+				t.Errorf("report missing %q", tt.contains)
+			}
+			if tt.format == ReportFormatJSON {
+				var decoded CommandSummary
+				if err := json.Unmarshal(content, &decoded); err != nil {
+					t.Errorf("json report is invalid: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestHTMLReporter_GenerateCommandReport_InvalidFormat(t *testing.T) {
+	r := &HTMLReporter{logger: logger.NewTest(), reportsDir: t.TempDir()}
+
+	if _, err := r.GenerateCommandReport(NewCommandSummary("scan cluster", false), "pdf"); err == nil {
+		t.Error("expected error for unsupported report format")
+	}
+}
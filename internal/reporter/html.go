@@ -71,6 +71,7 @@ func (r *HTMLReporter) buildReportData(summary *types.MigrationSummary, config *
 		Timestamp:     timestamp.Format("2006-01-02 15:04:05"),
 		ExecutionMode: getExecutionMode(isDryRun),
 		Summary:       summary,
+		GitOps:        summary.GitOps,
 		Config: types.ReportConfig{
 			MultipleRegistries: config.Settings.MultipleRegistries,
 			Concurrency:        config.Settings.Concurrency,
@@ -118,12 +119,51 @@ func (r *HTMLReporter) calculateStatistics(summary *types.MigrationSummary) type
 		FailureRate:       failureRate,
 		SkippedRate:       skippedRate,
 		ProcessingTime:    "N/A",
-		AverageImageSize:  "N/A",
+		AverageImageSize:  averageImageSize(summary),
 		TopSourceRegistry: "DockerHub",
 		TopTargetRegistry: topRegistry,
 	}
 }
 
+func averageImageSize(summary *types.MigrationSummary) string {
+	sizes := make(map[string]int64)
+	for _, result := range summary.Results {
+		if result.Image != nil && result.Image.SizeBytes > 0 {
+			sizes[result.Image.Image] = result.Image.SizeBytes
+		}
+	}
+
+	if len(sizes) == 0 {
+		return "N/A"
+	}
+
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	return formatImageSize(total / int64(len(sizes)))
+}
+
+func formatImageSize(sizeBytes int64) string {
+	if sizeBytes <= 0 {
+		return ""
+	}
+
+	const unit = 1024
+	if sizeBytes < unit {
+		return fmt.Sprintf("%d B", sizeBytes)
+	}
+
+	value := float64(sizeBytes)
+	suffixes := []string{"KB", "MB", "GB", "TB"}
+	i := -1
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
+
 func (r *HTMLReporter) calculateRegistryStats(summary *types.MigrationSummary, config *types.Config) []types.RegistryStatistic {
 	registryStats := make(map[string]*types.RegistryStatistic)
 
@@ -174,6 +214,9 @@ func (r *HTMLReporter) buildImageStatusList(summary *types.MigrationSummary) []t
 			status = "Ignorado"
 			statusClass = "warning"
 			errorMsg = result.Reason
+			if result.ReasonCode != "" {
+				errorMsg = fmt.Sprintf("[%s] %s", result.ReasonCode, result.Reason)
+			}
 		} else if !result.Success {
 			status = "Falha"
 			statusClass = "danger"
@@ -192,6 +235,8 @@ func (r *HTMLReporter) buildImageStatusList(summary *types.MigrationSummary) []t
 			ResourceType: result.Image.ResourceType,
 			Namespace:    result.Image.Namespace,
 			Container:    result.Image.Container,
+			Digest:       result.Image.Digest,
+			Size:         formatImageSize(result.Image.SizeBytes),
 		})
 	}
 
@@ -265,6 +310,10 @@ func (r *HTMLReporter) generateHTML(data types.ReportData) (string, error) {
                 <h3>{{printf "%.1f%%" .Statistics.SuccessRate}}</h3>
                 <p>Taxa de Sucesso</p>
             </div>
+            <div class="stat-card">
+                <h3>{{.Statistics.AverageImageSize}}</h3>
+                <p>Tamanho Médio das Imagens</p>
+            </div>
         </div>
 
         <div class="section">
@@ -383,21 +432,23 @@ func (r *HTMLReporter) generateHTML(data types.ReportData) (string, error) {
                             <th>Status</th>
                             <th>Namespace</th>
                             <th>Recurso</th>
+                            <th>Tamanho</th>
                         </tr>
                     </thead>
                     <tbody>
                         {{range .ImagesByStatus}}
                         <tr>
-                            <td class="truncate" title="{{.SourceImage}}">{{.SourceImage}}</td>
+                            <td class="truncate" title="{{.SourceImage}}{{if .Digest}}@{{.Digest}}{{end}}">{{.SourceImage}}</td>
                             <td class="truncate" title="{{.TargetImage}}">{{.TargetImage}}</td>
                             <td><strong>{{.Registry}}</strong></td>
                             <td><span class="badge {{.StatusClass}}">{{.Status}}</span></td>
                             <td>{{.Namespace}}</td>
                             <td>{{.ResourceType}}</td>
+                            <td>{{.Size}}</td>
                         </tr>
                         {{if .Error}}
                         <tr style="background: #fff3cd;">
-                            <td colspan="6" style="font-size: 0.9rem; color: #856404;">
+                            <td colspan="7" style="font-size: 0.9rem; color: #856404;">
                                 <strong>Erro:</strong> {{.Error}}
                             </td>
                         </tr>
@@ -408,6 +459,57 @@ func (r *HTMLReporter) generateHTML(data types.ReportData) (string, error) {
             </div>
         </div>
 
+        {{if .GitOps}}
+        <div class="section">
+            <div class="section-header">🔀 Atualização de Repositórios GitOps</div>
+            <div class="section-content">
+                <div class="config-grid">
+                    <div class="config-item">
+                        <strong>Repositórios Processados:</strong><br>
+                        {{.GitOps.ProcessedRepositories}} de {{.GitOps.TotalRepositories}}
+                    </div>
+                    <div class="config-item">
+                        <strong>Pull Requests Criados:</strong><br>
+                        {{.GitOps.SuccessfulPRs}}
+                    </div>
+                    <div class="config-item">
+                        <strong>Arquivos Alterados:</strong><br>
+                        {{.GitOps.TotalFilesChanged}}
+                    </div>
+                    <div class="config-item">
+                        <strong>Imagens Substituídas:</strong><br>
+                        {{.GitOps.TotalImagesReplaced}}
+                    </div>
+                    <div class="config-item">
+                        <strong>Falhas:</strong><br>
+                        {{.GitOps.FailedOperations}}
+                    </div>
+                </div>
+
+                {{if .GitOps.Results}}
+                <table class="table" style="margin-top: 20px;">
+                    <thead>
+                        <tr>
+                            <th>Repositório</th>
+                            <th>Status</th>
+                            <th>Pull Request</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .GitOps.Results}}
+                        <tr>
+                            <td><strong>{{.Repository}}</strong></td>
+                            <td>{{if .Success}}<span class="badge success">Sucesso</span>{{else}}<span class="badge danger">Falha</span>{{end}}</td>
+                            <td>{{if .PullRequest}}<a href="{{.PullRequest.URL}}">#{{.PullRequest.Number}}</a>{{else}}-{{end}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+                {{end}}
+            </div>
+        </div>
+        {{end}}
+
         <div class="footer">
             <p>🏴‍☠️ <strong>Privateer Migration Engine</strong> | Relatório gerado automaticamente</p>
             <p style="font-size: 0.9rem; margin-top: 10px;">
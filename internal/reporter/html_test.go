@@ -0,0 +1,44 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestFormatImageSize(t *testing.T) {
+	tests := []struct {
+		size     int64
+		expected string
+	}{
+		{0, ""},
+		{512, "512 B"},
+		{1536, "1.5 KB"},
+		{52428800, "50.0 MB"},
+		{3221225472, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatImageSize(tt.size); got != tt.expected {
+			t.Errorf("formatImageSize(%d) = %q, expected %q", tt.size, got, tt.expected)
+		}
+	}
+}
+
+func TestAverageImageSize(t *testing.T) {
+	summary := &types.MigrationSummary{
+		Results: []*types.MigrationResult{
+			{Image: &types.ImageInfo{Image: "nginx:1.25", SizeBytes: 10 * 1024 * 1024}},
+			{Image: &types.ImageInfo{Image: "nginx:1.25", SizeBytes: 10 * 1024 * 1024}},
+			{Image: &types.ImageInfo{Image: "redis:7", SizeBytes: 30 * 1024 * 1024}},
+			{Image: &types.ImageInfo{Image: "busybox:1.36"}},
+		},
+	}
+
+	if got := averageImageSize(summary); got != "20.0 MB" {
+		t.Errorf("averageImageSize() = %q, expected %q", got, "20.0 MB")
+	}
+	if got := averageImageSize(&types.MigrationSummary{}); got != "N/A" {
+		t.Errorf("averageImageSize() without sizes = %q, expected N/A", got)
+	}
+}
@@ -0,0 +1,72 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+type Inventory struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Context     string             `json:"context,omitempty"`
+	Images      []*types.ImageInfo `json:"images"`
+}
+
+func NewInventory(context string, images []*types.ImageInfo) *Inventory {
+	if images == nil {
+		images = []*types.ImageInfo{}
+	}
+
+	return &Inventory{
+		GeneratedAt: time.Now().UTC(),
+		Context:     context,
+		Images:      images,
+	}
+}
+
+func (i *Inventory) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar inventário: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func LoadInventory(path string) ([]*types.ImageInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler inventário %s: %w", path, err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("inventário %s está vazio", path)
+	}
+
+	var images []*types.ImageInfo
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &images); err != nil {
+			return nil, fmt.Errorf("falha ao decodificar inventário %s: %w", path, err)
+		}
+	} else {
+		var inventory Inventory
+		if err := json.Unmarshal(data, &inventory); err != nil {
+			return nil, fmt.Errorf("falha ao decodificar inventário %s: %w", path, err)
+		}
+		images = inventory.Images
+	}
+
+	var result []*types.ImageInfo
+	for _, image := range images {
+		if image == nil || image.Image == "" {
+			continue
+		}
+		result = append(result, image)
+	}
+
+	return result, nil
+}
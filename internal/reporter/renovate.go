@@ -0,0 +1,112 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/internal/registry"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+var renovatePublicRegistries = []string{
+	"docker.io",
+	"index.docker.io",
+	"registry-1.docker.io",
+	"registry.hub.docker.com",
+	"quay.io",
+	"gcr.io",
+	"registry.k8s.io",
+	"k8s.gcr.io",
+	"ghcr.io",
+	"public.ecr.aws",
+	"mcr.microsoft.com",
+}
+
+type RenovateConfig struct {
+	PackageRules []RenovatePackageRule `json:"packageRules"`
+}
+
+type RenovatePackageRule struct {
+	Description             string   `json:"description"`
+	MatchDatasources        []string `json:"matchDatasources"`
+	MatchPackagePrefixes    []string `json:"matchPackagePrefixes,omitempty"`
+	MatchPackagePatterns    []string `json:"matchPackagePatterns,omitempty"`
+	ReplacementNameTemplate string   `json:"replacementNameTemplate"`
+}
+
+func GenerateRenovateConfig(config *types.Config) (*RenovateConfig, error) {
+	target, err := renovateTargetRegistry(config)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := registry.TargetPrefix(target)
+	describe := func(source string) string {
+		return fmt.Sprintf("Privateer: %s → %s (%s)", source, prefix, target.Name)
+	}
+
+	rules := []RenovatePackageRule{
+		{
+			Description:             describe("Docker Hub (library)"),
+			MatchDatasources:        []string{"docker"},
+			MatchPackagePatterns:    []string{"^[^./:]+$"},
+			ReplacementNameTemplate: fmt.Sprintf("%s/library/{{{packageName}}}", prefix),
+		},
+		{
+			Description:             describe("Docker Hub"),
+			MatchDatasources:        []string{"docker"},
+			MatchPackagePatterns:    []string{"^[^./:]+/[^/]+$"},
+			ReplacementNameTemplate: fmt.Sprintf("%s/{{{packageName}}}", prefix),
+		},
+	}
+
+	seen := make(map[string]bool)
+	publicRegistries := append(append([]string{}, renovatePublicRegistries...), config.ImageDetection.CustomPublicRegistries...)
+	for _, host := range publicRegistries {
+		if host == "" || seen[host] || strings.HasPrefix(host, "regex:") || strings.Contains(host, "*") {
+			continue
+		}
+		seen[host] = true
+
+		rules = append(rules, RenovatePackageRule{
+			Description:             describe(host),
+			MatchDatasources:        []string{"docker"},
+			MatchPackagePrefixes:    []string{host + "/"},
+			ReplacementNameTemplate: fmt.Sprintf("{{{replace '^%s/' '%s/' packageName}}}", regexp.QuoteMeta(host), prefix),
+		})
+	}
+
+	return &RenovateConfig{PackageRules: rules}, nil
+}
+
+func (c *RenovateConfig) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar configuração do Renovate: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func renovateTargetRegistry(config *types.Config) (*types.RegistryConfig, error) {
+	candidates := make([]*types.RegistryConfig, 0, len(config.Registries))
+	for i := range config.Registries {
+		reg := &config.Registries[i]
+		if !reg.Enabled || reg.Type == "oci-layout" {
+			continue
+		}
+		candidates = append(candidates, reg)
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("nenhum registry privado habilitado para gerar regras do Renovate")
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Priority > candidates[j].Priority
+	})
+
+	return candidates[0], nil
+}
@@ -0,0 +1,83 @@
+package reporter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestGenerateRenovateConfig_PrivateHosts(t *testing.T) {
+	tests := []struct {
+		name           string
+		registries     []types.RegistryConfig
+		expectedPrefix string
+	}{
+		{
+			name: "highest priority harbor registry",
+			registries: []types.RegistryConfig{
+				{Name: "docker-local", Type: "docker", URL: "https://registry.company.com", Enabled: true, Priority: 1},
+				{Name: "harbor-prod", Type: "harbor", URL: "https://harbor.company.com", Project: "mirror", Enabled: true, Priority: 10},
+			},
+			expectedPrefix: "harbor.company.com/mirror",
+		},
+		{
+			name: "disabled registries are ignored",
+			registries: []types.RegistryConfig{
+				{Name: "ecr-prod", Type: "ecr", AccountID: "123456789012", Region: "us-east-1", Enabled: true, Priority: 1},
+				{Name: "harbor-old", Type: "harbor", URL: "https://harbor.old.com", Enabled: false, Priority: 10},
+			},
+			expectedPrefix: "123456789012.dkr.ecr.us-east-1.amazonaws.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renovateConfig, err := GenerateRenovateConfig(&types.Config{Registries: tt.registries})
+			if err != nil {
+				t.Fatalf("GenerateRenovateConfig() unexpected error: %v", err)
+			}
+
+			rulesByPrefix := make(map[string]RenovatePackageRule)
+			for _, rule := range renovateConfig.PackageRules {
+				if !strings.Contains(rule.ReplacementNameTemplate, tt.expectedPrefix+"/") {
+					t.Errorf("rule %q does not reference %s: %s", rule.Description, tt.expectedPrefix, rule.ReplacementNameTemplate)
+				}
+				for _, prefix := range rule.MatchPackagePrefixes {
+					rulesByPrefix[prefix] = rule
+				}
+			}
+
+			quay, found := rulesByPrefix["quay.io/"]
+			if !found {
+				t.Fatalf("missing rule for quay.io: %+v", renovateConfig.PackageRules)
+			}
+			expected := "{{{replace '^quay\\.io/' '" + tt.expectedPrefix + "/' packageName}}}"
+			if quay.ReplacementNameTemplate != expected {
+				t.Errorf("quay.io template = %s, expected %s", quay.ReplacementNameTemplate, expected)
+			}
+
+			data, err := renovateConfig.JSON()
+			if err != nil {
+				t.Fatalf("JSON() unexpected error: %v", err)
+			}
+			var decoded map[string][]map[string]interface{}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("generated JSON is invalid: %v", err)
+			}
+			if len(decoded["packageRules"]) != len(renovateConfig.PackageRules) {
+				t.Errorf("packageRules length = %d, expected %d", len(decoded["packageRules"]), len(renovateConfig.PackageRules))
+			}
+		})
+	}
+}
+
+func TestGenerateRenovateConfig_NoRegistries(t *testing.T) {
+	_, err := GenerateRenovateConfig(&types.Config{Registries: []types.RegistryConfig{
+		{Name: "airgap", Type: "oci-layout", Path: "/tmp/airgap", Enabled: true},
+	}})
+	if err == nil {
+		t.Error("expected error when no private registry is enabled")
+	}
+}
@@ -0,0 +1,290 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const (
+	OutputFormatText = "text"
+	OutputFormatJSON = "json"
+)
+
+type CommandSummary struct {
+	Command      string              `json:"command"`
+	DryRun       bool                `json:"dry_run"`
+	Success      bool                `json:"success"`
+	TimedOut     bool                `json:"timed_out,omitempty"`
+	Scan         *ScanFindings       `json:"scan,omitempty"`
+	Migration    *ClusterPhaseReport `json:"migration,omitempty"`
+	GitOps       *GitOpsPhaseReport  `json:"gitops,omitempty"`
+	Prune        *PruneFindings      `json:"prune,omitempty"`
+	PullRequests []string            `json:"pull_requests,omitempty"`
+	Drift        []DriftFinding      `json:"drift,omitempty"`
+	Report       string              `json:"report,omitempty"`
+}
+
+type PruneFindings struct {
+	Removed []string `json:"removed,omitempty"`
+	Failed  []string `json:"failed,omitempty"`
+}
+
+type DriftFinding struct {
+	Repository  string `json:"repository"`
+	FilePath    string `json:"file_path"`
+	LineNumber  int    `json:"line_number,omitempty"`
+	SourceImage string `json:"source_image"`
+	TargetImage string `json:"target_image"`
+}
+
+type ScanFindings struct {
+	TotalImages              int                  `json:"total_images"`
+	UniqueImages             int                  `json:"unique_images"`
+	AvailableInPrivate       int                  `json:"available_in_private"`
+	UniqueAvailableInPrivate int                  `json:"unique_available_in_private"`
+	NotAvailable             []string             `json:"not_available,omitempty"`
+	Repositories             []RepositoryFindings `json:"repositories,omitempty"`
+	RepoOnlyImages           []string             `json:"repo_only_images,omitempty"`
+	UnhealthyRegistries      []UnhealthyRegistry  `json:"unhealthy_registries,omitempty"`
+	DeprecatedRegistries     []DeprecatedImage    `json:"deprecated_registries,omitempty"`
+}
+
+type DeprecatedImage struct {
+	Image       string `json:"image"`
+	Replacement string `json:"replacement"`
+}
+
+type UnhealthyRegistry struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+type RepositoryFindings struct {
+	Repository string   `json:"repository"`
+	Detections int      `json:"detections"`
+	Images     []string `json:"images,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+func NewUnhealthyRegistries(failures map[string]error) []UnhealthyRegistry {
+	unhealthy := make([]UnhealthyRegistry, 0, len(failures))
+	for name, err := range failures {
+		unhealthy = append(unhealthy, UnhealthyRegistry{Name: name, Error: err.Error()})
+	}
+	sort.Slice(unhealthy, func(i, j int) bool {
+		return unhealthy[i].Name < unhealthy[j].Name
+	})
+	return unhealthy
+}
+
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case OutputFormatText, OutputFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("formato de saída inválido: %s (use text ou json)", format)
+	}
+}
+
+func NewCommandSummary(command string, isDryRun bool) *CommandSummary {
+	return &CommandSummary{Command: command, DryRun: isDryRun, Success: true}
+}
+
+func NewMigrationCommandSummary(command string, summary *types.MigrationSummary, isDryRun bool) *CommandSummary {
+	commandSummary := NewCommandSummary(command, isDryRun)
+	if summary == nil {
+		return commandSummary
+	}
+
+	cluster := buildClusterPhase(summary)
+	commandSummary.Migration = &cluster
+	commandSummary.Success = summary.FailureCount == 0
+	commandSummary.setGitOps(summary.GitOps)
+
+	return commandSummary
+}
+
+func NewGitOpsCommandSummary(command string, gitops *types.GitOpsSummary, isDryRun bool) *CommandSummary {
+	commandSummary := NewCommandSummary(command, isDryRun)
+	commandSummary.setGitOps(gitops)
+	return commandSummary
+}
+
+func NewDriftCommandSummary(command string, gitops *types.GitOpsSummary) *CommandSummary {
+	commandSummary := NewCommandSummary(command, true)
+	commandSummary.setGitOps(gitops)
+	commandSummary.Drift = buildDriftFindings(gitops)
+	commandSummary.Success = commandSummary.Success && len(commandSummary.Drift) == 0
+	return commandSummary
+}
+
+func buildDriftFindings(gitops *types.GitOpsSummary) []DriftFinding {
+	if gitops == nil {
+		return nil
+	}
+
+	var findings []DriftFinding
+	for _, result := range gitops.Results {
+		for _, replacement := range result.ImagesChanged {
+			findings = append(findings, DriftFinding{
+				Repository:  result.Repository,
+				FilePath:    replacement.FilePath,
+				LineNumber:  replacement.LineNumber,
+				SourceImage: replacement.SourceImage,
+				TargetImage: replacement.TargetImage,
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Repository != findings[j].Repository {
+			return findings[i].Repository < findings[j].Repository
+		}
+		if findings[i].FilePath != findings[j].FilePath {
+			return findings[i].FilePath < findings[j].FilePath
+		}
+		return findings[i].LineNumber < findings[j].LineNumber
+	})
+
+	return findings
+}
+
+func (s *CommandSummary) DriftError() error {
+	if len(s.Drift) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d referência(s) a imagens públicas já disponíveis em registries privados", types.ErrDriftDetected, len(s.Drift))
+}
+
+func (s *CommandSummary) MarkTimedOut() {
+	s.TimedOut = true
+	s.Success = false
+}
+
+func (s *CommandSummary) setGitOps(gitops *types.GitOpsSummary) {
+	s.GitOps = buildGitOpsPhase(gitops)
+	if s.GitOps == nil {
+		return
+	}
+
+	for _, repository := range s.GitOps.Repositories {
+		if repository.PullRequestURL != "" {
+			s.PullRequests = append(s.PullRequests, repository.PullRequestURL)
+		}
+	}
+	s.Success = s.Success && s.GitOps.FailedOperations == 0
+}
+
+func (s *CommandSummary) Write(w io.Writer, format string) error {
+	switch format {
+	case OutputFormatJSON:
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return fmt.Errorf("falha ao gerar JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case OutputFormatText, "":
+		_, err := io.WriteString(w, s.text())
+		return err
+	default:
+		return ValidateOutputFormat(format)
+	}
+}
+
+func (s *CommandSummary) text() string {
+	var b strings.Builder
+
+	status := "success"
+	if s.TimedOut {
+		status = "timed_out"
+	} else if !s.Success {
+		status = "failed"
+	}
+	fmt.Fprintf(&b, "command: %s\n", s.Command)
+	fmt.Fprintf(&b, "dry_run: %t\n", s.DryRun)
+	fmt.Fprintf(&b, "status: %s\n", status)
+
+	if s.Scan != nil {
+		fmt.Fprintf(&b, "scan: total_images=%d unique_images=%d available_in_private=%d unique_available_in_private=%d not_available=%d\n",
+			s.Scan.TotalImages, s.Scan.UniqueImages, s.Scan.AvailableInPrivate, s.Scan.UniqueAvailableInPrivate, len(s.Scan.NotAvailable))
+		for _, image := range s.Scan.NotAvailable {
+			fmt.Fprintf(&b, "  not_available: %s\n", image)
+		}
+		for _, repository := range s.Scan.Repositories {
+			if repository.Error != "" {
+				fmt.Fprintf(&b, "  repository: %s error=%s\n", repository.Repository, repository.Error)
+				continue
+			}
+			fmt.Fprintf(&b, "  repository: %s detections=%d\n", repository.Repository, repository.Detections)
+		}
+		for _, image := range s.Scan.RepoOnlyImages {
+			fmt.Fprintf(&b, "  repo_only: %s\n", image)
+		}
+		for _, registry := range s.Scan.UnhealthyRegistries {
+			fmt.Fprintf(&b, "  unhealthy_registry: %s error=%s\n", registry.Name, registry.Error)
+		}
+		for _, image := range s.Scan.DeprecatedRegistries {
+			fmt.Fprintf(&b, "  deprecated_registry: %s replacement=%s\n", image.Image, image.Replacement)
+		}
+	}
+
+	if s.Migration != nil {
+		fmt.Fprintf(&b, "migration: total=%d success=%d failed=%d skipped=%d\n",
+			s.Migration.TotalImages, s.Migration.SuccessCount, s.Migration.FailureCount, s.Migration.SkippedCount)
+		for _, failure := range s.Migration.Failures {
+			fmt.Fprintf(&b, "  failed: %s\n", failure)
+		}
+	}
+
+	if s.GitOps != nil {
+		fmt.Fprintf(&b, "gitops: repositories=%d pull_requests=%d failed=%d files_changed=%d images_replaced=%d\n",
+			s.GitOps.ProcessedRepositories, s.GitOps.SuccessfulPRs, s.GitOps.FailedOperations,
+			s.GitOps.TotalFilesChanged, s.GitOps.TotalImagesReplaced)
+		if s.GitOps.DeferredRepositories > 0 {
+			fmt.Fprintf(&b, "  deferred_repositories: %d\n", s.GitOps.DeferredRepositories)
+		}
+		for _, repository := range s.GitOps.Repositories {
+			if repository.Deferred {
+				fmt.Fprintf(&b, "  deferred: %s\n", repository.Repository)
+			}
+			if repository.Error != "" {
+				fmt.Fprintf(&b, "  failed: %s: %s\n", repository.Repository, repository.Error)
+			}
+		}
+	}
+
+	if s.Prune != nil {
+		fmt.Fprintf(&b, "prune: removed=%d failed=%d\n", len(s.Prune.Removed), len(s.Prune.Failed))
+		removedLabel := "removed"
+		if s.DryRun {
+			removedLabel = "would_remove"
+		}
+		for _, image := range s.Prune.Removed {
+			fmt.Fprintf(&b, "  %s: %s\n", removedLabel, image)
+		}
+		for _, image := range s.Prune.Failed {
+			fmt.Fprintf(&b, "  failed: %s\n", image)
+		}
+	}
+
+	for _, drift := range s.Drift {
+		fmt.Fprintf(&b, "drift: %s %s:%d %s -> %s\n",
+			drift.Repository, drift.FilePath, drift.LineNumber, drift.SourceImage, drift.TargetImage)
+	}
+
+	for _, url := range s.PullRequests {
+		fmt.Fprintf(&b, "pull_request: %s\n", url)
+	}
+
+	if s.Report != "" {
+		fmt.Fprintf(&b, "report: %s\n", s.Report)
+	}
+
+	return b.String()
+}
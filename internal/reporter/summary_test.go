@@ -0,0 +1,236 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestCommandSummary_Write_JSONForMigrate(t *testing.T) {
+	summary := NewMigrationCommandSummary("migrate all", newTestCombinedSummary(), true)
+
+	var buf bytes.Buffer
+	if err := summary.Write(&buf, OutputFormatJSON); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	var decoded CommandSummary
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("summary is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if decoded.Command != "migrate all" || !decoded.DryRun || decoded.Success {
+		t.Errorf("decoded summary = %+v", decoded)
+	}
+	if decoded.Migration == nil || decoded.Migration.TotalImages != 3 || decoded.Migration.SuccessCount != 1 ||
+		decoded.Migration.FailureCount != 1 || decoded.Migration.SkippedCount != 1 {
+		t.Errorf("decoded migration = %+v", decoded.Migration)
+	}
+	if decoded.GitOps == nil || decoded.GitOps.SuccessfulPRs != 1 {
+		t.Errorf("decoded gitops = %+v", decoded.GitOps)
+	}
+	if len(decoded.PullRequests) != 1 || decoded.PullRequests[0] != "https://github.com/company/manifests/pull/7" {
+		t.Errorf("decoded pull requests = %v", decoded.PullRequests)
+	}
+}
+
+func TestCommandSummary_Write_Text(t *testing.T) {
+	summary := NewMigrationCommandSummary("migrate all", newTestCombinedSummary(), false)
+
+	var buf bytes.Buffer
+	if err := summary.Write(&buf, OutputFormatText); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"command: migrate all",
+		"status: failed",
+		"migration: total=3 success=1 failed=1 skipped=1",
+		"failed: redis:7.0 -> harbor: timeout",
+		"pull_request: https://github.com/company/manifests/pull/7",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("text summary missing %q:\n%s", expected, buf.String())
+		}
+	}
+}
+
+func TestCommandSummary_Write_UnhealthyRegistries(t *testing.T) {
+	summary := NewCommandSummary("scan cluster", false)
+	summary.Scan = &ScanFindings{
+		TotalImages:  2,
+		NotAvailable: []string{"redis:7.0"},
+		UnhealthyRegistries: NewUnhealthyRegistries(map[string]error{
+			"harbor": errors.New("falha na conexão com registry: connection refused"),
+			"ecr":    errors.New("registry retornou status 503"),
+		}),
+	}
+
+	var buf bytes.Buffer
+	if err := summary.Write(&buf, OutputFormatText); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	expected := "  unhealthy_registry: ecr error=registry retornou status 503\n" +
+		"  unhealthy_registry: harbor error=falha na conexão com registry: connection refused\n"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("text summary missing unhealthy registries:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := summary.Write(&buf, OutputFormatJSON); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	var decoded CommandSummary
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("summary is not valid JSON: %v", err)
+	}
+	if len(decoded.Scan.UnhealthyRegistries) != 2 || decoded.Scan.UnhealthyRegistries[0].Name != "ecr" {
+		t.Errorf("decoded unhealthy registries = %+v", decoded.Scan.UnhealthyRegistries)
+	}
+}
+
+func TestCommandSummary_Write_UniqueImageCounts(t *testing.T) {
+	summary := NewCommandSummary("scan cluster", false)
+	summary.Scan = &ScanFindings{
+		TotalImages:              53,
+		UniqueImages:             3,
+		AvailableInPrivate:       50,
+		UniqueAvailableInPrivate: 1,
+		NotAvailable:             []string{"nginx:1.25", "redis:7.0"},
+	}
+
+	var buf bytes.Buffer
+	if err := summary.Write(&buf, OutputFormatText); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	expected := "scan: total_images=53 unique_images=3 available_in_private=50 unique_available_in_private=1 not_available=2\n"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("text summary missing unique counts:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := summary.Write(&buf, OutputFormatJSON); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	var decoded CommandSummary
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("summary is not valid JSON: %v", err)
+	}
+	if decoded.Scan.TotalImages != 53 || decoded.Scan.UniqueImages != 3 || decoded.Scan.UniqueAvailableInPrivate != 1 {
+		t.Errorf("decoded scan findings = %+v", decoded.Scan)
+	}
+}
+
+func TestCommandSummary_Write_Prune(t *testing.T) {
+	summary := NewCommandSummary("prune-local", true)
+	summary.Prune = &PruneFindings{
+		Removed: []string{"harbor.company.com/library/nginx:1.25", "harbor.company.com/library/redis:7.0"},
+	}
+
+	var buf bytes.Buffer
+	if err := summary.Write(&buf, OutputFormatText); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	expected := "prune: removed=2 failed=0\n" +
+		"  would_remove: harbor.company.com/library/nginx:1.25\n" +
+		"  would_remove: harbor.company.com/library/redis:7.0\n"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("text summary missing prune findings:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := summary.Write(&buf, OutputFormatJSON); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	var decoded CommandSummary
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("summary is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if decoded.Prune == nil || len(decoded.Prune.Removed) != 2 {
+		t.Errorf("decoded prune findings = %+v", decoded.Prune)
+	}
+}
+
+func TestNewDriftCommandSummary(t *testing.T) {
+	gitops := &types.GitOpsSummary{
+		TotalRepositories:     2,
+		ProcessedRepositories: 2,
+		Results: []*types.GitOpsResult{
+			{
+				Repository: "company/manifests",
+				Success:    true,
+				ImagesChanged: []types.ImageReplacement{
+					{SourceImage: "redis:7.0", TargetImage: "harbor.company.com/library/redis:7.0", FilePath: "apps/redis.yaml", LineNumber: 21},
+					{SourceImage: "nginx:1.25", TargetImage: "harbor.company.com/library/nginx:1.25", FilePath: "apps/nginx.yaml", LineNumber: 8},
+				},
+			},
+			{Repository: "company/clean", Success: true},
+		},
+	}
+
+	summary := NewDriftCommandSummary("verify github", gitops)
+
+	if summary.Success || !summary.DryRun {
+		t.Errorf("summary success = %t dry_run = %t, expected failed dry-run", summary.Success, summary.DryRun)
+	}
+
+	expected := []DriftFinding{
+		{Repository: "company/manifests", FilePath: "apps/nginx.yaml", LineNumber: 8, SourceImage: "nginx:1.25", TargetImage: "harbor.company.com/library/nginx:1.25"},
+		{Repository: "company/manifests", FilePath: "apps/redis.yaml", LineNumber: 21, SourceImage: "redis:7.0", TargetImage: "harbor.company.com/library/redis:7.0"},
+	}
+	if len(summary.Drift) != len(expected) {
+		t.Fatalf("drift = %+v, expected %+v", summary.Drift, expected)
+	}
+	for i := range expected {
+		if summary.Drift[i] != expected[i] {
+			t.Errorf("drift[%d] = %+v, expected %+v", i, summary.Drift[i], expected[i])
+		}
+	}
+
+	if err := summary.DriftError(); !errors.Is(err, types.ErrDriftDetected) {
+		t.Errorf("DriftError() = %v, expected ErrDriftDetected", err)
+	}
+
+	var buf bytes.Buffer
+	if err := summary.Write(&buf, OutputFormatJSON); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	var decoded CommandSummary
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("summary is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(decoded.Drift) != 2 || decoded.Drift[0].SourceImage != "nginx:1.25" {
+		t.Errorf("decoded drift = %+v", decoded.Drift)
+	}
+
+	clean := NewDriftCommandSummary("verify github", &types.GitOpsSummary{Results: []*types.GitOpsResult{{Repository: "company/clean", Success: true}}})
+	if !clean.Success || clean.DriftError() != nil {
+		t.Errorf("clean summary = %+v, error = %v, expected no drift", clean, clean.DriftError())
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{OutputFormatText, false},
+		{OutputFormatJSON, false},
+		{"yaml", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if err := ValidateOutputFormat(tt.format); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOutputFormat(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -18,17 +18,16 @@ func (fs *FileScanner) scanArgoCDApplication(content, filePath string, publicIma
 
 	detections = append(detections, fs.scanGenericYAML(content, filePath, publicImageMap)...)
 
-	detections = append(detections, fs.scanArgoCDHelmValues(content, filePath, publicImageMap)...)
-
 	if matches := imagePatterns["argocd_values"].FindAllStringSubmatch(content, -1); len(matches) > 0 {
 		for _, match := range matches {
 			if len(match) > 1 {
 				imageName := match[1]
-				if _, isPublic := publicImageMap[imageName]; isPublic {
+				if _, isPublic := lookupPublicImage(publicImageMap, imageName); isPublic {
 					detections = append(detections, types.ImageDetectionResult{
 						Image:      imageName,
 						Repository: fs.extractRepository(imageName),
 						Tag:        fs.extractTag(imageName),
+						Digest:     fs.extractDigest(imageName),
 						Registry:   fs.extractRegistry(imageName),
 						FullImage:  imageName,
 						IsPublic:   true,
@@ -50,65 +49,6 @@ func (fs *FileScanner) scanArgoCDApplication(content, filePath string, publicIma
 	return detections
 }
 
-func (fs *FileScanner) scanArgoCDHelmValues(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
-	var detections []types.ImageDetectionResult
-
-	lines := strings.Split(content, "\n")
-	inValuesBlock := false
-	valuesStartLine := 0
-	valuesIndentLevel := 0
-	valuesContent := strings.Builder{}
-
-	for i, line := range lines {
-		trimmedLine := strings.TrimSpace(line)
-
-		if strings.Contains(line, "values:") && strings.Contains(line, "|") {
-			inValuesBlock = true
-			valuesStartLine = i + 1
-			valuesIndentLevel = len(line) - len(strings.TrimLeft(line, " \t"))
-
-			fs.logger.Debug("argocd_values_block_start").
-				Str("file", filePath).
-				Int("start_line", valuesStartLine).
-				Int("indent_level", valuesIndentLevel).
-				Send()
-			continue
-		}
-
-		if inValuesBlock {
-			currentIndentLevel := len(line) - len(strings.TrimLeft(line, " \t"))
-
-			if trimmedLine == "" {
-				valuesContent.WriteString(line + "\n")
-				continue
-			}
-
-			if currentIndentLevel <= valuesIndentLevel && trimmedLine != "" {
-				inValuesBlock = false
-				break
-			}
-
-			valuesContent.WriteString(line + "\n")
-		}
-	}
-
-	if valuesContent.Len() > 0 {
-		valuesText := valuesContent.String()
-
-		fs.logger.Debug("argocd_values_content_extracted").
-			Str("file", filePath).
-			Int("content_length", len(valuesText)).
-			Int("values_start_line", valuesStartLine).
-			Send()
-
-		detections = append(detections, fs.scanArgoCDImageField(valuesText, filePath, publicImageMap, valuesStartLine)...)
-		detections = append(detections, fs.scanArgoCDHelmSeparatedFields(valuesText, filePath, publicImageMap, valuesStartLine)...)
-		detections = append(detections, fs.scanArgoCDInitContainers(valuesText, filePath, publicImageMap, valuesStartLine)...)
-	}
-
-	return detections
-}
-
 func (fs *FileScanner) scanArgoCDImageField(valuesContent, filePath string, publicImageMap map[string]*types.ImageInfo, baseLineOffset int) []types.ImageDetectionResult {
 	var detections []types.ImageDetectionResult
 	lines := strings.Split(valuesContent, "\n")
@@ -119,11 +59,12 @@ func (fs *FileScanner) scanArgoCDImageField(valuesContent, filePath string, publ
 		if matches := imageRegex.FindStringSubmatch(line); len(matches) > 1 {
 			imageName := strings.Trim(matches[1], `"' `)
 
-			if _, isPublic := publicImageMap[imageName]; isPublic {
+			if _, isPublic := lookupPublicImage(publicImageMap, imageName); isPublic {
 				detections = append(detections, types.ImageDetectionResult{
 					Image:      imageName,
 					Repository: fs.extractRepository(imageName),
 					Tag:        fs.extractTag(imageName),
+					Digest:     fs.extractDigest(imageName),
 					Registry:   fs.extractRegistry(imageName),
 					FullImage:  imageName,
 					IsPublic:   true,
@@ -269,20 +210,22 @@ func (fs *FileScanner) buildHelmImageDetection(registry, repository, tag string,
 		fullImage = repository + ":" + tag
 
 		dockerIOFormat := "docker.io/" + repository + ":" + tag
-		if _, exists := publicImageMap[dockerIOFormat]; exists {
+		if _, exists := lookupPublicImage(publicImageMap, dockerIOFormat); exists {
 			fullImage = dockerIOFormat
 		}
 	}
 
+	_, isPublic := lookupPublicImage(publicImageMap, fullImage)
+
 	fs.logger.Debug("checking_image_in_public_map").
 		Str("full_image", fullImage).
 		Str("registry", detectedRegistry).
 		Str("repository", repository).
 		Str("tag", tag).
-		Bool("exists", publicImageMap[fullImage] != nil).
+		Bool("exists", isPublic).
 		Send()
 
-	if _, isPublic := publicImageMap[fullImage]; isPublic {
+	if isPublic {
 		return &types.ImageDetectionResult{
 			Image:      fullImage,
 			Repository: repository,
@@ -336,11 +279,12 @@ func (fs *FileScanner) scanArgoCDInitContainers(valuesContent, filePath string,
 			if matches := initContainerImageRegex.FindStringSubmatch(line); len(matches) > 1 {
 				imageName := strings.Trim(matches[1], `"' `)
 
-				if _, isPublic := publicImageMap[imageName]; isPublic {
+				if _, isPublic := lookupPublicImage(publicImageMap, imageName); isPublic {
 					detections = append(detections, types.ImageDetectionResult{
 						Image:      imageName,
 						Repository: fs.extractRepository(imageName),
 						Tag:        fs.extractTag(imageName),
+						Digest:     fs.extractDigest(imageName),
 						Registry:   fs.extractRegistry(imageName),
 						FullImage:  imageName,
 						IsPublic:   true,
@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+var blockScalarHeaderPattern = regexp.MustCompile(`^(\s*)(?:-\s+)?([^\s#'"][^#]*?):\s*[|>][-+0-9]*\s*(?:#.*)?$`)
+
+type blockScalar struct {
+	key        string
+	lineOffset int
+	content    string
+}
+
+func extractBlockScalars(content string) []blockScalar {
+	var blocks []blockScalar
+	lines := strings.Split(content, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		matches := blockScalarHeaderPattern.FindStringSubmatch(lines[i])
+		if matches == nil {
+			continue
+		}
+
+		headerIndent := len(matches[1])
+		block := blockScalar{key: strings.TrimSpace(matches[2]), lineOffset: i + 1}
+		blockContent := strings.Builder{}
+
+		j := i + 1
+		for ; j < len(lines); j++ {
+			line := lines[j]
+			if strings.TrimSpace(line) == "" {
+				blockContent.WriteString(line + "\n")
+				continue
+			}
+			if len(line)-len(strings.TrimLeft(line, " \t")) <= headerIndent {
+				break
+			}
+			blockContent.WriteString(line + "\n")
+		}
+
+		if strings.TrimSpace(blockContent.String()) != "" {
+			block.content = blockContent.String()
+			blocks = append(blocks, block)
+		}
+		i = j - 1
+	}
+
+	return blocks
+}
+
+func looksLikeYAML(content string) bool {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(stripGoTemplateLines(content)), &root); err != nil || len(root.Content) == 0 {
+		return false
+	}
+	kind := root.Content[0].Kind
+	return kind == yaml.MappingNode || kind == yaml.SequenceNode
+}
+
+func (fs *FileScanner) scanBlockScalars(content, filePath string, publicImageMap map[string]*types.ImageInfo, existing []types.ImageDetectionResult) []types.ImageDetectionResult {
+	detected := make(map[string]bool, len(existing))
+	for _, detection := range existing {
+		detected[fmt.Sprintf("%d:%s", detection.LineNumber, detection.FullImage)] = true
+	}
+	return fs.scanNestedBlockScalars(content, filePath, publicImageMap, 0, detected)
+}
+
+func (fs *FileScanner) scanNestedBlockScalars(content, filePath string, publicImageMap map[string]*types.ImageInfo, lineOffset int, detected map[string]bool) []types.ImageDetectionResult {
+	var detections []types.ImageDetectionResult
+
+	for _, block := range extractBlockScalars(content) {
+		if !looksLikeYAML(block.content) {
+			continue
+		}
+
+		offset := lineOffset + block.lineOffset
+
+		fs.logger.Debug("block_scalar_content_extracted").
+			Str("file", filePath).
+			Str("key", block.key).
+			Int("start_line", offset+1).
+			Int("content_length", len(block.content)).
+			Send()
+
+		var found []types.ImageDetectionResult
+		found = append(found, fs.scanArgoCDImageField(block.content, filePath, publicImageMap, offset+1)...)
+		found = append(found, fs.scanArgoCDHelmSeparatedFields(block.content, filePath, publicImageMap, offset+1)...)
+		found = append(found, fs.scanArgoCDInitContainers(block.content, filePath, publicImageMap, offset+1)...)
+
+		for _, detection := range found {
+			key := fmt.Sprintf("%d:%s", detection.LineNumber, detection.FullImage)
+			if detected[key] {
+				continue
+			}
+			detected[key] = true
+			detections = append(detections, detection)
+
+			fs.logger.Debug("block_scalar_image_detected").
+				Str("file", filePath).
+				Str("key", block.key).
+				Str("image", detection.FullImage).
+				Int("line", detection.LineNumber).
+				Send()
+		}
+
+		detections = append(detections, fs.scanNestedBlockScalars(block.content, filePath, publicImageMap, offset, detected)...)
+	}
+
+	return detections
+}
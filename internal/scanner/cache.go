@@ -0,0 +1,140 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/kevinfinalboss/privateer/internal/state"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+// detectorRulesVersion identifies the detection rules: the image patterns plus
+// the module version and VCS revision of the build, so rebuilding the same
+// sources keeps the cache valid.
+var detectorRulesVersion = sync.OnceValue(func() string {
+	hasher := sha256.New()
+
+	patterns := make([]string, 0, len(imagePatterns))
+	for name := range imagePatterns {
+		patterns = append(patterns, name)
+	}
+	sort.Strings(patterns)
+	for _, name := range patterns {
+		fmt.Fprintf(hasher, "pattern:%s:%s\n", name, imagePatterns[name].String())
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(hasher, "module:%s\n", info.Main.Version)
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				fmt.Fprintf(hasher, "%s:%s\n", setting.Key, setting.Value)
+			}
+		}
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil))
+})
+
+const detectionsBucket = "detections"
+
+type detectionCache struct {
+	store      *state.Store
+	repository string
+	Version    string                         `json:"version"`
+	Entries    map[string]detectionCacheEntry `json:"entries"`
+	dirty      bool
+}
+
+type detectionCacheEntry struct {
+	SHA            string                       `json:"sha"`
+	Fingerprint    string                       `json:"fingerprint"`
+	Detections     []types.ImageDetectionResult `json:"detections,omitempty"`
+	RepoOnlyImages []types.ImageDetectionResult `json:"repo_only_images,omitempty"`
+}
+
+func loadDetectionCache(store *state.Store, repository string) *detectionCache {
+	version := detectorRulesVersion()
+	cache := &detectionCache{Version: version, Entries: make(map[string]detectionCacheEntry)}
+	if store == nil {
+		return cache
+	}
+
+	cache.store = store
+	cache.repository = repository
+
+	if found, err := store.Get(detectionsBucket, repository, cache); err != nil || !found || cache.Entries == nil || cache.Version != version {
+		cache.Version = version
+		cache.Entries = make(map[string]detectionCacheEntry)
+	}
+
+	return cache
+}
+
+func (c *detectionCache) get(filePath, sha, fingerprint string) (detectionCacheEntry, bool) {
+	if c.store == nil || sha == "" {
+		return detectionCacheEntry{}, false
+	}
+
+	entry, exists := c.Entries[filePath]
+	if !exists || entry.SHA != sha || entry.Fingerprint != fingerprint {
+		return detectionCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (c *detectionCache) put(filePath, sha, fingerprint string, detections, repoOnlyImages []types.ImageDetectionResult) {
+	if c.store == nil || sha == "" {
+		return
+	}
+
+	c.Entries[filePath] = detectionCacheEntry{
+		SHA:            sha,
+		Fingerprint:    fingerprint,
+		Detections:     detections,
+		RepoOnlyImages: repoOnlyImages,
+	}
+	c.dirty = true
+}
+
+func (c *detectionCache) save() error {
+	if c.store == nil || !c.dirty {
+		return nil
+	}
+
+	if err := c.store.Put(detectionsBucket, c.repository, c); err != nil {
+		return fmt.Errorf("falha ao gravar cache de detecções: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+func (fs *FileScanner) detectionFingerprint(publicImageMap map[string]*types.ImageInfo) string {
+	keys := make([]string, 0, len(publicImageMap))
+	for key := range publicImageMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "rules:%s\n", detectorRulesVersion())
+	for _, key := range keys {
+		fmt.Fprintf(hasher, "image:%s\n", key)
+	}
+	for _, reg := range fs.config.Registries {
+		fmt.Fprintf(hasher, "registry:%s:%s:%t:%s:%s:%s:%s:%s:%s:%s\n",
+			reg.Name, reg.Type, reg.Enabled, reg.Role, reg.URL, reg.Username, reg.Region, reg.Project, reg.Path, reg.AccountID)
+	}
+	detection, _ := json.Marshal(fs.config.ImageDetection)
+	fmt.Fprintf(hasher, "detection:%s\n", detection)
+	fmt.Fprintf(hasher, "dockerfiles:%t\n", fs.config.GitOps.ScanDockerfiles)
+	fmt.Fprintf(hasher, "max_file_size:%d\n", fs.config.GitOps.MaxFileSize)
+
+	return hex.EncodeToString(hasher.Sum(nil))
+}
@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+type composeBuild struct {
+	Service    string
+	Dockerfile string
+}
+
+func isDockerComposeFile(filePath string) bool {
+	return strings.Contains(strings.ToLower(path.Base(filePath)), "compose")
+}
+
+func (fs *FileScanner) scanDockerCompose(ctx context.Context, readFile fileReader, content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil || len(root.Content) == 0 {
+		return fs.scanGenericYAML(content, filePath, publicImageMap)
+	}
+
+	services := mappingValue(root.Content[0], "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return fs.scanGenericYAML(content, filePath, publicImageMap)
+	}
+
+	var detections []types.ImageDetectionResult
+	var builds []composeBuild
+
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		serviceName, service := services.Content[i].Value, services.Content[i+1]
+
+		if imageNode := mappingValue(service, "image"); imageNode != nil && imageNode.Kind == yaml.ScalarNode && imageNode.Value != "" {
+			imageName := imageNode.Value
+			if _, isPublic := lookupPublicImage(publicImageMap, imageName); isPublic {
+				detections = append(detections, types.ImageDetectionResult{
+					Image:      imageName,
+					Repository: fs.extractRepository(imageName),
+					Tag:        fs.extractTag(imageName),
+					Digest:     fs.extractDigest(imageName),
+					Registry:   fs.extractRegistry(imageName),
+					FullImage:  imageName,
+					IsPublic:   true,
+					LineNumber: imageNode.Line,
+					Context:    fmt.Sprintf("image: %s", imageName),
+					Confidence: 0.8,
+					FilePath:   filePath,
+				})
+			}
+		}
+
+		if dockerfile := composeDockerfile(filePath, mappingValue(service, "build")); dockerfile != "" {
+			builds = append(builds, composeBuild{Service: serviceName, Dockerfile: dockerfile})
+		}
+	}
+
+	detections = append(detections, fs.scanEnvImageValues(content, filePath, publicImageMap)...)
+
+	if !fs.config.GitOps.ScanDockerfiles {
+		return detections
+	}
+
+	scanned := make(map[string]bool)
+	for _, build := range builds {
+		if scanned[build.Dockerfile] {
+			continue
+		}
+		scanned[build.Dockerfile] = true
+
+		dockerfileContent, err := readFile(ctx, build.Dockerfile)
+		if err != nil {
+			fs.logger.Warn("compose_dockerfile_read_failed").
+				Str("file", filePath).
+				Str("service", build.Service).
+				Str("dockerfile", build.Dockerfile).
+				Err(err).
+				Send()
+			continue
+		}
+
+		detections = append(detections, fs.scanDockerfile(dockerfileContent, build.Dockerfile, publicImageMap)...)
+	}
+
+	return detections
+}
+
+func composeDockerfile(composePath string, build *yaml.Node) string {
+	if build == nil {
+		return ""
+	}
+
+	buildContext, dockerfile := "", "Dockerfile"
+	switch build.Kind {
+	case yaml.ScalarNode:
+		buildContext = build.Value
+	case yaml.MappingNode:
+		if value := mappingValue(build, "context"); value != nil {
+			buildContext = value.Value
+		}
+		if value := mappingValue(build, "dockerfile"); value != nil && value.Value != "" {
+			dockerfile = value.Value
+		}
+	default:
+		return ""
+	}
+
+	if strings.Contains(buildContext, "://") || strings.HasPrefix(buildContext, "git@") {
+		return ""
+	}
+	if buildContext == "" {
+		buildContext = "."
+	}
+
+	return path.Clean(path.Join(path.Dir(composePath), buildContext, dockerfile))
+}
+
+func (fs *FileScanner) scanDockerfile(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+	var detections []types.ImageDetectionResult
+	stages := make(map[string]bool)
+
+	for lineNum, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		imageName := args[0]
+		isStage := stages[strings.ToLower(imageName)]
+		if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = true
+		}
+
+		if isStage || imageName == "scratch" || strings.Contains(imageName, "$") {
+			continue
+		}
+
+		if _, isPublic := lookupPublicImage(publicImageMap, imageName); !isPublic {
+			continue
+		}
+
+		detections = append(detections, types.ImageDetectionResult{
+			Image:      imageName,
+			Repository: fs.extractRepository(imageName),
+			Tag:        fs.extractTag(imageName),
+			Digest:     fs.extractDigest(imageName),
+			Registry:   fs.extractRegistry(imageName),
+			FullImage:  imageName,
+			IsPublic:   true,
+			LineNumber: lineNum + 1,
+			Context:    strings.TrimSpace(line),
+			Confidence: 0.8,
+			FilePath:   filePath,
+		})
+
+		fs.logger.Debug("dockerfile_base_image_detected").
+			Str("file", filePath).
+			Str("image", imageName).
+			Int("line", lineNum+1).
+			Send()
+	}
+
+	return detections
+}
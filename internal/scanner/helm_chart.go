@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+type helmChartDefinition struct {
+	Dependencies []helmChartDependency `yaml:"dependencies"`
+}
+
+type helmChartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+}
+
+// exactChartVersionPattern matches a pinned chart version; anything else, such
+// as ^13.0.0, 2.x.x or 1.2, is a range that Helm resolves at install time.
+var exactChartVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?$`)
+
+func isHelmChartFile(filePath string) bool {
+	name := path.Base(filePath)
+	return name == "Chart.yaml" || name == "Chart.yml"
+}
+
+func (fs *FileScanner) scanHelmChartDependencies(content, filePath string) []types.ImageDetectionResult {
+	var chart helmChartDefinition
+	if err := yaml.Unmarshal([]byte(content), &chart); err != nil {
+		fs.logger.Warn("invalid_helm_chart").
+			Str("file", filePath).
+			Err(err).
+			Send()
+		return nil
+	}
+
+	var artifacts []types.ImageDetectionResult
+	for _, dependency := range chart.Dependencies {
+		if !strings.HasPrefix(dependency.Repository, "oci://") || dependency.Name == "" {
+			continue
+		}
+
+		repository := strings.TrimSuffix(strings.TrimPrefix(dependency.Repository, "oci://"), "/") + "/" + dependency.Name
+		artifact := repository
+		tag := ""
+		if exactChartVersionPattern.MatchString(dependency.Version) {
+			tag = dependency.Version
+			artifact += ":" + tag
+		}
+
+		if !fs.classifier.IsPublic(artifact) {
+			continue
+		}
+
+		artifacts = append(artifacts, types.ImageDetectionResult{
+			Image:      artifact,
+			Repository: fs.extractRepository(repository),
+			Tag:        tag,
+			Registry:   fs.extractRegistry(artifact),
+			FullImage:  artifact,
+			IsPublic:   true,
+			LineNumber: fs.findLineNumber(content, dependency.Repository),
+			Context:    fmt.Sprintf("dependency: %s (%s)", dependency.Name, dependency.Repository),
+			Confidence: 0.8,
+			FilePath:   filePath,
+		})
+
+		fs.logger.Debug("helm_oci_dependency_detected").
+			Str("file", filePath).
+			Str("dependency", dependency.Name).
+			Str("artifact", artifact).
+			Send()
+	}
+
+	return artifacts
+}
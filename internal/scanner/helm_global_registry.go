@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"fmt"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+func (fs *FileScanner) scanHelmGlobalRegistry(content, filePath string, publicImageMap map[string]*types.ImageInfo, existing []types.ImageDetectionResult) []types.ImageDetectionResult {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+
+	document := root.Content[0]
+	globalRegistry, registryKey := helmGlobalRegistry(document)
+	if globalRegistry == "" {
+		return nil
+	}
+
+	fs.logger.Debug("helm_global_registry_detected").
+		Str("file", filePath).
+		Str("registry", globalRegistry).
+		Str("key", "global."+registryKey).
+		Send()
+
+	if !utils.IsPublicRegistry(globalRegistry) {
+		return nil
+	}
+
+	detectedLines := make(map[int]bool, len(existing))
+	for _, detection := range existing {
+		detectedLines[detection.LineNumber] = true
+	}
+
+	var detections []types.ImageDetectionResult
+	walkHelmImageBlocks(document, func(repositoryNode, tagNode *yaml.Node) {
+		repository, tag := repositoryNode.Value, tagNode.Value
+		if repository == "" || tag == "" || fs.repositoryContainsRegistry(repository) || detectedLines[repositoryNode.Line] {
+			return
+		}
+
+		var fullImage string
+		if globalRegistry == "docker.io" {
+			fullImage = utils.BuildDockerIOImageName(repository, tag)
+		} else {
+			fullImage = utils.BuildFullImageName(globalRegistry, repository, tag)
+		}
+
+		if _, isInCluster := lookupPublicImage(publicImageMap, fullImage); !isInCluster {
+			fs.logger.Debug("public_image_not_in_cluster").
+				Str("full_image", fullImage).
+				Send()
+			return
+		}
+
+		detections = append(detections, types.ImageDetectionResult{
+			Image:      fullImage,
+			Repository: utils.ExtractRepository(fullImage),
+			Tag:        tag,
+			Registry:   globalRegistry,
+			FullImage:  fullImage,
+			IsPublic:   true,
+			LineNumber: repositoryNode.Line,
+			Context:    fmt.Sprintf("global.%s: %s, repository: %s, tag: %s", registryKey, globalRegistry, repository, tag),
+			Confidence: 0.9,
+			FilePath:   filePath,
+		})
+
+		fs.logger.Info("helm_image_detected").
+			Str("file", filePath).
+			Str("type", "helm_global_registry").
+			Str("registry", globalRegistry).
+			Str("repository", repository).
+			Str("tag", tag).
+			Str("full_image", fullImage).
+			Int("repo_line", repositoryNode.Line).
+			Int("tag_line", tagNode.Line).
+			Send()
+	})
+
+	return detections
+}
+
+func helmGlobalRegistry(document *yaml.Node) (string, string) {
+	global := mappingValue(document, "global")
+	for _, key := range []string{"imageRegistry", "registry"} {
+		if value := mappingValue(global, key); value != nil && value.Kind == yaml.ScalarNode && value.Value != "" {
+			return value.Value, key
+		}
+	}
+	return "", ""
+}
+
+func walkHelmImageBlocks(node *yaml.Node, visit func(repositoryNode, tagNode *yaml.Node)) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		repositoryNode := mappingValue(node, "repository")
+		tagNode := mappingValue(node, "tag")
+		if repositoryNode != nil && tagNode != nil && mappingValue(node, "registry") == nil &&
+			repositoryNode.Kind == yaml.ScalarNode && tagNode.Kind == yaml.ScalarNode {
+			visit(repositoryNode, tagNode)
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "global" {
+				continue
+			}
+			walkHelmImageBlocks(node.Content[i+1], visit)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			walkHelmImageBlocks(child, visit)
+		}
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
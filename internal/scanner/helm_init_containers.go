@@ -0,0 +1,138 @@
+package scanner
+
+import (
+	"fmt"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+func (fs *FileScanner) scanHelmInitContainers(content, filePath string, publicImageMap map[string]*types.ImageInfo, existing []types.ImageDetectionResult) []types.ImageDetectionResult {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+
+	detected := make(map[string]bool, len(existing))
+	for _, detection := range existing {
+		detected[fmt.Sprintf("%d:%s", detection.LineNumber, detection.FullImage)] = true
+	}
+
+	var detections []types.ImageDetectionResult
+	walkHelmInitContainers(root.Content[0], func(imageNode *yaml.Node) {
+		detection, found := fs.helmInitContainerImage(imageNode, filePath)
+		if !found || detected[fmt.Sprintf("%d:%s", detection.LineNumber, detection.FullImage)] {
+			return
+		}
+
+		if _, isInCluster := lookupPublicImage(publicImageMap, detection.FullImage); !isInCluster {
+			fs.logger.Debug("public_image_not_in_cluster").
+				Str("full_image", detection.FullImage).
+				Send()
+			return
+		}
+
+		detected[fmt.Sprintf("%d:%s", detection.LineNumber, detection.FullImage)] = true
+		detections = append(detections, detection)
+
+		fs.logger.Info("helm_init_container_image_detected").
+			Str("file", filePath).
+			Str("image", detection.FullImage).
+			Int("line", detection.LineNumber).
+			Send()
+	})
+
+	return detections
+}
+
+func (fs *FileScanner) helmInitContainerImage(imageNode *yaml.Node, filePath string) (types.ImageDetectionResult, bool) {
+	switch imageNode.Kind {
+	case yaml.ScalarNode:
+		imageName := imageNode.Value
+		if imageName == "" || !utils.IsPublicRegistry(fs.extractRegistry(imageName)) {
+			return types.ImageDetectionResult{}, false
+		}
+
+		return types.ImageDetectionResult{
+			Image:      imageName,
+			Repository: fs.extractRepository(imageName),
+			Tag:        fs.extractTag(imageName),
+			Digest:     fs.extractDigest(imageName),
+			Registry:   fs.extractRegistry(imageName),
+			FullImage:  imageName,
+			IsPublic:   true,
+			LineNumber: imageNode.Line,
+			Context:    fmt.Sprintf("image: %s", imageName),
+			Confidence: 0.9,
+			FilePath:   filePath,
+		}, true
+	case yaml.MappingNode:
+		repositoryNode := mappingValue(imageNode, "repository")
+		tagNode := mappingValue(imageNode, "tag")
+		if repositoryNode == nil || tagNode == nil || repositoryNode.Value == "" || tagNode.Value == "" {
+			return types.ImageDetectionResult{}, false
+		}
+
+		var registry string
+		if registryNode := mappingValue(imageNode, "registry"); registryNode != nil {
+			registry = registryNode.Value
+		}
+
+		repository, tag := repositoryNode.Value, tagNode.Value
+		detectedRegistry, detectedRepository, fileType := registry, repository, "helm_separated"
+		if registry == "" {
+			detectedRegistry = fs.extractRegistryFromRepository(repository)
+			detectedRepository = fs.extractRepositoryFromCombined(repository)
+			fileType = "helm_combined"
+		}
+
+		if !utils.IsPublicRegistry(detectedRegistry) {
+			return types.ImageDetectionResult{}, false
+		}
+
+		var fullImage string
+		if detectedRegistry == "docker.io" {
+			fullImage = utils.BuildDockerIOImageName(detectedRepository, tag)
+		} else {
+			fullImage = utils.BuildFullImageName(detectedRegistry, detectedRepository, tag)
+		}
+
+		return types.ImageDetectionResult{
+			Image:      fullImage,
+			Repository: utils.ExtractRepository(fullImage),
+			Tag:        tag,
+			Registry:   detectedRegistry,
+			FullImage:  fullImage,
+			IsPublic:   true,
+			LineNumber: repositoryNode.Line,
+			Context:    fs.buildHelmContext(registry, repository, tag, fileType),
+			Confidence: 0.9,
+			FilePath:   filePath,
+		}, true
+	}
+
+	return types.ImageDetectionResult{}, false
+}
+
+func walkHelmInitContainers(node *yaml.Node, visit func(imageNode *yaml.Node)) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			if node.Content[i].Value == "initContainers" && value.Kind == yaml.SequenceNode {
+				for _, container := range value.Content {
+					if imageNode := mappingValue(container, "image"); imageNode != nil {
+						visit(imageNode)
+					}
+				}
+				continue
+			}
+			walkHelmInitContainers(value, visit)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			walkHelmInitContainers(child, visit)
+		}
+	}
+}
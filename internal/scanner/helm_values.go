@@ -108,7 +108,7 @@ func (fs *FileScanner) scanHelmValues(content, filePath string, publicImageMap m
 					Bool("is_public_registry", true).
 					Send()
 
-				if _, isInCluster := publicImageMap[fullImage]; isInCluster {
+				if _, isInCluster := lookupPublicImage(publicImageMap, fullImage); isInCluster {
 					detection := types.ImageDetectionResult{
 						Image:      fullImage,
 						Repository: utils.ExtractRepository(fullImage),
@@ -152,18 +152,24 @@ func (fs *FileScanner) scanHelmValues(content, filePath string, publicImageMap m
 		}
 	}
 
+	detections = append(detections, fs.scanHelmGlobalRegistry(content, filePath, publicImageMap, detections)...)
+
 	inlineDetections := fs.scanGenericYAML(content, filePath, publicImageMap)
 	detections = append(detections, inlineDetections...)
 
+	detections = append(detections, fs.scanHelmInitContainers(content, filePath, publicImageMap, detections)...)
+
 	return detections
 }
 
 func (fs *FileScanner) repositoryContainsRegistry(repository string) bool {
 	parts := strings.Split(repository, "/")
-	if len(parts) >= 2 && strings.Contains(parts[0], ".") {
-		return true
+	if len(parts) < 2 {
+		return false
 	}
-	return false
+
+	host := parts[0]
+	return strings.Contains(host, ".") || strings.Contains(host, ":") || host == "localhost"
 }
 
 func (fs *FileScanner) extractRegistryFromRepository(repository string) string {
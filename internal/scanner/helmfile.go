@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+func isHelmfileFile(filePath string) bool {
+	return strings.HasSuffix(strings.ToLower(filePath), ".gotmpl") || isHelmfileManifest(filePath)
+}
+
+func isHelmfileManifest(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	return strings.HasPrefix(path.Base(lower), "helmfile") || strings.Contains("/"+lower, "/helmfile.d/")
+}
+
+func stripGoTemplateLines(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "{{") || strings.Contains(line, "}}") {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (fs *FileScanner) scanHelmfile(ctx context.Context, readFile fileReader, content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+	literalContent := stripGoTemplateLines(content)
+	detections := fs.scanHelmValues(literalContent, filePath, publicImageMap)
+
+	if !isHelmfileManifest(filePath) {
+		return detections
+	}
+
+	baseDir := path.Dir(filePath)
+	visited := map[string]bool{filePath: true}
+
+	for _, valuesFile := range helmfileValuesFiles(literalContent) {
+		if strings.Contains(valuesFile, "://") || strings.Contains(valuesFile, "::") {
+			fs.logger.Warn("helmfile_remote_values_skipped").
+				Str("file", filePath).
+				Str("values", valuesFile).
+				Send()
+			continue
+		}
+
+		valuesPath := path.Clean(path.Join(baseDir, valuesFile))
+		if visited[valuesPath] {
+			continue
+		}
+		visited[valuesPath] = true
+
+		valuesContent, err := readFile(ctx, valuesPath)
+		if err != nil {
+			fs.logger.Warn("helmfile_values_read_failed").
+				Str("file", filePath).
+				Str("values", valuesPath).
+				Err(err).
+				Send()
+			continue
+		}
+
+		detections = append(detections, fs.scanHelmValues(stripGoTemplateLines(valuesContent), valuesPath, publicImageMap)...)
+	}
+
+	return detections
+}
+
+func helmfileValuesFiles(content string) []string {
+	var files []string
+
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(content)))
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); err != nil {
+			if !errors.Is(err, io.EOF) {
+				return files
+			}
+			break
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+
+		releases := mappingValue(document.Content[0], "releases")
+		if releases == nil || releases.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, release := range releases.Content {
+			files = append(files, sequenceValues(mappingValue(release, "values"))...)
+		}
+	}
+
+	return files
+}
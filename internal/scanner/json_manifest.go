@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+var jsonImagePattern = regexp.MustCompile(`"image"\s*:\s*"([^"]+)"`)
+
+type jsonImageReference struct {
+	image      string
+	lineNumber int
+	context    string
+}
+
+func (fs *FileScanner) scanJSONManifest(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+	var detections []types.ImageDetectionResult
+
+	for _, ref := range fs.jsonImageReferences(content, filePath) {
+		if _, isPublic := lookupPublicImage(publicImageMap, ref.image); !isPublic {
+			continue
+		}
+
+		detections = append(detections, types.ImageDetectionResult{
+			Image:      ref.image,
+			Repository: fs.extractRepository(ref.image),
+			Tag:        fs.extractTag(ref.image),
+			Digest:     fs.extractDigest(ref.image),
+			Registry:   fs.extractRegistry(ref.image),
+			FullImage:  ref.image,
+			IsPublic:   true,
+			LineNumber: ref.lineNumber,
+			Context:    ref.context,
+			Confidence: 1.0,
+			FilePath:   filePath,
+		})
+
+		fs.logger.Debug("json_image_detected").
+			Str("file", filePath).
+			Str("image", ref.image).
+			Int("line", ref.lineNumber).
+			Send()
+	}
+
+	return detections
+}
+
+func (fs *FileScanner) scanJSONRepoOnlyImages(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+	var repoOnly []types.ImageDetectionResult
+
+	for _, ref := range fs.jsonImageReferences(content, filePath) {
+		if strings.ContainsAny(ref.image, "{}$") {
+			continue
+		}
+
+		if _, inCluster := lookupPublicImage(publicImageMap, ref.image); inCluster {
+			continue
+		}
+
+		if !fs.classifier.IsPublic(ref.image) {
+			continue
+		}
+
+		repoOnly = append(repoOnly, types.ImageDetectionResult{
+			Image:      ref.image,
+			Repository: fs.extractRepository(ref.image),
+			Tag:        fs.extractTag(ref.image),
+			Digest:     fs.extractDigest(ref.image),
+			Registry:   fs.extractRegistry(ref.image),
+			FullImage:  ref.image,
+			IsPublic:   true,
+			LineNumber: ref.lineNumber,
+			Context:    ref.context,
+			Confidence: 0.5,
+			FilePath:   filePath,
+		})
+	}
+
+	return repoOnly
+}
+
+func (fs *FileScanner) jsonImageReferences(content, filePath string) []jsonImageReference {
+	var document interface{}
+	if err := json.Unmarshal([]byte(content), &document); err != nil {
+		fs.logger.Warn("invalid_json_manifest").
+			Str("file", filePath).
+			Err(err).
+			Send()
+		return nil
+	}
+
+	var images []string
+	collectJSONImages(document, &images)
+	if len(images) == 0 {
+		return nil
+	}
+
+	occurrences := make(map[string][]jsonImageReference)
+	for lineNum, line := range strings.Split(content, "\n") {
+		for _, match := range jsonImagePattern.FindAllStringSubmatch(line, -1) {
+			var image string
+			if err := json.Unmarshal([]byte(`"`+match[1]+`"`), &image); err != nil {
+				continue
+			}
+			occurrences[image] = append(occurrences[image], jsonImageReference{
+				image:      image,
+				lineNumber: lineNum + 1,
+				context:    strings.TrimSpace(match[0]),
+			})
+		}
+	}
+
+	var references []jsonImageReference
+	for _, image := range images {
+		candidates := occurrences[image]
+		if len(candidates) == 0 {
+			continue
+		}
+		references = append(references, candidates[0])
+		occurrences[image] = candidates[1:]
+	}
+
+	sort.SliceStable(references, func(i, j int) bool {
+		return references[i].lineNumber < references[j].lineNumber
+	})
+
+	return references
+}
+
+func collectJSONImages(node interface{}, images *[]string) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if image, ok := child.(string); ok && key == "image" {
+				if image != "" {
+					*images = append(*images, image)
+				}
+				continue
+			}
+			collectJSONImages(child, images)
+		}
+	case []interface{}:
+		for _, child := range value {
+			collectJSONImages(child, images)
+		}
+	}
+}
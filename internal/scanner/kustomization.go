@@ -1,13 +1,159 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"gopkg.in/yaml.v3"
 )
 
-func (fs *FileScanner) scanKustomization(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+func isKustomizationFile(filePath string) bool {
+	return strings.Contains(strings.ToLower(path.Base(filePath)), "kustomization")
+}
+
+func isRemoteKustomizeRef(ref string) bool {
+	return strings.Contains(ref, "://") ||
+		strings.HasPrefix(ref, "git@") ||
+		strings.HasPrefix(ref, "github.com/") ||
+		strings.HasPrefix(ref, "gitlab.com/") ||
+		strings.HasPrefix(ref, "bitbucket.org/") ||
+		strings.Contains(ref, "?ref=")
+}
+
+func (fs *FileScanner) scanKustomization(ctx context.Context, readFile fileReader, content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+	return fs.scanKustomizationTree(ctx, readFile, content, filePath, publicImageMap, map[string]bool{filePath: true})
+}
+
+func (fs *FileScanner) scanKustomizationTree(ctx context.Context, readFile fileReader, content, filePath string, publicImageMap map[string]*types.ImageInfo, visited map[string]bool) []types.ImageDetectionResult {
+	detections := withFilePath(fs.scanKustomizationImages(content, filePath, publicImageMap), filePath)
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil || len(root.Content) == 0 {
+		return detections
+	}
+	document := root.Content[0]
+	baseDir := path.Dir(filePath)
+
+	for _, ref := range kustomizationRemoteRefs(document) {
+		fs.logger.Warn("kustomize_remote_base_skipped").
+			Str("file", filePath).
+			Str("ref", ref).
+			Send()
+	}
+
+	for _, component := range sequenceValues(mappingValue(document, "components")) {
+		if isRemoteKustomizeRef(component) {
+			continue
+		}
+
+		componentDir := path.Clean(path.Join(baseDir, component))
+		componentFile, componentContent, found := fs.readKustomization(ctx, readFile, componentDir)
+		if !found {
+			fs.logger.Warn("kustomize_component_not_found").
+				Str("file", filePath).
+				Str("component", component).
+				Send()
+			continue
+		}
+		if visited[componentFile] {
+			continue
+		}
+		visited[componentFile] = true
+
+		detections = append(detections, fs.scanKustomizationTree(ctx, readFile, componentContent, componentFile, publicImageMap, visited)...)
+	}
+
+	for _, patch := range kustomizationPatchFiles(document) {
+		patchFile := path.Clean(path.Join(baseDir, patch))
+		if visited[patchFile] {
+			continue
+		}
+		visited[patchFile] = true
+
+		patchContent, err := readFile(ctx, patchFile)
+		if err != nil {
+			fs.logger.Warn("kustomize_patch_read_failed").
+				Str("file", filePath).
+				Str("patch", patchFile).
+				Err(err).
+				Send()
+			continue
+		}
+
+		detections = append(detections, withFilePath(fs.scanKubernetesManifest(patchContent, patchFile, publicImageMap), patchFile)...)
+	}
+
+	return detections
+}
+
+func (fs *FileScanner) readKustomization(ctx context.Context, readFile fileReader, dir string) (string, string, bool) {
+	for _, name := range kustomizationFileNames {
+		candidate := path.Join(dir, name)
+		if content, err := readFile(ctx, candidate); err == nil {
+			return candidate, content, true
+		}
+	}
+	return "", "", false
+}
+
+func kustomizationRemoteRefs(document *yaml.Node) []string {
+	var refs []string
+	for _, key := range []string{"resources", "bases", "components"} {
+		for _, ref := range sequenceValues(mappingValue(document, key)) {
+			if isRemoteKustomizeRef(ref) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+func kustomizationPatchFiles(document *yaml.Node) []string {
+	var files []string
+	for _, patch := range sequenceValues(mappingValue(document, "patchesStrategicMerge")) {
+		if !strings.Contains(patch, "\n") {
+			files = append(files, patch)
+		}
+	}
+
+	patches := mappingValue(document, "patches")
+	if patches != nil && patches.Kind == yaml.SequenceNode {
+		for _, patch := range patches.Content {
+			if patchPath := mappingValue(patch, "path"); patchPath != nil && patchPath.Value != "" {
+				files = append(files, patchPath.Value)
+			}
+		}
+	}
+	return files
+}
+
+func sequenceValues(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var values []string
+	for _, item := range node.Content {
+		if item.Kind == yaml.ScalarNode && item.Value != "" {
+			values = append(values, item.Value)
+		}
+	}
+	return values
+}
+
+func withFilePath(detections []types.ImageDetectionResult, filePath string) []types.ImageDetectionResult {
+	for i := range detections {
+		detections[i].FilePath = filePath
+	}
+	return detections
+}
+
+func (fs *FileScanner) scanKustomizationImages(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
 	var detections []types.ImageDetectionResult
 	lines := strings.Split(content, "\n")
 
@@ -26,7 +172,7 @@ func (fs *FileScanner) scanKustomization(content, filePath string, publicImageMa
 		if currentNewName != "" && currentNewTag != "" {
 			fullImage := fmt.Sprintf("%s:%s", currentNewName, currentNewTag)
 
-			if _, isPublic := publicImageMap[fullImage]; isPublic {
+			if _, isPublic := lookupPublicImage(publicImageMap, fullImage); isPublic {
 				detections = append(detections, types.ImageDetectionResult{
 					Image:      fullImage,
 					Repository: currentNewName,
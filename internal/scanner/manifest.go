@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
 )
 
 func (fs *FileScanner) scanKubernetesManifest(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
@@ -13,11 +14,19 @@ func (fs *FileScanner) scanKubernetesManifest(content, filePath string, publicIm
 	for lineNum, line := range lines {
 		if matches := imagePatterns["yaml_image"].FindStringSubmatch(line); len(matches) > 1 {
 			imageName := matches[1]
-			if _, isPublic := publicImageMap[imageName]; isPublic {
+			if utils.IsTemplatedImage(imageName) {
+				fs.logger.Debug("templated_image_skipped").
+					Str("file", filePath).
+					Int("line", lineNum+1).
+					Send()
+				continue
+			}
+			if _, isPublic := lookupPublicImage(publicImageMap, imageName); isPublic {
 				detections = append(detections, types.ImageDetectionResult{
 					Image:      imageName,
 					Repository: fs.extractRepository(imageName),
 					Tag:        fs.extractTag(imageName),
+					Digest:     fs.extractDigest(imageName),
 					Registry:   fs.extractRegistry(imageName),
 					FullImage:  imageName,
 					IsPublic:   true,
@@ -36,5 +45,45 @@ func (fs *FileScanner) scanKubernetesManifest(content, filePath string, publicIm
 		}
 	}
 
+	detections = append(detections, fs.scanEnvImageValues(content, filePath, publicImageMap)...)
+	detections = append(detections, fs.scanBlockScalars(content, filePath, publicImageMap, detections)...)
+
+	return detections
+}
+
+func (fs *FileScanner) scanEnvImageValues(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+	var detections []types.ImageDetectionResult
+	lines := strings.Split(content, "\n")
+
+	for _, envValue := range utils.FindEnvValues(lines) {
+		imageName := envValue.Value
+		if utils.IsTemplatedImage(imageName) {
+			continue
+		}
+		if _, isPublic := lookupPublicImage(publicImageMap, imageName); !isPublic {
+			continue
+		}
+
+		detections = append(detections, types.ImageDetectionResult{
+			Image:      imageName,
+			Repository: fs.extractRepository(imageName),
+			Tag:        fs.extractTag(imageName),
+			Digest:     fs.extractDigest(imageName),
+			Registry:   fs.extractRegistry(imageName),
+			FullImage:  imageName,
+			IsPublic:   true,
+			LineNumber: envValue.Line + 1,
+			Context:    strings.TrimSpace(lines[envValue.Line]),
+			Confidence: 0.4,
+			FilePath:   filePath,
+		})
+
+		fs.logger.Debug("env_image_detected").
+			Str("file", filePath).
+			Str("image", imageName).
+			Int("line", envValue.Line+1).
+			Send()
+	}
+
 	return detections
 }
@@ -5,17 +5,31 @@ import (
 	"encoding/base64"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/kevinfinalboss/privateer/internal/classifier"
 	"github.com/kevinfinalboss/privateer/internal/github"
 	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/state"
 	"github.com/kevinfinalboss/privateer/pkg/types"
+	"github.com/kevinfinalboss/privateer/pkg/utils"
 )
 
 type FileScanner struct {
 	githubClient *github.Client
+	sshReader    *github.SSHRepositoryReader
+	classifier   *classifier.Classifier
 	logger       *logger.Logger
 	config       *types.Config
+	state        *state.Store
+}
+
+type RepositoryScanResult struct {
+	Repository     string
+	Detections     []types.ImageDetectionResult
+	RepoOnlyImages []types.ImageDetectionResult
+	Error          error
 }
 
 type FileType int
@@ -27,6 +41,9 @@ const (
 	FileTypeArgoCDApplication
 	FileTypeKustomization
 	FileTypeDockerCompose
+	FileTypeJSONManifest
+	FileTypeHelmChart
+	FileTypeHelmfile
 )
 
 var (
@@ -51,35 +68,57 @@ var (
 func NewFileScanner(githubClient *github.Client, logger *logger.Logger, config *types.Config) *FileScanner {
 	return &FileScanner{
 		githubClient: githubClient,
+		sshReader:    github.NewSSHRepositoryReader(logger),
+		classifier:   classifier.New(config, logger),
 		logger:       logger,
 		config:       config,
 	}
 }
 
+func (fs *FileScanner) UseState(store *state.Store) *FileScanner {
+	fs.state = store
+	return fs
+}
+
+type fileReader func(ctx context.Context, filePath string) (string, error)
+
 func (fs *FileScanner) ScanRepositoryForImages(ctx context.Context, repoConfig types.GitHubRepositoryConfig, publicImages []*types.ImageInfo) ([]types.ImageDetectionResult, error) {
+	detections, _, err := fs.scanRepository(ctx, repoConfig, publicImages)
+	return detections, err
+}
+
+func (fs *FileScanner) scanRepository(ctx context.Context, repoConfig types.GitHubRepositoryConfig, publicImages []*types.ImageInfo) ([]types.ImageDetectionResult, []types.ImageDetectionResult, error) {
 	fs.logger.Info("scanning_repository_for_images").
 		Str("repository", repoConfig.Name).
 		Int("public_images", len(publicImages)).
 		Send()
 
-	owner, repo, err := fs.parseRepositoryName(repoConfig.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	repoManager := github.NewRepositoryManager(fs.githubClient)
-	files, err := repoManager.ListRepositoryFiles(ctx, repoConfig)
+	files, readFile, closeRepository, err := fs.openRepository(ctx, repoConfig)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao listar arquivos do repositório: %w", err)
+		return nil, nil, err
 	}
+	defer closeRepository()
 
-	relevantFiles := repoManager.GetFilesByExtension(files, []string{"yaml", "yml"})
+	relevantFiles := github.NewRepositoryManager(fs.githubClient).GetFilesByExtension(files, []string{"yaml", "yml", "json", "gotmpl"})
 
 	var allDetections []types.ImageDetectionResult
+	var repoOnlyImages []types.ImageDetectionResult
 	publicImageMap := fs.createPublicImageMap(publicImages)
 
+	cache := loadDetectionCache(fs.state, repoConfig.Name)
+	fingerprint := fs.detectionFingerprint(publicImageMap)
+	cachedFiles := 0
+
 	for _, file := range relevantFiles {
-		detections, err := fs.scanFile(ctx, owner, repo, file.Path, publicImageMap)
+		cacheable := (!fs.config.GitOps.ScanDockerfiles || !isDockerComposeFile(file.Path)) && !isKustomizationFile(file.Path) && !isHelmfileManifest(file.Path)
+		if entry, found := cache.get(file.Path, file.SHA, fingerprint); cacheable && found {
+			allDetections = append(allDetections, entry.Detections...)
+			repoOnlyImages = append(repoOnlyImages, entry.RepoOnlyImages...)
+			cachedFiles++
+			continue
+		}
+
+		detections, repoOnly, err := fs.scanFile(ctx, readFile, file, publicImageMap)
 		if err != nil {
 			fs.logger.Warn("file_scan_failed").
 				Str("file", file.Path).
@@ -88,43 +127,160 @@ func (fs *FileScanner) ScanRepositoryForImages(ctx context.Context, repoConfig t
 			continue
 		}
 
+		if cacheable {
+			cache.put(file.Path, file.SHA, fingerprint, detections, repoOnly)
+		}
 		allDetections = append(allDetections, detections...)
+		repoOnlyImages = append(repoOnlyImages, repoOnly...)
 	}
 
+	if err := cache.save(); err != nil {
+		fs.logger.Warn("detection_cache_save_failed").
+			Str("repository", repoConfig.Name).
+			Err(err).
+			Send()
+	}
+
+	allDetections = dedupeDetections(allDetections)
+
 	fs.logger.Info("repository_scan_completed").
 		Str("repository", repoConfig.Name).
 		Int("files_scanned", len(relevantFiles)).
+		Int("files_cached", cachedFiles).
 		Int("images_detected", len(allDetections)).
+		Int("repo_only_images", len(repoOnlyImages)).
 		Send()
 
-	return allDetections, nil
+	return allDetections, repoOnlyImages, nil
+}
+
+func dedupeDetections(detections []types.ImageDetectionResult) []types.ImageDetectionResult {
+	seen := make(map[string]bool)
+	unique := detections[:0]
+	for _, detection := range detections {
+		key := fmt.Sprintf("%s:%d:%s", detection.FilePath, detection.LineNumber, detection.FullImage)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, detection)
+	}
+	return unique
+}
+
+func (fs *FileScanner) ScanRepositories(ctx context.Context, publicImages []*types.ImageInfo) []RepositoryScanResult {
+	var repositories []types.GitHubRepositoryConfig
+	for _, repo := range fs.config.GitHub.Repositories {
+		if repo.Enabled {
+			repositories = append(repositories, repo)
+		}
+	}
+
+	sort.SliceStable(repositories, func(i, j int) bool {
+		return repositories[i].Priority > repositories[j].Priority
+	})
+
+	results := make([]RepositoryScanResult, 0, len(repositories))
+	for _, repoConfig := range repositories {
+		detections, repoOnlyImages, err := fs.scanRepository(ctx, repoConfig, publicImages)
+		if err != nil {
+			fs.logger.Error("repository_scan_failed").
+				Str("repository", repoConfig.Name).
+				Err(err).
+				Send()
+		}
+
+		results = append(results, RepositoryScanResult{
+			Repository:     repoConfig.Name,
+			Detections:     detections,
+			RepoOnlyImages: repoOnlyImages,
+			Error:          err,
+		})
+	}
+
+	return results
+}
+
+func (fs *FileScanner) openRepository(ctx context.Context, repoConfig types.GitHubRepositoryConfig) ([]types.TreeEntry, fileReader, func(), error) {
+	if github.UsesSSH(repoConfig) {
+		checkout, err := fs.sshReader.Clone(ctx, repoConfig)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		files, err := checkout.ListFiles()
+		if err != nil {
+			checkout.Close()
+			return nil, nil, nil, err
+		}
+
+		readFile := func(ctx context.Context, filePath string) (string, error) {
+			return checkout.ReadFile(filePath)
+		}
+		closeCheckout := func() {
+			if err := checkout.Close(); err != nil {
+				fs.logger.Warn("ssh_checkout_cleanup_failed").
+					Str("repository", repoConfig.Name).
+					Err(err).
+					Send()
+			}
+		}
+		return files, readFile, closeCheckout, nil
+	}
+
+	owner, repo, err := fs.parseRepositoryName(repoConfig.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	files, err := github.NewRepositoryManager(fs.githubClient).ListRepositoryFiles(ctx, repoConfig)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("falha ao listar arquivos do repositório: %w", err)
+	}
+
+	return files, fs.githubFileReader(owner, repo, repoConfig.PRSettings.BaseBranch), func() {}, nil
+}
+
+func (fs *FileScanner) githubFileReader(owner, repo, ref string) fileReader {
+	return func(ctx context.Context, filePath string) (string, error) {
+		content, err := fs.githubClient.GetFileContent(ctx, owner, repo, filePath, ref)
+		if err != nil {
+			return "", err
+		}
+
+		decodedContent, err := base64.StdEncoding.DecodeString(content.Content)
+		if err != nil {
+			return "", fmt.Errorf("falha ao decodificar conteúdo: %w", err)
+		}
+		return string(decodedContent), nil
+	}
 }
 
-func (fs *FileScanner) scanFile(ctx context.Context, owner, repo, filePath string, publicImageMap map[string]*types.ImageInfo) ([]types.ImageDetectionResult, error) {
+func (fs *FileScanner) scanFile(ctx context.Context, readFile fileReader, file types.TreeEntry, publicImageMap map[string]*types.ImageInfo) ([]types.ImageDetectionResult, []types.ImageDetectionResult, error) {
+	filePath := file.Path
+
+	if fs.exceedsMaxFileSize(filePath, int64(file.Size)) {
+		return nil, nil, nil
+	}
+
 	fs.logger.Debug("scanning_file_for_images").
 		Str("file", filePath).
 		Int("public_images_to_check", len(publicImageMap)).
 		Send()
 
-	content, err := fs.githubClient.GetFileContent(ctx, owner, repo, filePath, "")
+	fileContent, err := readFile(ctx, filePath)
 	if err != nil {
 		fs.logger.Error("failed_to_get_file_content").
 			Str("file", filePath).
 			Err(err).
 			Send()
-		return nil, err
+		return nil, nil, err
 	}
 
-	decodedContent, err := base64.StdEncoding.DecodeString(content.Content)
-	if err != nil {
-		fs.logger.Error("failed_to_decode_file_content").
-			Str("file", filePath).
-			Err(err).
-			Send()
-		return nil, fmt.Errorf("falha ao decodificar conteúdo: %w", err)
+	if fs.exceedsMaxFileSize(filePath, int64(len(fileContent))) {
+		return nil, nil, nil
 	}
 
-	fileContent := string(decodedContent)
 	fileType := fs.detectFileType(fileContent, filePath)
 
 	fs.logger.Debug("file_analysis_info").
@@ -143,7 +299,14 @@ func (fs *FileScanner) scanFile(ctx context.Context, owner, repo, filePath strin
 	case FileTypeArgoCDApplication:
 		detections = fs.scanArgoCDApplication(fileContent, filePath, publicImageMap)
 	case FileTypeKustomization:
-		detections = fs.scanKustomization(fileContent, filePath, publicImageMap)
+		detections = fs.scanKustomization(ctx, readFile, fileContent, filePath, publicImageMap)
+	case FileTypeJSONManifest:
+		detections = fs.scanJSONManifest(fileContent, filePath, publicImageMap)
+	case FileTypeDockerCompose:
+		detections = fs.scanDockerCompose(ctx, readFile, fileContent, filePath, publicImageMap)
+	case FileTypeHelmfile:
+		detections = fs.scanHelmfile(ctx, readFile, fileContent, filePath, publicImageMap)
+	case FileTypeHelmChart:
 	default:
 		detections = fs.scanGenericYAML(fileContent, filePath, publicImageMap)
 	}
@@ -173,7 +336,28 @@ func (fs *FileScanner) scanFile(ctx context.Context, owner, repo, filePath strin
 			Send()
 	}
 
-	return detections, nil
+	switch fileType {
+	case FileTypeJSONManifest:
+		return detections, fs.scanJSONRepoOnlyImages(fileContent, filePath, publicImageMap), nil
+	case FileTypeHelmChart:
+		return detections, fs.scanHelmChartDependencies(fileContent, filePath), nil
+	}
+
+	return detections, fs.scanRepoOnlyImages(fileContent, filePath, publicImageMap), nil
+}
+
+func (fs *FileScanner) exceedsMaxFileSize(filePath string, size int64) bool {
+	maxFileSize := fs.config.GitOps.MaxFileSize
+	if maxFileSize <= 0 || size <= maxFileSize {
+		return false
+	}
+
+	fs.logger.Debug("file_skipped_max_size").
+		Str("file", filePath).
+		Int64("size", size).
+		Int64("max_file_size", maxFileSize).
+		Send()
+	return true
 }
 
 func (fs *FileScanner) scanGenericYAML(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
@@ -183,11 +367,19 @@ func (fs *FileScanner) scanGenericYAML(content, filePath string, publicImageMap
 	for lineNum, line := range lines {
 		if matches := imagePatterns["yaml_image"].FindStringSubmatch(line); len(matches) > 1 {
 			imageName := matches[1]
-			if _, isPublic := publicImageMap[imageName]; isPublic {
+			if utils.IsTemplatedImage(imageName) {
+				fs.logger.Debug("templated_image_skipped").
+					Str("file", filePath).
+					Int("line", lineNum+1).
+					Send()
+				continue
+			}
+			if _, isPublic := lookupPublicImage(publicImageMap, imageName); isPublic {
 				detections = append(detections, types.ImageDetectionResult{
 					Image:      imageName,
 					Repository: fs.extractRepository(imageName),
 					Tag:        fs.extractTag(imageName),
+					Digest:     fs.extractDigest(imageName),
 					Registry:   fs.extractRegistry(imageName),
 					FullImage:  imageName,
 					IsPublic:   true,
@@ -200,12 +392,72 @@ func (fs *FileScanner) scanGenericYAML(content, filePath string, publicImageMap
 		}
 	}
 
+	detections = append(detections, fs.scanEnvImageValues(content, filePath, publicImageMap)...)
+	detections = append(detections, fs.scanBlockScalars(content, filePath, publicImageMap, detections)...)
+
 	return detections
 }
 
+func (fs *FileScanner) scanRepoOnlyImages(content, filePath string, publicImageMap map[string]*types.ImageInfo) []types.ImageDetectionResult {
+	var repoOnly []types.ImageDetectionResult
+	lines := strings.Split(content, "\n")
+
+	for lineNum, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		matches := imagePatterns["yaml_image"].FindStringSubmatch(line)
+		if len(matches) < 2 {
+			continue
+		}
+
+		imageName := matches[1]
+		if strings.ContainsAny(imageName, "{}$") {
+			continue
+		}
+
+		if _, inCluster := lookupPublicImage(publicImageMap, imageName); inCluster {
+			continue
+		}
+
+		if !fs.classifier.IsPublic(imageName) {
+			continue
+		}
+
+		repoOnly = append(repoOnly, types.ImageDetectionResult{
+			Image:      imageName,
+			Repository: fs.extractRepository(imageName),
+			Tag:        fs.extractTag(imageName),
+			Digest:     fs.extractDigest(imageName),
+			Registry:   fs.extractRegistry(imageName),
+			FullImage:  imageName,
+			IsPublic:   true,
+			LineNumber: lineNum + 1,
+			Context:    strings.TrimSpace(line),
+			Confidence: 0.5,
+			FilePath:   filePath,
+		})
+	}
+
+	return repoOnly
+}
+
 func (fs *FileScanner) detectFileType(content, filePath string) FileType {
 	fileName := strings.ToLower(filePath)
 
+	if strings.HasSuffix(fileName, ".json") {
+		return FileTypeJSONManifest
+	}
+
+	if isHelmChartFile(filePath) {
+		return FileTypeHelmChart
+	}
+
+	if isHelmfileFile(filePath) {
+		return FileTypeHelmfile
+	}
+
 	if strings.Contains(content, "apiVersion: argoproj.io") && strings.Contains(content, "kind: Application") {
 		return FileTypeArgoCDApplication
 	}
@@ -241,28 +493,59 @@ func (fs *FileScanner) detectFileType(content, filePath string) FileType {
 func (fs *FileScanner) createPublicImageMap(publicImages []*types.ImageInfo) map[string]*types.ImageInfo {
 	imageMap := make(map[string]*types.ImageInfo)
 	for _, img := range publicImages {
-		imageMap[img.Image] = img
+		key := normalizeImageKey(img.Image)
+		imageMap[key] = img
 
-		if !strings.Contains(img.Image, ":") {
-			imageMap[img.Image+":latest"] = img
+		if !strings.Contains(key, ":") {
+			imageMap[key+":latest"] = img
 		}
 	}
 	return imageMap
 }
 
-func (fs *FileScanner) extractRepository(imageName string) string {
-	if strings.Contains(imageName, ":") {
-		return strings.Split(imageName, ":")[0]
+func lookupPublicImage(publicImageMap map[string]*types.ImageInfo, imageName string) (*types.ImageInfo, bool) {
+	img, exists := publicImageMap[normalizeImageKey(imageName)]
+	return img, exists
+}
+
+func normalizeImageKey(imageName string) string {
+	imageName = strings.TrimSpace(imageName)
+	if utils.IsTemplatedImage(imageName) || strings.ContainsAny(imageName, " \t") {
+		return imageName
+	}
+
+	name, tag, digest := utils.SplitReference(imageName)
+	host, repository := utils.SplitHost(name)
+
+	key := host + "/" + repository
+	if tag == "" && digest == "" {
+		tag = "latest"
+	}
+	if tag != "" {
+		key += ":" + tag
 	}
-	return imageName
+	if digest != "" {
+		key += "@" + strings.ToLower(digest)
+	}
+	return key
+}
+
+func (fs *FileScanner) extractRepository(imageName string) string {
+	repository, _, _ := utils.SplitReference(imageName)
+	return repository
 }
 
 func (fs *FileScanner) extractTag(imageName string) string {
-	if strings.Contains(imageName, ":") {
-		parts := strings.Split(imageName, ":")
-		return parts[len(parts)-1]
+	_, tag, digest := utils.SplitReference(imageName)
+	if tag == "" && digest == "" {
+		return "latest"
 	}
-	return "latest"
+	return tag
+}
+
+func (fs *FileScanner) extractDigest(imageName string) string {
+	_, _, digest := utils.SplitReference(imageName)
+	return digest
 }
 
 func (fs *FileScanner) extractRegistry(imageName string) string {
@@ -286,7 +569,7 @@ func (fs *FileScanner) findLineNumber(content, searchText string) int {
 func (fs *FileScanner) parseRepositoryName(repoName string) (owner, repo string, err error) {
 	parts := strings.Split(repoName, "/")
 	if len(parts) != 2 {
-		return "", "", fmt.Errorf("formato de repositório inválido: %s", repoName)
+		return "", "", fmt.Errorf("%w: %s", types.ErrInvalidRepoName, repoName)
 	}
 	return parts[0], parts[1], nil
 }
@@ -303,6 +586,12 @@ func (fs *FileScanner) fileTypeToString(ft FileType) string {
 		return "kustomization"
 	case FileTypeDockerCompose:
 		return "docker_compose"
+	case FileTypeJSONManifest:
+		return "json_manifest"
+	case FileTypeHelmChart:
+		return "helm_chart"
+	case FileTypeHelmfile:
+		return "helmfile"
 	default:
 		return "unknown"
 	}
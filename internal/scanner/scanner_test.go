@@ -0,0 +1,1055 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/classifier"
+	"github.com/kevinfinalboss/privateer/internal/github"
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/internal/state"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+func newTestFileScanner() *FileScanner {
+	config := &types.Config{}
+	return &FileScanner{
+		classifier: classifier.New(config, logger.NewTest()),
+		logger:     logger.NewTest(),
+		config:     config,
+	}
+}
+
+func openTestState(t *testing.T) *state.Store {
+	store, err := state.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("state.Open() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestNormalizeImageKey(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"nginx:1.21", "docker.io/library/nginx:1.21"},
+		{"Docker.io/library/nginx:1.21", "docker.io/library/nginx:1.21"},
+		{"Docker.io/Library/Nginx:1.21", "docker.io/Library/Nginx:1.21"},
+		{"index.docker.io/library/nginx:1.21", "docker.io/library/nginx:1.21"},
+		{"Quay.IO/Prometheus/Node-Exporter:v1.0-RC", "quay.io/Prometheus/Node-Exporter:v1.0-RC"},
+		{"Registry.Example.com:5000/Team/App", "registry.example.com:5000/Team/App:latest"},
+		{"nginx@sha256:ABC", "docker.io/library/nginx@sha256:abc"},
+		{"{{ .Values.Image }}", "{{ .Values.Image }}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if result := normalizeImageKey(tt.image); result != tt.expected {
+				t.Errorf("normalizeImageKey(%s) = %s, expected %s", tt.image, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFileScanner_createPublicImageMap_CaseInsensitive(t *testing.T) {
+	fs := newTestFileScanner()
+
+	publicImages := []*types.ImageInfo{
+		{Image: "docker.io/bitnami/redis:7.0"},
+		{Image: "Quay.io/prometheus/node-exporter"},
+	}
+	publicImageMap := fs.createPublicImageMap(publicImages)
+
+	tests := []struct {
+		image    string
+		expected bool
+	}{
+		{"docker.io/bitnami/redis:7.0", true},
+		{"Docker.IO/bitnami/redis:7.0", true},
+		{"docker.io/Bitnami/redis:7.0", false},
+		{"Quay.io/Prometheus/node-exporter", false},
+		{"quay.io/prometheus/node-exporter", true},
+		{"quay.io/prometheus/node-exporter:latest", true},
+		{"docker.io/bitnami/redis:7.0-ALPINE", false},
+		{"bitnami/redis:7.0", true},
+		{"index.docker.io/bitnami/redis:7.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if _, found := lookupPublicImage(publicImageMap, tt.image); found != tt.expected {
+				t.Errorf("lookupPublicImage(%s) = %v, expected %v", tt.image, found, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFileScanner_scanGenericYAML_MixedCaseHost(t *testing.T) {
+	fs := newTestFileScanner()
+
+	publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{
+		{Image: "docker.io/bitnami/redis:7.0"},
+	})
+
+	content := "services:\n  cache:\n    image: Docker.io/bitnami/redis:7.0\n"
+	detections := fs.scanGenericYAML(content, "compose.yaml", publicImageMap)
+
+	if len(detections) != 1 {
+		t.Fatalf("expected 1 detection, got %d", len(detections))
+	}
+	if detections[0].Image != "Docker.io/bitnami/redis:7.0" {
+		t.Errorf("detection image = %s, expected original casing to be preserved", detections[0].Image)
+	}
+	if detections[0].LineNumber != 3 {
+		t.Errorf("detection line = %d, expected 3", detections[0].LineNumber)
+	}
+}
+
+func TestFileScanner_scanGenericYAML_DigestPinned(t *testing.T) {
+	fs := newTestFileScanner()
+
+	digest := "sha256:0f6a8d6a3f1d7e0c2b5e8c9a4b3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d"
+	publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{
+		{Image: "quay.io/prometheus/node-exporter@" + digest},
+		{Image: "registry.local:5000/team/app:1.2@" + digest},
+	})
+
+	content := "spec:\n  containers:\n    - name: exporter\n      image: quay.io/prometheus/node-exporter@" + digest +
+		"\n    - name: app\n      image: \"registry.local:5000/team/app:1.2@" + digest + "\"\n"
+	detections := fs.scanGenericYAML(content, "exporter.yaml", publicImageMap)
+
+	expected := []struct {
+		repository string
+		tag        string
+		line       int
+	}{
+		{"quay.io/prometheus/node-exporter", "", 4},
+		{"registry.local:5000/team/app", "1.2", 6},
+	}
+	if len(detections) != len(expected) {
+		t.Fatalf("expected %d detections, got %d: %+v", len(expected), len(detections), detections)
+	}
+	for i, want := range expected {
+		detection := detections[i]
+		if detection.Repository != want.repository || detection.Tag != want.tag || detection.Digest != digest {
+			t.Errorf("detection %d = repository %s, tag %q, digest %s; expected %s, %q, %s",
+				i, detection.Repository, detection.Tag, detection.Digest, want.repository, want.tag, digest)
+		}
+		if detection.LineNumber != want.line {
+			t.Errorf("detection %d line = %d, expected %d", i, detection.LineNumber, want.line)
+		}
+	}
+
+	if tag := fs.extractTag("nginx"); tag != "latest" {
+		t.Errorf("extractTag(nginx) = %s, expected latest", tag)
+	}
+}
+
+func TestFileScanner_repositoryContainsRegistry(t *testing.T) {
+	fs := newTestFileScanner()
+
+	tests := []struct {
+		repository       string
+		expected         bool
+		expectedRegistry string
+		expectedRepo     string
+	}{
+		{"quay.io/prometheus/node-exporter", true, "quay.io", "prometheus/node-exporter"},
+		{"registry.local:5000/team/app", true, "registry.local:5000", "team/app"},
+		{"myregistry:5000/app", true, "myregistry:5000", "app"},
+		{"localhost/app", true, "localhost", "app"},
+		{"bitnami/redis", false, "docker.io", "bitnami/redis"},
+		{"nginx", false, "docker.io", "nginx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repository, func(t *testing.T) {
+			if result := fs.repositoryContainsRegistry(tt.repository); result != tt.expected {
+				t.Errorf("repositoryContainsRegistry(%s) = %v, expected %v", tt.repository, result, tt.expected)
+			}
+			if registry := fs.extractRegistryFromRepository(tt.repository); registry != tt.expectedRegistry {
+				t.Errorf("extractRegistryFromRepository(%s) = %s, expected %s", tt.repository, registry, tt.expectedRegistry)
+			}
+			if repo := fs.extractRepositoryFromCombined(tt.repository); repo != tt.expectedRepo {
+				t.Errorf("extractRepositoryFromCombined(%s) = %s, expected %s", tt.repository, repo, tt.expectedRepo)
+			}
+		})
+	}
+}
+
+func TestFileScanner_scanHelmValues_GlobalRegistry(t *testing.T) {
+	fs := newTestFileScanner()
+
+	publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{
+		{Image: "quay.io/bitnami/postgresql:16.2.0"},
+		{Image: "quay.io/bitnami/postgres-exporter:0.15.0"},
+		{Image: "quay.io/prometheus/node-exporter:v1.6.0"},
+	})
+
+	content := `global:
+  imageRegistry: quay.io
+  storageClass: standard
+
+primary:
+  persistence:
+    size: 8Gi
+  resources:
+    limits:
+      memory: 512Mi
+  image:
+    repository: bitnami/postgresql
+    tag: "16.2.0"
+
+metrics:
+  enabled: true
+  image:
+    repository: bitnami/postgres-exporter
+    tag: 0.15.0
+
+nodeExporter:
+  image:
+    registry: ghcr.io
+    repository: prometheus/node-exporter
+    tag: v1.6.0
+`
+
+	detections := fs.scanHelmValues(content, "charts/postgresql/values.yaml", publicImageMap)
+
+	expected := map[string]int{
+		"quay.io/bitnami/postgresql:16.2.0":        12,
+		"quay.io/bitnami/postgres-exporter:0.15.0": 18,
+	}
+	if len(detections) != len(expected) {
+		t.Fatalf("expected %d detections, got %d: %+v", len(expected), len(detections), detections)
+	}
+	for _, detection := range detections {
+		line, ok := expected[detection.FullImage]
+		if !ok {
+			t.Errorf("unexpected detection %s", detection.FullImage)
+			continue
+		}
+		if detection.LineNumber != line {
+			t.Errorf("%s line = %d, expected %d", detection.FullImage, detection.LineNumber, line)
+		}
+		if detection.Registry != "quay.io" {
+			t.Errorf("%s registry = %s, expected quay.io", detection.FullImage, detection.Registry)
+		}
+	}
+}
+
+func TestFileScanner_scanHelmValues_InitContainers(t *testing.T) {
+	fs := newTestFileScanner()
+
+	publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{
+		{Image: "busybox:1.36"},
+		{Image: "alpine/git:2.43.0"},
+		{Image: "flyway/flyway:10.4"},
+	})
+
+	content := `replicaCount: 1
+image:
+  repository: ghcr.io/company/api
+  tag: 1.0.0
+initContainers:
+  - name: wait-for-db
+    image: busybox:1.36
+  - image: alpine/git:2.43.0
+    name: clone
+  - name: migrate
+    image:
+      repository: flyway/flyway
+      tag: "10.4"
+`
+
+	detections := fs.scanHelmValues(content, "values.yaml", publicImageMap)
+
+	var got []string
+	for _, detection := range detections {
+		got = append(got, fmt.Sprintf("%d:%s", detection.LineNumber, detection.FullImage))
+	}
+	sort.Strings(got)
+	expected := []string{"12:docker.io/flyway/flyway:10.4", "7:busybox:1.36", "8:alpine/git:2.43.0"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("detections = %v, expected %v", got, expected)
+	}
+}
+
+func TestFileScanner_scanKubernetesManifest_BlockScalar(t *testing.T) {
+	fs := newTestFileScanner()
+
+	publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{
+		{Image: "bitnami/redis:7.2"},
+		{Image: "nginx:1.21"},
+		{Image: "busybox:1.36"},
+	})
+
+	content := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: platform-apps
+data:
+  app.yaml: |
+    controller:
+      image:
+        repository: bitnami/redis
+        tag: "7.2"
+    sidecars:
+      - name: proxy
+        image: nginx:1.21
+  bootstrap.sh: |
+    echo "image: busybox:1.36"
+`
+
+	detections := fs.scanKubernetesManifest(content, "configmap.yaml", publicImageMap)
+
+	var got []string
+	for _, detection := range detections {
+		got = append(got, fmt.Sprintf("%d:%s", detection.LineNumber, detection.FullImage))
+	}
+	sort.Strings(got)
+	expected := []string{"13:nginx:1.21", "9:docker.io/bitnami/redis:7.2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("detections = %v, expected %v", got, expected)
+	}
+}
+
+func TestFileScanner_scanKubernetesManifest_EnvImage(t *testing.T) {
+	fs := newTestFileScanner()
+
+	publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{
+		{Image: "nginx:1.21"},
+		{Image: "busybox:1.36"},
+	})
+
+	content := `apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: company/app:2.0
+          env:
+            - name: SIDECAR_IMAGE
+              value: "nginx:1.21"
+            - name: LOG_LEVEL
+              value: debug
+            - name: PROXY_IMAGE
+              valueFrom:
+                configMapKeyRef:
+                  name: images
+                  key: proxy
+          args:
+            - value: busybox:1.36
+`
+	detections := fs.scanKubernetesManifest(content, "deployment.yaml", publicImageMap)
+
+	if len(detections) != 1 {
+		t.Fatalf("expected 1 detection, got %d: %+v", len(detections), detections)
+	}
+
+	detection := detections[0]
+	if detection.Image != "nginx:1.21" || detection.LineNumber != 11 {
+		t.Errorf("detection = %s at line %d, expected nginx:1.21 at line 11", detection.Image, detection.LineNumber)
+	}
+	if detection.Confidence >= 0.7 {
+		t.Errorf("env detection confidence = %v, expected low confidence", detection.Confidence)
+	}
+	if detection.Context != `value: "nginx:1.21"` {
+		t.Errorf("detection context = %q", detection.Context)
+	}
+}
+
+func TestFileScanner_scanKubernetesManifest_TemplatedImage(t *testing.T) {
+	fs := newTestFileScanner()
+
+	publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{
+		{Image: "nginx:1.21"},
+		{Image: "{{"},
+	})
+
+	content := `apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: {{ include "app.image" . }}
+        - name: proxy
+          image: "{{ .Values.proxy.repository }}:{{ .Values.proxy.tag }}"
+        - name: web
+          image: nginx:1.21
+`
+	detections := fs.scanKubernetesManifest(content, "templates/deployment.yaml", publicImageMap)
+	detections = append(detections, fs.scanGenericYAML(content, "templates/deployment.yaml", publicImageMap)...)
+
+	for _, detection := range detections {
+		if detection.LineNumber != 12 || detection.Image != "nginx:1.21" {
+			t.Errorf("unexpected detection %s at line %d", detection.Image, detection.LineNumber)
+		}
+	}
+	if len(detections) != 2 {
+		t.Errorf("expected the literal image to be detected once per scanner, got %d: %+v", len(detections), detections)
+	}
+}
+
+func TestFileScanner_scanDockerCompose_BuildAndImage(t *testing.T) {
+	content := `services:
+  api:
+    build:
+      context: ./api
+      dockerfile: Dockerfile.prod
+    image: redis:7.0
+  worker:
+    build: ./worker
+  db:
+    image: postgres:16
+`
+	dockerfiles := map[string]string{
+		"deploy/api/Dockerfile.prod": "FROM golang:1.22 AS builder\nRUN go build ./...\nFROM builder AS test\nFROM --platform=linux/amd64 alpine:3.19\n",
+		"deploy/worker/Dockerfile":   "FROM node:20\n",
+	}
+	readFile := func(ctx context.Context, filePath string) (string, error) {
+		content, found := dockerfiles[filePath]
+		if !found {
+			return "", fmt.Errorf("arquivo %s não encontrado", filePath)
+		}
+		return content, nil
+	}
+
+	tests := []struct {
+		name            string
+		scanDockerfiles bool
+		expected        []string
+	}{
+		{
+			name:     "image of services with build is still evaluated",
+			expected: []string{"deploy/docker-compose.yaml:6:redis:7.0", "deploy/docker-compose.yaml:10:postgres:16"},
+		},
+		{
+			name:            "referenced Dockerfile base images are scanned when enabled",
+			scanDockerfiles: true,
+			expected: []string{
+				"deploy/docker-compose.yaml:6:redis:7.0",
+				"deploy/docker-compose.yaml:10:postgres:16",
+				"deploy/api/Dockerfile.prod:1:golang:1.22",
+				"deploy/api/Dockerfile.prod:4:alpine:3.19",
+				"deploy/worker/Dockerfile:1:node:20",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newTestFileScanner()
+			fs.config.GitOps.ScanDockerfiles = tt.scanDockerfiles
+			publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{
+				{Image: "redis:7.0"}, {Image: "postgres:16"}, {Image: "golang:1.22"}, {Image: "alpine:3.19"}, {Image: "node:20"},
+			})
+
+			detections := fs.scanDockerCompose(context.Background(), readFile, content, "deploy/docker-compose.yaml", publicImageMap)
+
+			var got []string
+			for _, detection := range detections {
+				got = append(got, fmt.Sprintf("%s:%d:%s", detection.FilePath, detection.LineNumber, detection.FullImage))
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("detections = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+type testGitHubRepository struct {
+	mu      sync.Mutex
+	files   map[string]string
+	fetches map[string]int
+}
+
+func (r *testGitHubRepository) setFile(path, content string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[path] = content
+}
+
+func (r *testGitHubRepository) fetchCount(path string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fetches[path]
+}
+
+func newTestGitHubServer(t *testing.T, files map[string]string) *httptest.Server {
+	server, _ := newTestGitHubRepositoryServer(t, files)
+	return server
+}
+
+func newTestGitHubRepositoryServer(t *testing.T, files map[string]string) (*httptest.Server, *testGitHubRepository) {
+	repository := &testGitHubRepository{files: files, fetches: make(map[string]int)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repository.mu.Lock()
+		defer repository.mu.Unlock()
+
+		var response interface{}
+
+		switch {
+		case r.URL.Path == "/repos/company/manifests":
+			response = types.Repository{FullName: "company/manifests", DefaultBranch: "main"}
+		case r.URL.Path == "/repos/company/manifests/branches":
+			response = []types.Branch{{Name: "main", Commit: types.Commit{SHA: "abc123"}}}
+		case r.URL.Path == "/repos/company/manifests/git/trees/abc123":
+			tree := types.Tree{SHA: "abc123"}
+			for path, content := range repository.files {
+				tree.Tree = append(tree.Tree, types.TreeEntry{Path: path, Type: "blob", Size: len(content), SHA: fmt.Sprintf("%x", sha1.Sum([]byte(content)))})
+			}
+			response = tree
+		case strings.HasPrefix(r.URL.Path, "/repos/company/manifests/contents/"):
+			path := strings.TrimPrefix(r.URL.Path, "/repos/company/manifests/contents/")
+			content, found := repository.files[path]
+			if !found {
+				http.NotFound(w, r)
+				return
+			}
+			repository.fetches[path]++
+			response = types.FileContent{Path: path, Content: base64.StdEncoding.EncodeToString([]byte(content))}
+		default:
+			if r.Method != http.MethodGet {
+				t.Errorf("unexpected %s request to %s", r.Method, r.URL.Path)
+			}
+			http.NotFound(w, r)
+			return
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server, repository
+}
+
+func TestFileScanner_scanKustomization_ComponentsAndPatches(t *testing.T) {
+	content := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - ../../base
+  - github.com/example/platform//monitoring?ref=v1.0.0
+components:
+  - ../../components/cache
+patchesStrategicMerge:
+  - sidecar-patch.yaml
+`
+	files := map[string]string{
+		"components/cache/kustomization.yaml": `apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+images:
+  - name: redis
+    newName: redis
+    newTag: "7.0"
+`,
+		"overlays/prod/sidecar-patch.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      containers:
+        - name: proxy
+          image: nginx:1.25
+`,
+	}
+	readFile := func(ctx context.Context, filePath string) (string, error) {
+		content, found := files[filePath]
+		if !found {
+			return "", fmt.Errorf("arquivo %s não encontrado", filePath)
+		}
+		return content, nil
+	}
+
+	fs := newTestFileScanner()
+	publicImageMap := fs.createPublicImageMap([]*types.ImageInfo{{Image: "redis:7.0"}, {Image: "nginx:1.25"}})
+
+	detections := fs.scanKustomization(context.Background(), readFile, content, "overlays/prod/kustomization.yaml", publicImageMap)
+
+	var got []string
+	for _, detection := range detections {
+		got = append(got, fmt.Sprintf("%s:%d:%s", detection.FilePath, detection.LineNumber, detection.FullImage))
+	}
+	expected := []string{
+		"components/cache/kustomization.yaml:6:redis:7.0",
+		"overlays/prod/sidecar-patch.yaml:10:nginx:1.25",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("detections = %v, expected %v", got, expected)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		t.Fatalf("invalid kustomization: %v", err)
+	}
+	remoteRefs := kustomizationRemoteRefs(root.Content[0])
+	if !reflect.DeepEqual(remoteRefs, []string{"github.com/example/platform//monitoring?ref=v1.0.0"}) {
+		t.Errorf("remote refs = %v, expected only the GitHub base", remoteRefs)
+	}
+}
+
+func TestFileScanner_ScanRepositories(t *testing.T) {
+	server := newTestGitHubServer(t, map[string]string{
+		"apps/web/deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  template:\n    spec:\n      containers:\n        - name: web\n          image: nginx:1.25\n",
+		"apps/web/README.md":       "image: nginx:1.25\n",
+		"legacy/values.yaml":       "image: redis:7.0\n",
+	})
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Token:  "token",
+			APIURL: server.URL,
+			Repositories: []types.GitHubRepositoryConfig{
+				{Name: "company/manifests", Enabled: true, Paths: []string{"apps/"}},
+				{Name: "company/disabled", Enabled: false},
+			},
+		},
+	}
+
+	log := logger.NewTest()
+	fs := NewFileScanner(github.NewClient(&config.GitHub, log), log, config)
+	fs.UseState(openTestState(t))
+
+	results := fs.ScanRepositories(context.Background(), []*types.ImageInfo{
+		{Image: "nginx:1.25"},
+		{Image: "redis:7.0"},
+	})
+
+	if len(results) != 1 {
+		t.Fatalf("expected only the enabled repository to be scanned, got %+v", results)
+	}
+
+	result := results[0]
+	if result.Error != nil {
+		t.Fatalf("ScanRepositories() unexpected error: %v", result.Error)
+	}
+	if result.Repository != "company/manifests" || len(result.Detections) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	detection := result.Detections[0]
+	if detection.FilePath != "apps/web/deployment.yaml" || detection.LineNumber != 10 || detection.FullImage != "nginx:1.25" {
+		t.Errorf("detection = %s:%d %s, expected apps/web/deployment.yaml:10 nginx:1.25", detection.FilePath, detection.LineNumber, detection.FullImage)
+	}
+}
+
+func TestFileScanner_ScanRepositories_MaxFileSize(t *testing.T) {
+	server, repository := newTestGitHubRepositoryServer(t, map[string]string{
+		"apps/web.yaml":     "image: nginx:1.25\n",
+		"rendered/all.yaml": "image: redis:7.0\n" + strings.Repeat("# generated\n", 100),
+	})
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Token:        "token",
+			APIURL:       server.URL,
+			Repositories: []types.GitHubRepositoryConfig{{Name: "company/manifests", Enabled: true}},
+		},
+		GitOps: types.GitOpsConfig{MaxFileSize: 256},
+	}
+
+	log := logger.NewTest()
+	fs := NewFileScanner(github.NewClient(&config.GitHub, log), log, config)
+	fs.UseState(openTestState(t))
+
+	results := fs.ScanRepositories(context.Background(), []*types.ImageInfo{{Image: "nginx:1.25"}, {Image: "redis:7.0"}})
+
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if len(results[0].Detections) != 1 || results[0].Detections[0].FilePath != "apps/web.yaml" {
+		t.Errorf("detections = %+v, expected only apps/web.yaml", results[0].Detections)
+	}
+	if fetches := repository.fetchCount("rendered/all.yaml"); fetches != 0 {
+		t.Errorf("oversized file fetched %d times, expected 0", fetches)
+	}
+
+	readFile := func(ctx context.Context, filePath string) (string, error) {
+		return "image: redis:7.0\n" + strings.Repeat("# generated\n", 100), nil
+	}
+	detections, _, err := fs.scanFile(context.Background(), readFile, types.TreeEntry{Path: "rendered/all.yaml"},
+		fs.createPublicImageMap([]*types.ImageInfo{{Image: "redis:7.0"}}))
+	if err != nil || len(detections) != 0 {
+		t.Errorf("scanFile() without tree size = %+v, %v, expected oversized content to be skipped", detections, err)
+	}
+}
+
+func TestFileScanner_ScanRepositories_RepoOnlyImages(t *testing.T) {
+	server := newTestGitHubServer(t, map[string]string{
+		"apps/web/values.yaml": "web:\n  image: nginx:1.25\n" +
+			"exporter:\n  image: quay.io/prometheus/node-exporter:v1.6.0\n" +
+			"# image: busybox:1.36\n" +
+			"internal:\n  image: registry.company.com/team/app:1.0.0\n" +
+			"templated:\n  image: \"{{ .Values.image }}\"\n",
+	})
+
+	config := &types.Config{
+		Registries: []types.RegistryConfig{
+			{Name: "harbor", Type: "harbor", URL: "https://registry.company.com"},
+		},
+		GitHub: types.GitHubConfig{
+			Token:  "token",
+			APIURL: server.URL,
+			Repositories: []types.GitHubRepositoryConfig{
+				{Name: "company/manifests", Enabled: true},
+			},
+		},
+	}
+
+	log := logger.NewTest()
+	fs := NewFileScanner(github.NewClient(&config.GitHub, log), log, config)
+	fs.UseState(openTestState(t))
+
+	results := fs.ScanRepositories(context.Background(), []*types.ImageInfo{{Image: "nginx:1.25"}})
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	repoOnly := results[0].RepoOnlyImages
+	if len(repoOnly) != 1 {
+		t.Fatalf("expected 1 repo-only image, got %+v", repoOnly)
+	}
+	if repoOnly[0].FullImage != "quay.io/prometheus/node-exporter:v1.6.0" || repoOnly[0].LineNumber != 4 {
+		t.Errorf("repo-only image = %s at line %d", repoOnly[0].FullImage, repoOnly[0].LineNumber)
+	}
+
+	for _, detection := range results[0].Detections {
+		if detection.FullImage != "nginx:1.25" {
+			t.Errorf("unexpected cluster detection: %s", detection.FullImage)
+		}
+	}
+}
+
+func TestFileScanner_ScanRepositories_RepoOnlyImages_RegistryPatterns(t *testing.T) {
+	server := newTestGitHubServer(t, map[string]string{
+		"apps/web/values.yaml": "exporter:\n  image: quay.io/prometheus/node-exporter:v1.6.0\n" +
+			"internal:\n  image: quay.io/company/app:1.0.0\n" +
+			"debug:\n  image: docker.io/tools/debug:1.0\n",
+	})
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Token:        "token",
+			APIURL:       server.URL,
+			Repositories: []types.GitHubRepositoryConfig{{Name: "company/manifests", Enabled: true}},
+		},
+		ImageDetection: types.ImageDetectionConfig{
+			CustomPrivateRegistries: []string{`regex:^quay\.io/(company|internal)/`},
+			IgnoreRegistries:        []string{"docker.io/tools/*"},
+		},
+	}
+
+	log := logger.NewTest()
+	fs := NewFileScanner(github.NewClient(&config.GitHub, log), log, config)
+	fs.UseState(openTestState(t))
+
+	results := fs.ScanRepositories(context.Background(), nil)
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	repoOnly := results[0].RepoOnlyImages
+	if len(repoOnly) != 1 || repoOnly[0].FullImage != "quay.io/prometheus/node-exporter:v1.6.0" {
+		t.Errorf("repo-only images = %+v, expected only the public node-exporter image", repoOnly)
+	}
+}
+
+func TestFileScanner_ScanRepositories_DetectionCache(t *testing.T) {
+	deployment := "apiVersion: apps/v1\nkind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n        - name: web\n          image: nginx:1.25\n"
+	server, repository := newTestGitHubRepositoryServer(t, map[string]string{
+		"apps/web/deployment.yaml": deployment,
+		"apps/cache/values.yaml":   "image: redis:7.0\n",
+	})
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Token:        "token",
+			APIURL:       server.URL,
+			Repositories: []types.GitHubRepositoryConfig{{Name: "company/manifests", Enabled: true}},
+		},
+	}
+
+	log := logger.NewTest()
+	fs := NewFileScanner(github.NewClient(&config.GitHub, log), log, config)
+	fs.UseState(openTestState(t))
+
+	publicImages := []*types.ImageInfo{{Image: "nginx:1.25"}, {Image: "redis:7.0"}}
+
+	scan := func() RepositoryScanResult {
+		results := fs.ScanRepositories(context.Background(), publicImages)
+		if len(results) != 1 || results[0].Error != nil {
+			t.Fatalf("unexpected results: %+v", results)
+		}
+		return results[0]
+	}
+
+	first := scan()
+	second := scan()
+
+	if repository.fetchCount("apps/web/deployment.yaml") != 1 || repository.fetchCount("apps/cache/values.yaml") != 1 {
+		t.Error("unchanged files were fetched again on the second scan")
+	}
+	if len(second.Detections) != len(first.Detections) || len(second.Detections) != 2 {
+		t.Errorf("cached detections = %d, expected %d", len(second.Detections), len(first.Detections))
+	}
+
+	repository.setFile("apps/web/deployment.yaml", strings.Replace(deployment, "nginx:1.25", "nginx:1.25\n        - name: sidecar\n          image: redis:7.0", 1))
+	third := scan()
+
+	if repository.fetchCount("apps/web/deployment.yaml") != 2 {
+		t.Errorf("file with a changed SHA should be fetched again, got %d fetches", repository.fetchCount("apps/web/deployment.yaml"))
+	}
+	if repository.fetchCount("apps/cache/values.yaml") != 1 {
+		t.Errorf("unchanged file was fetched again, got %d fetches", repository.fetchCount("apps/cache/values.yaml"))
+	}
+	if len(third.Detections) != 3 {
+		t.Errorf("detections after change = %d, expected 3", len(third.Detections))
+	}
+
+	cached := loadDetectionCache(fs.state, "company/manifests")
+	if len(cached.Entries) == 0 {
+		t.Fatal("detection cache not written to the state store")
+	}
+	cached.Version = "0"
+	if err := fs.state.Put(detectionsBucket, "company/manifests", cached); err != nil {
+		t.Fatal(err)
+	}
+	scan()
+
+	if repository.fetchCount("apps/cache/values.yaml") != 2 {
+		t.Errorf("cache written by an older detector version should be discarded, got %d fetches", repository.fetchCount("apps/cache/values.yaml"))
+	}
+}
+
+func TestFileScanner_detectionFingerprint(t *testing.T) {
+	publicImageMap := map[string]*types.ImageInfo{"docker.io/library/nginx:1.25": {Image: "nginx:1.25"}}
+
+	fs := newTestFileScanner()
+	base := fs.detectionFingerprint(publicImageMap)
+	if base != fs.detectionFingerprint(publicImageMap) {
+		t.Fatal("fingerprint is not stable for the same config")
+	}
+
+	changes := map[string]func(config *types.Config){
+		"include_only":       func(c *types.Config) { c.ImageDetection.IncludeOnly = []string{"docker.io"} },
+		"no_migrate":         func(c *types.Config) { c.ImageDetection.NoMigrate = []string{"nginx"} },
+		"max_file_size":      func(c *types.Config) { c.GitOps.MaxFileSize = 1024 },
+		"scan_dockerfiles":   func(c *types.Config) { c.GitOps.ScanDockerfiles = true },
+		"ignore_registries":  func(c *types.Config) { c.ImageDetection.IgnoreRegistries = []string{"quay.io"} },
+		"registry_region":    func(c *types.Config) { c.Registries = []types.RegistryConfig{{Type: "ecr", Region: "us-east-1"}} },
+		"registry_account":   func(c *types.Config) { c.Registries = []types.RegistryConfig{{Type: "ecr", AccountID: "123456789012"}} },
+		"registry_path":      func(c *types.Config) { c.Registries = []types.RegistryConfig{{Type: "harbor", Path: "team"}} },
+		"registry_enabled":   func(c *types.Config) { c.Registries = []types.RegistryConfig{{Type: "harbor", Enabled: true}} },
+		"registry_role_name": func(c *types.Config) { c.Registries = []types.RegistryConfig{{Name: "mirror", Role: "validate"}} },
+	}
+
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			changed := newTestFileScanner()
+			change(changed.config)
+			if changed.detectionFingerprint(publicImageMap) == base {
+				t.Errorf("changing %s did not change the detection fingerprint", name)
+			}
+		})
+	}
+}
+
+func TestFileScanner_ScanRepositories_JSONManifest(t *testing.T) {
+	server := newTestGitHubServer(t, map[string]string{
+		"apps/web/deployment.json": "{\n" +
+			"  \"apiVersion\": \"apps/v1\",\n" +
+			"  \"kind\": \"Deployment\",\n" +
+			"  \"spec\": {\n" +
+			"    \"template\": {\n" +
+			"      \"spec\": {\n" +
+			"        \"containers\": [\n" +
+			"          {\"name\": \"web\", \"image\": \"nginx:1.25\"},\n" +
+			"          {\"name\": \"metrics\", \"image\": \"quay.io/prometheus/node-exporter:v1.6.0\"}\n" +
+			"        ]\n" +
+			"      }\n" +
+			"    }\n" +
+			"  }\n" +
+			"}\n",
+		"apps/web/package.json": "{\"name\": \"web\"}",
+	})
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Token:        "token",
+			APIURL:       server.URL,
+			Repositories: []types.GitHubRepositoryConfig{{Name: "company/manifests", Enabled: true}},
+		},
+	}
+
+	log := logger.NewTest()
+	fs := NewFileScanner(github.NewClient(&config.GitHub, log), log, config)
+	fs.UseState(openTestState(t))
+
+	results := fs.ScanRepositories(context.Background(), []*types.ImageInfo{{Image: "nginx:1.25"}})
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	result := results[0]
+	if len(result.Detections) != 1 {
+		t.Fatalf("expected 1 detection, got %+v", result.Detections)
+	}
+
+	detection := result.Detections[0]
+	if detection.FilePath != "apps/web/deployment.json" || detection.LineNumber != 8 || detection.FullImage != "nginx:1.25" {
+		t.Errorf("detection = %s:%d %s, expected apps/web/deployment.json:8 nginx:1.25", detection.FilePath, detection.LineNumber, detection.FullImage)
+	}
+	if detection.Context != `"image": "nginx:1.25"` {
+		t.Errorf("detection context = %q", detection.Context)
+	}
+
+	if len(result.RepoOnlyImages) != 1 || result.RepoOnlyImages[0].FullImage != "quay.io/prometheus/node-exporter:v1.6.0" {
+		t.Errorf("repo only images = %+v", result.RepoOnlyImages)
+	}
+}
+
+func TestFileScanner_ScanRepositories_HelmChartOCIDependencies(t *testing.T) {
+	server := newTestGitHubServer(t, map[string]string{
+		"charts/web/Chart.yaml": "apiVersion: v2\n" +
+			"name: web\n" +
+			"version: 1.0.0\n" +
+			"dependencies:\n" +
+			"  - name: redis\n" +
+			"    version: 18.1.5\n" +
+			"    repository: oci://registry-1.docker.io/bitnamicharts\n" +
+			"  - name: postgresql\n" +
+			"    version: \"^13.0.0\"\n" +
+			"    repository: oci://quay.io/company/charts\n" +
+			"  - name: common\n" +
+			"    version: 2.x.x\n" +
+			"    repository: https://charts.bitnami.com/bitnami\n" +
+			"  - name: internal\n" +
+			"    version: 0.3.0\n" +
+			"    repository: oci://harbor.company.com/charts\n",
+	})
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Token:        "token",
+			APIURL:       server.URL,
+			Repositories: []types.GitHubRepositoryConfig{{Name: "company/manifests", Enabled: true}},
+		},
+		Registries: []types.RegistryConfig{{Name: "harbor", Type: "harbor", URL: "https://harbor.company.com"}},
+	}
+
+	log := logger.NewTest()
+	fs := NewFileScanner(github.NewClient(&config.GitHub, log), log, config)
+	fs.UseState(openTestState(t))
+
+	results := fs.ScanRepositories(context.Background(), []*types.ImageInfo{{Image: "nginx:1.25"}})
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	result := results[0]
+	if len(result.Detections) != 0 {
+		t.Errorf("expected no cluster detections, got %+v", result.Detections)
+	}
+
+	expected := map[string]struct {
+		line int
+		tag  string
+	}{
+		"registry-1.docker.io/bitnamicharts/redis:18.1.5": {line: 7, tag: "18.1.5"},
+		"quay.io/company/charts/postgresql":               {line: 10},
+	}
+	if len(result.RepoOnlyImages) != len(expected) {
+		t.Fatalf("expected %d chart artifacts, got %+v", len(expected), result.RepoOnlyImages)
+	}
+	for _, artifact := range result.RepoOnlyImages {
+		want, ok := expected[artifact.FullImage]
+		if !ok {
+			t.Errorf("unexpected chart artifact %q", artifact.FullImage)
+			continue
+		}
+		if artifact.FilePath != "charts/web/Chart.yaml" || artifact.LineNumber != want.line {
+			t.Errorf("artifact %s at %s:%d, expected line %d", artifact.FullImage, artifact.FilePath, artifact.LineNumber, want.line)
+		}
+		if artifact.Tag != want.tag {
+			t.Errorf("artifact %s tag = %q, expected %q", artifact.FullImage, artifact.Tag, want.tag)
+		}
+	}
+}
+
+func TestFileScanner_ScanRepositories_HelmfileTemplates(t *testing.T) {
+	server := newTestGitHubServer(t, map[string]string{
+		"deploy/helmfile.yaml": `repositories:
+  - name: bitnami
+    url: https://charts.bitnami.com/bitnami
+releases:
+  - name: api
+    namespace: {{ .Environment.Name }}
+    chart: ./charts/api
+    values:
+      - values/api.yaml.gotmpl
+  - name: cache
+    chart: bitnami/redis
+    values:
+      - metrics:
+          image: redis:7.0
+`,
+		"deploy/values/api.yaml.gotmpl": `replicaCount: {{ .Values.replicas | default 2 }}
+image: "{{ .Values.registry }}/api:{{ .Values.tag }}"
+sidecar:
+  name: proxy
+  image: nginx:1.25
+{{- if .Values.debug }}
+debug: true
+{{- end }}
+`,
+	})
+
+	config := &types.Config{
+		GitHub: types.GitHubConfig{
+			Token:        "token",
+			APIURL:       server.URL,
+			Repositories: []types.GitHubRepositoryConfig{{Name: "company/manifests", Enabled: true}},
+		},
+	}
+
+	log := logger.NewTest()
+	fs := NewFileScanner(github.NewClient(&config.GitHub, log), log, config)
+	fs.UseState(openTestState(t))
+
+	results := fs.ScanRepositories(context.Background(), []*types.ImageInfo{{Image: "nginx:1.25"}, {Image: "redis:7.0"}})
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	var got []string
+	for _, detection := range results[0].Detections {
+		got = append(got, fmt.Sprintf("%s:%d:%s", detection.FilePath, detection.LineNumber, detection.FullImage))
+	}
+	sort.Strings(got)
+	expected := []string{
+		"deploy/helmfile.yaml:14:redis:7.0",
+		"deploy/values/api.yaml.gotmpl:5:nginx:1.25",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("detections = %v, expected %v", got, expected)
+	}
+}
@@ -0,0 +1,98 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type Store struct {
+	db *bolt.DB
+}
+
+func DefaultPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".privateer", "state.db")
+}
+
+func Open(path string) (*Store, error) {
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("falha ao criar diretório de estado %s: %w", path, err)
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir estado %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) Get(bucket, key string, value interface{}) (bool, error) {
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		data := b.Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, value)
+	})
+	if err != nil {
+		return false, fmt.Errorf("falha ao ler %s/%s do estado: %w", bucket, key, err)
+	}
+	return found, nil
+}
+
+func (s *Store) Put(bucket, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar %s/%s: %w", bucket, key, err)
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao gravar %s/%s no estado: %w", bucket, key, err)
+	}
+	return nil
+}
+
+func (s *Store) Reverse(bucket string, fn func(key string, data []byte) (bool, error)) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+
+		cursor := b.Cursor()
+		for key, data := cursor.Last(); key != nil; key, data = cursor.Prev() {
+			next, err := fn(string(key), data)
+			if err != nil || !next {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStore_GetAndPut(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer store.Close()
+
+	var missing map[string]string
+	if found, err := store.Get("entries", "company/manifests", &missing); err != nil || found {
+		t.Fatalf("Get() on empty store = %v, %v; expected not found", found, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, bucket := range []string{"entries", "other"} {
+		wg.Add(1)
+		go func(bucket string) {
+			defer wg.Done()
+			if err := store.Put(bucket, "company/manifests", map[string]string{"bucket": bucket}); err != nil {
+				t.Errorf("Put(%s) error = %v", bucket, err)
+			}
+		}(bucket)
+	}
+	wg.Wait()
+
+	var loaded map[string]string
+	found, err := store.Get("entries", "company/manifests", &loaded)
+	if err != nil || !found {
+		t.Fatalf("Get() = %v, %v; expected found", found, err)
+	}
+	if loaded["bucket"] != "entries" {
+		t.Errorf("Get() = %v, expected the value saved in its own bucket", loaded)
+	}
+
+	if found, err := store.Get("entries", "company/other", &loaded); err != nil || found {
+		t.Errorf("Get() unknown key = %v, %v; expected not found", found, err)
+	}
+}
+
+func TestStore_Reverse(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer store.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := store.Put("runs", key, key); err != nil {
+			t.Fatalf("Put(%q) error = %v", key, err)
+		}
+	}
+
+	var keys []string
+	err = store.Reverse("runs", func(key string, data []byte) (bool, error) {
+		keys = append(keys, key)
+		return len(keys) < 2, nil
+	})
+	if err != nil {
+		t.Fatalf("Reverse() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "c" || keys[1] != "b" {
+		t.Errorf("Reverse() keys = %v, expected [c b]", keys)
+	}
+}
@@ -107,7 +107,11 @@ func (d *DiscordWebhook) SendMigrationComplete(ctx context.Context, summary *typ
 		color = 0x0099ff
 	}
 
-	if summary.FailureCount > 0 {
+	if summary.GitOps != nil {
+		operation = "✅ MIGRAÇÃO COMPLETA CONCLUÍDA"
+	}
+
+	if summary.FailureCount > 0 || (summary.GitOps != nil && summary.GitOps.FailedOperations > 0) {
 		operation = "⚠️ MIGRAÇÃO COM FALHAS"
 		color = 0xff6600
 	}
@@ -129,6 +133,16 @@ func (d *DiscordWebhook) SendMigrationComplete(ctx context.Context, summary *typ
 		},
 	}
 
+	if summary.GitOps != nil {
+		fields = append(fields, types.DiscordEmbedField{
+			Name: "🔀 GitOps",
+			Value: fmt.Sprintf("**Repositórios:** %d/%d\n**PRs:** %d\n**Arquivos:** %d\n**Imagens substituídas:** %d\n**❌ Falhas:** %d",
+				summary.GitOps.ProcessedRepositories, summary.GitOps.TotalRepositories, summary.GitOps.SuccessfulPRs,
+				summary.GitOps.TotalFilesChanged, summary.GitOps.TotalImagesReplaced, summary.GitOps.FailedOperations),
+			Inline: true,
+		})
+	}
+
 	successExamples := d.getSuccessExamples(summary.Results, 3)
 	if len(successExamples) > 0 {
 		fields = append(fields, types.DiscordEmbedField{
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const defaultHTTPWebhookRetries = 3
+
+type HTTPWebhook struct {
+	url        string
+	headers    map[string]string
+	retries    int
+	retryDelay time.Duration
+	logger     *logger.Logger
+	client     *http.Client
+}
+
+func NewHTTPWebhook(config types.HTTPWebhookConfig, logger *logger.Logger) *HTTPWebhook {
+	retries := config.Retries
+	if retries <= 0 {
+		retries = defaultHTTPWebhookRetries
+	}
+
+	return &HTTPWebhook{
+		url:        config.URL,
+		headers:    config.Headers,
+		retries:    retries,
+		retryDelay: time.Second,
+		logger:     logger,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (h *HTTPWebhook) Send(ctx context.Context, payload any) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar resumo para o webhook HTTP: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= h.retries; attempt++ {
+		retryable, err := h.post(ctx, jsonData)
+		if err == nil {
+			h.logger.Debug("http_webhook_sent").
+				Str("url", h.url).
+				Int("attempt", attempt).
+				Send()
+			return nil
+		}
+		lastErr = err
+		if !retryable || attempt == h.retries {
+			break
+		}
+
+		h.logger.Debug("http_webhook_retry").
+			Str("url", h.url).
+			Int("attempt", attempt).
+			Err(err).
+			Send()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(h.retryDelay * time.Duration(attempt)):
+		}
+	}
+
+	return lastErr
+}
+
+func (h *HTTPWebhook) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", h.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("falha ao criar requisição do webhook HTTP: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range h.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("falha ao enviar webhook HTTP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("webhook HTTP retornou status %d", resp.StatusCode)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestHTTPWebhook_Send(t *testing.T) {
+	summary := map[string]any{
+		"command": "migrate cluster",
+		"dry_run": false,
+		"success": true,
+		"migration": map[string]any{
+			"total_images":  3,
+			"success_count": 3,
+		},
+	}
+	expectedBody, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		statuses      []int
+		wantErr       bool
+		expectedCalls int
+	}{
+		{name: "delivered on first attempt", statuses: []int{http.StatusOK}, expectedCalls: 1},
+		{name: "retries server errors", statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusAccepted}, expectedCalls: 3},
+		{name: "gives up after retries", statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}, wantErr: true, expectedCalls: 3},
+		{name: "does not retry client errors", statuses: []int{http.StatusUnauthorized}, wantErr: true, expectedCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if r.Method != http.MethodPost {
+					t.Errorf("method = %s, expected POST", r.Method)
+				}
+				if string(body) != string(expectedBody) {
+					t.Errorf("body = %s, expected %s", body, expectedBody)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization header = %q", got)
+				}
+				if got := r.Header.Get("X-Team"); got != "platform" {
+					t.Errorf("X-Team header = %q", got)
+				}
+				if got := r.Header.Get("Content-Type"); got != "application/json" {
+					t.Errorf("Content-Type header = %q", got)
+				}
+				w.WriteHeader(tt.statuses[calls])
+				calls++
+			}))
+			defer server.Close()
+
+			webhook := NewHTTPWebhook(types.HTTPWebhookConfig{
+				Enabled: true,
+				URL:     server.URL,
+				Headers: map[string]string{"Authorization": "Bearer secret", "X-Team": "platform"},
+			}, logger.NewTest())
+			webhook.retryDelay = 0
+
+			err := webhook.Send(context.Background(), summary)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("calls = %d, expected %d", calls, tt.expectedCalls)
+			}
+		})
+	}
+}
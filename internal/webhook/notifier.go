@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+type discordTarget struct {
+	webhook  *DiscordWebhook
+	notifyOn string
+}
+
+type DiscordNotifier struct {
+	targets []discordTarget
+	logger  *logger.Logger
+}
+
+func NewDiscordNotifier(config types.WebhookConfig, logger *logger.Logger) *DiscordNotifier {
+	notifier := &DiscordNotifier{logger: logger}
+
+	configs := append([]types.DiscordWebhookConfig{config.Discord}, config.DiscordTargets...)
+	for _, cfg := range configs {
+		if !cfg.Enabled || cfg.URL == "" {
+			continue
+		}
+		notifyOn := cfg.NotifyOn
+		if notifyOn == "" {
+			notifyOn = types.NotifyOnAlways
+		}
+		notifier.targets = append(notifier.targets, discordTarget{
+			webhook:  NewDiscordWebhook(cfg, logger),
+			notifyOn: notifyOn,
+		})
+	}
+
+	if len(notifier.targets) == 0 {
+		return nil
+	}
+	return notifier
+}
+
+func (n *DiscordNotifier) TargetCount() int {
+	return len(n.targets)
+}
+
+func (n *DiscordNotifier) SendMigrationStart(ctx context.Context, totalImages int, registries []string, dryRun bool) error {
+	return n.dispatch("", func(target *DiscordWebhook) error {
+		return target.SendMigrationStart(ctx, totalImages, registries, dryRun)
+	})
+}
+
+func (n *DiscordNotifier) SendMigrationComplete(ctx context.Context, summary *types.MigrationSummary, dryRun bool) error {
+	outcome := types.NotifyOnSuccess
+	if summary.FailureCount > 0 || (summary.GitOps != nil && summary.GitOps.FailedOperations > 0) {
+		outcome = types.NotifyOnFailure
+	}
+
+	return n.dispatch(outcome, func(target *DiscordWebhook) error {
+		return target.SendMigrationComplete(ctx, summary, dryRun)
+	})
+}
+
+func (n *DiscordNotifier) SendError(ctx context.Context, errorMsg string, operation string) error {
+	return n.dispatch(types.NotifyOnFailure, func(target *DiscordWebhook) error {
+		return target.SendError(ctx, errorMsg, operation)
+	})
+}
+
+func (n *DiscordNotifier) SendMessage(ctx context.Context, outcome string, message types.DiscordMessage) error {
+	return n.dispatch(outcome, func(target *DiscordWebhook) error {
+		targetMessage := message
+		targetMessage.Username = target.GetName()
+		targetMessage.AvatarURL = target.GetAvatar()
+		return target.SendMessage(ctx, targetMessage)
+	})
+}
+
+func (n *DiscordNotifier) dispatch(outcome string, send func(target *DiscordWebhook) error) error {
+	var errs []error
+	for _, target := range n.targets {
+		if target.notifyOn != types.NotifyOnAlways && target.notifyOn != outcome {
+			n.logger.Debug("discord_target_skipped").
+				Str("notify_on", target.notifyOn).
+				Str("outcome", outcome).
+				Send()
+			continue
+		}
+		if err := send(target.webhook); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
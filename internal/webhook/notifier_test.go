@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/internal/logger"
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestDiscordNotifier_SendMigrationComplete_RoutesByOutcome(t *testing.T) {
+	tests := []struct {
+		name            string
+		summary         *types.MigrationSummary
+		expectedOnCall  int
+		expectedGeneral int
+	}{
+		{
+			name:            "success only reaches general channel",
+			summary:         &types.MigrationSummary{TotalImages: 2, SuccessCount: 2},
+			expectedOnCall:  0,
+			expectedGeneral: 1,
+		},
+		{
+			name:            "failure reaches on-call channel",
+			summary:         &types.MigrationSummary{TotalImages: 2, SuccessCount: 1, FailureCount: 1},
+			expectedOnCall:  1,
+			expectedGeneral: 0,
+		},
+		{
+			name:            "gitops failure reaches on-call channel",
+			summary:         &types.MigrationSummary{TotalImages: 1, SuccessCount: 1, GitOps: &types.GitOpsSummary{FailedOperations: 1}},
+			expectedOnCall:  1,
+			expectedGeneral: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			onCallCalls := 0
+			onCall := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				onCallCalls++
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer onCall.Close()
+
+			generalCalls := 0
+			general := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				generalCalls++
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer general.Close()
+
+			notifier := NewDiscordNotifier(types.WebhookConfig{
+				DiscordTargets: []types.DiscordWebhookConfig{
+					{Enabled: true, URL: onCall.URL, NotifyOn: types.NotifyOnFailure},
+					{Enabled: true, URL: general.URL, NotifyOn: types.NotifyOnSuccess},
+				},
+			}, logger.NewTest())
+
+			if err := notifier.SendMigrationComplete(context.Background(), tt.summary, false); err != nil {
+				t.Fatalf("SendMigrationComplete() error = %v", err)
+			}
+			if onCallCalls != tt.expectedOnCall {
+				t.Errorf("on-call calls = %d, expected %d", onCallCalls, tt.expectedOnCall)
+			}
+			if generalCalls != tt.expectedGeneral {
+				t.Errorf("general calls = %d, expected %d", generalCalls, tt.expectedGeneral)
+			}
+		})
+	}
+}
+
+func TestNewDiscordNotifier_NoEnabledTargets(t *testing.T) {
+	notifier := NewDiscordNotifier(types.WebhookConfig{
+		Discord:        types.DiscordWebhookConfig{Enabled: false, URL: "https://discord.example.com/hook"},
+		DiscordTargets: []types.DiscordWebhookConfig{{Enabled: true}},
+	}, logger.NewTest())
+
+	if notifier != nil {
+		t.Errorf("expected nil notifier, got %d targets", notifier.TargetCount())
+	}
+}
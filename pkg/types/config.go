@@ -1,50 +1,124 @@
 package types
 
+import "time"
+
 type RegistryConfig struct {
-	Name      string   `yaml:"name"`
-	Type      string   `yaml:"type"`
-	Enabled   bool     `yaml:"enabled"`
-	Priority  int      `yaml:"priority"`
-	URL       string   `yaml:"url,omitempty"`
-	Username  string   `yaml:"username,omitempty"`
-	Password  string   `yaml:"password,omitempty"`
-	Insecure  bool     `yaml:"insecure,omitempty"`
-	Region    string   `yaml:"region,omitempty"`
-	Project   string   `yaml:"project,omitempty"`
-	AccountID string   `yaml:"account_id,omitempty"`
-	Profiles  []string `yaml:"profiles,omitempty"`
-	AccessKey string   `yaml:"access_key,omitempty"`
-	SecretKey string   `yaml:"secret_key,omitempty"`
+	Name            string            `yaml:"name"`
+	Type            string            `yaml:"type"`
+	Enabled         bool              `yaml:"enabled"`
+	Priority        int               `yaml:"priority"`
+	Role            string            `yaml:"role,omitempty"`
+	URL             string            `yaml:"url,omitempty"`
+	Username        string            `yaml:"username,omitempty"`
+	Password        string            `yaml:"password,omitempty"`
+	Insecure        bool              `yaml:"insecure,omitempty"`
+	InsecureHosts   []string          `yaml:"insecure_hosts,omitempty"`
+	Anonymous       bool              `yaml:"anonymous,omitempty"`
+	Region          string            `yaml:"region,omitempty"`
+	Project         string            `yaml:"project,omitempty"`
+	Path            string            `yaml:"path,omitempty"`
+	AccountID       string            `yaml:"account_id,omitempty"`
+	Profiles        []string          `yaml:"profiles,omitempty"`
+	AccessKey       string            `yaml:"access_key,omitempty"`
+	SecretKey       string            `yaml:"secret_key,omitempty"`
+	RoleARN         string            `yaml:"role_arn,omitempty"`
+	ExternalID      string            `yaml:"external_id,omitempty"`
+	SessionName     string            `yaml:"session_name,omitempty"`
+	ScanOnPush      bool              `yaml:"scan_on_push,omitempty"`
+	LifecyclePolicy string            `yaml:"lifecycle_policy,omitempty"`
+	RepositoryTags  map[string]string `yaml:"repository_tags,omitempty"`
+	Timeout         time.Duration     `yaml:"timeout,omitempty"`
+}
+
+const (
+	RegistryRoleTarget   = "target"
+	RegistryRoleValidate = "validate"
+	RegistryRoleBoth     = "both"
+)
+
+func (r RegistryConfig) IsPushTarget() bool {
+	return r.Role != RegistryRoleValidate
 }
 
 type KubernetesConfig struct {
 	Context    string   `yaml:"context"`
 	Namespaces []string `yaml:"namespaces"`
+	// ScanPods lists the Pods of each namespace to find ephemeral container
+	// images. It is opt-in because it needs pods:list on top of the workload
+	// permissions.
+	ScanPods bool `yaml:"scan_pods"`
 }
 
 type WebhookConfig struct {
-	Discord DiscordWebhookConfig `yaml:"discord"`
+	Discord        DiscordWebhookConfig   `yaml:"discord"`
+	DiscordTargets []DiscordWebhookConfig `yaml:"discord_targets,omitempty"`
+	HTTP           HTTPWebhookConfig      `yaml:"http,omitempty"`
 }
 
+const (
+	NotifyOnAlways  = "always"
+	NotifyOnSuccess = "success"
+	NotifyOnFailure = "failure"
+)
+
 type DiscordWebhookConfig struct {
-	Enabled bool   `yaml:"enabled"`
-	URL     string `yaml:"url"`
-	Avatar  string `yaml:"avatar,omitempty"`
-	Name    string `yaml:"name,omitempty"`
+	Enabled  bool   `yaml:"enabled"`
+	URL      string `yaml:"url"`
+	Avatar   string `yaml:"avatar,omitempty"`
+	Name     string `yaml:"name,omitempty"`
+	NotifyOn string `yaml:"notify_on,omitempty"`
+}
+
+type HTTPWebhookConfig struct {
+	Enabled bool              `yaml:"enabled"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Retries int               `yaml:"retries,omitempty"`
 }
 
 type SettingsConfig struct {
-	Language           string `yaml:"language"`
-	LogLevel           string `yaml:"log_level"`
-	DryRun             bool   `yaml:"dry_run"`
-	Concurrency        int    `yaml:"concurrency"`
-	MultipleRegistries bool   `yaml:"multiple_registries"`
+	Language            string              `yaml:"language"`
+	LogLevel            string              `yaml:"log_level"`
+	DryRun              bool                `yaml:"dry_run"`
+	Concurrency         int                 `yaml:"concurrency"`
+	MultipleRegistries  bool                `yaml:"multiple_registries"`
+	PullMirrors         map[string][]string `yaml:"pull_mirrors,omitempty"`
+	PreserveAnnotations bool                `yaml:"preserve_annotations,omitempty"`
+	AdditionalTags      []string            `yaml:"additional_tags,omitempty"`
+	Since               string              `yaml:"since,omitempty"`
+	MaxBandwidth        int64               `yaml:"max_bandwidth,omitempty"`
+	NamespaceScheduling NamespaceScheduling `yaml:"namespace_scheduling,omitempty"`
+	RegistrySelection   string              `yaml:"registry_selection,omitempty"`
+	RegistryRules       []RegistryRule      `yaml:"registry_rules,omitempty"`
+}
+
+// RegistrySelectionRoundRobinBalanced sends each image to the registry that
+// received the fewest images in the current run; it does not look at what is
+// already stored in the registries.
+const (
+	RegistrySelectionPriority           = "priority"
+	RegistrySelectionRoundRobin         = "round_robin"
+	RegistrySelectionRoundRobinBalanced = "round_robin_balanced"
+	RegistrySelectionByRule             = "by_rule"
+)
+
+type RegistryRule struct {
+	Pattern  string `yaml:"pattern"`
+	Registry string `yaml:"registry"`
+}
+
+type NamespaceScheduling struct {
+	Enabled     bool     `yaml:"enabled"`
+	Order       []string `yaml:"order,omitempty"`
+	Concurrency int      `yaml:"concurrency,omitempty"`
 }
 
 type ImageDetectionConfig struct {
 	CustomPublicRegistries  []string `yaml:"custom_public_registries"`
 	CustomPrivateRegistries []string `yaml:"custom_private_registries"`
 	IgnoreRegistries        []string `yaml:"ignore_registries"`
+	IncludeOnly             []string `yaml:"include_only"`
+	NoMigrate               []string `yaml:"no_migrate"`
 }
 
 type Config struct {
@@ -55,8 +129,21 @@ type Config struct {
 	Settings       SettingsConfig       `yaml:"settings"`
 	ImageDetection ImageDetectionConfig `yaml:"image_detection"`
 	Webhooks       WebhookConfig        `yaml:"webhooks"`
+	History        HistoryConfig        `yaml:"history,omitempty"`
 }
 
+type HistoryConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path,omitempty"`
+}
+
+const (
+	SkipReasonAlreadyExists = "already_exists"
+	SkipReasonSinceVersion  = "since_version"
+	SkipReasonSinceCreated  = "since_created"
+	SkipReasonNoMigrate     = "no_migrate"
+)
+
 type MigrationResult struct {
 	Image       *ImageInfo
 	TargetImage string
@@ -64,7 +151,9 @@ type MigrationResult struct {
 	Success     bool
 	Error       error
 	Skipped     bool
+	ReasonCode  string
 	Reason      string
+	Tags        []string
 }
 
 type MigrationSummary struct {
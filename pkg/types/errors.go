@@ -0,0 +1,13 @@
+package types
+
+import "errors"
+
+var (
+	ErrRegistryNotFound    = errors.New("registry não encontrado")
+	ErrInvalidRepoName     = errors.New("formato de repositório inválido")
+	ErrTokenUnauthorized   = errors.New("token não autorizado")
+	ErrRepositoryNotFound  = errors.New("repositório não encontrado")
+	ErrDriftDetected       = errors.New("drift detectado nos repositórios GitOps")
+	ErrPublicImagesFound   = errors.New("imagens públicas em execução no cluster")
+	ErrRegistryUnavailable = errors.New("registry temporariamente indisponível")
+)
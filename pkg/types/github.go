@@ -1,13 +1,26 @@
 package types
 
+const (
+	BranchStrategyCreateNew = "create_new"
+	BranchStrategyUseMain   = "use_main"
+)
+
 type GitHubRepositoryConfig struct {
-	Name           string   `yaml:"name"`
-	Enabled        bool     `yaml:"enabled"`
-	Priority       int      `yaml:"priority"`
-	Paths          []string `yaml:"paths"`
-	ExcludedPaths  []string `yaml:"excluded_paths"`
-	BranchStrategy string   `yaml:"branch_strategy"`
-	PRSettings     PRConfig `yaml:"pr_settings"`
+	Name           string    `yaml:"name"`
+	Enabled        bool      `yaml:"enabled"`
+	Priority       int       `yaml:"priority"`
+	Paths          []string  `yaml:"paths"`
+	ExcludedPaths  []string  `yaml:"excluded_paths"`
+	BranchStrategy string    `yaml:"branch_strategy"`
+	PRSettings     PRConfig  `yaml:"pr_settings"`
+	SSH            SSHConfig `yaml:"ssh,omitempty"`
+}
+
+type SSHConfig struct {
+	PrivateKeyPath    string `yaml:"private_key_path"`
+	URL               string `yaml:"url,omitempty"`
+	KnownHostsPath    string `yaml:"known_hosts_path,omitempty"`
+	AcceptNewHostKeys bool   `yaml:"accept_new_host_keys,omitempty"`
 }
 
 type PRConfig struct {
@@ -16,12 +29,14 @@ type PRConfig struct {
 	Labels       []string `yaml:"labels"`
 	Template     string   `yaml:"template"`
 	Draft        bool     `yaml:"draft"`
+	BaseBranch   string   `yaml:"base_branch,omitempty"`
 	CommitPrefix string   `yaml:"commit_prefix"`
 }
 
 type GitHubConfig struct {
 	Enabled      bool                     `yaml:"enabled"`
 	Token        string                   `yaml:"token"`
+	APIURL       string                   `yaml:"api_url"`
 	Repositories []GitHubRepositoryConfig `yaml:"repositories"`
 }
 
@@ -36,6 +51,7 @@ type ImageDetectionResult struct {
 	Image      string  `json:"image"`
 	Repository string  `json:"repository,omitempty"`
 	Tag        string  `json:"tag,omitempty"`
+	Digest     string  `json:"digest,omitempty"`
 	Registry   string  `json:"registry,omitempty"`
 	FullImage  string  `json:"full_image"`
 	IsPublic   bool    `json:"is_public"`
@@ -102,6 +118,22 @@ type LabelRequest struct {
 	Labels []string `json:"labels"`
 }
 
+type CreateIssueRequest struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+type IssueResponse struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+}
+
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
 type GitHubResponse struct {
 	StatusCode int
 	Headers    map[string][]string
@@ -187,6 +219,7 @@ type UpdateFileRequest struct {
 type Committer struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
+	Date  string `json:"date,omitempty"`
 }
 
 type UpdateFileResponse struct {
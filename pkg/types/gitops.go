@@ -17,17 +17,19 @@ type GitOpsResult struct {
 	FilesChanged   []FileChange       `json:"files_changed"`
 	ImagesChanged  []ImageReplacement `json:"images_changed"`
 	Success        bool               `json:"success"`
+	Deferred       bool               `json:"deferred,omitempty"`
 	Error          error              `json:"error,omitempty"`
 	ProcessingTime string             `json:"processing_time"`
 }
 
 type FileChange struct {
-	FilePath      string             `json:"file_path"`
-	FileType      string             `json:"file_type"`
-	Changes       []ImageReplacement `json:"changes"`
-	LinesChanged  int                `json:"lines_changed"`
-	Validated     bool               `json:"validated"`
-	BackupContent string             `json:"backup_content,omitempty"`
+	FilePath       string             `json:"file_path"`
+	FileType       string             `json:"file_type"`
+	Changes        []ImageReplacement `json:"changes"`
+	LinesChanged   int                `json:"lines_changed"`
+	Validated      bool               `json:"validated"`
+	BackupContent  string             `json:"backup_content,omitempty"`
+	PreviewContent string             `json:"preview_content,omitempty"`
 }
 
 type ImageReplacement struct {
@@ -38,6 +40,7 @@ type ImageReplacement struct {
 	LineNumber     int    `json:"line_number"`
 	Context        string `json:"context"`
 	ReplacementKey string `json:"replacement_key"`
+	TargetDigest   string `json:"target_digest,omitempty"`
 }
 
 type PullRequestInfo struct {
@@ -61,6 +64,8 @@ type GitOpsSummary struct {
 	FailedOperations      int             `json:"failed_operations"`
 	TotalFilesChanged     int             `json:"total_files_changed"`
 	TotalImagesReplaced   int             `json:"total_images_replaced"`
+	DeferredRepositories  int             `json:"deferred_repositories,omitempty"`
+	TrackingIssueURL      string          `json:"tracking_issue_url,omitempty"`
 	Results               []*GitOpsResult `json:"results"`
 	ProcessingTime        string          `json:"processing_time"`
 	Errors                []error         `json:"errors,omitempty"`
@@ -93,6 +98,33 @@ type GitOpsConfig struct {
 	MappingRules    []RepositoryMapping `yaml:"mapping_rules"`
 	ValidationRules ValidationConfig    `yaml:"validation"`
 	TagResolution   TagResolutionConfig `yaml:"tag_resolution"`
+	ExportPatches   bool                `yaml:"export_patches,omitempty"`
+	DryRunPreview   bool                `yaml:"dry_run_preview,omitempty"`
+	PinDigest       bool                `yaml:"pin_digest,omitempty"`
+	RequirePush     bool                `yaml:"require_push,omitempty"`
+	NoCleanup       bool                `yaml:"no_cleanup,omitempty"`
+	MaxFileSize     int64               `yaml:"max_file_size,omitempty"`
+	Incremental     bool                `yaml:"incremental,omitempty"`
+	MaxPRs          int                 `yaml:"max_prs,omitempty"`
+	Branch          string              `yaml:"branch,omitempty"`
+	ScanDockerfiles bool                `yaml:"scan_dockerfiles,omitempty"`
+	ImageOverrides  map[string]string   `yaml:"image_overrides,omitempty"`
+	TrackingIssue   TrackingIssueConfig `yaml:"tracking_issue,omitempty"`
+	Committer       CommitterConfig     `yaml:"committer"`
+}
+
+type TrackingIssueConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	Repository string   `yaml:"repository,omitempty"`
+	Title      string   `yaml:"title,omitempty"`
+	Labels     []string `yaml:"labels,omitempty"`
+}
+
+type CommitterConfig struct {
+	Name       string   `yaml:"name"`
+	Email      string   `yaml:"email"`
+	Trailers   []string `yaml:"trailers,omitempty"`
+	UseRunDate bool     `yaml:"use_run_date,omitempty"`
 }
 
 type ValidationConfig struct {
@@ -6,16 +6,21 @@ import (
 )
 
 type ImageInfo struct {
-	Image           string `json:"image"`
-	ResourceType    string `json:"resource_type"`
-	ResourceName    string `json:"resource_name"`
-	Namespace       string `json:"namespace"`
-	Container       string `json:"container"`
-	IsInitContainer bool   `json:"is_init_container"`
-	IsPublic        bool   `json:"is_public"`
-	Registry        string `json:"registry,omitempty"`
-	Repository      string `json:"repository,omitempty"`
-	Tag             string `json:"tag,omitempty"`
+	Image                string            `json:"image"`
+	ResourceType         string            `json:"resource_type"`
+	ResourceName         string            `json:"resource_name"`
+	Namespace            string            `json:"namespace"`
+	Container            string            `json:"container"`
+	IsInitContainer      bool              `json:"is_init_container"`
+	IsEphemeralContainer bool              `json:"is_ephemeral_container,omitempty"`
+	IsPublic             bool              `json:"is_public"`
+	NoMigrate            bool              `json:"no_migrate,omitempty"`
+	Registry             string            `json:"registry,omitempty"`
+	Repository           string            `json:"repository,omitempty"`
+	Tag                  string            `json:"tag,omitempty"`
+	Digest               string            `json:"digest,omitempty"`
+	SizeBytes            int64             `json:"size_bytes,omitempty"`
+	Annotations          map[string]string `json:"annotations,omitempty"`
 }
 
 type ParsedImage struct {
@@ -28,64 +33,25 @@ type ParsedImage struct {
 	Digest         string
 }
 
+// Deprecated: use utils.ParseImageName, which caches parsed references.
 func ParseImageName(imageName string) *ParsedImage {
-	parsed := &ParsedImage{
-		OriginalImage: imageName,
-		Tag:           "latest",
-	}
-
-	workingImage := imageName
-
-	if strings.Contains(workingImage, "@") {
-		parts := strings.Split(workingImage, "@")
-		workingImage = parts[0]
-		parsed.Digest = parts[1]
-	}
+	return NewParsedImage(imageName)
+}
 
-	if strings.Contains(workingImage, ":") {
-		parts := strings.Split(workingImage, ":")
-		workingImage = parts[0]
-		parsed.Tag = parts[1]
+func (p *ParsedImage) HasExplicitTag() bool {
+	name := strings.Split(p.OriginalImage, "@")[0]
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
 	}
+	return strings.Contains(name, ":")
+}
 
-	parts := strings.Split(workingImage, "/")
-
-	switch len(parts) {
-	case 1:
-		parsed.Registry = "docker.io"
-		parsed.Namespace = "library"
-		parsed.Repository = parts[0]
-		parsed.FullRepository = fmt.Sprintf("library/%s", parts[0])
-
-	case 2:
-		if strings.Contains(parts[0], ".") || strings.Contains(parts[0], ":") {
-			parsed.Registry = parts[0]
-			parsed.Namespace = ""
-			parsed.Repository = parts[1]
-			parsed.FullRepository = parts[1]
-		} else {
-			parsed.Registry = "docker.io"
-			parsed.Namespace = parts[0]
-			parsed.Repository = parts[1]
-			parsed.FullRepository = fmt.Sprintf("%s/%s", parts[0], parts[1])
-		}
-
-	case 3:
-		parsed.Registry = parts[0]
-		parsed.Namespace = parts[1]
-		parsed.Repository = parts[2]
-		parsed.FullRepository = fmt.Sprintf("%s/%s", parts[1], parts[2])
-
-	default:
-		parsed.Registry = parts[0]
-		parsed.Repository = parts[len(parts)-1]
-		parsed.Namespace = strings.Join(parts[1:len(parts)-1], "/")
-		parsed.FullRepository = strings.Join(parts[1:], "/")
+func (p *ParsedImage) Reference() string {
+	if p.Digest == "" {
+		return ":" + p.Tag
 	}
-
-	if parsed.Registry == "index.docker.io" || parsed.Registry == "registry-1.docker.io" {
-		parsed.Registry = "docker.io"
+	if !p.HasExplicitTag() {
+		return "@" + p.Digest
 	}
-
-	return parsed
+	return fmt.Sprintf(":%s@%s", p.Tag, p.Digest)
 }
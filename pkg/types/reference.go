@@ -0,0 +1,69 @@
+package types
+
+import "strings"
+
+const dockerHubHost = "docker.io"
+
+var dockerHubAliases = map[string]bool{
+	"docker.io":               true,
+	"index.docker.io":         true,
+	"registry-1.docker.io":    true,
+	"registry.hub.docker.com": true,
+}
+
+func IsDomainComponent(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost"
+}
+
+func CanonicalHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	if dockerHubAliases[host] {
+		return dockerHubHost
+	}
+	return host
+}
+
+func SplitReference(imageName string) (name, tag, digest string) {
+	name, digest, _ = strings.Cut(imageName, "@")
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		name, tag = name[:idx], name[idx+1:]
+	}
+	return name, tag, digest
+}
+
+func SplitHost(name string) (host, repository string) {
+	components := strings.SplitN(name, "/", 2)
+	if len(components) == 2 && IsDomainComponent(components[0]) {
+		host, repository = CanonicalHost(components[0]), components[1]
+	} else {
+		host, repository = dockerHubHost, name
+	}
+
+	if host == dockerHubHost && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return host, repository
+}
+
+func NewParsedImage(imageName string) *ParsedImage {
+	name, tag, digest := SplitReference(imageName)
+	if tag == "" {
+		tag = "latest"
+	}
+
+	host, repository := SplitHost(name)
+	parsed := &ParsedImage{
+		OriginalImage:  imageName,
+		Registry:       host,
+		Repository:     repository,
+		FullRepository: repository,
+		Tag:            tag,
+		Digest:         digest,
+	}
+	if idx := strings.LastIndex(repository, "/"); idx != -1 {
+		parsed.Namespace, parsed.Repository = repository[:idx], repository[idx+1:]
+	}
+	return parsed
+}
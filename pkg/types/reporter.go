@@ -5,6 +5,7 @@ type ReportData struct {
 	Timestamp      string
 	ExecutionMode  string
 	Summary        *MigrationSummary
+	GitOps         *GitOpsSummary
 	Config         ReportConfig
 	Statistics     ReportStatistics
 	RegistryStats  []RegistryStatistic
@@ -52,4 +53,6 @@ type ImageStatus struct {
 	ResourceType string
 	Namespace    string
 	Container    string
+	Digest       string
+	Size         string
 }
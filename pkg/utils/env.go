@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var envValuePattern = regexp.MustCompile(`^(\s*(?:-\s+)?value:\s*)(["']?)([^"'\s]+)(["']?)(\s*(?:#.*)?)$`)
+
+type EnvValue struct {
+	Line   int
+	Value  string
+	prefix string
+	open   string
+	close  string
+	suffix string
+}
+
+func (v EnvValue) WithValue(value string) string {
+	return v.prefix + v.open + value + v.close + v.suffix
+}
+
+func FindEnvValues(lines []string) []EnvValue {
+	var values []EnvValue
+	envIndent := -1
+
+	for lineNum, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if trimmedLine == "" || strings.HasPrefix(trimmedLine, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if envIndent >= 0 && indent <= envIndent && !strings.HasPrefix(trimmedLine, "-") {
+			envIndent = -1
+		}
+
+		if trimmedLine == "env:" || strings.HasPrefix(trimmedLine, "env: #") {
+			envIndent = indent
+			continue
+		}
+
+		if envIndent < 0 {
+			continue
+		}
+
+		matches := envValuePattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if matches == nil || matches[2] != matches[4] {
+			continue
+		}
+
+		values = append(values, EnvValue{
+			Line:   lineNum,
+			Value:  matches[3],
+			prefix: matches[1],
+			open:   matches[2],
+			close:  matches[4],
+			suffix: matches[5],
+		})
+	}
+
+	return values
+}
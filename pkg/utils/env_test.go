@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindEnvValues(t *testing.T) {
+	content := `spec:
+  containers:
+    - name: app
+      image: company/app:2.0
+      env:
+        - name: SIDECAR_IMAGE
+          value: "nginx:1.21"
+        - value: 'busybox:1.36'
+          name: INIT_IMAGE
+        - name: LOG_LEVEL
+          value: debug  # nível de log
+        - name: MALFORMED
+          value: "redis:7
+      args:
+        - value: alpine:3.19
+`
+	lines := strings.Split(content, "\n")
+	values := FindEnvValues(lines)
+
+	expected := []struct {
+		line     int
+		value    string
+		replaced string
+	}{
+		{6, "nginx:1.21", `          value: "registry.company.com/nginx:1.21"`},
+		{7, "busybox:1.36", `        - value: 'registry.company.com/busybox:1.36'`},
+		{10, "debug", `          value: registry.company.com/debug  # nível de log`},
+	}
+
+	if len(values) != len(expected) {
+		t.Fatalf("FindEnvValues() returned %d values, expected %d: %+v", len(values), len(expected), values)
+	}
+	for i, want := range expected {
+		got := values[i]
+		if got.Line != want.line || got.Value != want.value {
+			t.Errorf("value %d = %q at line %d, expected %q at line %d", i, got.Value, got.Line, want.value, want.line)
+		}
+		if replaced := got.WithValue("registry.company.com/" + got.Value); replaced != want.replaced {
+			t.Errorf("WithValue() = %q, expected %q", replaced, want.replaced)
+		}
+	}
+}
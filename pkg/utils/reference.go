@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+const maxImageNameLength = 255
+
+var (
+	domainComponentPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+	pathComponentPattern   = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*$`)
+	tagPattern             = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	digestPattern          = regexp.MustCompile(`^[a-z0-9]+([+._-][a-z0-9]+)*:[a-fA-F0-9]+$`)
+	portPattern            = regexp.MustCompile(`^[0-9]+$`)
+)
+
+func ValidateImageReference(ref string) error {
+	if ref == "" {
+		return fmt.Errorf("referência de imagem vazia")
+	}
+	if strings.TrimSpace(ref) != ref || strings.ContainsAny(ref, " \t\n") {
+		return fmt.Errorf("referência %q contém espaços", ref)
+	}
+
+	name := ref
+	if idx := strings.Index(name, "@"); idx != -1 {
+		digest := name[idx+1:]
+		name = name[:idx]
+		if !digestPattern.MatchString(digest) {
+			return fmt.Errorf("digest inválido %q em %q", digest, ref)
+		}
+	}
+
+	if idx := strings.LastIndex(name, ":"); idx != -1 && !strings.Contains(name[idx+1:], "/") {
+		tag := name[idx+1:]
+		name = name[:idx]
+		if !tagPattern.MatchString(tag) {
+			return fmt.Errorf("tag inválida %q em %q", tag, ref)
+		}
+	}
+
+	if name == "" {
+		return fmt.Errorf("referência %q não possui nome de repositório", ref)
+	}
+	if len(name) > maxImageNameLength {
+		return fmt.Errorf("nome do repositório em %q excede %d caracteres", ref, maxImageNameLength)
+	}
+
+	components := strings.Split(name, "/")
+	for i, component := range components {
+		if component == "" {
+			return fmt.Errorf("referência %q possui segmento vazio no caminho (barra duplicada ou nas extremidades)", ref)
+		}
+		if i == 0 && len(components) > 1 && types.IsDomainComponent(component) {
+			if err := validateDomain(component); err != nil {
+				return fmt.Errorf("domínio inválido em %q: %w", ref, err)
+			}
+			continue
+		}
+		if !pathComponentPattern.MatchString(component) {
+			return fmt.Errorf("segmento %q inválido em %q (use apenas letras minúsculas, números e separadores . _ -)", component, ref)
+		}
+	}
+
+	return nil
+}
+
+func validateDomain(domain string) error {
+	host := domain
+	if idx := strings.LastIndex(domain, ":"); idx != -1 {
+		host = domain[:idx]
+		if port := domain[idx+1:]; !portPattern.MatchString(port) {
+			return fmt.Errorf("porta inválida %q", port)
+		}
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if !domainComponentPattern.MatchString(label) {
+			return fmt.Errorf("componente de domínio inválido %q em %q", label, domain)
+		}
+	}
+	return nil
+}
+
+type Ref struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+func CanonicalHost(host string) string {
+	return types.CanonicalHost(host)
+}
+
+func SplitReference(imageName string) (name, tag, digest string) {
+	return types.SplitReference(imageName)
+}
+
+func SplitHost(name string) (host, repository string) {
+	return types.SplitHost(name)
+}
+
+func WithTag(imageName, tag string) string {
+	name, _, _ := SplitReference(imageName)
+	return name + ":" + tag
+}
+
+func WithDigest(imageName, digest string) string {
+	name, _, _ := SplitReference(imageName)
+	return name + "@" + digest
+}
+
+func CanonicalRef(s string) (Ref, error) {
+	s = strings.TrimSpace(s)
+
+	var ref Ref
+	name, tag, digest := SplitReference(s)
+	base, _, hasDigest := strings.Cut(s, "@")
+	if hasDigest {
+		ref.Digest = strings.ToLower(digest)
+	}
+	hasTag := len(name) < len(base)
+	ref.Tag = tag
+
+	normalized := strings.ToLower(name)
+	if hasTag {
+		normalized += ":" + ref.Tag
+	}
+	if hasDigest {
+		normalized += "@" + ref.Digest
+	}
+	if err := ValidateImageReference(normalized); err != nil {
+		return Ref{}, err
+	}
+
+	ref.Registry, ref.Repository = SplitHost(name)
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	return ref, nil
+}
+
+func (r Ref) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+func (r Ref) String() string {
+	s := r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+func (r Ref) Equal(other Ref) bool {
+	if r.Registry != other.Registry || r.Repository != other.Repository {
+		return false
+	}
+	if r.Digest != "" && other.Digest != "" {
+		return r.Digest == other.Digest
+	}
+	return r.Tag == other.Tag && r.Digest == other.Digest
+}
+
+func SameImage(a, b string) bool {
+	refA, err := CanonicalRef(a)
+	if err != nil {
+		return false
+	}
+	refB, err := CanonicalRef(b)
+	if err != nil {
+		return false
+	}
+	return refA.Equal(refB)
+}
+
+func IsTemplatedImage(imageName string) bool {
+	return strings.Contains(imageName, "{{") || strings.Contains(imageName, "}}")
+}
@@ -0,0 +1,189 @@
+package utils
+
+import "testing"
+
+func TestValidateImageReference(t *testing.T) {
+	tests := []struct {
+		ref     string
+		wantErr bool
+	}{
+		{"nginx", false},
+		{"nginx:1.25", false},
+		{"harbor.company.com/library/nginx:1.25", false},
+		{"localhost:5000/team/app:v1.0.0", false},
+		{"123456789012.dkr.ecr.us-east-1.amazonaws.com/app@sha256:abc123", false},
+		{"ghcr.io/org/app:latest@sha256:abcd1234", false},
+		{"registry.example.com/my_team/app__v2:1.0", false},
+		{"", true},
+		{"harbor.company.com//nginx:1.25", true},
+		{"harbor.company.com/library/:1.25", true},
+		{"harbor.company.com/", true},
+		{"ghcr.io/MyOrg/app:latest", true},
+		{"registry.example.com/app:", true},
+		{"registry.example.com/app:-bad", true},
+		{"registry.example.com/app@sha256:xyz", true},
+		{"bad_domain.com:port/app", true},
+		{"registry.example.com/my app:1.0", true},
+		{"registry.example.com/app-:1.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			err := ValidateImageReference(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateImageReference(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanonicalRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected Ref
+		wantErr  bool
+	}{
+		{ref: "nginx", expected: Ref{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{ref: "nginx:1.25", expected: Ref{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}},
+		{ref: "library/nginx:1.25", expected: Ref{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}},
+		{ref: "docker.io/nginx:1.25", expected: Ref{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}},
+		{ref: "index.docker.io/library/nginx:1.25", expected: Ref{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}},
+		{ref: "registry-1.docker.io/bitnami/redis:7.0", expected: Ref{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.0"}},
+		{ref: "registry.hub.docker.com/bitnami/redis", expected: Ref{Registry: "docker.io", Repository: "bitnami/redis", Tag: "latest"}},
+		{ref: "bitnami/redis:7.0", expected: Ref{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.0"}},
+		{ref: "Docker.IO/bitnami/redis:7.0-ALPINE", expected: Ref{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.0-ALPINE"}},
+		{ref: "Quay.IO/Prometheus/Node-Exporter:v1", expected: Ref{Registry: "quay.io", Repository: "Prometheus/Node-Exporter", Tag: "v1"}},
+		{ref: "quay.io/prometheus/node-exporter", expected: Ref{Registry: "quay.io", Repository: "prometheus/node-exporter", Tag: "latest"}},
+		{ref: "quay.io/nginx:1.25", expected: Ref{Registry: "quay.io", Repository: "nginx", Tag: "1.25"}},
+		{ref: "registry.k8s.io/ingress-nginx/controller:v1.9.4", expected: Ref{Registry: "registry.k8s.io", Repository: "ingress-nginx/controller", Tag: "v1.9.4"}},
+		{ref: "localhost/app", expected: Ref{Registry: "localhost", Repository: "app", Tag: "latest"}},
+		{ref: "localhost:5000/team/app", expected: Ref{Registry: "localhost:5000", Repository: "team/app", Tag: "latest"}},
+		{ref: "Registry.Example.com:5000/team/app:v2", expected: Ref{Registry: "registry.example.com:5000", Repository: "team/app", Tag: "v2"}},
+		{ref: "nginx@sha256:ABC123", expected: Ref{Registry: "docker.io", Repository: "library/nginx", Digest: "sha256:abc123"}},
+		{ref: "ghcr.io/org/app:1.0@sha256:abcd", expected: Ref{Registry: "ghcr.io", Repository: "org/app", Tag: "1.0", Digest: "sha256:abcd"}},
+		{ref: " nginx:1.25 ", expected: Ref{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}},
+		{ref: "", wantErr: true},
+		{ref: "registry.example.com//app", wantErr: true},
+		{ref: "registry.example.com/app:", wantErr: true},
+		{ref: "nginx@sha256:xyz", wantErr: true},
+		{ref: "{{ .Values.image }}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			ref, err := CanonicalRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CanonicalRef(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if !tt.wantErr && ref != tt.expected {
+				t.Errorf("CanonicalRef(%q) = %+v, expected %+v", tt.ref, ref, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRef_String(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{"nginx", "docker.io/library/nginx:latest"},
+		{"index.docker.io/bitnami/redis:7.0", "docker.io/bitnami/redis:7.0"},
+		{"localhost:5000/app@sha256:abc", "localhost:5000/app@sha256:abc"},
+		{"ghcr.io/org/app:1.0@sha256:abcd", "ghcr.io/org/app:1.0@sha256:abcd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			ref, err := CanonicalRef(tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ref.String(); got != tt.expected {
+				t.Errorf("String() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSameImage(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"nginx", "docker.io/library/nginx:latest", true},
+		{"nginx:1.25", "index.docker.io/library/nginx:1.25", true},
+		{"bitnami/redis:7.0", "registry-1.docker.io/bitnami/redis:7.0", true},
+		{"Quay.io/prometheus/node-exporter", "quay.io/prometheus/node-exporter:latest", true},
+		{"quay.io/Prometheus/node-exporter", "quay.io/prometheus/node-exporter", false},
+		{"nginx@sha256:abc", "nginx:1.25@sha256:abc", true},
+		{"nginx:1.25@sha256:abc", "nginx:1.26@sha256:abc", true},
+		{"nginx:1.25", "nginx:1.26", false},
+		{"nginx:1.25", "nginx:1.25@sha256:abc", false},
+		{"nginx@sha256:abc", "nginx@sha256:def", false},
+		{"nginx:latest", "nginx@sha256:abc", false},
+		{"quay.io/nginx:1.25", "nginx:1.25", false},
+		{"registry.example.com/app", "registry.example.com:5000/app", false},
+		{"library/nginx", "bitnami/nginx", false},
+		{"nginx:1.25", "{{ .Values.image }}", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := SameImage(tt.a, tt.b); got != tt.expected {
+				t.Errorf("SameImage(%q, %q) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitReference(t *testing.T) {
+	tests := []struct {
+		image               string
+		name, tag, digest   string
+		withTag, withDigest string
+	}{
+		{"nginx", "nginx", "", "", "nginx:v2", "nginx@sha256:def"},
+		{"localhost:5000/team/app:1.0", "localhost:5000/team/app", "1.0", "", "localhost:5000/team/app:v2", "localhost:5000/team/app@sha256:def"},
+		{"registry.example.com:5000/app", "registry.example.com:5000/app", "", "", "registry.example.com:5000/app:v2", "registry.example.com:5000/app@sha256:def"},
+		{"ghcr.io/org/app:1.0@sha256:abc", "ghcr.io/org/app", "1.0", "sha256:abc", "ghcr.io/org/app:v2", "ghcr.io/org/app@sha256:def"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag, digest := SplitReference(tt.image)
+			if name != tt.name || tag != tt.tag || digest != tt.digest {
+				t.Errorf("SplitReference() = (%q, %q, %q), expected (%q, %q, %q)", name, tag, digest, tt.name, tt.tag, tt.digest)
+			}
+			if got := WithTag(tt.image, "v2"); got != tt.withTag {
+				t.Errorf("WithTag() = %q, expected %q", got, tt.withTag)
+			}
+			if got := WithDigest(tt.image, "sha256:def"); got != tt.withDigest {
+				t.Errorf("WithDigest() = %q, expected %q", got, tt.withDigest)
+			}
+		})
+	}
+}
+
+func TestSplitHost(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       string
+		repository string
+	}{
+		{"nginx", "docker.io", "library/nginx"},
+		{"bitnami/redis", "docker.io", "bitnami/redis"},
+		{"Index.Docker.io/nginx", "docker.io", "library/nginx"},
+		{"localhost/app", "localhost", "app"},
+		{"Quay.IO/Prometheus/Node-Exporter", "quay.io", "Prometheus/Node-Exporter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, repository := SplitHost(tt.name)
+			if host != tt.host || repository != tt.repository {
+				t.Errorf("SplitHost() = (%q, %q), expected (%q, %q)", host, repository, tt.host, tt.repository)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package utils
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/kevinfinalboss/privateer/pkg/types"
 )
@@ -31,35 +33,42 @@ func IsPublicRegistry(registry string) bool {
 	return false
 }
 
-func ExtractRegistry(imageName string) string {
-	if strings.Contains(imageName, "/") {
-		parts := strings.Split(imageName, "/")
-		if len(parts) >= 2 && strings.Contains(parts[0], ".") {
-			return parts[0]
+var deprecatedRegistryPrefixes = []struct {
+	prefix      string
+	replacement string
+}{
+	{prefix: "k8s.gcr.io/", replacement: "registry.k8s.io/"},
+	{prefix: "gcr.io/google-containers/", replacement: "registry.k8s.io/"},
+	{prefix: "gcr.io/google_containers/", replacement: "registry.k8s.io/"},
+}
+
+func DeprecatedRegistryReplacement(imageName string) (string, bool) {
+	lower := strings.ToLower(imageName)
+	for _, deprecated := range deprecatedRegistryPrefixes {
+		if strings.HasPrefix(lower, deprecated.prefix) {
+			return deprecated.replacement + imageName[len(deprecated.prefix):], true
 		}
 	}
-	return "docker.io"
+	return "", false
+}
+
+func ExtractRegistry(imageName string) string {
+	return ParseImageName(imageName).Registry
 }
 
 func ExtractRepository(imageName string) string {
-	if strings.Contains(imageName, ":") {
-		return strings.Split(imageName, ":")[0]
-	}
-	return imageName
+	name, _, _ := SplitReference(imageName)
+	return name
 }
 
+// Deprecated: use SplitReference and take the last path segment of the name.
 func ExtractRepositoryOnly(imageName string) string {
-	fullRepo := ExtractRepository(imageName)
-	parts := strings.Split(fullRepo, "/")
-	return parts[len(parts)-1]
+	name, _, _ := SplitReference(imageName)
+	return name[strings.LastIndex(name, "/")+1:]
 }
 
 func ExtractTag(imageName string) string {
-	if strings.Contains(imageName, ":") {
-		parts := strings.Split(imageName, ":")
-		return parts[len(parts)-1]
-	}
-	return "latest"
+	return ParseImageName(imageName).Tag
 }
 
 func BuildFullImageName(registry, repository, tag string) string {
@@ -76,6 +85,71 @@ func BuildDockerIOImageName(repository, tag string) string {
 	return fmt.Sprintf("docker.io/%s:%s", repository, tag)
 }
 
+func CountUniqueImages(images []*types.ImageInfo) int {
+	unique := make(map[string]bool, len(images))
+	for _, image := range images {
+		unique[image.Image] = true
+	}
+	return len(unique)
+}
+
+const maxParsedImageCacheSize = 4096
+
+var parsedImageCache = struct {
+	sync.RWMutex
+	entries map[string]types.ParsedImage
+}{entries: make(map[string]types.ParsedImage)}
+
 func ParseImageName(imageName string) *types.ParsedImage {
-	return types.ParseImageName(imageName)
+	parsedImageCache.RLock()
+	cached, found := parsedImageCache.entries[imageName]
+	parsedImageCache.RUnlock()
+
+	if found {
+		return &cached
+	}
+
+	parsed := types.NewParsedImage(imageName)
+
+	parsedImageCache.Lock()
+	if len(parsedImageCache.entries) >= maxParsedImageCacheSize {
+		parsedImageCache.entries = make(map[string]types.ParsedImage)
+	}
+	parsedImageCache.entries[imageName] = *parsed
+	parsedImageCache.Unlock()
+
+	return parsed
+}
+
+type RegistryPattern struct {
+	prefix string
+	re     *regexp.Regexp
+}
+
+func CompileRegistryPattern(pattern string) (*RegistryPattern, error) {
+	if expr, ok := strings.CutPrefix(pattern, "regex:"); ok {
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return nil, fmt.Errorf("regex inválida %q: %w", expr, err)
+		}
+		return &RegistryPattern{re: re}, nil
+	}
+
+	patternLower := strings.ToLower(pattern)
+	if !strings.Contains(patternLower, "*") {
+		return &RegistryPattern{prefix: patternLower}, nil
+	}
+
+	parts := strings.Split(patternLower, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return &RegistryPattern{re: regexp.MustCompile("^" + strings.Join(parts, "[^/]*"))}, nil
+}
+
+func (p *RegistryPattern) Match(imageLower string) bool {
+	if p.re != nil {
+		return p.re.MatchString(imageLower)
+	}
+	return strings.HasPrefix(imageLower, p.prefix)
 }
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kevinfinalboss/privateer/pkg/types"
+)
+
+func TestParseImageName_Cached(t *testing.T) {
+	images := []string{
+		"nginx",
+		"nginx:1.25",
+		"bitnami/redis:7.0",
+		"quay.io/prometheus/node-exporter:v1.6.0",
+		"registry.example.com/team/group/app:2.0",
+		"ghcr.io/org/app@sha256:abc123",
+	}
+
+	for _, image := range images {
+		t.Run(image, func(t *testing.T) {
+			expected := types.NewParsedImage(image)
+
+			first := ParseImageName(image)
+			parsedImageCache.RLock()
+			_, cached := parsedImageCache.entries[image]
+			parsedImageCache.RUnlock()
+			if !cached {
+				t.Fatalf("expected %s to be cached after first parse", image)
+			}
+
+			second := ParseImageName(image)
+			if *first != *expected || *second != *expected {
+				t.Errorf("ParseImageName(%s) = %+v / %+v, expected %+v", image, *first, *second, *expected)
+			}
+		})
+	}
+}
+
+func TestParseImageName(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected types.ParsedImage
+	}{
+		{"nginx", types.ParsedImage{Registry: "docker.io", Namespace: "library", Repository: "nginx", FullRepository: "library/nginx", Tag: "latest"}},
+		{"bitnami/redis:7.0", types.ParsedImage{Registry: "docker.io", Namespace: "bitnami", Repository: "redis", FullRepository: "bitnami/redis", Tag: "7.0"}},
+		{"index.docker.io/nginx:1.25", types.ParsedImage{Registry: "docker.io", Namespace: "library", Repository: "nginx", FullRepository: "library/nginx", Tag: "1.25"}},
+		{"ghcr.io/app:1.0", types.ParsedImage{Registry: "ghcr.io", Repository: "app", FullRepository: "app", Tag: "1.0"}},
+		{"registry.local:5000/team/app:1.0", types.ParsedImage{Registry: "registry.local:5000", Namespace: "team", Repository: "app", FullRepository: "team/app", Tag: "1.0"}},
+		{"localhost:5000/app", types.ParsedImage{Registry: "localhost:5000", Repository: "app", FullRepository: "app", Tag: "latest"}},
+		{"registry.local:5000/a/b/app@sha256:abc", types.ParsedImage{Registry: "registry.local:5000", Namespace: "a/b", Repository: "app", FullRepository: "a/b/app", Tag: "latest", Digest: "sha256:abc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			tt.expected.OriginalImage = tt.image
+			if got := ParseImageName(tt.image); *got != tt.expected {
+				t.Errorf("ParseImageName(%q) = %+v, expected %+v", tt.image, *got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseImageName_CacheIsolation(t *testing.T) {
+	first := ParseImageName("alpine:3.19")
+	first.Tag = "mutated"
+
+	second := ParseImageName("alpine:3.19")
+	if second.Tag != "3.19" {
+		t.Errorf("cached result was mutated through a returned pointer: tag = %s", second.Tag)
+	}
+}
+
+func TestDeprecatedParsingWrappers(t *testing.T) {
+	tests := []struct {
+		image          string
+		repositoryOnly string
+	}{
+		{"nginx", "nginx"},
+		{"bitnami/redis:7.0", "redis"},
+		{"registry.local:5000/team/app:1.0", "app"},
+		{"ghcr.io/org/app@sha256:abc123", "app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := ExtractRepositoryOnly(tt.image); got != tt.repositoryOnly {
+				t.Errorf("ExtractRepositoryOnly(%q) = %s, expected %s", tt.image, got, tt.repositoryOnly)
+			}
+			if got, expected := types.ParseImageName(tt.image), ParseImageName(tt.image); *got != *expected {
+				t.Errorf("types.ParseImageName(%q) = %+v, expected %+v", tt.image, *got, *expected)
+			}
+		})
+	}
+}
+
+func BenchmarkParseImageName(b *testing.B) {
+	images := make([]string, 64)
+	for i := range images {
+		images[i] = fmt.Sprintf("registry.example.com/team/app-%d:1.%d.0", i, i)
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ParseImageName(images[i%len(images)])
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			types.NewParsedImage(images[i%len(images)])
+		}
+	})
+}
+
+func TestCountUniqueImages(t *testing.T) {
+	var images []*types.ImageInfo
+	for i := 0; i < 50; i++ {
+		images = append(images, &types.ImageInfo{Image: "nginx:1.21", Namespace: "web", ResourceName: fmt.Sprintf("web-%d", i)})
+	}
+	images = append(images,
+		&types.ImageInfo{Image: "redis:7.0", Namespace: "cache"},
+		&types.ImageInfo{Image: "redis:7.0", Namespace: "sessions"},
+		&types.ImageInfo{Image: "nginx:1.25", Namespace: "web"},
+	)
+
+	if got := len(images); got != 53 {
+		t.Fatalf("total occurrences = %d, expected 53", got)
+	}
+	if got := CountUniqueImages(images); got != 3 {
+		t.Errorf("CountUniqueImages() = %d, expected 3", got)
+	}
+	if got := CountUniqueImages(nil); got != 0 {
+		t.Errorf("CountUniqueImages(nil) = %d, expected 0", got)
+	}
+}
+
+func TestDeprecatedRegistryReplacement(t *testing.T) {
+	tests := []struct {
+		image       string
+		replacement string
+		deprecated  bool
+	}{
+		{"k8s.gcr.io/pause:3.2", "registry.k8s.io/pause:3.2", true},
+		{"K8s.Gcr.io/coredns/coredns:v1.8.0", "registry.k8s.io/coredns/coredns:v1.8.0", true},
+		{"gcr.io/google-containers/busybox:1.27", "registry.k8s.io/busybox:1.27", true},
+		{"gcr.io/google_containers/pause-amd64:3.1", "registry.k8s.io/pause-amd64:3.1", true},
+		{"registry.k8s.io/pause:3.9", "", false},
+		{"gcr.io/distroless/static:nonroot", "", false},
+		{"nginx:1.25", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			replacement, deprecated := DeprecatedRegistryReplacement(tt.image)
+			if deprecated != tt.deprecated || replacement != tt.replacement {
+				t.Errorf("DeprecatedRegistryReplacement(%q) = (%q, %v), expected (%q, %v)", tt.image, replacement, deprecated, tt.replacement, tt.deprecated)
+			}
+		})
+	}
+}
+
+func TestCompileRegistryPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		image    string
+		expected bool
+	}{
+		{name: "prefix match", pattern: "ghcr.io/myorg", image: "ghcr.io/myorg/app:1.0", expected: true},
+		{name: "prefix mismatch", pattern: "ghcr.io/myorg", image: "ghcr.io/other/app:1.0", expected: false},
+		{name: "glob host wildcard", pattern: "*.azurecr.io", image: "mycompany.azurecr.io/app:1.0", expected: true},
+		{name: "glob host wildcard does not cross path", pattern: "*.azurecr.io", image: "docker.io/azurecr.io/app:1.0", expected: false},
+		{name: "glob path wildcard", pattern: "ghcr.io/myorg/*", image: "ghcr.io/myorg/team/app:1.0", expected: true},
+		{name: "glob path wildcard other owner", pattern: "ghcr.io/myorg/*", image: "ghcr.io/other/app:1.0", expected: false},
+		{name: "glob is case insensitive", pattern: "*.AzureCR.io", image: "mycompany.azurecr.io/app:1.0", expected: true},
+		{name: "regex match", pattern: `regex:^[0-9]+\.dkr\.ecr\.[a-z0-9-]+\.amazonaws\.com/`, image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:1.0", expected: true},
+		{name: "regex mismatch", pattern: `regex:^quay\.io/(prometheus|grafana)/`, image: "quay.io/jetstack/cert-manager:1.0", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := CompileRegistryPattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("CompileRegistryPattern(%q) error = %v", tt.pattern, err)
+			}
+			if result := pattern.Match(tt.image); result != tt.expected {
+				t.Errorf("Match(%q) with %q = %v, expected %v", tt.image, tt.pattern, result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := CompileRegistryPattern("regex:(["); err == nil {
+		t.Error("CompileRegistryPattern() should reject an invalid regex")
+	}
+}